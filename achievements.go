@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// achievementsFile is the file in the data directory that records the
+// achievements unlocked. It is the source of truth: store integrations only
+// mirror it.
+const achievementsFile = "achievements.json"
+
+// achievement is something the player can unlock. Its ID is also its API
+// name in the stores, so it must never change once released.
+type achievement struct {
+	id, title, description string
+	// earned reports whether the game as it stands unlocks the achievement,
+	// given the statistics with the game counted if it is over.
+	earned func(s engine.Snapshot, stats *statsStore) bool
+}
+
+// advancedWinsTarget is how many wins at the Advanced level earn Regular.
+const advancedWinsTarget = 10
+
+// achievements lists every achievement.
+var achievements = []achievement{
+	{"first_win", "First Win", "Win a game.", func(s engine.Snapshot, _ *statsStore) bool {
+		return s.State == engine.StateGameOver && s.PlayerPoints > s.CPUPoints
+	}},
+	{"first_pisti", "Pişti!", "Make a Pişti.", func(s engine.Snapshot, _ *statsStore) bool {
+		return playerPistis(s, false) > 0
+	}},
+	{"jack_pisti", "Jack of All Trades", "Make a Jack Pişti.", func(s engine.Snapshot, _ *statsStore) bool {
+		return playerPistis(s, true) > 0
+	}},
+	{"hat_trick", "Hat Trick", "Make three Piştis in one game.", func(s engine.Snapshot, _ *statsStore) bool {
+		return playerPistis(s, false) >= 3
+	}},
+	{"fifty_points", "Half Century", "Score 50 points in one game.", func(s engine.Snapshot, _ *statsStore) bool {
+		return s.PlayerPoints >= 50
+	}},
+	{"beat_advanced", "Card Shark", "Beat the Advanced level.", func(s engine.Snapshot, _ *statsStore) bool {
+		return s.State == engine.StateGameOver && s.PlayerPoints > s.CPUPoints && s.Level == engine.LevelAdvanced
+	}},
+	{"beat_expert", "Grandmaster", "Beat the Expert level.", func(s engine.Snapshot, _ *statsStore) bool {
+		return s.State == engine.StateGameOver && s.PlayerPoints > s.CPUPoints && s.Level == engine.LevelExpert
+	}},
+	{"shutout", "Clean Sheet", "Win a game without the CPU scoring.", func(s engine.Snapshot, _ *statsStore) bool {
+		return s.State == engine.StateGameOver && s.PlayerPoints > 0 && s.CPUPoints == 0
+	}},
+	{"ten_advanced", "Regular", "Win 10 games at the Advanced level.", func(_ engine.Snapshot, stats *statsStore) bool {
+		return stats.wins(engine.LevelAdvanced) >= advancedWinsTarget
+	}},
+}
+
+// playerPistis counts the player's Piştis in the game, or only the Jack
+// Piştis if jack is set.
+func playerPistis(s engine.Snapshot, jack bool) int {
+	n := 0
+	for _, e := range s.Captures {
+		if e.By == engine.Player && e.Pisti && (e.Jack || !jack) {
+			n++
+		}
+	}
+	return n
+}
+
+// achievementMirror passes unlocked achievements on to a store's API. Store
+// integrations register one in an init function of a file behind their
+// build tag.
+type achievementMirror interface {
+	name() string
+	// unlock marks the achievements with the given IDs as unlocked in the
+	// store. Achievements already unlocked there are passed again.
+	unlock(ids []string) error
+}
+
+// achievementMirrors are the store integrations built in.
+var achievementMirrors []achievementMirror
+
+// achievementStore is the record of unlocked achievements. A nil store,
+// for platforms without a data directory, unlocks nothing.
+type achievementStore struct {
+	path     string
+	unlocked map[string]time.Time // By ID.
+}
+
+// loadAchievements reads the record in the data directory and brings the
+// store integrations up to date with it.
+func loadAchievements() *achievementStore {
+	path := dataPath(achievementsFile)
+	if path == "" {
+		return nil
+	}
+	s := &achievementStore{path: path, unlocked: make(map[string]time.Time)}
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("ERROR: Failed to read the achievements: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &s.unlocked); err != nil {
+			log.Printf("ERROR: Failed to read the achievements in %s: %v", path, err)
+		}
+	}
+	// Achievements unlocked before a store build was installed are passed on too.
+	if ids := s.ids(); len(ids) > 0 {
+		mirrorAchievements(ids)
+	}
+	return s
+}
+
+// ids returns the IDs of the unlocked achievements.
+func (s *achievementStore) ids() []string {
+	var ids []string
+	for _, a := range achievements {
+		if _, ok := s.unlocked[a.id]; ok {
+			ids = append(ids, a.id)
+		}
+	}
+	return ids
+}
+
+// unlockedAt returns when the achievement was unlocked, and whether it was.
+func (s *achievementStore) unlockedAt(id string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	at, ok := s.unlocked[id]
+	return at, ok
+}
+
+// check unlocks the achievements the game now earns and returns their
+// titles. The record is saved and the stores are told at once.
+func (s *achievementStore) check(c *engine.Casino, stats *statsStore) []string {
+	if s == nil {
+		return nil
+	}
+	snap := c.Snapshot()
+	var ids, titles []string
+	for _, a := range achievements {
+		if _, ok := s.unlocked[a.id]; ok || !a.earned(snap, stats) {
+			continue
+		}
+		s.unlocked[a.id] = time.Now().UTC()
+		ids = append(ids, a.id)
+		titles = append(titles, a.title)
+		log.Printf("Achievement unlocked: %s", a.title)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		log.Printf("ERROR: Failed to save the achievements: %v", err)
+	}
+	mirrorAchievements(ids)
+	return titles
+}
+
+// save writes the record.
+func (s *achievementStore) save() error {
+	b, err := json.MarshalIndent(s.unlocked, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0o644)
+}
+
+// mirrorAchievements passes the achievements to every store integration. A
+// store that fails is logged; the local record is kept either way.
+func mirrorAchievements(ids []string) {
+	for _, m := range achievementMirrors {
+		if err := m.unlock(ids); err != nil {
+			log.Printf("ERROR: Failed to pass achievements to %s: %v", m.name(), err)
+		}
+	}
+}
+
+// toastTime is how long an unlock stays over the table; it fades out over
+// the last quarter.
+const toastTime = 3 * time.Second
+
+var toastColor = color.NRGBA{R: 40, G: 30, B: 10, A: 210} // Dark amber, like a trophy's plate.
+
+// achievementToast is the note shown over the table when achievements are
+// unlocked.
+type achievementToast struct {
+	text       *canvas.Text
+	background *canvas.Rectangle
+	overlay    *fyne.Container
+	fade       *fyne.Animation
+}
+
+// newAchievementToast creates a hidden toast, to be stacked over the table.
+// The gap keeps it below the top bar.
+func newAchievementToast(topBarGap fyne.CanvasObject) *achievementToast {
+	t := &achievementToast{text: canvas.NewText("", color.White), background: canvas.NewRectangle(toastColor)}
+	t.text.TextStyle.Bold = true
+	t.text.Alignment = fyne.TextAlignCenter
+	t.background.CornerRadius = 8
+	icon := widget.NewIcon(theme.ConfirmIcon())
+	note := container.NewStack(t.background, container.NewPadded(container.NewHBox(icon, container.NewCenter(t.text))))
+	t.overlay = container.NewVBox(topBarGap, container.NewCenter(note), layout.NewSpacer())
+	t.overlay.Hide()
+	return t
+}
+
+// show puts the text over the table for toastTime, replacing a toast still
+// shown.
+func (t *achievementToast) show(text string) {
+	if t.fade != nil {
+		t.fade.Stop()
+	}
+	t.text.Text = text
+	t.overlay.Show()
+	t.fade = fyne.NewAnimation(toastTime, func(f float32) {
+		if f >= 1 {
+			t.overlay.Hide()
+			return
+		}
+		alpha := uint8(255)
+		if f > 0.75 {
+			alpha = uint8(255 * (1 - f) * 4)
+		}
+		t.text.Color = color.NRGBA{R: 255, G: 255, B: 255, A: alpha}
+		t.background.FillColor = color.NRGBA{R: toastColor.R, G: toastColor.G, B: toastColor.B, A: uint8(uint16(toastColor.A) * uint16(alpha) / 255)}
+		t.overlay.Refresh()
+	})
+	t.fade.Start()
+}
+
+// showAchievements shows the titles of newly unlocked achievements in a
+// toast over the table.
+func (ui *AppUI) showAchievements(titles []string) {
+	if len(titles) == 0 {
+		return
+	}
+	shown := make([]string, len(titles))
+	for i, title := range titles {
+		shown[i] = T(title)
+	}
+	ui.toast.show(Tf("Achievement unlocked: %s", strings.Join(shown, ", ")))
+}
+
+// showAchievementGallery lists every achievement, the unlocked ones with the
+// day they were unlocked and the others greyed out.
+func (ui *AppUI) showAchievementGallery() {
+	if ui.achievements == nil {
+		dialog.ShowInformation(T("Achievements"), T("Achievements need a user data directory, which this device does not have."), ui.window)
+		return
+	}
+	list := container.NewVBox()
+	for _, a := range achievements {
+		at, ok := ui.achievements.unlockedAt(a.id)
+		var icon fyne.Resource = theme.NewDisabledResource(theme.ConfirmIcon())
+		status := T("Locked")
+		if ok {
+			icon = theme.ConfirmIcon()
+			status = Tf("Unlocked on %s", at.Local().Format(time.DateOnly))
+		}
+		title := widget.NewLabelWithStyle(T(a.title), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		description := widget.NewLabel(T(a.description))
+		description.Wrapping = fyne.TextWrapWord
+		when := widget.NewLabelWithStyle(status, fyne.TextAlignTrailing, fyne.TextStyle{Italic: true})
+		if !ok {
+			title.Importance = widget.LowImportance
+			description.Importance = widget.LowImportance
+		}
+		row := container.NewBorder(nil, nil, container.NewCenter(widget.NewIcon(icon)), nil,
+			container.NewVBox(container.NewBorder(nil, nil, nil, when, title), description))
+		list.Add(row)
+	}
+	summary := widget.NewLabel(Tf("%d of %d unlocked", len(ui.achievements.ids()), len(achievements)))
+	// The list scrolls where the window is too short for all of it.
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(320, min(list.MinSize().Height, ui.window.Canvas().Size().Height-160)))
+	dialog.ShowCustom(T("Achievements"), T("Close"), container.NewBorder(summary, nil, nil, nil, scroll), ui.window)
+}
@@ -0,0 +1,71 @@
+//go:build steam
+
+package main
+
+// Steam builds mirror the achievements to Steamworks. They need the
+// Steamworks SDK (1.60 or later): point CGO_LDFLAGS at its redistributable
+// library with -L, build with -tags steam, and ship the library and
+// steam_appid.txt next to the executable. The achievements must be set up in
+// the Steamworks partner site with the IDs in achievements.go as API names.
+
+/*
+#cgo LDFLAGS: -lsteam_api
+#include <stdbool.h>
+#include <stdlib.h>
+
+// The flat API's C entry points, declared here because its headers are C++.
+typedef struct ISteamUserStats ISteamUserStats;
+extern int SteamAPI_InitFlat(char *errMsg);
+extern ISteamUserStats *SteamAPI_SteamUserStats_v013(void);
+extern bool SteamAPI_ISteamUserStats_SetAchievement(ISteamUserStats *self, const char *name);
+extern bool SteamAPI_ISteamUserStats_StoreStats(ISteamUserStats *self);
+extern void SteamAPI_RunCallbacks(void);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"unsafe"
+)
+
+func init() {
+	var msg [1024]C.char // SteamErrMsg.
+	if r := C.SteamAPI_InitFlat(&msg[0]); r != 0 {
+		log.Printf("ERROR: Steam is unavailable, achievements stay local: %s", C.GoString(&msg[0]))
+		return
+	}
+	achievementMirrors = append(achievementMirrors, steamAchievements{})
+}
+
+// steamAchievements mirrors achievements to Steam.
+type steamAchievements struct{}
+
+// name implements achievementMirror.
+func (steamAchievements) name() string { return "Steam" }
+
+// unlock implements achievementMirror.
+func (steamAchievements) unlock(ids []string) error {
+	stats := C.SteamAPI_SteamUserStats_v013()
+	if stats == nil {
+		return errors.New("no user stats interface")
+	}
+	var failed []string
+	for _, id := range ids {
+		name := C.CString(id)
+		if !C.SteamAPI_ISteamUserStats_SetAchievement(stats, name) {
+			failed = append(failed, id)
+		}
+		C.free(unsafe.Pointer(name))
+	}
+	// Stores the change on Steam's servers; the result comes back as a callback.
+	if !C.SteamAPI_ISteamUserStats_StoreStats(stats) {
+		return errors.New("storing the stats failed")
+	}
+	C.SteamAPI_RunCallbacks()
+	if len(failed) > 0 {
+		return fmt.Errorf("unknown achievements %v; add them in the Steamworks partner site", failed)
+	}
+	return nil
+}
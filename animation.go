@@ -0,0 +1,127 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+
+	"pishti/engine"
+)
+
+// capturedScale is the size, against a card on the table, that a captured
+// pile shrinks to by the time it reaches the score.
+const capturedScale = 0.4
+
+// cardAnimator slides card images across the window, above the table and
+// below the dialogs and overlays.
+type cardAnimator struct {
+	layer   *fyne.Container // Without a layout; each flying card is placed by hand.
+	running int             // Flights not finished yet.
+	landing int             // Plays still on their way to the table.
+	flights int             // Every flight started, finished or not.
+}
+
+// newCardAnimator returns an animator with nothing in flight.
+func newCardAnimator() *cardAnimator {
+	return &cardAnimator{layer: container.NewWithoutLayout()}
+}
+
+// cardAnimationTime returns how long a card takes to slide at the game
+// speed picked in settings, 0 for no animation.
+func cardAnimationTime() time.Duration {
+	speed := fyne.CurrentApp().Preferences().FloatWithFallback(prefGameSpeed, 1)
+	return time.Duration(float64(appConfig.UI.CardAnimation) / speed)
+}
+
+// fly slides a card image of the given size from one place in the window to
+// another, scaling it down to scale on the way, and calls done once it has
+// arrived.
+func (a *cardAnimator) fly(res fyne.Resource, size fyne.Size, start, end fyne.Position, scale float32, done func()) {
+	origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(a.layer)
+	start, end = start.Subtract(origin), end.Subtract(origin)
+	img := canvas.NewImageFromResource(res)
+	img.FillMode = canvas.ImageFillStretch
+	img.Resize(size)
+	img.Move(start)
+	a.layer.Add(img)
+	a.running++
+	a.flights++
+	finished := false
+	anim := fyne.NewAnimation(cardAnimationTime(), func(t float32) {
+		if finished {
+			return
+		}
+		s := 1 + (scale-1)*t
+		img.Resize(fyne.NewSize(size.Width*s, size.Height*s))
+		img.Move(fyne.NewPos(start.X+(end.X-start.X)*t, start.Y+(end.Y-start.Y)*t))
+		canvas.Refresh(img)
+		if t < 1 {
+			return
+		}
+		finished = true
+		a.layer.Remove(img)
+		a.running--
+		done()
+	})
+	anim.Curve = fyne.AnimationEaseOut
+	anim.Start()
+}
+
+// centeredOn returns where a card of the given size sits in the middle of
+// the object, in window coordinates. The object must be visible.
+func centeredOn(o fyne.CanvasObject, size fyne.Size) fyne.Position {
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(o)
+	return pos.Add(fyne.NewPos((o.Size().Width-size.Width)/2, (o.Size().Height-size.Height)/2))
+}
+
+// tablePosition returns where the top card of the table is drawn, in window
+// coordinates. It is measured on the pile, which is never hidden.
+func (ui *AppUI) tablePosition() fyne.Position {
+	return fyne.CurrentApp().Driver().AbsolutePositionForObject(ui.tableStack)
+}
+
+// animatePlay slides a card just played from its hand slot onto the table.
+// The table shows the card only once it has landed.
+func (ui *AppUI) animatePlay(by engine.PlayerID, slot int, card *engine.Card) {
+	if card == nil || slot < 0 || slot >= engine.HandSize || cardAnimationTime() <= 0 {
+		return
+	}
+	from := ui.playerCardWidgets[slot]
+	if by == engine.CPU {
+		from = ui.cpuCardWidgets[slot]
+	}
+	size := ui.tableCardWidget.Size()
+	ui.cards.landing++
+	ui.cards.fly(getCardResource(card), size, centeredOn(from, size), ui.tablePosition(), 1, func() {
+		ui.cards.landing--
+		if ui.cards.landing == 0 {
+			ui.tableCardWidget.Show()
+		}
+	})
+	if ui.cards.landing > 0 { // Not landed already, as when animations finish at once.
+		ui.tableCardWidget.Hide()
+	}
+}
+
+// animateCapture slides the captured pile, shown by its top card, off the
+// table towards the score of the side that took it.
+func (ui *AppUI) animateCapture(by engine.PlayerID, top *engine.Card) {
+	if top == nil || by == engine.NoPlayer || cardAnimationTime() <= 0 {
+		return
+	}
+	to := ui.playerScoreLabel
+	if by == engine.CPU {
+		to = ui.cpuScoreLabel
+	}
+	size := ui.tableCardWidget.Size()
+	small := fyne.NewSize(size.Width*capturedScale, size.Height*capturedScale)
+	ui.cards.fly(getCardResource(top), size, ui.tablePosition(), centeredOn(to, small), capturedScale, func() {})
+}
+
+// isAnimating reports whether a card is still sliding; the player waits for
+// it before playing the next one.
+func (ui *AppUI) isAnimating() bool {
+	return ui.cards.running > 0
+}
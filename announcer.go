@@ -0,0 +1,142 @@
+package main
+
+import (
+	"log"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// announcerClips maps each announcement to the base name of its pre-recorded
+// clip, looked up in an "announcer/<language>" folder of the sound pack dirs.
+var announcerClips = map[SoundEffect]string{
+	SoundAnnouncePisti:        "pisti",
+	SoundAnnounceJackPisti:    "jack_pisti",
+	SoundAnnouncePlayerWins:   "you_win",
+	SoundAnnounceCPUWins:      "cpu_wins",
+	SoundAnnounceTie:          "tie",
+	SoundAnnounceMilestone50:  "milestone_50",
+	SoundAnnounceMilestone100: "milestone_100",
+}
+
+// announcerLanguages lists the supported announcer languages by code, with
+// the phrases spoken by text-to-speech when no clip is available.
+var announcerLanguages = map[string]map[string]string{
+	"en": {
+		"pisti":         "Pishti!",
+		"jack_pisti":    "Jack Pishti!",
+		"you_win":       "You win!",
+		"cpu_wins":      "The CPU wins.",
+		"tie":           "It's a tie.",
+		"milestone_50":  "Fifty points!",
+		"milestone_100": "One hundred points!",
+	},
+	"tr": {
+		"pisti":         "Pişti!",
+		"jack_pisti":    "Vale pişti!",
+		"you_win":       "Kazandın!",
+		"cpu_wins":      "Bilgisayar kazandı.",
+		"tie":           "Berabere.",
+		"milestone_50":  "Elli puan!",
+		"milestone_100": "Yüz puan!",
+	},
+}
+
+// scoreMilestones are the scores announced when the player first reaches them.
+var scoreMilestones = []struct {
+	points int
+	effect SoundEffect
+}{
+	{50, SoundAnnounceMilestone50},
+	{100, SoundAnnounceMilestone100},
+}
+
+var (
+	announcerMutex    sync.Mutex
+	announcerLanguage string // Empty when the announcer is off.
+)
+
+// SetAnnouncerLanguage switches the announcer to the given language code and
+// loads its clips, or turns the announcer off for an empty or unknown code.
+func SetAnnouncerLanguage(lang string) {
+	if _, ok := announcerLanguages[lang]; !ok {
+		lang = ""
+	}
+	announcerMutex.Lock()
+	announcerLanguage = lang
+	announcerMutex.Unlock()
+	// Load the clips outside the lock; decoding can take a moment.
+	clips := make(map[SoundEffect][]byte)
+	if lang != "" {
+		var dirs []string
+		for _, dir := range soundPackDirs() {
+			dirs = append(dirs, filepath.Join(dir, "announcer", lang))
+		}
+		for effect, name := range announcerClips {
+			if pcm := loadCustomSound(name, dirs); pcm != nil {
+				clips[effect] = pcm
+			}
+		}
+	}
+	soundMutex.Lock()
+	for effect := range announcerClips {
+		if pcm, ok := clips[effect]; ok {
+			soundData[effect] = [][]byte{pcm}
+		} else {
+			delete(soundData, effect) // Fall back to text-to-speech.
+		}
+	}
+	soundMutex.Unlock()
+}
+
+// Announce speaks the announcement in the selected language, preferring a
+// recorded clip and falling back to the platform's text-to-speech.
+func Announce(effect SoundEffect) {
+	announcerMutex.Lock()
+	lang := announcerLanguage
+	announcerMutex.Unlock()
+	if lang == "" || IsMuted() {
+		return
+	}
+	soundMutex.Lock()
+	_, hasClip := soundData[effect]
+	soundMutex.Unlock()
+	if hasClip {
+		PlaySound(effect)
+		return
+	}
+	speak(lang, announcerLanguages[lang][announcerClips[effect]])
+}
+
+// speak runs the platform's text-to-speech command in the background.
+// If no speech backend is available the announcement is silently skipped.
+func speak(lang, text string) {
+	if text == "" {
+		return
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("say", text)
+	case "windows":
+		script := "Add-Type -AssemblyName System.Speech; " +
+			"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak($args[0])"
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script, text)
+	default:
+		for _, name := range []string{"espeak-ng", "espeak"} {
+			if path, err := exec.LookPath(name); err == nil {
+				cmd = exec.Command(path, "-v", lang, text)
+				break
+			}
+		}
+	}
+	if cmd == nil {
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		log.Printf("ERROR: Failed to start text-to-speech: %v", err)
+		return
+	}
+	go cmd.Wait() // Reap the process once it finishes speaking.
+}
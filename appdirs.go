@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// The game keeps its files in two per-user directories, both named appDirName:
+//
+//   - the config directory (os.UserConfigDir) holds config.toml;
+//   - the data directory holds everything the game writes or reads besides
+//     its settings, in the folders below. On Linux and the BSDs it follows
+//     the XDG base directory spec ($XDG_DATA_HOME, usually ~/.local/share);
+//     elsewhere it is the config directory.
+//
+// Preferences changed in the game are stored by Fyne under the app ID.
+const appDirName = "Pishti"
+
+// Folders of the data directory.
+const (
+	savesDir   = "saves"   // Saved games.
+	logsDir    = "logs"    // The log of the last sessions.
+	skinsDir   = "skins"   // Card and table art replacing the built-in images.
+	soundsDir  = "sounds"  // Sound packs; see soundPackDirs.
+	crashesDir = "crashes" // Crash reports; see writeCrashReport.
+	scriptsDir = "scripts" // Event hooks; see loadEventHooks.
+)
+
+// dataFolders are the folders setupAppDirs creates.
+var dataFolders = []string{savesDir, logsDir, skinsDir, soundsDir, crashesDir, scriptsDir}
+
+// dataLayoutVersion is the version of the data directory layout, kept in
+// the layoutFile so a later release knows what it finds there. Version 2
+// added the scripts folder.
+const dataLayoutVersion = 2
+
+// layoutFile records the data directory's layout version.
+const layoutFile = "layout-version"
+
+// logFile is the log of the current session, in the logs folder; the one
+// before is kept as previousLogFile.
+const (
+	logFile         = "pishti.log"
+	previousLogFile = "pishti.previous.log"
+)
+
+// configDir returns the game's config directory, or "" if the platform has
+// none.
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, appDirName)
+}
+
+// dataDir returns the game's data directory, or "" if the platform has none.
+func dataDir() string {
+	switch runtime.GOOS {
+	case "js":
+		return "" // A web page has no file system; Fyne keeps preferences in local storage.
+	case "windows", "darwin", "ios", "android":
+		return configDir()
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share", appDirName)
+}
+
+// dataPath returns a folder of the data directory, or "" if there is none.
+func dataPath(folder string) string {
+	dir := dataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, folder)
+}
+
+// setupAppDirs creates the game's directories on first run and brings the
+// data directory of an earlier release up to the current layout. Problems
+// are logged; the game then runs without the missing folders.
+func setupAppDirs() {
+	data := dataDir()
+	if data == "" && inBrowser() {
+		return
+	}
+	if data == "" {
+		log.Printf("ERROR: No user data directory; saves, logs and sound packs are unavailable")
+		return
+	}
+	version := readLayoutVersion(data)
+	if version == dataLayoutVersion {
+		return
+	}
+	if version > dataLayoutVersion {
+		log.Printf("ERROR: Data directory %s was set up by a newer release (layout %d)", data, version)
+		return
+	}
+	if err := os.MkdirAll(data, 0o755); err != nil {
+		log.Printf("ERROR: Failed to create %s: %v", data, err)
+		return
+	}
+	if version == 0 {
+		migrateDataDir(data)
+	}
+	for _, folder := range dataFolders {
+		if err := os.MkdirAll(filepath.Join(data, folder), 0o755); err != nil {
+			log.Printf("ERROR: Failed to create %s: %v", filepath.Join(data, folder), err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(data, layoutFile), []byte(strconv.Itoa(dataLayoutVersion)+"\n"), 0o644)
+	if err != nil {
+		log.Printf("ERROR: Failed to record the data directory layout: %v", err)
+	}
+}
+
+// readLayoutVersion returns the layout version of a data directory, 0 for
+// one that has not been set up yet.
+func readLayoutVersion(data string) int {
+	b, err := os.ReadFile(filepath.Join(data, layoutFile))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("ERROR: Failed to read the data directory layout: %v", err)
+		}
+		return 0
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		log.Printf("ERROR: Invalid data directory layout %q", strings.TrimSpace(string(b)))
+		return 0
+	}
+	return version
+}
+
+// migrateDataDir moves the folders earlier releases kept in the config
+// directory into the data directory, unless it already has them.
+func migrateDataDir(data string) {
+	config := configDir()
+	if config == "" || config == data {
+		return
+	}
+	for _, folder := range []string{soundsDir, crashesDir} {
+		from, to := filepath.Join(config, folder), filepath.Join(data, folder)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+		if err := os.Rename(from, to); err != nil {
+			log.Printf("ERROR: Failed to move %s to %s: %v", from, to, err)
+			continue
+		}
+		log.Printf("Moved %s to %s", from, to)
+	}
+}
+
+// startLogFile copies the log to the logs folder for the rest of the
+// session, keeping the previous session's log. Without the folder the log
+// only goes to standard error.
+func startLogFile() {
+	dir := dataPath(logsDir)
+	if dir == "" {
+		return
+	}
+	current := filepath.Join(dir, logFile)
+	if err := os.Rename(current, filepath.Join(dir, previousLogFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("ERROR: Failed to keep the previous log: %v", err)
+	}
+	f, err := os.Create(current)
+	if err != nil {
+		log.Printf("ERROR: Failed to create the log file: %v", err)
+		return
+	}
+	log.SetOutput(io.MultiWriter(os.Stderr, f))
+}
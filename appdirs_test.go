@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSetupAppDirsMigratesOldFolders(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "android", "js":
+		t.Skip("the data directory is the config directory here")
+	}
+	config, data := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("XDG_DATA_HOME", data)
+	oldSound := filepath.Join(config, appDirName, soundsDir, "cardplay.wav")
+	if err := os.MkdirAll(filepath.Dir(oldSound), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(oldSound, []byte("RIFF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupAppDirs()
+	if _, err := os.Stat(filepath.Join(data, appDirName, soundsDir, "cardplay.wav")); err != nil {
+		t.Errorf("sound pack not moved to the data directory: %v", err)
+	}
+	for _, folder := range dataFolders {
+		if info, err := os.Stat(dataPath(folder)); err != nil || !info.IsDir() {
+			t.Errorf("folder %s not created: %v", folder, err)
+		}
+	}
+	if got := readLayoutVersion(dataDir()); got != dataLayoutVersion {
+		t.Errorf("layout version = %d, want %d", got, dataLayoutVersion)
+	}
+}
@@ -1,56 +1,173 @@
 package main
 
 import (
+	"container/list"
 	"embed"
+	"io/fs"
+	"log"
 	"path"
 	"strconv"
+	"sync"
+	"sync/atomic"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"pishti/engine"
 )
 
 //go:embed assets
 var embeddedAssets embed.FS
 
+// assetFS is where images and sounds are read from: the embedded copy, or
+// the assets directory on disk while it is watched in development mode. Card
+// images may come from a skin instead; see cardArtFS.
+var assetFS fs.FS = embeddedAssets
+
+// assetGeneration counts live reloads. It is part of the name of every
+// resource loaded since, so Fyne does not draw a changed SVG from the
+// rasters it cached under the old name.
+var assetGeneration atomic.Int32
+
 var (
 	resourceCardBack   fyne.Resource
 	resourceFrame      fyne.Resource
 	resourceBackground fyne.Resource
-	resourceCardCache  = make(map[string]fyne.Resource)
+	resourceIcon       fyne.Resource
+	cardFaces          = newResourceCache(cardCacheBudget)
 )
 
-// loadResources initializes all global resource variables.
+// cardCacheBudget is the default cap on the bytes of card face images kept in
+// memory. About half the deck fits, which covers the cards on screen with
+// room to spare.
+const cardCacheBudget = 128 << 10
+
+// resourceCache holds embedded images loaded on first use, dropping the
+// least recently used ones once their total size exceeds the budget.
+type resourceCache struct {
+	mu      sync.Mutex
+	budget  int // In bytes; 0 means unlimited.
+	size    int
+	order   *list.List // Of cacheEntry values, most recently used first.
+	entries map[string]*list.Element
+}
+
+// cacheEntry is a loaded resource and the embedded path it came from.
+type cacheEntry struct {
+	path string
+	res  fyne.Resource
+}
+
+// newResourceCache returns an empty cache with the given budget in bytes.
+func newResourceCache(budget int) *resourceCache {
+	return &resourceCache{budget: budget, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the embedded resource at path p, loading it if needed. It
+// returns nil if there is no such asset.
+func (rc *resourceCache) get(p string) fyne.Resource {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if e, ok := rc.entries[p]; ok {
+		rc.order.MoveToFront(e)
+		return e.Value.(cacheEntry).res
+	}
+	data, err := fs.ReadFile(cardArtFS(p), p)
+	if err != nil {
+		return nil
+	}
+	res := fyne.NewStaticResource(resourceName(p), data)
+	rc.entries[p] = rc.order.PushFront(cacheEntry{path: p, res: res})
+	rc.size += len(data)
+	// Evict from the back, but always keep the resource just loaded.
+	for rc.budget > 0 && rc.size > rc.budget && rc.order.Len() > 1 {
+		old := rc.order.Remove(rc.order.Back()).(cacheEntry)
+		rc.size -= len(old.res.Content())
+		delete(rc.entries, old.path)
+	}
+	return res
+}
+
+// preload loads every given path and lifts the budget so they all stay loaded.
+func (rc *resourceCache) preload(paths []string) {
+	rc.mu.Lock()
+	rc.budget = 0
+	rc.mu.Unlock()
+	for _, p := range paths {
+		rc.get(p)
+	}
+}
+
+// loadResources initializes all global resource variables. The UI art is on
+// screen from the start and is loaded at once; card faces are loaded as they
+// are dealt unless preloadCards is set.
 // This must be called after the Fyne app has been created to avoid deadlocks.
-func loadResources() {
-	resourceCardBack = mustLoadResource("assets/cards/back.png")
-	resourceFrame = mustLoadResource("assets/ui/frame.png")
-	resourceBackground = mustLoadResource("assets/ui/background.jpg")
-	// Pre-load all card resources into the cache.
-	for i := 1; i <= DeckSize; i++ {
-		iconPath := strconv.Itoa(i)
-		res := mustLoadResource("assets/cards/" + iconPath + ".png")
-		resourceCardCache[iconPath] = res
-	}
-}
-
-// getCardResource safely retrieves a card's resource from the cache.
-func getCardResource(card *Card) fyne.Resource {
-	iconPath := card.GetIconPath()
-	res, ok := resourceCardCache[iconPath]
-	if !ok {
-		// This case should not happen with valid card data, but as a safeguard,
-		// return the card back resource to avoid a crash with a nil resource.
+func loadResources(preloadCards bool) {
+	loadUIArt()
+	cardFaces = newResourceCache(appConfig.UI.CardCacheKB << 10)
+	if preloadCards {
+		paths := make([]string, engine.DeckSize)
+		for i := range paths {
+			paths[i] = cardAssetPath(strconv.Itoa(i + 1))
+		}
+		cardFaces.preload(paths)
+	}
+}
+
+// loadUIArt loads the card back and the interface images, in the designs
+// chosen for the table. A missing image is replaced so the game stays
+// playable: a placeholder for the card back, the toolkit's logo for the
+// icon, and nothing for the decorations.
+func loadUIArt() {
+	resourceCardBack = loadResourceOr(cardBackPart.imagePath(cardAssetPath("back")), theme.BrokenImageIcon())
+	resourceFrame = loadResourceOr(framePart.imagePath("assets/ui/frame.png"), nil)
+	resourceBackground = loadResourceOr(backgroundPart.imagePath("assets/ui/background.jpg"), nil)
+	resourceIcon = loadResourceOr("assets/ui/icon.png", theme.FyneLogo())
+}
+
+// cardAssetPath returns the asset path of the named card image. A vector
+// version (name.svg) is preferred over the bitmap (name.png): Fyne draws SVG
+// images at the size and scale they are shown at, so they stay sharp on
+// HiDPI screens and when the cards are enlarged, where a bitmap is stretched.
+func cardAssetPath(name string) string {
+	svg := cardImageDir + name + ".svg"
+	if _, err := fs.Stat(cardArtFS(svg), svg); err == nil {
+		return svg
+	}
+	return cardImageDir + name + ".png"
+}
+
+// getCardResource returns a card's face image, loading it on first use.
+func getCardResource(card *engine.Card) fyne.Resource {
+	p := cardAssetPath(card.GetIconPath())
+	res := cardFaces.get(p)
+	if res == nil {
+		// Show the back rather than an empty slot, and say why.
+		log.Printf("ERROR: Missing card image %s", p)
+		reportProblem(problemSkin, "Some card images are missing; their cards are shown face down.")
 		return resourceCardBack
 	}
 	return res
 }
 
-// mustLoadResource loads a resource from the embedded filesystem and panics on error.
-func mustLoadResource(p string) fyne.Resource {
-	data, err := embeddedAssets.ReadFile(p)
+// loadResourceOr loads a resource from the asset filesystem, or the card
+// skin for card images, reporting a missing or unreadable file and returning
+// fallback instead.
+func loadResourceOr(p string, fallback fyne.Resource) fyne.Resource {
+	data, err := fs.ReadFile(cardArtFS(p), p)
 	if err != nil {
-		// If an asset is not found, it's a critical error.
-		// Panicking gives a clear stack trace and message.
-		panic("failed to load embedded asset " + p + ": " + err.Error())
+		log.Printf("ERROR: Failed to load image %s: %v", p, err)
+		reportProblem(problemSkin, "Some images are missing; placeholders are shown instead.")
+		return fallback
+	}
+	return fyne.NewStaticResource(resourceName(p), data)
+}
+
+// resourceName names the resource loaded from path p after its file, with
+// the reload generation in front once assets have been reloaded.
+func resourceName(p string) string {
+	if g := assetGeneration.Load(); g > 0 {
+		return strconv.Itoa(int(g)) + "-" + path.Base(p)
 	}
-	return fyne.NewStaticResource(path.Base(p), data)
+	return path.Base(p)
 }
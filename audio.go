@@ -2,10 +2,12 @@ package main
 
 import (
 	"bytes"
-	"github.com/hajimehoshi/go-mp3"
+	"encoding/binary"
 	"github.com/hajimehoshi/oto/v2"
 	"io"
+	"io/fs"
 	"log"
+	"math"
 	"sync"
 	"time"
 )
@@ -25,97 +27,227 @@ const (
 	SoundUndo
 	SoundDeal
 	SoundPistiJack
+	SoundReminder
+	// Announcer clips, loaded per language by SetAnnouncerLanguage.
+	SoundAnnouncePisti
+	SoundAnnounceJackPisti
+	SoundAnnouncePlayerWins
+	SoundAnnounceCPUWins
+	SoundAnnounceTie
+	SoundAnnounceMilestone50
+	SoundAnnounceMilestone100
+	SoundMenuMusic
 )
 
+const (
+	defaultSampleRate = 44100
+	channelCount      = 2
+	bytesPerSample    = 2
+)
+
+// sampleRate is the output rate of the audio context, which every sound is
+// converted to. It is fixed once initAudio has run.
+var sampleRate = defaultSampleRate
+
 var (
 	otoCtx           *oto.Context
-	soundData        = make(map[SoundEffect][]byte)
+	soundData        = make(map[SoundEffect][][]byte)  // Each effect's recordings; one is picked at random.
 	lastPlayTimes    = make(map[SoundEffect]time.Time) // Per-sound rate limiting.
 	soundLoaded      = false
-	soundMutex       sync.Mutex                  // Protects the lastPlayTimes map and activePlayers.
-	soundRateLimit   = 10 * time.Millisecond     // 10ms delay between sounds (allows faster playback).
-	activePlayers    = make(map[oto.Player]bool) // Track active players for cleanup.
+	soundMutex       sync.Mutex                         // Protects the lastPlayTimes map and activePlayers.
+	soundRateLimit   = defaultSoundRateLimit            // Minimum delay between starts of the same sound, see SetSoundRateLimit.
+	activePlayers    = make(map[oto.Player]SoundEffect) // Track active players and what they play, for cleanup.
 	backgroundPlayer oto.Player
+	// Volume levels in the range 0.0-1.0, protected by soundMutex.
+	masterVolume  = defaultMasterVolume
+	musicVolume   = defaultMusicVolume
+	effectsVolume = defaultEffectsVolume
+	muted         = false // Silences everything while keeping the audio context alive.
+	musicOn       = true  // False silences only the music.
+	effectsOn     = true  // False silences only the effects, the announcer's included.
+)
+
+// defaultSoundRateLimit is 10ms, short enough for quick successive plays.
+const defaultSoundRateLimit = 10 * time.Millisecond
+
+// Default volume levels, used when no preference has been saved yet.
+const (
+	defaultMasterVolume  = 1.0
+	defaultMusicVolume   = 0.15
+	defaultEffectsVolume = 1.0
 )
 
-// initAudio initializes the audio context. This must be called once at startup.
-func initAudio() {
-	// 44100, 2 channels (stereo), 2 bytes (16-bit) is a standard setting.
+// initAudio initializes the audio context with the given output sample rate
+// and device buffer length, where a zero buffer leaves the choice to the
+// driver, on the device with the ID from audioDevices, or the default for ""
+// or a device that is not plugged in. This must be called once at startup.
+func initAudio(rate int, buffer time.Duration, device string) {
+	sampleRate = rate
+	useAudioDevice(pluggedInAudioDevice(device, audioDevices()))
+	// 2 channels (stereo) and 2 bytes (16-bit) are a standard setting.
 	var readyChan chan struct{}
 	var err error
-	otoCtx, readyChan, err = oto.NewContext(44100, 2, 2)
+	otoCtx, readyChan, err = oto.NewContextWithOptions(&oto.NewContextOptions{
+		SampleRate:   sampleRate,
+		ChannelCount: channelCount,
+		Format:       oto.FormatSignedInt16LE,
+		BufferSize:   buffer,
+	})
 	if err != nil {
 		log.Printf("ERROR: Failed to initialize audio context: %v. Audio will be disabled.", err)
+		if inBrowser() {
+			reportProblem(problemAudio, "Sound is off: this browser does not support Web Audio.")
+		} else {
+			reportProblem(problemAudio, "Sound is off: no audio device could be opened.")
+		}
+		markSoundsReady() // Nothing will load, so don't keep anyone waiting.
 		return
 	}
 	// The audio context needs a moment to initialize. Must wait for the ready signal before using it.
+	// In a browser it is only ready after the first tap or key press, so the game starts silent.
 	// This is done in a separate goroutine to avoid blocking the UI from appearing.
 	go func() {
 		<-readyChan
 		soundLoaded = true
+		go runSoundWorker()
 		// Now that the context is ready, load the sounds.
 		loadAllSounds()
-		// Start a background goroutine to clean up finished audio players.
-		go cleanupActivePlayers()
+		markSoundsReady()
 		// Once sounds are loaded, start the background music automatically.
 		PlayBackgroundMusic()
 	}()
 }
 
-// cleanupActivePlayers runs in the background and periodically removes finished
-// sound effect players from the activePlayers map to prevent memory leaks.
-func cleanupActivePlayers() {
-	ticker := time.NewTicker(500 * time.Millisecond) // Check every half second.
-	defer ticker.Stop()
-	for range ticker.C {
+// playerCleanupGrace is added to a sound's length before its player is
+// checked, covering the device buffer that is still draining.
+const playerCleanupGrace = 200 * time.Millisecond
+
+// scheduleCleanup closes a sound effect player and removes it from
+// activePlayers once it has finished, based on the length of its sound.
+// A player that is still busy (e.g. delayed by a slow device) is checked again later.
+func scheduleCleanup(player oto.Player, length time.Duration) {
+	time.AfterFunc(length+playerCleanupGrace, func() {
 		soundMutex.Lock()
-		for player, active := range activePlayers {
-			if active && !player.IsPlaying() {
-				player.Close()
-				delete(activePlayers, player)
+		defer soundMutex.Unlock()
+		if _, ok := activePlayers[player]; !ok {
+			return // Already released.
+		}
+		if player.IsPlaying() {
+			scheduleCleanup(player, 0)
+			return
+		}
+		releasePlayer(player)
+	})
+}
+
+// soundNames maps every effect to the base file name used by the embedded
+// assets and by user sound packs.
+var soundNames = map[SoundEffect]string{
+	SoundCardPlay:   "play",
+	SoundCapture:    "capture",
+	SoundPistiJack:  "pisti_jack",
+	SoundPisti:      "pisti",
+	SoundGameStart:  "start",
+	SoundPlayerWins: "player_wins",
+	SoundCPUWins:    "cpu_wins",
+	SoundTie:        "tie",
+	SoundBackground: "background",
+	SoundMenuMusic:  "menu",
+	SoundUndo:       "undo",
+	SoundDeal:       "deal",
+	SoundReminder:   "reminder",
+}
+
+// loadAllSounds is called once the audio context is ready. The effects are
+// decoded concurrently, and sounds from a user sound pack take precedence over
+// the embedded ones.
+func loadAllSounds() {
+	packDirs := soundPackDirs()
+	reportUnknownSoundFiles(packDirs)
+	var wg sync.WaitGroup
+	for effect, name := range soundNames {
+		if effect == SoundBackground || effect == SoundMenuMusic {
+			loadMusic(effect, name, packDirs) // Music is streamed, so there is nothing to decode up front.
+			soundsLoadedCount.Add(1)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer soundsLoadedCount.Add(1)
+			loadRecordings(effect, name, packDirs)
+		}()
+	}
+	wg.Wait()
+}
+
+// loadRecordings decodes an effect and its alternatives and puts them in
+// soundData. An effect with no playable recording is left as it was.
+func loadRecordings(effect SoundEffect, name string, packDirs []string) {
+	var recordings [][]byte
+	if pcm := loadEffect(effect, name, packDirs); pcm != nil {
+		recordings = append(recordings, pcm)
+	}
+	recordings = append(recordings, loadVariants(name, packDirs)...)
+	if len(recordings) == 0 {
+		return
+	}
+	soundMutex.Lock()
+	soundData[effect] = recordings
+	soundMutex.Unlock()
+}
+
+// loadEffect decodes a single effect, preferring a user sound pack file.
+func loadEffect(effect SoundEffect, name string, packDirs []string) []byte {
+	if pcm := loadCustomSound(name, packDirs); pcm != nil {
+		return pcm
+	}
+	if effect == SoundReminder {
+		// The reminder chime has no asset; it is synthesized so it stays soft and short.
+		return synthesizeChime()
+	}
+	return loadSound("assets/sounds/" + name + ".mp3")
+}
+
+// synthesizeChime generates a quiet two-note chime as 16-bit stereo PCM.
+func synthesizeChime() []byte {
+	notes := []float64{880, 1318.5} // A5 followed by E6.
+	noteLength := sampleRate / 4    // Each note lasts a quarter second.
+	var buf bytes.Buffer
+	for _, freq := range notes {
+		for i := 0; i < noteLength; i++ {
+			t := float64(i) / float64(sampleRate)
+			// An exponential decay envelope gives a soft, bell-like tone.
+			value := 0.2 * math.Exp(-8*t) * math.Sin(2*math.Pi*freq*t)
+			sample := int16(value * math.MaxInt16)
+			for ch := 0; ch < channelCount; ch++ {
+				binary.Write(&buf, binary.LittleEndian, sample)
 			}
 		}
-		soundMutex.Unlock()
 	}
+	return buf.Bytes()
 }
 
-// loadAllSounds is called once the audio context is ready.
-func loadAllSounds() {
-	loadSound(SoundCardPlay, "assets/sounds/play.mp3")
-	loadSound(SoundCapture, "assets/sounds/capture.mp3")
-	loadSound(SoundPistiJack, "assets/sounds/pisti_jack.mp3")
-	loadSound(SoundPisti, "assets/sounds/pisti.mp3")
-	loadSound(SoundGameStart, "assets/sounds/start.mp3")
-	loadSound(SoundPlayerWins, "assets/sounds/player_wins.mp3")
-	loadSound(SoundCPUWins, "assets/sounds/cpu_wins.mp3")
-	loadSound(SoundTie, "assets/sounds/tie.mp3")
-	loadSound(SoundBackground, "assets/sounds/background.mp3")
-	loadSound(SoundUndo, "assets/sounds/undo.mp3")
-	loadSound(SoundDeal, "assets/sounds/deal.mp3")
-}
-
-// loadSound loads a sound from the embedded assets into memory.
-func loadSound(effect SoundEffect, path string) {
+// loadSound loads a sound from the embedded assets and decodes it, returning
+// nil if that fails.
+func loadSound(path string) []byte {
 	if !soundLoaded {
-		return // Audio context failed to initialize.
+		return nil // Audio context failed to initialize.
 	}
-	fileBytes, err := embeddedAssets.ReadFile(path)
+	fileBytes, err := fs.ReadFile(assetFS, path)
 	if err != nil {
 		log.Printf("ERROR: Failed to load sound asset %s: %v", path, err)
-		return
+		reportProblem(problemSounds, "Some sounds are missing and will stay silent.")
+		return nil
 	}
-	// Decode the entire mp3 file into a raw byte slice.
-	decoder, err := mp3.NewDecoder(bytes.NewReader(fileBytes))
+	// Decode the entire file into a raw byte slice; the decoder is chosen by extension.
+	decodedBytes, err := decodeAudio(path, fileBytes)
 	if err != nil {
-		log.Printf("ERROR: Failed to decode mp3 %s: %v", path, err)
-		return
+		log.Printf("ERROR: Failed to decode sound %s: %v", path, err)
+		reportProblem(problemSounds, "Some sounds are missing and will stay silent.")
+		return nil
 	}
-	decodedBytes, err := io.ReadAll(decoder)
-	if err != nil {
-		log.Printf("ERROR: Failed to read decoded mp3 %s: %v", path, err)
-		return
-	}
-	soundData[effect] = decodedBytes
+	return decodedBytes
 }
 
 // loopingReader is a custom io.Reader that wraps another reader and seeks
@@ -138,51 +270,231 @@ func (lr *loopingReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
-// PlayBackgroundMusic starts the looping background music.
+// PlayBackgroundMusic starts the looping music track for the current screen.
 func PlayBackgroundMusic() {
-	if !soundLoaded {
+	startMusic(musicFadeIn)
+}
+
+// startMusic starts the current music track, fading it in over the given time.
+// The music is kept compressed and decoded while it plays, as the decoded PCM
+// of a long track would take tens of megabytes.
+func startMusic(fadeIn time.Duration) {
+	if !soundLoaded || !audioContextHealthy() {
 		return
 	}
+	soundMutex.Lock()
 	// If the player is already created and playing, do nothing.
 	if backgroundPlayer != nil && backgroundPlayer.IsPlaying() {
+		soundMutex.Unlock()
 		return
 	}
-	data, ok := soundData[SoundBackground]
-	if !ok || len(data) == 0 {
-		return // Background music not loaded.
+	track := musicTracks[currentTrack]
+	soundMutex.Unlock()
+	if len(track.data) == 0 {
+		return // Music not loaded.
+	}
+	stream, err := openAudioStream(track.path, track.data)
+	if err != nil {
+		log.Printf("ERROR: Failed to decode music %s: %v", track.path, err)
+		return
+	}
+	// Create an infinite loop stream that decodes the music as it plays.
+	loopingStream := &loopingReader{reader: stream}
+	soundMutex.Lock()
+	if backgroundPlayer != nil {
+		backgroundPlayer.Close() // A paused player that is being replaced.
 	}
-	// Create an infinite loop stream from decoded music data.
-	loopingStream := &loopingReader{reader: bytes.NewReader(data)}
 	backgroundPlayer = otoCtx.NewPlayer(loopingStream)
-	// Set the volume to 15% to ensure it's not overpowering.
-	backgroundPlayer.SetVolume(0.15)
+	musicFade = 0 // Start silent and fade in instead of starting abruptly.
+	backgroundPlayer.SetVolume(currentMusicVolume())
 	backgroundPlayer.Play()
+	soundMutex.Unlock()
+	fadeMusic(1, fadeIn)
+}
+
+// SetMasterVolume sets the overall volume, scaling both music and effects.
+func SetMasterVolume(volume float64) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	masterVolume = clampVolume(volume)
+	applyVolumes()
 }
 
-// PlaySound plays a pre-loaded sound effect.
+// SetMusicVolume sets the background music volume.
+func SetMusicVolume(volume float64) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	musicVolume = clampVolume(volume)
+	applyVolumes()
+}
+
+// SetEffectsVolume sets the volume of sound effects.
+func SetEffectsVolume(volume float64) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	effectsVolume = clampVolume(volume)
+	applyVolumes()
+}
+
+// SetMuted silences or restores all audio without closing the audio context.
+func SetMuted(mute bool) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	muted = mute
+	applyVolumes()
+}
+
+// SetMusicEnabled turns the background music on or off, leaving the effects
+// playing.
+func SetMusicEnabled(on bool) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	musicOn = on
+	applyVolumes()
+}
+
+// SetEffectsEnabled turns the sound effects on or off, leaving the music
+// playing.
+func SetEffectsEnabled(on bool) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	effectsOn = on
+	applyVolumes()
+}
+
+// IsMuted reports whether audio is currently muted.
+func IsMuted() bool {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	return muted
+}
+
+// currentMusicVolume returns the effective music volume. The caller must hold soundMutex.
+func currentMusicVolume() float64 {
+	if muted || !musicOn {
+		return 0
+	}
+	return masterVolume * musicVolume * musicFade
+}
+
+// currentEffectsVolume returns the effective volume of an effect. The caller must hold soundMutex.
+func currentEffectsVolume(effect SoundEffect) float64 {
+	if muted || !effectsOn {
+		return 0
+	}
+	return masterVolume * effectsVolume * effectVolume(effect)
+}
+
+// applyVolumes pushes the current levels to the music and to effects that are
+// still playing. The caller must hold soundMutex.
+func applyVolumes() {
+	if backgroundPlayer != nil {
+		backgroundPlayer.SetVolume(currentMusicVolume())
+	}
+	if outgoingPlayer != nil {
+		outgoingPlayer.SetVolume(outgoingMusicVolume())
+	}
+	for player, effect := range activePlayers {
+		player.SetVolume(currentEffectsVolume(effect))
+	}
+}
+
+// clampVolume limits a volume level to the 0.0-1.0 range.
+func clampVolume(volume float64) float64 {
+	return math.Max(0, math.Min(1, volume))
+}
+
+// PlaySound plays a pre-loaded sound effect in the centre of the stereo field.
 func PlaySound(effect SoundEffect) {
+	PlaySoundPanned(effect, 0)
+}
+
+// soundQueueSize is how many requests may wait for the sound worker. A full
+// queue means the worker is stuck on the device, and more sounds are dropped.
+const soundQueueSize = 32
+
+// soundRequest asks the sound worker to play an effect.
+type soundRequest struct {
+	effect SoundEffect
+	pan    float64
+	at     time.Time // When it was requested, for the rate limit.
+}
+
+// soundRequests feeds the sound worker started by initAudio.
+var soundRequests = make(chan soundRequest, soundQueueSize)
+
+// PlaySoundPanned plays a pre-loaded sound effect positioned between the left
+// (-1) and right (+1) channel. It only queues the request, so callers such as
+// the UI goroutine never wait for the mixer.
+func PlaySoundPanned(effect SoundEffect, pan float64) {
 	if !soundLoaded {
 		return // Audio disabled.
 	}
-	// The rate limiter needs to be protected by a mutex to prevent race conditions
-	// when sounds are triggered from different threads (e.g., UI and timers).
+	select {
+	case soundRequests <- soundRequest{effect: effect, pan: pan, at: time.Now()}:
+	default:
+		log.Printf("ERROR: Sound queue full, dropping %s", soundNames[effect])
+	}
+}
+
+// runSoundWorker plays the queued sounds one by one. It is the only place
+// effects are started, so the rules on volume, ducking and polyphony are
+// applied in a single order.
+func runSoundWorker() {
+	for req := range soundRequests {
+		playEffect(req)
+	}
+}
+
+// playEffect starts a requested effect on the sound worker.
+func playEffect(req soundRequest) {
+	effect, pan := req.effect, req.pan
+	if !audioContextHealthy() {
+		return // The output device has failed.
+	}
+	// Volumes and the loading state are also changed from the UI, so they are read under the mutex.
 	soundMutex.Lock()
-	// Rate limit each sound effect type individually.
-	if time.Since(lastPlayTimes[effect]) < soundRateLimit {
+	// Sounds requested while loading are either queued or dropped.
+	if !soundsReady {
+		if queueWhileLoading[effect] {
+			pendingSounds = append(pendingSounds, effect)
+		}
 		soundMutex.Unlock()
 		return
 	}
-	lastPlayTimes[effect] = time.Now()
-	data, ok := soundData[effect]
-	if !ok || len(data) == 0 {
+	// There is no point creating a player that cannot be heard.
+	if muted || !effectsOn || !effectEnabled(effect) {
+		soundMutex.Unlock()
+		return
+	}
+	// Rate limit each sound effect type individually, by when they were asked for.
+	if req.at.Sub(lastPlayTimes[effect]) < soundRateLimit {
+		soundMutex.Unlock()
+		return
+	}
+	lastPlayTimes[effect] = req.at
+	data := pickVariant(soundData[effect])
+	if len(data) == 0 {
 		soundMutex.Unlock()
 		return // Sound not loaded.
 	}
+	makeRoomFor(effect)
 	// Create a new player for the sound effect.
-	player := otoCtx.NewPlayer(bytes.NewReader(data))
+	var source io.Reader = bytes.NewReader(data)
+	if pan != 0 {
+		source = newPannedReader(data, pan)
+	}
+	player := otoCtx.NewPlayer(source)
+	player.SetVolume(currentEffectsVolume(effect))
 	// Add it to the activePlayers map to prevent it from being garbage-collected
-	// while it is playing. The cleanup goroutine will remove it later.
-	activePlayers[player] = true
+	// while it is playing. It is closed and removed once the sound has finished.
+	activePlayers[player] = effect
+	voices[effect] = append(voices[effect], player)
 	soundMutex.Unlock()
 	player.Play()
+	scheduleCleanup(player, soundDuration(data))
+	// Make room for important effects so they don't fight with the music.
+	if ducksMusic(effect) {
+		duckMusic(soundDuration(data))
+	}
 }
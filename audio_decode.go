@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// decodeAudio decodes an MP3, OGG/Vorbis or WAV file into 16-bit stereo PCM at
+// the audio context's sample rate. It is meant for short effects; long music
+// should be played from openAudioStream instead of being held in memory.
+func decodeAudio(path string, data []byte) ([]byte, error) {
+	stream, err := openAudioStream(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(stream)
+}
+
+// openAudioStream returns a stream that decodes the file on the fly into 16-bit
+// stereo PCM at the context's sample rate. The decoder is chosen by the file
+// extension. The stream can be rewound with Seek(0, io.SeekStart).
+func openAudioStream(path string, data []byte) (io.ReadSeeker, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".mp3":
+		decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		// go-mp3 always produces 16-bit stereo, so only the sample rate may need converting.
+		if decoder.SampleRate() == sampleRate {
+			return decoder, nil
+		}
+		return newPCMStream(&mp3Source{decoder: decoder}, 2, decoder.SampleRate()), nil
+	case ".ogg", ".oga":
+		reader, err := oggvorbis.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return newPCMStream(&oggSource{reader: reader}, reader.Channels(), reader.SampleRate()), nil
+	case ".wav":
+		source, channels, rate, err := parseWAV(data)
+		if err != nil {
+			return nil, err
+		}
+		return newPCMStream(source, channels, rate), nil
+	default:
+		return nil, fmt.Errorf("unsupported audio format %q", ext)
+	}
+}
+
+// sampleSource produces interleaved float samples in the -1.0 to 1.0 range.
+type sampleSource interface {
+	ReadSamples(p []float32) (int, error)
+	Rewind() error
+}
+
+// mp3Source adapts a go-mp3 decoder, which yields 16-bit stereo, to a sampleSource.
+type mp3Source struct {
+	decoder *mp3.Decoder
+	buf     []byte
+}
+
+func (s *mp3Source) ReadSamples(p []float32) (int, error) {
+	if cap(s.buf) < len(p)*2 {
+		s.buf = make([]byte, len(p)*2)
+	}
+	n, err := s.decoder.Read(s.buf[:len(p)*2])
+	for i := 0; i < n/2; i++ {
+		p[i] = float32(int16(binary.LittleEndian.Uint16(s.buf[i*2:]))) / 32768
+	}
+	return n / 2, err
+}
+
+func (s *mp3Source) Rewind() error {
+	_, err := s.decoder.Seek(0, io.SeekStart)
+	return err
+}
+
+// oggSource adapts an OGG/Vorbis reader to a sampleSource.
+type oggSource struct {
+	reader *oggvorbis.Reader
+}
+
+func (s *oggSource) ReadSamples(p []float32) (int, error) {
+	return s.reader.Read(p)
+}
+
+func (s *oggSource) Rewind() error {
+	return s.reader.SetPosition(0)
+}
+
+// WAV encodings understood by the decoder.
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE // Sample layout still follows bitsPerSample.
+)
+
+// wavSource reads samples straight from the data chunk of a WAV file.
+type wavSource struct {
+	body          []byte
+	format        uint16
+	bitsPerSample uint16
+	offset        int
+}
+
+// parseWAV validates an uncompressed WAV file with 8-, 16- or 24-bit integer
+// samples, or 32-bit float samples, and returns a source for its sample data.
+func parseWAV(data []byte) (*wavSource, int, int, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, 0, errors.New("not a RIFF/WAVE file")
+	}
+	var (
+		format, channels, bitsPerSample uint16
+		rate                            uint32
+		haveFormat                      bool
+	)
+	// Walk the chunks looking for the format description and the sample data.
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		if size > len(body) {
+			size = len(body) // Tolerate truncated files.
+		}
+		body = body[:size]
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, 0, errors.New("malformed fmt chunk")
+			}
+			format = binary.LittleEndian.Uint16(body[0:2])
+			channels = binary.LittleEndian.Uint16(body[2:4])
+			rate = binary.LittleEndian.Uint32(body[4:8])
+			bitsPerSample = binary.LittleEndian.Uint16(body[14:16])
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, 0, 0, errors.New("data chunk before fmt chunk")
+			}
+			if err := checkWAVFormat(format, bitsPerSample); err != nil {
+				return nil, 0, 0, err
+			}
+			if channels == 0 || rate == 0 {
+				return nil, 0, 0, errors.New("invalid channel count or sample rate")
+			}
+			source := &wavSource{body: body, format: format, bitsPerSample: bitsPerSample}
+			return source, int(channels), int(rate), nil
+		}
+		pos += 8 + size + size%2 // Chunks are padded to an even size.
+	}
+	return nil, 0, 0, errors.New("no data chunk found")
+}
+
+// checkWAVFormat rejects sample encodings the decoder cannot handle.
+func checkWAVFormat(format, bitsPerSample uint16) error {
+	switch {
+	case format == wavFormatFloat && bitsPerSample == 32:
+		return nil
+	case format != wavFormatPCM && format != wavFormatExtensible:
+		return fmt.Errorf("unsupported WAV encoding %d", format)
+	case bitsPerSample == 8 || bitsPerSample == 16 || bitsPerSample == 24:
+		return nil
+	default:
+		return fmt.Errorf("unsupported WAV sample size %d bits", bitsPerSample)
+	}
+}
+
+func (s *wavSource) ReadSamples(p []float32) (int, error) {
+	sampleSize := int(s.bitsPerSample) / 8
+	n := 0
+	for n < len(p) && s.offset+sampleSize <= len(s.body) {
+		b := s.body[s.offset:]
+		switch {
+		case s.format == wavFormatFloat:
+			p[n] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case s.bitsPerSample == 8:
+			p[n] = (float32(b[0]) - 128) / 128 // 8-bit WAV is unsigned.
+		case s.bitsPerSample == 16:
+			p[n] = float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+		default: // 24-bit.
+			v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+			p[n] = float32(v) / 8388608
+		}
+		s.offset += sampleSize
+		n++
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+func (s *wavSource) Rewind() error {
+	s.offset = 0
+	return nil
+}
+
+// pcmStream converts a sampleSource with any channel count and sample rate into
+// the context's 16-bit stereo format while it is read, using linear
+// interpolation for resampling. Only a small window of samples is kept.
+type pcmStream struct {
+	source   sampleSource
+	channels int
+	step     float64   // Input frames consumed per output frame.
+	buf      []float32 // Decoded input samples not yet fully consumed.
+	pos      float64   // Fractional input frame position within buf.
+	eof      bool
+	chunk    []float32
+}
+
+const pcmChunkFrames = 4096
+
+func newPCMStream(source sampleSource, channels, rate int) *pcmStream {
+	return &pcmStream{
+		source:   source,
+		channels: channels,
+		step:     float64(rate) / float64(sampleRate),
+		chunk:    make([]float32, pcmChunkFrames*channels),
+	}
+}
+
+// fill decodes another chunk of input, dropping frames that are no longer needed.
+func (s *pcmStream) fill() error {
+	if consumed := int(s.pos); consumed > 0 {
+		s.buf = s.buf[consumed*s.channels:]
+		s.pos -= float64(consumed)
+	}
+	n, err := s.source.ReadSamples(s.chunk)
+	s.buf = append(s.buf, s.chunk[:n]...)
+	if errors.Is(err, io.EOF) {
+		s.eof = true
+		return nil
+	}
+	return err
+}
+
+// sample returns the value of an output channel for an input frame, duplicating
+// the last channel for mono sources.
+func (s *pcmStream) sample(frame, ch int) float32 {
+	if ch >= s.channels {
+		ch = s.channels - 1
+	}
+	return s.buf[frame*s.channels+ch]
+}
+
+func (s *pcmStream) Read(p []byte) (int, error) {
+	const frameSize = channelCount * bytesPerSample
+	n := 0
+	for n+frameSize <= len(p) {
+		frame := int(s.pos)
+		frames := len(s.buf) / s.channels
+		// Interpolation needs the frame after the current one as well.
+		if frame+1 >= frames && !s.eof {
+			if err := s.fill(); err != nil {
+				return n, err
+			}
+			continue
+		}
+		if frame >= frames {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			break
+		}
+		next := frame + 1
+		if next >= frames {
+			next = frame // The very last frame has nothing to blend with.
+		}
+		frac := float32(s.pos - float64(frame))
+		for ch := 0; ch < channelCount; ch++ {
+			v := s.sample(frame, ch)*(1-frac) + s.sample(next, ch)*frac
+			v = float32(math.Max(-1, math.Min(1, float64(v))))
+			binary.LittleEndian.PutUint16(p[n:], uint16(int16(v*math.MaxInt16)))
+			n += bytesPerSample
+		}
+		s.pos += s.step
+	}
+	return n, nil
+}
+
+// Seek supports rewinding to the start, which is all looping playback needs.
+func (s *pcmStream) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, errors.New("pcmStream only supports seeking to the start")
+	}
+	if err := s.source.Rewind(); err != nil {
+		return 0, err
+	}
+	s.buf, s.pos, s.eof = s.buf[:0], 0, false
+	return 0, nil
+}
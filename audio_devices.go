@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// audioDevice is an output the player can pick in the audio settings.
+type audioDevice struct {
+	ID   string // What the platform knows it by; for ALSA, the card's ID.
+	Name string // Shown in the settings.
+}
+
+// audioDevices lists the outputs the game can choose among, or nil where
+// the platform only offers its default.
+func audioDevices() []audioDevice {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	b, err := os.ReadFile("/proc/asound/cards")
+	if err != nil {
+		return nil
+	}
+	return parseALSACards(string(b))
+}
+
+// parseALSACards returns the sound cards in /proc/asound/cards, which lists
+// each under its number and ID:
+//
+//	0 [PCH            ]: HDA-Intel - HDA Intel PCH
+//	                     HDA Intel PCH at 0xf7f10000 irq 32
+//	1 [Headset        ]: USB-Audio - USB Headset
+func parseALSACards(s string) []audioDevice {
+	var devices []audioDevice
+	for _, line := range strings.Split(s, "\n") {
+		number, rest, ok := strings.Cut(line, "[")
+		if _, err := strconv.Atoi(strings.TrimSpace(number)); !ok || err != nil {
+			continue
+		}
+		id, rest, ok := strings.Cut(rest, "]")
+		if !ok {
+			continue
+		}
+		d := audioDevice{ID: strings.TrimSpace(id), Name: strings.TrimSpace(id)}
+		if _, name, ok := strings.Cut(rest, " - "); ok {
+			d.Name = strings.TrimSpace(name)
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// pluggedInAudioDevice returns the device with the ID if it is among the
+// devices, and the system default, "", otherwise.
+func pluggedInAudioDevice(id string, devices []audioDevice) string {
+	for _, d := range devices {
+		if d.ID == id {
+			return id
+		}
+	}
+	return ""
+}
+
+// alsaCard is ALSA_CARD as the game was started with, restored for the
+// system default.
+var alsaCard, alsaCardSet = os.LookupEnv("ALSA_CARD")
+
+// useAudioDevice makes the audio context open on the device with the ID, or
+// on the system default for "". ALSA's default device follows
+// ALSA_CARD unless a sound server such as PulseAudio or PipeWire has taken
+// it over; then the server's own choice is used, which it moves to the
+// headphones as they are plugged in.
+func useAudioDevice(id string) {
+	switch {
+	case id != "":
+		os.Setenv("ALSA_CARD", id)
+	case alsaCardSet:
+		os.Setenv("ALSA_CARD", alsaCard)
+	default:
+		os.Unsetenv("ALSA_CARD")
+	}
+}
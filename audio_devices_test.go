@@ -0,0 +1,22 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseALSACards(t *testing.T) {
+	cards := ` 0 [PCH            ]: HDA-Intel - HDA Intel PCH
+                      HDA Intel PCH at 0xf7f10000 irq 32
+ 1 [Headset        ]: USB-Audio - USB Headset
+                      Logitech USB Headset at usb-0000:00:14.0-2, full speed
+ 2 [Loopback       ]: Loopback
+`
+	want := []audioDevice{{"PCH", "HDA Intel PCH"}, {"Headset", "USB Headset"}, {"Loopback", "Loopback"}}
+	if got := parseALSACards(cards); !slices.Equal(got, want) {
+		t.Errorf("parseALSACards = %v, want %v", got, want)
+	}
+	if got := parseALSACards("--- no soundcards ---\n"); len(got) != 0 {
+		t.Errorf("no cards parsed as %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+// Per-effect settings let players tone down individual sounds, such as the
+// card-play click, while keeping the rest of the audio.
+
+var (
+	effectVolumes  = make(map[SoundEffect]float64) // Missing entries mean full volume, protected by soundMutex.
+	disabledEffect = make(map[SoundEffect]bool)    // Effects switched off by the player, protected by soundMutex.
+)
+
+// adjustableEffects lists the effects shown in the per-effect settings, in display order.
+var adjustableEffects = []struct {
+	effect SoundEffect
+	label  string
+}{
+	{SoundCardPlay, "Card play"},
+	{SoundCapture, "Capture"},
+	{SoundPisti, "Pişti"},
+	{SoundPistiJack, "Jack Pişti"},
+	{SoundDeal, "Deal"},
+	{SoundUndo, "Undo"},
+	{SoundGameStart, "Game start"},
+	{SoundPlayerWins, "You win"},
+	{SoundCPUWins, "CPU wins"},
+	{SoundTie, "Tie"},
+	{SoundReminder, "Turn reminder"},
+}
+
+// SetEffectVolume sets the volume of a single effect, relative to the effects volume.
+func SetEffectVolume(effect SoundEffect, volume float64) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	effectVolumes[effect] = clampVolume(volume)
+	applyVolumes()
+}
+
+// SetEffectEnabled switches a single effect on or off.
+func SetEffectEnabled(effect SoundEffect, enabled bool) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	disabledEffect[effect] = !enabled
+}
+
+// effectVolume returns the relative volume of an effect. The caller must hold soundMutex.
+func effectVolume(effect SoundEffect) float64 {
+	if volume, ok := effectVolumes[effect]; ok {
+		return volume
+	}
+	return 1
+}
+
+// effectEnabled reports whether an effect may play. The caller must hold soundMutex.
+func effectEnabled(effect SoundEffect) bool {
+	return !disabledEffect[effect]
+}
+
+// EffectiveVolume returns the level an effect currently plays at, after the
+// master, group and per-effect volumes, or 0 if it is muted or switched off.
+func EffectiveVolume(effect SoundEffect) float64 {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	switch {
+	case muted:
+		return 0
+	case effect == SoundBackground:
+		return masterVolume * musicVolume
+	case !effectsOn || !effectEnabled(effect):
+		return 0
+	default:
+		return currentEffectsVolume(effect)
+	}
+}
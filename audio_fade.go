@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	fadeStep     = 20 * time.Millisecond
+	musicFadeIn  = 2 * time.Second
+	musicFadeOut = 600 * time.Millisecond
+	duckLevel    = 0.25 // Fraction of the music kept under an effect at full effects volume.
+	minDuckLevel = 0.05
+	duckIn       = 150 * time.Millisecond
+	duckOut      = 800 * time.Millisecond
+)
+
+var (
+	musicFade      = 1.0  // Multiplier applied to the music by fades and dips, protected by soundMutex.
+	fadeGeneration int    // Incremented by every new fade so older fades stop, protected by soundMutex.
+	duckingEnabled = true // Whether the music makes room for important effects, protected by soundMutex.
+)
+
+// fadeMusic ramps the music towards target (0.0-1.0) over the given duration.
+// Starting a new fade cancels the one in progress. The returned channel is
+// closed when the fade finishes or is cancelled, and the generation identifies it.
+func fadeMusic(target float64, duration time.Duration) (<-chan struct{}, int) {
+	done := make(chan struct{})
+	soundMutex.Lock()
+	fadeGeneration++
+	generation := fadeGeneration
+	start := musicFade
+	soundMutex.Unlock()
+	go func() {
+		defer close(done)
+		steps := int(duration / fadeStep)
+		if steps < 1 {
+			steps = 1
+		}
+		for i := 1; i <= steps; i++ {
+			time.Sleep(fadeStep)
+			soundMutex.Lock()
+			if fadeGeneration != generation {
+				soundMutex.Unlock()
+				return // A newer fade took over.
+			}
+			musicFade = start + (target-start)*float64(i)/float64(steps)
+			if backgroundPlayer != nil {
+				backgroundPlayer.SetVolume(currentMusicVolume())
+			}
+			soundMutex.Unlock()
+		}
+	}()
+	return done, generation
+}
+
+// FadeOutMusic fades the background music to silence, e.g. before quitting.
+// The returned channel is closed once the music is silent.
+func FadeOutMusic() <-chan struct{} {
+	if !soundLoaded {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	done, _ := fadeMusic(0, musicFadeOut)
+	return done
+}
+
+// PauseMusic fades the background music out and then pauses it.
+func PauseMusic() {
+	soundMutex.Lock()
+	hasMusic := backgroundPlayer != nil
+	soundMutex.Unlock()
+	if !soundLoaded || !hasMusic {
+		return
+	}
+	done, generation := fadeMusic(0, musicFadeOut)
+	go func() {
+		<-done
+		soundMutex.Lock()
+		defer soundMutex.Unlock()
+		// Only pause if nothing (such as ResumeMusic) has started a new fade meanwhile.
+		if fadeGeneration == generation {
+			backgroundPlayer.Pause()
+		}
+	}()
+}
+
+// ResumeMusic restarts paused background music with a fade in.
+func ResumeMusic() {
+	soundMutex.Lock()
+	if !soundLoaded {
+		soundMutex.Unlock()
+		return
+	}
+	if backgroundPlayer == nil {
+		// The track was switched while paused, so start the new one.
+		soundMutex.Unlock()
+		PlayBackgroundMusic()
+		return
+	}
+	backgroundPlayer.Play() // Has no effect if the music is still playing.
+	soundMutex.Unlock()
+	fadeMusic(1, musicFadeIn)
+}
+
+// SetDucking enables or disables lowering the music under important effects.
+func SetDucking(enabled bool) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	duckingEnabled = enabled
+}
+
+// duckMusic lowers the music while an effect of the given length plays, then
+// brings it back, unless another fade has started in the meantime. The quieter
+// the effects are set, the deeper the music ducks, so stingers stay audible.
+func duckMusic(hold time.Duration) {
+	soundMutex.Lock()
+	enabled := duckingEnabled
+	level := math.Max(minDuckLevel, duckLevel*effectsVolume)
+	soundMutex.Unlock()
+	if !enabled {
+		return
+	}
+	done, generation := fadeMusic(level, duckIn)
+	go func() {
+		<-done
+		time.Sleep(hold)
+		soundMutex.Lock()
+		stillDucked := fadeGeneration == generation
+		soundMutex.Unlock()
+		if stillDucked {
+			fadeMusic(1, duckOut)
+		}
+	}()
+}
+
+// ducksMusic reports whether an effect is important enough for the music to make room for it.
+func ducksMusic(effect SoundEffect) bool {
+	switch effect {
+	case SoundPisti, SoundPistiJack, SoundCapture, SoundPlayerWins, SoundCPUWins, SoundTie:
+		return true
+	}
+	return false
+}
+
+// soundDuration returns the playing time of decoded PCM data.
+func soundDuration(pcm []byte) time.Duration {
+	bytesPerSecond := sampleRate * channelCount * bytesPerSample
+	return time.Duration(len(pcm)) * time.Second / time.Duration(bytesPerSecond)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"sync"
+)
+
+// oto supports a single audio context per process and its errors are sticky,
+// so a context whose device disappeared cannot be reopened or moved to another
+// output. On most desktops the sound server (PulseAudio, PipeWire, WASAPI's
+// default endpoint) follows the default device itself; when the device is
+// lost anyway, audio is switched off cleanly and the failure is recorded so
+// the UI can tell the player to restart. For the same reason another output
+// device picked in the settings is only used from the next start.
+
+var (
+	audioFailureMutex sync.Mutex
+	audioFailure      error // The error that disabled audio, if any.
+)
+
+// audioContextHealthy reports whether the audio context can still play. If the
+// device has failed, it disables audio so no more players are fed to the dead
+// context, and remembers the error.
+func audioContextHealthy() bool {
+	if otoCtx == nil {
+		return false
+	}
+	err := otoCtx.Err()
+	if err == nil {
+		return true
+	}
+	audioFailureMutex.Lock()
+	defer audioFailureMutex.Unlock()
+	if audioFailure == nil {
+		audioFailure = err
+		soundLoaded = false
+		log.Printf("ERROR: Audio output failed: %v. Audio is disabled until the game is restarted.", err)
+		reportProblem(problemAudio, "Sound stopped because the audio device was lost. Restart Pishti to get it back.")
+	}
+	return false
+}
+
+// AudioFailure returns the error that disabled audio during the session, or nil.
+func AudioFailure() error {
+	audioFailureMutex.Lock()
+	defer audioFailureMutex.Unlock()
+	return audioFailure
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/oto/v2"
+)
+
+// Limits on how often and how many copies of an effect may play, so quick runs
+// of captures don't flood the mixer. When an effect is at its polyphony cap,
+// its oldest copy is stopped to make room rather than the new one being dropped.
+
+// defaultMaxVoices caps simultaneous copies of effects not listed in maxVoices.
+const defaultMaxVoices = 2
+
+var (
+	// maxVoices holds per-effect polyphony caps, protected by soundMutex.
+	maxVoices = map[SoundEffect]int{
+		SoundCardPlay: 4,
+		SoundDeal:     4,
+		SoundCapture:  3,
+	}
+	// voices lists the live players of each effect, oldest first, protected by soundMutex.
+	voices = make(map[SoundEffect][]oto.Player)
+)
+
+// SetSoundRateLimit sets the minimum interval between two starts of the same effect.
+func SetSoundRateLimit(interval time.Duration) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	soundRateLimit = max(0, interval)
+}
+
+// SetMaxVoices sets how many copies of an effect may play at once. Values
+// below one are treated as one.
+func SetMaxVoices(effect SoundEffect, n int) {
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	maxVoices[effect] = max(1, n)
+}
+
+// voiceLimit returns the polyphony cap of an effect. The caller must hold soundMutex.
+func voiceLimit(effect SoundEffect) int {
+	if n, ok := maxVoices[effect]; ok {
+		return n
+	}
+	return defaultMaxVoices
+}
+
+// makeRoomFor stops the oldest copies of an effect until another one fits
+// under its cap. The caller must hold soundMutex.
+func makeRoomFor(effect SoundEffect) {
+	for len(voices[effect]) >= voiceLimit(effect) {
+		releasePlayer(voices[effect][0])
+	}
+}
+
+// releasePlayer closes a player and forgets it. The caller must hold soundMutex.
+func releasePlayer(player oto.Player) {
+	effect, ok := activePlayers[player]
+	if !ok {
+		return
+	}
+	player.Close()
+	delete(activePlayers, player)
+	live := voices[effect]
+	for i, p := range live {
+		if p == player {
+			voices[effect] = append(live[:i:i], live[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/fs"
+	"log"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/oto/v2"
+)
+
+// The start screen plays a calmer menu track, and a game plays the gameplay
+// track. Switching between them crossfades instead of cutting.
+
+const musicCrossfade = 1500 * time.Millisecond
+
+// musicTrack is a music file kept compressed until it plays.
+type musicTrack struct {
+	path string
+	data []byte
+}
+
+var (
+	musicTracks    = make(map[SoundEffect]musicTrack) // Filled while loading, protected by soundMutex.
+	currentTrack   = SoundMenuMusic                   // The track that plays or will play next, protected by soundMutex.
+	outgoingPlayer oto.Player                         // A track being crossfaded out, protected by soundMutex.
+	outgoingFade   float64                            // Volume multiplier of outgoingPlayer, protected by soundMutex.
+)
+
+// loadMusic finds a music track, preferring a user sound pack file. The menu
+// track falls back to a synthesized loop as no recording of it is bundled.
+func loadMusic(effect SoundEffect, name string, packDirs []string) {
+	if !soundLoaded {
+		return // Audio context failed to initialize.
+	}
+	if path, data := findCustomMusic(name, packDirs); data != nil {
+		setMusicTrack(effect, musicTrack{path, data})
+		return
+	}
+	if effect == SoundMenuMusic {
+		setMusicTrack(effect, musicTrack{"menu.wav", synthesizeMenuMusic()})
+		return
+	}
+	path := "assets/sounds/" + name + ".mp3"
+	data, err := fs.ReadFile(assetFS, path)
+	if err != nil {
+		log.Printf("ERROR: Failed to load music asset %s: %v", path, err)
+		reportProblem(problemSounds, "Some sounds are missing and will stay silent.")
+		return
+	}
+	setMusicTrack(effect, musicTrack{path, data})
+}
+
+// setMusicTrack stores a loaded track; it is used from the next time it starts.
+func setMusicTrack(effect SoundEffect, track musicTrack) {
+	soundMutex.Lock()
+	musicTracks[effect] = track
+	soundMutex.Unlock()
+}
+
+// SwitchMusic changes to another music track (SoundMenuMusic or
+// SoundBackground), crossfading if music is playing. Otherwise the new track
+// is used the next time the music starts.
+func SwitchMusic(track SoundEffect) {
+	soundMutex.Lock()
+	if currentTrack == track {
+		soundMutex.Unlock()
+		return
+	}
+	currentTrack = track
+	old := backgroundPlayer
+	backgroundPlayer = nil
+	if old == nil {
+		soundMutex.Unlock()
+		return
+	}
+	if !old.IsPlaying() {
+		old.Close() // Paused music has nothing to fade; ResumeMusic starts the new track.
+		soundMutex.Unlock()
+		return
+	}
+	if outgoingPlayer != nil {
+		outgoingPlayer.Close()
+	}
+	outgoingPlayer, outgoingFade = old, musicFade
+	soundMutex.Unlock()
+	go fadeOutgoing(old)
+	startMusic(musicCrossfade)
+}
+
+// fadeOutgoing fades out a track that has been replaced, then closes it.
+func fadeOutgoing(player oto.Player) {
+	soundMutex.Lock()
+	start := outgoingFade
+	soundMutex.Unlock()
+	steps := max(1, int(musicCrossfade/fadeStep))
+	for i := 1; i <= steps; i++ {
+		time.Sleep(fadeStep)
+		soundMutex.Lock()
+		if outgoingPlayer != player {
+			soundMutex.Unlock()
+			return // Another switch closed it already.
+		}
+		outgoingFade = start * (1 - float64(i)/float64(steps))
+		player.SetVolume(outgoingMusicVolume())
+		soundMutex.Unlock()
+	}
+	soundMutex.Lock()
+	if outgoingPlayer == player {
+		player.Close()
+		outgoingPlayer = nil
+	}
+	soundMutex.Unlock()
+}
+
+// outgoingMusicVolume returns the volume of a track being crossfaded out. The caller must hold soundMutex.
+func outgoingMusicVolume() float64 {
+	if muted || !musicOn {
+		return 0
+	}
+	return masterVolume * musicVolume * outgoingFade
+}
+
+// synthesizeMenuMusic generates a slow, quiet loop of sustained chords as a
+// mono WAV file, so there is a calm menu track without a bundled recording.
+func synthesizeMenuMusic() []byte {
+	const (
+		rate        = 22050 // Plenty for soft pads, and the decoder resamples it.
+		chordLength = 4 * rate
+		swell       = rate // One second fade at each end of a chord.
+	)
+	// A minor, F major, C major and G major, with the roots an octave down.
+	chords := [][]float64{
+		{110.00, 261.63, 329.63},
+		{87.31, 261.63, 349.23},
+		{130.81, 329.63, 392.00},
+		{98.00, 293.66, 392.00},
+	}
+	samples := make([]int16, 0, len(chords)*chordLength)
+	for _, chord := range chords {
+		for i := 0; i < chordLength; i++ {
+			t := float64(i) / rate
+			// Each chord swells in and out, so the chords blend and the loop joins without clicks.
+			edge := math.Min(1, float64(min(i, chordLength-i))/swell)
+			envelope := 0.5 - 0.5*math.Cos(math.Pi*edge)
+			value := 0.0
+			for _, freq := range chord {
+				value += math.Sin(2*math.Pi*freq*t) + 0.3*math.Sin(4*math.Pi*freq*t)
+			}
+			value *= 0.08 * envelope / float64(len(chord))
+			samples = append(samples, int16(value*math.MaxInt16))
+		}
+	}
+	var buf bytes.Buffer
+	dataSize := len(samples) * 2
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	format := struct {
+		Size                uint32
+		Format, Channels    uint16
+		Rate, ByteRate      uint32
+		BlockAlign, BitsPer uint16
+	}{16, wavFormatPCM, 1, rate, rate * 2, 2, 16}
+	binary.Write(&buf, binary.LittleEndian, format)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"pishti/engine"
+)
+
+// maxCardPan is how far card sounds are moved off centre, kept subtle so the
+// effects still sound natural on speakers.
+const maxCardPan = 0.4
+
+// pannedReader is a small mixing stage that applies a stereo balance to
+// 16-bit stereo PCM as it is read, leaving the shared sound data untouched.
+type pannedReader struct {
+	data        []byte
+	offset      int
+	left, right float64
+}
+
+// newPannedReader positions a sound between the left (-1) and right (+1) channel.
+// The nearer channel keeps full level while the other one is turned down.
+func newPannedReader(data []byte, pan float64) *pannedReader {
+	pan = math.Max(-1, math.Min(1, pan))
+	return &pannedReader{data: data, left: math.Min(1, 1-pan), right: math.Min(1, 1+pan)}
+}
+
+func (r *pannedReader) Read(p []byte) (int, error) {
+	const frameSize = channelCount * bytesPerSample
+	n := min(len(p), len(r.data)-r.offset)
+	n -= n % frameSize
+	if n == 0 {
+		return 0, io.EOF
+	}
+	for i := 0; i < n; i += frameSize {
+		frame := r.data[r.offset+i:]
+		l := float64(int16(binary.LittleEndian.Uint16(frame[0:])))
+		rt := float64(int16(binary.LittleEndian.Uint16(frame[2:])))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(l*r.left)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(rt*r.right)))
+	}
+	r.offset += n
+	return n, nil
+}
+
+// cardSoundPan returns the stereo position for a card played from a hand slot.
+// Slots are spread across the stereo field, and the player's sounds lean left
+// while the CPU's lean right, so the two sides can be told apart by ear.
+func cardSoundPan(playerID engine.PlayerID, slot int) float64 {
+	side := -0.5
+	if playerID == engine.CPU {
+		side = 0.5
+	}
+	spread := 0.0
+	if engine.HandSize > 1 {
+		spread = float64(slot)/float64(engine.HandSize-1)*2 - 1 // -1 for the first slot, +1 for the last.
+	}
+	return maxCardPan * (side + spread*0.5)
+}
@@ -0,0 +1,40 @@
+package main
+
+import "sync/atomic"
+
+// queueWhileLoading lists effects that are played once loading completes if
+// they are requested too early, instead of being dropped.
+var queueWhileLoading = map[SoundEffect]bool{
+	SoundGameStart: true,
+}
+
+var (
+	audioReady        = make(chan struct{}) // Closed once sounds are loaded or audio is unavailable.
+	soundsReady       bool                  // Set with audioReady, protected by soundMutex.
+	pendingSounds     []SoundEffect         // Queued until loading completes, protected by soundMutex.
+	soundsLoadedCount atomic.Int32          // Number of sounds processed so far, for progress reporting.
+)
+
+// AudioReady returns a channel that is closed when all sounds have been loaded,
+// or straight away if audio could not be initialized.
+func AudioReady() <-chan struct{} {
+	return audioReady
+}
+
+// AudioLoadProgress reports how many of the sounds have been loaded so far.
+func AudioLoadProgress() (loaded, total int) {
+	return int(soundsLoadedCount.Load()), len(soundNames)
+}
+
+// markSoundsReady signals readiness and plays any sounds queued while loading.
+func markSoundsReady() {
+	soundMutex.Lock()
+	soundsReady = true
+	pending := pendingSounds
+	pendingSounds = nil
+	soundMutex.Unlock()
+	close(audioReady)
+	for _, effect := range pending {
+		PlaySound(effect)
+	}
+}
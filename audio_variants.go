@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io/fs"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// An effect can have several alternative recordings, one of which is picked
+// at random each time it plays, so e.g. the victory jingle doesn't get stale.
+// Alternatives are named after the effect with a number: "player_wins_2.mp3",
+// "player_wins_3.ogg" and so on, either bundled or in a sound pack. Sound pack
+// alternatives are added to the main sound rather than replacing it.
+
+// maxSoundVariants is the highest alternative number that is looked for.
+const maxSoundVariants = 9
+
+// variantName returns the file base name of an effect's nth alternative.
+func variantName(name string, n int) string {
+	return name + "_" + strconv.Itoa(n)
+}
+
+// variantBase strips an alternative number from a file base name, returning
+// the name of the effect it belongs to.
+func variantBase(base string) string {
+	i := strings.LastIndex(base, "_")
+	if i < 0 {
+		return base
+	}
+	if n, err := strconv.Atoi(base[i+1:]); err == nil && n >= 2 && n <= maxSoundVariants {
+		return base[:i]
+	}
+	return base
+}
+
+// loadVariants decodes the alternative recordings of an effect, bundled ones first.
+func loadVariants(name string, packDirs []string) [][]byte {
+	var variants [][]byte
+	for n := 2; n <= maxSoundVariants; n++ {
+		path := "assets/sounds/" + variantName(name, n) + ".mp3"
+		if _, err := fs.Stat(assetFS, path); err != nil {
+			continue // Alternatives are optional.
+		}
+		if pcm := loadSound(path); pcm != nil {
+			variants = append(variants, pcm)
+		}
+	}
+	for n := 2; n <= maxSoundVariants; n++ {
+		if pcm := loadCustomSound(variantName(name, n), packDirs); pcm != nil {
+			variants = append(variants, pcm)
+		}
+	}
+	return variants
+}
+
+// pickVariant chooses one of an effect's recordings at random.
+func pickVariant(variants [][]byte) []byte {
+	if len(variants) == 0 {
+		return nil
+	}
+	return variants[rand.Intn(len(variants))]
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+)
+
+const (
+	particleCount     = 14
+	particleFrameRate = 15 // Frames per second; low on purpose to save battery.
+)
+
+// particle is a single drifting dot in the animated background.
+type particle struct {
+	circle *canvas.Circle
+	x, y   float32 // Position of the particle's centre.
+	dx, dy float32 // Drift per frame.
+	size   float32
+}
+
+// animatedBackground draws slowly drifting particles over the static background
+// image. When it is stopped, only the static image remains visible.
+type animatedBackground struct {
+	layer     *fyne.Container
+	particles []*particle
+	stop      chan struct{}
+}
+
+// newAnimatedBackground creates the particle layer. It does not start moving
+// until start is called.
+func newAnimatedBackground() *animatedBackground {
+	bg := &animatedBackground{layer: container.NewWithoutLayout()}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < particleCount; i++ {
+		p := &particle{
+			circle: canvas.NewCircle(color.NRGBA{R: 255, G: 255, B: 255, A: uint8(15 + rng.Intn(25))}),
+			x:      rng.Float32(),
+			y:      rng.Float32(),
+			dx:     (rng.Float32() - 0.5) * 0.0015,
+			dy:     -0.0004 - rng.Float32()*0.0008, // Always drift slowly upwards.
+			size:   4 + rng.Float32()*8,
+		}
+		p.circle.Resize(fyne.NewSize(p.size, p.size))
+		bg.particles = append(bg.particles, p)
+		bg.layer.Add(p.circle)
+	}
+	bg.layer.Hide()
+	return bg
+}
+
+// start begins the drift animation. Calling it while running has no effect.
+func (bg *animatedBackground) start() {
+	if bg.stop != nil {
+		return
+	}
+	bg.stop = make(chan struct{})
+	bg.layer.Show()
+	go bg.run(bg.stop)
+}
+
+// stopAnimation halts the drift and hides the particles, leaving the static background.
+func (bg *animatedBackground) stopAnimation() {
+	if bg.stop == nil {
+		return
+	}
+	close(bg.stop)
+	bg.stop = nil
+	bg.layer.Hide()
+}
+
+// run advances the particles at a fixed, low frame rate until stop is closed.
+func (bg *animatedBackground) run(stop chan struct{}) {
+	ticker := time.NewTicker(time.Second / particleFrameRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			fyne.Do(bg.step)
+		}
+	}
+}
+
+// step moves every particle one frame and wraps it around the edges.
+func (bg *animatedBackground) step() {
+	size := bg.layer.Size()
+	if size.Width == 0 || size.Height == 0 {
+		return // Not laid out yet.
+	}
+	for _, p := range bg.particles {
+		p.x = wrapUnit(p.x + p.dx)
+		p.y = wrapUnit(p.y + p.dy)
+		p.circle.Move(fyne.NewPos(p.x*size.Width-p.size/2, p.y*size.Height-p.size/2))
+	}
+	bg.layer.Refresh()
+}
+
+// wrapUnit wraps a value into the [0, 1) range.
+func wrapUnit(v float32) float32 {
+	return v - float32(math.Floor(float64(v)))
+}
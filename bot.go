@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pishti/engine"
+)
+
+// External bots are programs that pick the CPU's cards, offered as extra
+// levels. The config file lists them:
+//
+//	[[ai.bot]]
+//	name = "Lazy"                  # the level's name; a single word
+//	command = "/usr/local/bin/lazy-pishti"
+//	args = ["--quiet"]             # optional
+//	move_time = "2s"               # optional: how long a move may take
+//
+// The game talks to a bot over its standard input and output with the
+// lines described under "External bots" in PROTOCOL.md.
+
+const (
+	// defaultBotMoveTime is how long a bot may think about a move unless its
+	// move_time says otherwise.
+	defaultBotMoveTime = 2 * time.Second
+	// botStartTime is how long a bot may take to answer its first pishti.
+	botStartTime = 5 * time.Second
+)
+
+// botConfig is an [[ai.bot]] table of the config file.
+type botConfig struct {
+	Name     string        `toml:"name"`
+	Command  string        `toml:"command"`
+	Args     []string      `toml:"args"`
+	MoveTime time.Duration `toml:"move_time"`
+}
+
+// errBotTimeout is returned when a bot does not answer in time.
+var errBotTimeout = errors.New("no answer in time")
+
+// errBotCalledOff is returned when the move is no longer wanted while the
+// bot thinks, as when the game is restarted.
+var errBotCalledOff = errors.New("the move was called off")
+
+// externalBot is a running bot program, started on its first move. A bot
+// that breaks off or runs out of time is stopped and started again on the
+// next move, so a late answer is never taken for the next move's.
+type externalBot struct {
+	cfg   botConfig
+	mu    sync.Mutex // Moves are asked for one at a time.
+	cmd   *exec.Cmd
+	in    io.WriteCloser
+	lines chan string // The bot's output, closed when it exits.
+}
+
+// registerBots offers each configured bot as a level above the built-in
+// ones. Bots without a usable name or command are logged and skipped.
+func registerBots(bots []botConfig) {
+	level := engine.LevelExpert
+	for _, b := range bots {
+		switch _, taken := strategyNamed(b.Name); {
+		case b.Name == "" || strings.ContainsAny(b.Name, " \t"):
+			log.Printf("ERROR: Skipping bot %q: its name must be a single word", b.Name)
+			continue
+		case taken:
+			log.Printf("ERROR: Skipping bot %q: the name is taken by another level", b.Name)
+			continue
+		case b.Command == "":
+			log.Printf("ERROR: Skipping bot %q: no command", b.Name)
+			continue
+		}
+		level++
+		bot := &externalBot{cfg: b}
+		engine.RegisterStrategy(engine.Strategy{
+			Level: level, Name: b.Name, Undo: false,
+			Description: fmt.Sprintf("An external program, %s. No undo.", b.Command),
+			Choose:      bot.choose,
+		})
+	}
+}
+
+// moveTime returns how long the bot may think about a move.
+func (b *externalBot) moveTime() time.Duration {
+	if b.cfg.MoveTime > 0 {
+		return b.cfg.MoveTime
+	}
+	return defaultBotMoveTime
+}
+
+// choose asks the bot for the slot to play. When the bot fails or runs out
+// of time, the failure is logged and -1 lets the game pick a card instead.
+// A move called off while the bot thinks stops the bot and returns -1 too.
+func (b *externalBot) choose(p *engine.Position) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var done <-chan struct{} // Never closed for a move nobody calls off.
+	if p.Context != nil {
+		done = p.Context.Done()
+	}
+	slot, err := b.ask(p, done)
+	if errors.Is(err, errBotCalledOff) {
+		b.stop()
+		return -1
+	}
+	if err != nil {
+		log.Printf("ERROR: Bot %s failed to move, playing for it: %v", b.cfg.Name, err)
+		b.stop()
+		return -1
+	}
+	if slot < 0 || slot >= engine.HandSize || p.Hand[slot] == nil {
+		log.Printf("ERROR: Bot %s chose empty slot %d, playing for it", b.cfg.Name, slot)
+		return -1
+	}
+	return slot
+}
+
+// ask sends the position and waits for the bot's bestmove, or until done is
+// closed.
+func (b *externalBot) ask(p *engine.Position, done <-chan struct{}) (int, error) {
+	if b.cmd == nil {
+		if err := b.start(done); err != nil {
+			return -1, err
+		}
+	}
+	move := b.moveTime()
+	if err := b.send(positionLine(p)); err != nil {
+		return -1, err
+	}
+	if err := b.send(fmt.Sprintf("go movetime %d", move.Milliseconds())); err != nil {
+		return -1, err
+	}
+	line, err := b.await("bestmove", move, done)
+	if err != nil {
+		return -1, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return -1, fmt.Errorf("bad answer %q", line)
+	}
+	slot, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return -1, fmt.Errorf("bad answer %q", line)
+	}
+	return slot, nil
+}
+
+// start runs the bot's program and waits for it to answer pishti.
+func (b *externalBot) start(done <-chan struct{}) error {
+	cmd := exec.Command(b.cfg.Command, b.cfg.Args...)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = log.Writer()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	lines := make(chan string, 16)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	b.cmd, b.in, b.lines = cmd, in, lines
+	if err := b.send("pishti"); err != nil {
+		return err
+	}
+	if _, err := b.await("pishtiok", botStartTime, done); err != nil {
+		return fmt.Errorf("starting: %w", err)
+	}
+	log.Printf("Started bot %s: %s", b.cfg.Name, b.cfg.Command)
+	return nil
+}
+
+// stop tells the bot to quit and makes sure it does. It is a no-op if the
+// bot is not running.
+func (b *externalBot) stop() {
+	if b.cmd == nil {
+		return
+	}
+	cmd := b.cmd
+	b.send("quit")
+	b.in.Close()
+	cmd.Process.Kill()
+	go func(lines chan string) { // Reaped in the background, once its output is read.
+		for range lines {
+		}
+		cmd.Wait()
+	}(b.lines)
+	b.cmd, b.in, b.lines = nil, nil, nil
+}
+
+// send writes a line to the bot.
+func (b *externalBot) send(line string) error {
+	_, err := io.WriteString(b.in, line+"\n")
+	return err
+}
+
+// await reads the bot's lines until one starts with word, skipping the
+// others, such as info lines. It gives up after limit or once done is closed.
+func (b *externalBot) await(word string, limit time.Duration, done <-chan struct{}) (string, error) {
+	timeout := time.After(limit)
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				return "", errors.New("the program exited")
+			}
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] == word {
+				return line, nil
+			}
+		case <-timeout:
+			return "", fmt.Errorf("%w (%s)", errBotTimeout, limit)
+		case <-done:
+			return "", errBotCalledOff
+		}
+	}
+}
+
+// positionLine returns the position line describing what the side choosing
+// knows. Face-down cards of the table are sent as "??".
+func positionLine(p *engine.Position) string {
+	line := []string{"position", "hand"}
+	for _, card := range p.Hand {
+		line = append(line, cardCode(card))
+	}
+	line = append(line, "table", strconv.Itoa(len(p.Table)))
+	for i, card := range p.Table {
+		if i < p.Hidden {
+			line = append(line, "??")
+		} else {
+			line = append(line, cardCode(card))
+		}
+	}
+	line = append(line, "played", strconv.Itoa(len(p.GamePlayed)))
+	for _, card := range p.GamePlayed {
+		line = append(line, cardCode(card))
+	}
+	last := "no"
+	if p.LastPile {
+		last = "yes"
+	}
+	line = append(line,
+		"deck", strconv.Itoa(p.DeckLeft),
+		"opponent", strconv.Itoa(p.OpponentHeld),
+		"taken", strconv.Itoa(p.Taken), strconv.Itoa(p.OpponentTaken),
+		"last", last)
+	return strings.Join(line, " ")
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"pishti/engine"
+)
+
+// testBotEnv makes the test binary act as a bot when it is started by one
+// of these tests: "last" plays the last card held, "silent" never answers.
+const testBotEnv = "PISHTI_TEST_BOT"
+
+func TestBotHelperProcess(t *testing.T) {
+	mode := os.Getenv(testBotEnv)
+	if mode == "" {
+		return
+	}
+	in := bufio.NewScanner(os.Stdin)
+	var hand []string
+	for in.Scan() {
+		fields := strings.Fields(in.Text())
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "pishti":
+			fmt.Println("id name Test\npishtiok")
+		case fields[0] == "position":
+			hand = fields[2:6]
+		case fields[0] == "go" && mode == "last":
+			fmt.Println("info thinking")
+			for i := len(hand) - 1; i >= 0; i-- {
+				if hand[i] != "--" {
+					fmt.Println("bestmove", i)
+					break
+				}
+			}
+		case fields[0] == "quit":
+			os.Exit(0)
+		}
+	}
+	os.Exit(0)
+}
+
+// testBot returns a bot running the test binary in the given mode.
+func testBot(t *testing.T, mode string, moveTime time.Duration) *externalBot {
+	t.Setenv(testBotEnv, mode)
+	b := &externalBot{cfg: botConfig{Name: "Test", Command: os.Args[0], Args: []string{"-test.run=^TestBotHelperProcess$"}, MoveTime: moveTime}}
+	t.Cleanup(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.stop()
+	})
+	return b
+}
+
+func TestExternalBotPlaysAGame(t *testing.T) {
+	bot := testBot(t, "last", 0)
+	s := engine.Strategy{Name: "Test", Choose: bot.choose}
+	c := engine.NewCasino(nil, nil)
+	c.SetLevel(engine.LevelBeginner)
+	c.StartGameWithSeed(3)
+	for moves := 0; c.State() != engine.StateGameOver; moves++ {
+		advanceGame(c, func() {})
+		if c.State() != engine.StatePlayerTurn {
+			continue
+		}
+		hand := c.PlayerHand()
+		want := -1
+		for i := range hand {
+			if hand[i] != nil {
+				want = i
+			}
+		}
+		if got := c.PlayerChoice(s, nil); got != want {
+			t.Fatalf("move %d: the bot chose slot %d, want %d", moves, got, want)
+		}
+		if err := c.PlayerPlays(want); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExternalBotTimeLimit(t *testing.T) {
+	bot := testBot(t, "silent", 100*time.Millisecond)
+	c := engine.NewCasino(nil, nil)
+	c.SetLevel(engine.LevelBeginner)
+	c.StartGameWithSeed(3)
+	start := time.Now()
+	if got := c.PlayerChoice(engine.Strategy{Choose: bot.choose}, nil); got != -1 {
+		t.Errorf("a silent bot chose slot %d", got)
+	}
+	if time.Since(start) > botStartTime/2 || bot.cmd != nil {
+		t.Errorf("the silent bot was waited for %s and left running: %v", time.Since(start), bot.cmd != nil)
+	}
+}
+
+func TestExternalBotCalledOff(t *testing.T) {
+	bot := testBot(t, "silent", time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if got := bot.choose(&engine.Position{Context: ctx}); got != -1 {
+		t.Errorf("a bot whose move was called off chose slot %d", got)
+	}
+	if time.Since(start) > time.Second || bot.cmd != nil {
+		t.Errorf("the bot was waited for %s after its move was called off and left running: %v", time.Since(start), bot.cmd != nil)
+	}
+}
+
+func TestBotPositionLine(t *testing.T) {
+	ace := engine.NewCard(engine.Ace, engine.Spades, "")
+	ten := engine.NewCard(engine.Ten, engine.Diamonds, "")
+	p := &engine.Position{
+		Hand:       engine.Hand{ace, nil, ten, nil},
+		Table:      []*engine.Card{ace, ace, ten},
+		Hidden:     2,
+		GamePlayed: []*engine.Card{ten},
+		DeckLeft:   32, OpponentHeld: 3, Taken: 2, LastPile: true,
+	}
+	want := "position hand AS -- TD -- table 3 ?? ?? TD played 1 TD deck 32 opponent 3 taken 2 0 last yes"
+	if got := positionLine(p); got != want {
+		t.Errorf("position line\n%s\nwant\n%s", got, want)
+	}
+}
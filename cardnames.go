@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pishti/engine"
+)
+
+// cardNaming is how a language names the cards in the move history, the
+// replay captions and the overlay. Saves, game codes and the engine protocol
+// keep the canonical English names and codes whatever the language.
+type cardNaming struct {
+	ranks  [engine.King + 1]string
+	suits  [engine.Spades + 1]string
+	format string // Takes the rank, then the suit.
+}
+
+// cardNamings lists the languages with their own card names by code. English
+// is the engine's own naming and is not listed.
+var cardNamings = map[string]cardNaming{
+	// Turkish names the suit first, with the rank taking the possessive:
+	// "Maça Kızı", "Sinek Valesi". The Jack is also called Bacak; Vale is
+	// what the announcer says.
+	"tr": {
+		ranks: [...]string{"Ası", "İkilisi", "Üçlüsü", "Dörtlüsü", "Beşlisi", "Altılısı", "Yedilisi",
+			"Sekizlisi", "Dokuzlusu", "Onlusu", "Valesi", "Kızı", "Papazı"},
+		suits:  [...]string{"Kupa", "Karo", "Sinek", "Maça"},
+		format: "%[2]s %[1]s",
+	},
+}
+
+// cardLanguage is the language code of the card names shown, "" for English.
+// It is set at startup and changed from the settings.
+var cardLanguage string
+
+// setCardLanguage names the cards in the language of the given tag, such as
+// "tr" or "en-GB", falling back to English for languages without names.
+func setCardLanguage(tag string) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(tag, "_", "-")), "-")
+	if _, ok := cardNamings[lang]; !ok {
+		lang = ""
+	}
+	cardLanguage = lang
+}
+
+// cardName returns the card's name in the current language, e.g. "Ten of
+// Diamonds" or "Karo Onlusu".
+func cardName(c *engine.Card) string {
+	n, ok := cardNamings[cardLanguage]
+	if !ok {
+		return c.String()
+	}
+	return fmt.Sprintf(n.format, n.ranks[c.GetFace()], n.suits[c.GetSuit()])
+}
+
+// hiddenCardsMessage returns the engine's message naming the face-down cards
+// of the starting pile, which are at the bottom of the table, in the current
+// language. The message is returned as it is if the table is too short.
+func hiddenCardsMessage(table []*engine.Card, msg string) string {
+	if len(table) < 3 {
+		return msg
+	}
+	return Tf("You captured the hidden cards: %s, %s, and %s!",
+		T(table[0].GetFace().String()), T(table[1].GetFace().String()), T(table[2].GetFace().String()))
+}
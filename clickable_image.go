@@ -1,18 +1,28 @@
 package main
 
 import (
+	"image/color"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 // clickableImage is a custom widget that acts like an image but can be tapped.
+// One with a tap handler also takes the keyboard focus: Tab reaches it, Enter
+// or Space taps it and the arrow keys move on to its neighbours.
 type clickableImage struct {
 	widget.BaseWidget
 	Resource fyne.Resource
 	FillMode canvas.ImageFill
 	minSize  fyne.Size
 	onTapped func()
+	onArrow  func(step int)        // Moves the focus -1 or +1 along a row; nil leaves arrows alone.
+	cursor   func() desktop.Cursor // Chooses the cursor shown while hovering; nil means the default cursor.
+	disabled bool
+	focused  bool
 }
 
 // newClickableImage creates a new instance of the custom widget.
@@ -29,33 +39,122 @@ func newClickableImage(onTapped func()) *clickableImage {
 func (c *clickableImage) CreateRenderer() fyne.WidgetRenderer {
 	img := canvas.NewImageFromResource(c.Resource)
 	img.FillMode = c.FillMode
+	ring := canvas.NewRectangle(color.Transparent)
+	ring.StrokeWidth = 3
+	ring.CornerRadius = theme.InputRadiusSize()
 	return &clickableImageRenderer{
 		image:  img,
+		ring:   ring,
 		widget: c,
 	}
 }
 
 // Tapped is called when the user taps the widget.
 func (c *clickableImage) Tapped(_ *fyne.PointEvent) {
-	if c.onTapped != nil {
+	if c.onTapped != nil && !c.disabled {
 		c.onTapped()
 	}
 }
 
+// SetOnArrow sets what the left and right arrow keys do while the widget has
+// the focus, with step -1 for left and +1 for right.
+func (c *clickableImage) SetOnArrow(handler func(step int)) {
+	c.onArrow = handler
+}
+
+// Disabled implements fyne.Disableable. Images without a tap handler count
+// as disabled, so Tab passes over them.
+func (c *clickableImage) Disabled() bool {
+	return c.disabled || c.onTapped == nil
+}
+
+// Disable implements fyne.Disableable; taps are ignored until Enable.
+func (c *clickableImage) Disable() {
+	c.disabled = true
+}
+
+// Enable implements fyne.Disableable.
+func (c *clickableImage) Enable() {
+	c.disabled = false
+}
+
+// FocusGained implements fyne.Focusable.
+func (c *clickableImage) FocusGained() {
+	c.focused = true
+	c.Refresh()
+}
+
+// FocusLost implements fyne.Focusable.
+func (c *clickableImage) FocusLost() {
+	c.focused = false
+	c.Refresh()
+}
+
+// TypedRune implements fyne.Focusable.
+func (c *clickableImage) TypedRune(rune) {}
+
+// TypedKey implements fyne.Focusable. Keys the widget has no use for go on to
+// the window's shortcuts, which a focused widget would otherwise swallow.
+func (c *clickableImage) TypedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyReturn, fyne.KeyEnter, fyne.KeySpace:
+		c.Tapped(nil)
+	case fyne.KeyLeft, fyne.KeyRight:
+		if c.onArrow != nil {
+			step := 1
+			if ev.Name == fyne.KeyLeft {
+				step = -1
+			}
+			c.onArrow(step)
+		}
+	default:
+		if cv := fyne.CurrentApp().Driver().CanvasForObject(c); cv != nil && cv.OnTypedKey() != nil {
+			cv.OnTypedKey()(ev)
+		}
+	}
+}
+
 // SetOnTapped allows changing the tap handler.
 func (c *clickableImage) SetOnTapped(handler func()) {
 	c.onTapped = handler
 }
 
+// SetMinSize sets the size the widget asks for, a card's by default.
+func (c *clickableImage) SetMinSize(size fyne.Size) {
+	c.minSize = size
+	c.Refresh()
+}
+
+// SetCursorFunc sets the function that picks the hover cursor, so the cursor
+// can reflect whether the widget is currently interactive.
+func (c *clickableImage) SetCursorFunc(cursor func() desktop.Cursor) {
+	c.cursor = cursor
+}
+
+// Cursor implements desktop.Cursorable.
+func (c *clickableImage) Cursor() desktop.Cursor {
+	if c.cursor == nil {
+		return desktop.DefaultCursor
+	}
+	return c.cursor()
+}
+
 // --- Renderer for the custom widget ---
 
 type clickableImageRenderer struct {
 	image  *canvas.Image
+	ring   *canvas.Rectangle // Drawn around the image while it has the focus.
 	widget *clickableImage
+	// What the image was last drawn with, so refreshes that change nothing
+	// skip rebuilding its texture.
+	drawn      bool
+	drawnImage fyne.Resource
+	drawnFill  canvas.ImageFill
 }
 
 func (r *clickableImageRenderer) Layout(size fyne.Size) {
 	r.image.Resize(size)
+	r.ring.Resize(size)
 }
 
 func (r *clickableImageRenderer) MinSize() fyne.Size {
@@ -63,23 +162,34 @@ func (r *clickableImageRenderer) MinSize() fyne.Size {
 }
 
 func (r *clickableImageRenderer) Refresh() {
+	ringColor := color.Color(color.Transparent)
+	if r.widget.focused {
+		ringColor = theme.Color(theme.ColorNameFocus)
+	}
+	if r.ring.StrokeColor != ringColor {
+		r.ring.StrokeColor = ringColor
+		r.ring.Refresh()
+	}
+	res, fill := r.widget.Resource, r.widget.FillMode
+	if r.drawn && res == r.drawnImage && fill == r.drawnFill {
+		return
+	}
+	r.drawn, r.drawnImage, r.drawnFill = true, res, fill
 	// Update the resource.
-	r.image.Resource = r.widget.Resource
-	r.image.FillMode = r.widget.FillMode
+	r.image.Resource = res
+	r.image.FillMode = fill
 	// Force clear the underlying image data when resource is nil.
-	if r.widget.Resource == nil {
-		r.image.Resource = nil
+	if res == nil {
 		r.image.Image = nil        // Clear the underlying image data.
 		r.image.Translucency = 1.0 // Fully transparent.
 	} else {
 		r.image.Translucency = 0.0 // Fully opaque.
 	}
 	r.image.Refresh()
-	canvas.Refresh(r.image) // Extra refresh to ensure it updates.
 }
 
 func (r *clickableImageRenderer) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{r.image}
+	return []fyne.CanvasObject{r.image, r.ring}
 }
 
 func (r *clickableImageRenderer) Destroy() {}
@@ -0,0 +1,34 @@
+// Command pishti-server hosts online Pişti games between two people, for the
+// game's Play Online dialog and other clients of the protocol described in
+// package online:
+//
+//	pishti-server -addr :8080 -variant Classic
+//
+// Clients connect to ws://host:8080/play. Put the server behind a TLS
+// proxy to offer wss:// instead.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"pishti/engine"
+	"pishti/online"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	variant := flag.String("variant", engine.Variants()[0].Name, "rule variant of the games")
+	flag.Parse()
+	s, err := online.NewServer(*variant)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/play", s.Handler())
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Printf("Serving online games on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"pishti/engine"
+)
+
+// config is the contents of config.toml in the user config directory. It
+// holds the defaults and tuning values that used to be constants; settings
+// changed in the game are kept as preferences and win over its defaults.
+type config struct {
+	Rules   rulesConfig   `toml:"rules"`
+	AI      aiConfig      `toml:"ai"`
+	Audio   audioConfig   `toml:"audio"`
+	UI      uiConfig      `toml:"ui"`
+	Overlay overlayConfig `toml:"overlay"`
+	Voice   voiceConfig   `toml:"voice"`
+}
+
+// rulesConfig holds the rule defaults.
+type rulesConfig struct {
+	Variant     string `toml:"variant"`      // Rule variant used until one is picked in settings.
+	MatchTarget int    `toml:"match_target"` // Points that win a match of several deals; 0 plays single games.
+}
+
+// aiConfig holds the pauses around the CPU's moves, how long the Expert
+// thinks, and the external bots offered as levels.
+type aiConfig struct {
+	CPUDelay       time.Duration `toml:"cpu_delay"`
+	CapturePause   time.Duration `toml:"capture_pause"`
+	EndOfHandPause time.Duration `toml:"end_of_hand_pause"`
+	ThinkTime      time.Duration `toml:"think_time"` // Added to the CPU delay at most.
+	Bots           []botConfig   `toml:"bot"`
+}
+
+// audioConfig holds the audio defaults.
+type audioConfig struct {
+	MasterVolume   float64       `toml:"master_volume"`
+	MusicVolume    float64       `toml:"music_volume"`
+	EffectsVolume  float64       `toml:"effects_volume"`
+	SampleRate     int           `toml:"sample_rate"`
+	BufferMs       int           `toml:"buffer_ms"` // 0 lets the audio driver decide.
+	SoundRateLimit time.Duration `toml:"sound_rate_limit"`
+	// MaxVoices caps how many copies of an effect play at once, by sound
+	// name, e.g. { capture = 2, play = 4 }; effects not listed keep their caps.
+	MaxVoices map[string]int `toml:"max_voices"`
+}
+
+// uiConfig holds the interface options.
+type uiConfig struct {
+	AnimatedBackground bool          `toml:"animated_background"`
+	TurnReminder       time.Duration `toml:"turn_reminder"`  // 0 disables the reminder.
+	CardCacheKB        int           `toml:"card_cache_kb"`  // 0 keeps every card face loaded.
+	CardAnimation      time.Duration `toml:"card_animation"` // How long a card slides at normal speed; 0 disables it.
+}
+
+// overlayConfig holds the live state export for stream overlays.
+type overlayConfig struct {
+	File string `toml:"file"` // JSON file rewritten after every move; empty disables the export.
+}
+
+// voiceConfig holds the speech recognizer for voice control.
+type voiceConfig struct {
+	// Command runs an offline recognizer that prints each phrase it hears on
+	// a line of its own, e.g. ["whisper-stream", "-m", "ggml-tiny.en.bin"].
+	// Empty leaves voice control off.
+	Command []string `toml:"command"`
+}
+
+// appConfig is the configuration read at startup by loadConfig.
+var appConfig = defaultConfig()
+
+// defaultConfig returns the built-in configuration, used for anything the
+// config file leaves out.
+func defaultConfig() config {
+	return config{
+		Rules: rulesConfig{Variant: engine.Variants()[0].Name},
+		AI: aiConfig{
+			CPUDelay:       engine.DefaultPacing.CPUDelay,
+			CapturePause:   engine.DefaultPacing.CapturePause,
+			EndOfHandPause: engine.DefaultPacing.EndOfHandPause,
+			ThinkTime:      engine.DefaultThinkTime,
+		},
+		Audio: audioConfig{
+			MasterVolume:   defaultMasterVolume,
+			MusicVolume:    defaultMusicVolume,
+			EffectsVolume:  defaultEffectsVolume,
+			SampleRate:     defaultSampleRate,
+			SoundRateLimit: defaultSoundRateLimit,
+		},
+		UI: uiConfig{
+			TurnReminder:  10 * time.Second,
+			CardCacheKB:   cardCacheBudget >> 10,
+			CardAnimation: 250 * time.Millisecond,
+		},
+	}
+}
+
+// pacing returns the engine pacing described by the AI section.
+func (c aiConfig) pacing() engine.Pacing {
+	return engine.Pacing{CPUDelay: c.CPUDelay, CapturePause: c.CapturePause, EndOfHandPause: c.EndOfHandPause}
+}
+
+// configPath returns where the config file lives, or "" if the platform has
+// no user config directory.
+func configPath() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.toml")
+}
+
+// loadConfig reads the config file over the defaults. A missing file is
+// not an error; a broken one is reported and the defaults are kept.
+func loadConfig() config {
+	cfg := defaultConfig()
+	p := configPath()
+	if p == "" {
+		return cfg
+	}
+	meta, err := toml.DecodeFile(p, &cfg)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to read config file %s, using defaults: %v", p, err)
+		return defaultConfig()
+	}
+	for _, key := range meta.Undecoded() {
+		log.Printf("ERROR: Unknown setting %q in config file %s", key, p)
+	}
+	return cfg
+}
+
+// saveConfig writes the configuration to the config file.
+func saveConfig(cfg config) error {
+	p := configPath()
+	if p == "" {
+		return errors.New("no user config directory")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Pishti configuration. Durations are written like \"500ms\" or \"1.5s\".\n\n")
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// crashReportArg makes the program show the report written by a crashed
+// instance instead of starting a game; the report's path follows it.
+const crashReportArg = "--crash-report"
+
+// crashDir returns the directory crash reports are written to.
+func crashDir() string {
+	if dir := dataPath(crashesDir); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), appDirName, crashesDir)
+}
+
+// reportCrash handles a panic: it writes a crash report with the game state,
+// starts a new instance of the program to tell the player where it is, and
+// exits. It never returns.
+func reportCrash(casino *engine.Casino, value any, stack []byte) {
+	path, err := writeCrashReport(casino, value, stack)
+	if err != nil {
+		log.Printf("ERROR: Pishti crashed (%v) and the crash report could not be written: %v\n%s", value, err, stack)
+		os.Exit(2)
+	}
+	log.Printf("ERROR: Pishti crashed: %v. Report written to %s", value, path)
+	// The crashed instance cannot be trusted to draw, so a fresh one shows the dialog.
+	if exe, err := os.Executable(); err != nil {
+		log.Printf("ERROR: Failed to find the executable to show the crash report: %v", err)
+	} else if err := exec.Command(exe, crashReportArg, path).Start(); err != nil {
+		log.Printf("ERROR: Failed to show the crash report: %v", err)
+	}
+	os.Exit(2)
+}
+
+// writeCrashReport writes the panic, the engine state, the move journal and
+// the stack trace to a new file in crashDir and returns its path.
+func writeCrashReport(casino *engine.Casino, value any, stack []byte) (string, error) {
+	dir := crashDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pishti crash report, %s\n\n", now.Format(time.RFC1123))
+	fmt.Fprintf(&b, "Panic: %v\n\n", value)
+	if casino != nil {
+		fmt.Fprintf(&b, "Game:\n%s\n\nMoves:\n", casino.DebugDump())
+		for i, move := range casino.Snapshot().Moves {
+			fmt.Fprintf(&b, "%3d. %s\n", i+1, move)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Stack:\n%s", stack)
+	path := filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".txt")
+	return path, os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// showCrashReport runs a small app that tells the player the game crashed
+// and offers to open the folder holding the report.
+func showCrashReport(path string) {
+	a := app.NewWithID("io.github.ser7ach.pishti")
+	setMessageLanguage(a.Preferences().String(prefLanguage)) // The report itself stays in English.
+	w := a.NewWindow("Pishti")
+	message := widget.NewLabel(T("Sorry, Pishti ran into a problem and had to close.\n" +
+		"A report describing the game was saved to:"))
+	location := widget.NewLabel(path)
+	location.Wrapping = fyne.TextWrapBreak
+	openButton := widget.NewButton(T("Open Folder"), func() {
+		folder, err := url.Parse(storage.NewFileURI(filepath.Dir(path)).String())
+		if err == nil {
+			err = a.OpenURL(folder)
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to open the crash report folder: %v", err)
+		}
+	})
+	openButton.Importance = widget.HighImportance
+	closeButton := widget.NewButton(T("Close"), a.Quit)
+	buttons := container.NewHBox(layout.NewSpacer(), closeButton, openButton)
+	w.SetContent(container.NewPadded(container.NewVBox(message, location, buttons)))
+	w.Resize(fyne.NewSize(420, 0))
+	w.CenterOnScreen()
+	w.ShowAndRun()
+}
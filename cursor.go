@@ -0,0 +1,45 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+const blockedCursorSize = 24
+
+// blockedCursor is a "not allowed" cursor (a circle with a slash) shown over
+// cards while an animation is running and taps are ignored.
+type blockedCursor struct {
+	img image.Image
+}
+
+// newBlockedCursor draws the cursor image once.
+func newBlockedCursor() *blockedCursor {
+	img := image.NewNRGBA(image.Rect(0, 0, blockedCursorSize, blockedCursorSize))
+	red := color.NRGBA{R: 210, G: 40, B: 40, A: 255}
+	centre := float64(blockedCursorSize-1) / 2
+	outer, inner := centre, centre-3
+	for y := 0; y < blockedCursorSize; y++ {
+		for x := 0; x < blockedCursorSize; x++ {
+			dx, dy := float64(x)-centre, float64(y)-centre
+			dist := math.Hypot(dx, dy)
+			onRing := dist <= outer && dist >= inner
+			// The slash runs from top-left to bottom-right inside the ring.
+			onSlash := dist < inner && math.Abs(dx-dy) <= 2
+			if onRing || onSlash {
+				img.Set(x, y, red)
+			}
+		}
+	}
+	return &blockedCursor{img: img}
+}
+
+// Image implements desktop.Cursor with the hot-spot in the centre.
+func (c *blockedCursor) Image() (image.Image, int, int) {
+	return c.img, blockedCursorSize / 2, blockedCursorSize / 2
+}
+
+var cursorBlocked desktop.Cursor = newBlockedCursor()
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// prefDailyBestPrefix, followed by a level's name, keeps the player's best
+// score in the latest Daily Deal played at the level, as "date|points".
+const prefDailyBestPrefix = "dailyBest."
+
+// dailyDate returns the day of the Daily Deal at t. Days are counted in UTC,
+// so the deal changes at the same moment for every player.
+func dailyDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// dailySeed returns the seed the Daily Deal of the date is shuffled with.
+// It is hashed from the date, so it is not a seed anyone would type.
+func dailySeed(date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("pishti daily deal " + date))
+	return int64(h.Sum64() >> 1)
+}
+
+// startDailyDeal starts today's Daily Deal at the selected level, dealt the
+// same for everyone, so scores can be compared.
+func (ui *AppUI) startDailyDeal() {
+	date := dailyDate(time.Now())
+	if !ui.startGameWith(func() bool { return ui.loop.StartGameWithSeed(dailySeed(date)) }) {
+		return
+	}
+	ui.daily = date
+	ui.view.info.Set(Tf("Daily Deal of %s: everyone gets these cards today.", date))
+}
+
+// dailyResult records the points of a finished Daily Deal and returns the
+// line telling them, with the best of the day at the level.
+func (ui *AppUI) dailyResult(points int) string {
+	prefs := fyne.CurrentApp().Preferences()
+	level := levelName(ui.casino.Level())
+	key := prefDailyBestPrefix + level
+	best := points
+	if date, p, ok := strings.Cut(prefs.String(key), "|"); ok && date == ui.daily {
+		if n, err := strconv.Atoi(p); err == nil {
+			best = max(best, n)
+		}
+	}
+	prefs.SetString(key, fmt.Sprintf("%s|%d", ui.daily, best))
+	return "\n" + Tf("Daily Deal of %s at %s: %d points, your best today %d.", ui.daily, level, points, best)
+}
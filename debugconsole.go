@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// debugShortcut toggles the debug console.
+var debugShortcut = &desktop.CustomShortcut{
+	KeyName:  fyne.KeyD,
+	Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift,
+}
+
+const debugEventLines = 12 // Number of recent engine events kept for the console.
+
+// debugConsole is a hidden overlay for reproducing reported problems. It
+// shows the engine's state dump and the latest events, and accepts a few
+// commands to deal, replay a seed or look at the CPU's cards.
+type debugConsole struct {
+	ui      *AppUI
+	overlay *fyne.Container
+	state   *widget.Label
+	events  *widget.Label
+	output  *widget.Label
+	input   *widget.Entry
+	recent  []string
+}
+
+// newDebugConsole builds the overlay; it stays hidden until toggled.
+func newDebugConsole(ui *AppUI) *debugConsole {
+	d := &debugConsole{ui: ui}
+	mono := fyne.TextStyle{Monospace: true}
+	d.state = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	d.state.Wrapping = fyne.TextWrapWord
+	d.events = widget.NewLabelWithStyle("", fyne.TextAlignLeading, mono)
+	d.output = widget.NewLabelWithStyle("Type \"help\" for commands.", fyne.TextAlignLeading, mono)
+	d.output.Wrapping = fyne.TextWrapWord
+	d.input = widget.NewEntry()
+	d.input.SetPlaceHolder("Command")
+	d.input.OnSubmitted = func(line string) {
+		d.input.SetText("")
+		d.output.SetText(d.run(line))
+		d.refresh()
+	}
+	title := widget.NewLabelWithStyle("Debug Console", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), d.toggle)
+	header := container.NewBorder(nil, nil, nil, closeButton, title)
+	body := container.NewVScroll(container.NewVBox(d.state, widget.NewSeparator(), d.events))
+	footer := container.NewVBox(d.output, d.input)
+	shade := canvas.NewRectangle(color.NRGBA{A: 220})
+	d.overlay = container.NewStack(shade, container.NewBorder(header, footer, nil, nil, body))
+	d.overlay.Hide()
+	return d
+}
+
+// toggle shows or hides the console.
+func (d *debugConsole) toggle() {
+	if d.overlay.Visible() {
+		d.overlay.Hide()
+		return
+	}
+	d.refresh()
+	d.overlay.Show()
+	d.ui.window.Canvas().Focus(d.input)
+}
+
+// record remembers the latest events, whether or not the console is open.
+func (d *debugConsole) record(lines []string) {
+	d.recent = append(d.recent, lines...)
+	if extra := len(d.recent) - debugEventLines; extra > 0 {
+		d.recent = d.recent[extra:]
+	}
+	if d.overlay.Visible() {
+		d.refresh()
+	}
+}
+
+// refresh redraws the state dump and the event list.
+func (d *debugConsole) refresh() {
+	d.state.SetText(d.ui.casino.DebugDump())
+	d.events.SetText("Recent events:\n" + strings.Join(d.recent, "\n"))
+}
+
+// run carries out a console command and returns the text to show.
+func (d *debugConsole) run(line string) string {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) > 1 && fields[0] == "set" {
+		fields = fields[1:] // "set seed 42" is the same as "seed 42".
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	switch fields[0] {
+	case "help":
+		return "deal - start a new game with a fresh seed\n" +
+			"seed N - start a new game dealt from seed N\n" +
+			"reveal - show or hide the CPU's cards\n" +
+			"validate - check the engine's invariants"
+	case "deal":
+		if !d.ui.startGameWith(d.ui.loop.StartGame) {
+			return "Select a level first."
+		}
+		return fmt.Sprintf("Dealt seed %d.", d.ui.casino.Seed())
+	case "seed":
+		if len(fields) != 2 {
+			return "Usage: seed N"
+		}
+		seed, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Sprintf("Invalid seed %q.", fields[1])
+		}
+		if !d.ui.startGameWith(func() bool { return d.ui.loop.StartGameWithSeed(seed) }) {
+			return "Select a level first."
+		}
+		return fmt.Sprintf("Dealt seed %d.", d.ui.casino.Seed())
+	case "reveal":
+		view := d.ui.view
+		view.setRevealCPU(!view.revealCPU)
+		if view.revealCPU {
+			return "CPU cards shown."
+		}
+		return "CPU cards hidden."
+	case "validate":
+		if err := d.ui.casino.Validate(); err != nil {
+			return "Invalid: " + err.Error()
+		}
+		return "All invariants hold."
+	default:
+		return fmt.Sprintf("Unknown command %q.", fields[0])
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/fsnotify/fsnotify"
+)
+
+// devAssetsDir is the directory read in development mode. Paths under it
+// match the embedded ones, so the game is started from the checkout.
+const devAssetsDir = "assets"
+
+// devReloadDelay collects the burst of events an editor makes when saving
+// a file into a single reload.
+const devReloadDelay = 250 * time.Millisecond
+
+// useDevAssets switches asset loading to the assets directory on disk. It
+// must be called before loadResources.
+func useDevAssets() error {
+	if info, err := os.Stat(devAssetsDir); err != nil || !info.IsDir() {
+		return errors.New("no assets directory in " + workingDir() + "; start the game from the source checkout")
+	}
+	assetFS = os.DirFS(".")
+	return nil
+}
+
+// workingDir returns the current directory for messages.
+func workingDir() string {
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return "the current directory"
+}
+
+// watchAssets reloads images and sounds as they change on disk, so skins and
+// sounds can be tuned without rebuilding. It stops when the app quits.
+func (ui *AppUI) watchAssets() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("ERROR: Failed to watch the assets directory: %v", err)
+		return
+	}
+	// Watches are not recursive, so every directory is added.
+	err = filepath.WalkDir(devAssetsDir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			err = watcher.Add(p)
+		}
+		return err
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to watch the assets directory: %v", err)
+		watcher.Close()
+		return
+	}
+	fyne.CurrentApp().Lifecycle().SetOnStopped(func() { watcher.Close() })
+	log.Printf("Watching %s for changed assets", filepath.Join(workingDir(), devAssetsDir))
+	go func() {
+		changed := make(map[string]bool)
+		var flush <-chan time.Time
+		for {
+			select {
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if e.Has(fsnotify.Write) || e.Has(fsnotify.Create) || e.Has(fsnotify.Rename) {
+					changed[filepath.ToSlash(e.Name)] = true
+					flush = time.After(devReloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("ERROR: Watching assets: %v", err)
+			case <-flush:
+				paths := make([]string, 0, len(changed))
+				for p := range changed {
+					paths = append(paths, p)
+				}
+				clear(changed)
+				flush = nil
+				ui.reloadAssets(paths)
+			}
+		}
+	}()
+}
+
+// reloadAssets loads the changed asset files again and redraws what shows
+// them. Sounds are decoded on the watcher goroutine; the screen is updated
+// on the UI goroutine.
+func (ui *AppUI) reloadAssets(paths []string) {
+	var images bool
+	for _, p := range paths {
+		log.Printf("Reloading %s", p)
+		if strings.HasPrefix(p, devAssetsDir+"/sounds/") {
+			reloadSound(p)
+		} else {
+			images = true
+		}
+	}
+	if images {
+		fyne.Do(ui.reloadImages)
+	}
+}
+
+// reloadImages loads every image again and redraws what shows them, after
+// they changed on disk or another card skin was chosen. It must run on the
+// UI goroutine.
+func (ui *AppUI) reloadImages() {
+	// New names keep Fyne from drawing a changed image from its cache.
+	assetGeneration.Add(1)
+	// The whole cache is dropped: a new SVG replaces a PNG of the same card.
+	cardFaces = newResourceCache(appConfig.UI.CardCacheKB << 10)
+	loadUIArt()
+	for _, frame := range ui.frameImages {
+		frame.Resource = resourceFrame
+		frame.Refresh()
+	}
+	ui.backgroundImage.Resource = resourceBackground
+	ui.backgroundImage.Refresh()
+	ui.window.SetIcon(resourceIcon)
+	ui.view.redraw()
+}
+
+// reloadSound decodes the effect or music track a changed sound file
+// belongs to. A file that fails to decode leaves the old sound in place.
+func reloadSound(p string) {
+	base := strings.TrimSuffix(path.Base(p), path.Ext(p))
+	name := variantBase(base)
+	for effect, n := range soundNames {
+		if n != name {
+			continue
+		}
+		if effect == SoundBackground || effect == SoundMenuMusic {
+			loadMusic(effect, name, soundPackDirs()) // Heard from the next time the track starts.
+		} else {
+			loadRecordings(effect, name, soundPackDirs())
+		}
+		return
+	}
+	log.Printf("ERROR: %s is not used by any sound", p)
+}
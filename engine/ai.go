@@ -0,0 +1,325 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+func init() {
+	RegisterStrategy(Strategy{
+		Level: LevelBeginner, Name: "Beginner", Undo: true,
+		Description: "Takes the pile whenever it can and otherwise plays at random.",
+		Choose:      (*Position).cpuActionBeginner,
+	})
+	RegisterStrategy(Strategy{
+		Level: LevelIntermediate, Name: "Intermediate", Undo: true,
+		Description: "Remembers the cards played in the current hand.",
+		Choose:      (*Position).cpuActionIntermediate,
+	})
+	RegisterStrategy(Strategy{
+		Level: LevelAdvanced, Name: "Advanced", Undo: false,
+		Description: "Remembers every card played in the game. No undo.",
+		Choose:      (*Position).cpuActionAdvanced,
+	})
+	RegisterStrategy(Strategy{
+		Level: LevelExpert, Name: "Expert", Undo: false,
+		Description: "Plays out many guesses at the unseen cards and picks the best card on average. No undo.",
+		Choose:      (*Position).cpuActionExpert,
+	})
+}
+
+// Position is what the CPU knows when it picks a card. Strategies read it
+// and must not change it.
+type Position struct {
+	Hand        Hand    // The CPU's hand; played slots are nil.
+	Table       []*Card // The table pile, top card last.
+	HandPlayed  []*Card // Cards played since the current hand was dealt, oldest first.
+	GamePlayed  []*Card // Cards played since the game began, oldest first.
+	SafeDiscard *Card   // The card under the player's last Jack capture, if any this hand.
+	Rules       Rules   // The rules of the game, for what each card is worth.
+	Rand        *rand.Rand
+	// What a strategy that looks ahead needs to guess the rest of the game,
+	// from the side choosing: the CPU, or the player when it picks for them.
+	Unseen        []*Card       // Cards the side has not seen, in no useful order.
+	Hidden        int           // Cards at the bottom of Table still face down; Unseen holds their faces.
+	OpponentHeld  int           // Cards in the other side's hand.
+	DeckLeft      int           // Cards not dealt yet.
+	Taken         int           // Cards the side has taken, for the majority bonus.
+	OpponentTaken int           // Cards the other side has taken.
+	LastPile      bool          // Whether the side gets the last pile if nobody captures again.
+	Leads         bool          // Whether the side plays first in each new hand, as the player does.
+	ThinkTime     time.Duration // How long the strategy may take; see Casino.SetThinkTime.
+	// Context is done once the move is no longer wanted, as when the game
+	// is restarted; a strategy that takes a while should stop early then.
+	// It is nil for a move nobody will call off.
+	Context context.Context
+}
+
+// position returns the CPU's view of the game. The caller must hold the mutex.
+func (c *Casino) position() Position {
+	return c.positionOf(CPU)
+}
+
+// positionOf returns what the side knows of the game; only the CPU is told
+// of a safe discard. The caller must hold the mutex.
+func (c *Casino) positionOf(side PlayerID) Position {
+	other, taken, opponentTaken := Player, c.cardsCollectedByCPU, c.cardsCollectedByPlayer
+	var safeDiscard *Card
+	if side == CPU {
+		safeDiscard = c.safeDiscardCandidate
+	} else {
+		other, taken, opponentTaken = CPU, opponentTaken, taken
+	}
+	// The CPU also gets the last pile when nobody has captured.
+	lastPile := c.lastScorer == side || (side == CPU && c.lastScorer == NoPlayer)
+	return Position{
+		Hand:          *c.handOf(side),
+		Table:         c.tableCards.Cards(),
+		HandPlayed:    c.currentHandMemory.Cards(),
+		GamePlayed:    c.allPlayedCardsMemory.Cards(),
+		SafeDiscard:   safeDiscard,
+		Rules:         c.rules,
+		Rand:          c.rng,
+		Unseen:        c.unseenBy(side),
+		Hidden:        len(c.initialHiddenCards),
+		OpponentHeld:  c.handOf(other).Len(),
+		DeckLeft:      DeckSize - c.currentCard,
+		Taken:         taken,
+		OpponentTaken: opponentTaken,
+		LastPile:      lastPile,
+		Leads:         side == Player,
+		ThinkTime:     c.thinkTime,
+	}
+}
+
+// stopped reports whether the move is no longer wanted.
+func (p *Position) stopped() bool {
+	return p.Context != nil && p.Context.Err() != nil
+}
+
+// sameAs reports whether q is the position p was taken in: the same hand,
+// table and cards played.
+func (p *Position) sameAs(q *Position) bool {
+	if p.Hand != q.Hand || len(p.Table) != len(q.Table) || len(p.GamePlayed) != len(q.GamePlayed) || p.DeckLeft != q.DeckLeft {
+		return false
+	}
+	for i := range p.Table {
+		if p.Table[i] != q.Table[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// top returns the top card of the table, or nil if the table is empty.
+func (p *Position) top() *Card {
+	if len(p.Table) == 0 {
+		return nil
+	}
+	return p.Table[len(p.Table)-1]
+}
+
+// findMatchingCard looks for a card with a specific face in the CPU's hand.
+func (p *Position) findMatchingCard(face Rank) int {
+	for i, card := range p.Hand {
+		if card != nil && card.GetFace() == face {
+			return i
+		}
+	}
+	return -1 // Not found.
+}
+
+// findJack looks for a Jack in the CPU's hand.
+func (p *Position) findJack() int {
+	for i, card := range p.Hand {
+		if card != nil && card.GetFace() == Jack {
+			return i
+		}
+	}
+	return -1 // Not found.
+}
+
+// findRandomNonJack finds a random card in the CPU's hand that is not a Jack.
+func (p *Position) findRandomNonJack() int {
+	var cardsToPlay []int
+	for i := 0; i < HandSize; i++ {
+		if p.Hand[i] != nil && p.Hand[i].GetFace() != Jack {
+			cardsToPlay = append(cardsToPlay, i)
+		}
+	}
+	if len(cardsToPlay) > 0 {
+		return cardsToPlay[p.Rand.Intn(len(cardsToPlay))] // Use the game's RNG.
+	}
+	return -1 // No non-jack found
+}
+
+// tryCaptureMove checks if the CPU can make a capturing move (match top card or play a Jack).
+func (p *Position) tryCaptureMove() int {
+	if top := p.top(); top != nil {
+		topCardFace := top.GetFace()
+		// Try to match the top card.
+		if cardIdx := p.findMatchingCard(topCardFace); cardIdx != -1 {
+			return cardIdx
+		}
+		// Try to play Jack.
+		if cardIdx := p.findJack(); cardIdx != -1 {
+			return cardIdx
+		}
+	}
+	return -1 // No capture move found.
+}
+
+// trySafeDiscard checks if there's a known safe card to play (based on player's Jack capture).
+func (p *Position) trySafeDiscard() int {
+	if p.SafeDiscard != nil {
+		if cardIdx := p.findMatchingCard(p.SafeDiscard.GetFace()); cardIdx != -1 {
+			return cardIdx
+		}
+	}
+	return -1 // No safe discard move found.
+}
+
+func (p *Position) cpuActionBeginner() int {
+	// Try to make a capturing move.
+	if cardIdx := p.tryCaptureMove(); cardIdx != -1 {
+		return cardIdx
+	}
+	return -1 // No move found. Let the generic fallback in CPUaction handle it.
+}
+
+func (p *Position) cpuActionIntermediate() int {
+	// Try to make a capturing move.
+	if cardIdx := p.tryCaptureMove(); cardIdx != -1 {
+		return cardIdx
+	}
+	// Try to play a known "safe" card.
+	if cardIdx := p.trySafeDiscard(); cardIdx != -1 {
+		return cardIdx
+	}
+	// Find the most common non-Jack card face considering both the CPU's hand
+	// and the cards played in this hand, then discard it.
+	var faceCounts [numRanks]int // Indexed by rank.
+	// Count faces in the CPU's own hand.
+	for _, card := range p.Hand {
+		if card != nil && card.GetFace() != Jack { // Exclude Jacks.
+			faceCounts[card.GetFace()]++
+		}
+	}
+	// Count faces from the current hand memory(Short-term memory).
+	for _, card := range p.HandPlayed {
+		if card.GetFace() != Jack { // Exclude Jacks.
+			faceCounts[card.GetFace()]++
+		}
+	}
+	// Find the most common face among the cards the CPU holds.
+	mostCommonFace := Rank(-1)
+	maxCount := 1 // Only care if a face appears more than once (i.e., is a "safer" discard).
+	for _, card := range p.Hand {
+		if card != nil {
+			if count := faceCounts[card.GetFace()]; count > maxCount {
+				maxCount = count
+				mostCommonFace = card.GetFace()
+			}
+		}
+	}
+	// If a safe discard was found, play it.
+	if mostCommonFace >= 0 {
+		return p.findMatchingCard(mostCommonFace)
+	}
+	return -1 // // No move found. Let the generic fallback in CPUaction handle it.
+}
+
+func (p *Position) cpuActionAdvanced() int {
+	// 1. Try to make a capturing move.
+	if cardIdx := p.tryCaptureMove(); cardIdx != -1 {
+		return cardIdx
+	}
+	// Try to play a known "safe" card.
+	if cardIdx := p.trySafeDiscard(); cardIdx != -1 {
+		return cardIdx
+	}
+	// Play a card that maximizes its "match number" (frequency across all played cards + duplicates in hand).
+	greatestMatchNumber := 0
+	cardToPlay := -1
+	for i := 0; i < HandSize; i++ {
+		matchNumber := 0
+		if p.Hand[i] != nil && p.Hand[i].GetFace() != Jack { // Exclude Jacks.
+			// Count matches in all cards played memory(Long-term memory).
+			for _, played := range p.GamePlayed {
+				if played.GetFace() == p.Hand[i].GetFace() {
+					matchNumber++
+				}
+			}
+			// Count matches in the CPU's own hand.
+			for j := 0; j < HandSize; j++ {
+				if i == j || p.Hand[j] == nil {
+					continue
+				} else if p.Hand[j].GetFace() == p.Hand[i].GetFace() {
+					matchNumber++
+				}
+			}
+			if matchNumber > greatestMatchNumber {
+				greatestMatchNumber = matchNumber
+				cardToPlay = i
+			}
+		}
+	}
+	if greatestMatchNumber != 0 && cardToPlay != -1 {
+		return cardToPlay
+	}
+	// If no strategic move is found, play the least valuable non-Jack card.
+	leastValue := 100 // Start with a high value.
+	cardToPlay = -1
+	for i, card := range p.Hand {
+		if card != nil && card.GetFace() != Jack { // Exclude Jacks.
+			value := p.Rules.CardValue(card)
+			if value < leastValue {
+				leastValue = value
+				cardToPlay = i
+			}
+		}
+	}
+	if cardToPlay != -1 {
+		return cardToPlay
+	}
+	return -1 // No move found. Let the generic fallback in CPUaction handle it.
+}
+
+// CPUaction determines which card the CPU should play, using the strategy
+// registered for the level. The caller must hold the mutex.
+func (c *Casino) CPUaction() int {
+	pos := c.position()
+	return cpuChoice(&pos, c.level)
+}
+
+// cpuChoice returns the slot the level's strategy plays in the position, or
+// a harmless card if it has no preference. It needs no lock: the position is
+// a copy, and its Rand is only used by the goroutine that moves the CPU.
+func cpuChoice(pos *Position, level GameLevel) int {
+	cardIdx := -1
+	if s, ok := strategyFor(level); ok {
+		cardIdx = s.Choose(pos)
+		if pos.Hand.Peek(cardIdx) == nil {
+			cardIdx = -1 // Strategies may only pick a card the CPU holds.
+		}
+	}
+	// If the level-specific logic returns -1 (no strategic move found),
+	// this is the final fallback to play any available card.
+	// Must prioritize playing a non-Jack to avoid wasting a Jack on empty table.
+	if cardIdx == -1 {
+		// First, try to find any random non-Jack card.
+		cardIdx = pos.findRandomNonJack()
+		if cardIdx != -1 {
+			return cardIdx
+		}
+		// If no non-Jacks are found (i.e., the hand is only Jacks), find any card to play.
+		for i, card := range pos.Hand {
+			if card != nil {
+				return i // Return the first available card.
+			}
+		}
+	}
+	// Return the strategic move if one was found.
+	return cardIdx
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// benchLevels lists the CPU levels to benchmark. New levels, such as a
+// search-based one, only need an entry here.
+var benchLevels = []struct {
+	name  string
+	level GameLevel
+}{
+	{"beginner", LevelBeginner},
+	{"intermediate", LevelIntermediate},
+	{"advanced", LevelAdvanced},
+	{"expert", LevelExpert},
+}
+
+// cpuPosition plays a seeded game until plays cards have been played and it
+// is the CPU's turn to choose the next one.
+func cpuPosition(tb testing.TB, level GameLevel, seed int64, plays int) *Casino {
+	tb.Helper()
+	c := newTestCasino(tb, level, seed)
+	for played := 0; ; {
+		if err := c.CheckEndOfHand(); err != nil {
+			tb.Fatal(err)
+		}
+		switch c.State() {
+		case StateGameOver:
+			tb.Fatalf("game over before %d plays", plays)
+		case StatePileCaptured:
+			c.FinalizeCapture()
+		case StatePlayerTurn:
+			if err := c.PlayerPlays(firstHeld(c.PlayerHand())); err != nil {
+				tb.Fatal(err)
+			}
+			played++
+		case StateCPUTurn:
+			if played >= plays {
+				return c
+			}
+			if err := c.CPUPlays(); err != nil {
+				tb.Fatal(err)
+			}
+			played++
+		}
+	}
+}
+
+// BenchmarkCPUAction measures how long the CPU takes to pick a card at the
+// start, middle and end of a game, when its memory of played cards differs.
+func BenchmarkCPUAction(b *testing.B) {
+	positions := []struct {
+		name  string
+		plays int
+	}{
+		{"opening", 1},
+		{"midgame", 23},
+		{"lastHand", 43},
+	}
+	for _, l := range benchLevels {
+		for _, p := range positions {
+			b.Run(fmt.Sprintf("%s/%s", l.name, p.name), func(b *testing.B) {
+				c := cpuPosition(b, l.level, 11, p.plays)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if c.CPUaction() < 0 {
+						b.Fatal("no card chosen")
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkFullGame measures a whole game without pauses, CPU choices included.
+func BenchmarkFullGame(b *testing.B) {
+	for _, l := range benchLevels {
+		b.Run(l.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				playOut(b, newTestCasino(b, l.level, int64(i)))
+			}
+		})
+	}
+}
+
+func TestExpert(t *testing.T) {
+	c := cpuPosition(t, LevelExpert, 11, 9)
+	p := c.position()
+	known := make(map[*Card]bool)
+	for _, card := range append(p.Hand.Cards(), p.GamePlayed...) {
+		known[card] = true
+	}
+	unseen := make(map[*Card]bool)
+	for _, card := range p.Unseen {
+		if known[card] {
+			t.Errorf("%v is held or was played, but counted unseen", card)
+		}
+		unseen[card] = true
+	}
+	for _, card := range c.PlayerHand() {
+		if card != nil && !unseen[card] {
+			t.Errorf("the player's %v counted as seen", card)
+		}
+	}
+	if n := p.Hidden + p.OpponentHeld + p.DeckLeft; len(p.Unseen) < n {
+		t.Errorf("%d cards unseen, fewer than the %d to guess", len(p.Unseen), n)
+	}
+
+	// The Expert makes a Pişti when it can, and without time plays like Advanced.
+	rig(c, []*Card{card(Seven, Hearts)}, []*Card{card(Deuce, Spades)},
+		[]*Card{card(Three, Clubs), card(King, Diamonds), card(Seven, Clubs), card(Deuce, Hearts)})
+	c.gameState = StateCPUTurn
+	p = c.position()
+	if got := p.cpuActionExpert(); got != 2 {
+		t.Errorf("Expert played slot %d, want the Seven in slot 2", got)
+	}
+	p.ThinkTime = 0
+	if got, want := p.cpuActionExpert(), p.cpuActionAdvanced(); got != want {
+		t.Errorf("Expert without time played slot %d, Advanced %d", got, want)
+	}
+	// A move called off stops the search, however long it may take.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.ThinkTime, p.Context = time.Minute, ctx
+	start := time.Now()
+	p.cpuActionExpert()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("the Expert thought for %v about a move called off", d)
+	}
+	c.SetThinkTime(-time.Second)
+	if c.ThinkTime() != 0 {
+		t.Errorf("think time = %v, want 0", c.ThinkTime())
+	}
+}
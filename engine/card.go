@@ -0,0 +1,92 @@
+package engine
+
+import "fmt"
+
+// Rank is the face value of a card. The ranks are in deck order, from Ace up
+// to King.
+type Rank int
+
+const (
+	Ace Rank = iota
+	Deuce
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+	numRanks = iota // Number of ranks in a suit.
+)
+
+// rankNames are the display names of the ranks, indexed by Rank.
+var rankNames = [numRanks]string{"Ace", "Deuce", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King"}
+
+// String returns the rank's display name, e.g. "Deuce".
+func (r Rank) String() string {
+	if r < 0 || r >= numRanks {
+		return fmt.Sprintf("Rank(%d)", int(r))
+	}
+	return rankNames[r]
+}
+
+// Suit is the suit of a card, in deck order.
+type Suit int
+
+const (
+	Hearts Suit = iota
+	Diamonds
+	Clubs
+	Spades
+	numSuits = iota // Number of suits in the deck.
+)
+
+// suitNames are the display names of the suits, indexed by Suit.
+var suitNames = [numSuits]string{"Hearts", "Diamonds", "Clubs", "Spades"}
+
+// String returns the suit's display name, e.g. "Clubs".
+func (s Suit) String() string {
+	if s < 0 || s >= numSuits {
+		return fmt.Sprintf("Suit(%d)", int(s))
+	}
+	return suitNames[s]
+}
+
+type Card struct {
+	face     Rank
+	suit     Suit
+	iconPath string // Stores the icon identifier (e.g., "1") used to look up the card's .png image.
+}
+
+// NewCard is a constructor for the Card struct.
+func NewCard(cardFace Rank, cardSuit Suit, iconPath string) *Card {
+	return &Card{
+		face:     cardFace,
+		suit:     cardSuit,
+		iconPath: iconPath,
+	}
+}
+
+// GetIconPath returns the icon path of the card.
+func (c *Card) GetIconPath() string {
+	return c.iconPath
+}
+
+// GetFace returns the face of the card.
+func (c *Card) GetFace() Rank {
+	return c.face
+}
+
+// GetSuit returns the suit of the card.
+func (c *Card) GetSuit() Suit {
+	return c.suit
+}
+
+// String representation for debugging
+func (c *Card) String() string {
+	return fmt.Sprintf("%s of %s", c.face, c.suit)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import "testing"
+
+func TestCardNames(t *testing.T) {
+	tests := []struct {
+		card *Card
+		want string
+	}{
+		{card(Ace, Hearts), "Ace of Hearts"},
+		{card(Deuce, Clubs), "Deuce of Clubs"},
+		{card(King, Spades), "King of Spades"},
+		{card(Rank(13), Suit(-1)), "Rank(13) of Suit(-1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.card.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDeckOrderMatchesIcons(t *testing.T) {
+	c := NewCasino(nil, nil)
+	// The card images are numbered suit by suit, Ace to King.
+	tests := []struct {
+		index int
+		face  Rank
+		suit  Suit
+		icon  string
+	}{
+		{0, Ace, Hearts, "1"},
+		{12, King, Hearts, "13"},
+		{13, Ace, Diamonds, "14"},
+		{27, Deuce, Clubs, "28"},
+		{51, King, Spades, "52"},
+	}
+	for _, tt := range tests {
+		got := c.deck[tt.index]
+		if got.GetFace() != tt.face || got.GetSuit() != tt.suit || got.GetIconPath() != tt.icon {
+			t.Errorf("deck[%d] = %v with icon %q, want %v of %v with icon %q",
+				tt.index, got, got.GetIconPath(), tt.face, tt.suit, tt.icon)
+		}
+	}
+}
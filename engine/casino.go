@@ -0,0 +1,778 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PlayerID identifies who is taking an action.
+type PlayerID int
+
+const (
+	NoPlayer PlayerID = 0
+	Player   PlayerID = 1
+	CPU      PlayerID = 2
+)
+
+// String returns "player", "CPU" or "nobody".
+func (p PlayerID) String() string {
+	switch p {
+	case Player:
+		return "player"
+	case CPU:
+		return "CPU"
+	}
+	return "nobody"
+}
+
+// GameLevel defines the difficulty levels.
+type GameLevel int
+
+const (
+	LevelNotSelected GameLevel = iota
+	LevelBeginner
+	LevelIntermediate
+	LevelAdvanced
+	LevelExpert
+)
+
+const (
+	DeckSize = 52
+	HandSize = 4
+)
+
+// CaptureEvent records a single pile capture for the on-screen history.
+type CaptureEvent struct {
+	By     PlayerID // Who took the pile.
+	Cards  int      // Number of cards collected.
+	Points int      // Points scored by the capture.
+	Pisti  bool     // True for a standard or Jack Pişti.
+	Jack   bool     // True for a Jack Pişti.
+	Final  bool     // True when the remaining table pile is awarded at the end of the game.
+}
+
+// Move records a single card played, for the move journal.
+type Move struct {
+	By   PlayerID // Who played the card.
+	Card *Card
+	Slot int // Hand slot the card came from.
+}
+
+// String describes the move, e.g. "CPU played Ace of Spades from slot 2".
+func (m Move) String() string {
+	return fmt.Sprintf("%s played %v from slot %d", m.By, m.Card, m.Slot)
+}
+
+// Casino represents the game logic and state.
+// This struct will hold the game state, and methods will implement the game logic.
+type Casino struct {
+	cpuCards               Hand
+	allPlayedCardsMemory   Pile // Long-term memory for Advanced AI, tracking all cards played during the game.
+	currentHandMemory      Pile // Short-term memory for Intermediate/Advanced AI, tracking cards in the current hand.
+	deck                   []*Card
+	playerCards            Hand
+	tableCards             Pile // The table pile, top card last.
+	cardsCollectedByPlayer int  // Total number of cards collected by the player.
+	cardsCollectedByCPU    int  // Total number of cards collected by the CPU.
+	cpuPoint               int  // CPU's current score.
+	currentCard            int  // Index for dealing from the deck.
+	gameState              GameState
+	initialHiddenCards     []*Card   // The three face-down cards at the start of the game.
+	safeDiscardCandidate   *Card     // Card face that is likely safe to discard.
+	initialPileCaptureMsg  string    // Message to show when the initial pile is captured.
+	lastPlayedCPUCardIdx   int       // Index of the CPU card played.
+	lastPlayedPlayerCard   int       // Index of the player card played.
+	lastScorer             PlayerID  // Tracks who made the last capture (Player or CPU).
+	level                  GameLevel // The selected difficulty level.
+	variant                Variant   // The rules selected for the next game.
+	rules                  Rules     // The rules of the current game, fixed when it starts.
+	playerPoint            int       // Player's current score.
+	canUndo                bool
+	isInitialPile          bool
+	history                []turn          // Turns played in the current game, oldest first.
+	pendingTurn            turn            // Commands of the turn in progress.
+	redoTurns              []turn          // Undone turns, most recently undone last.
+	captureHistory         []CaptureEvent  // Every capture of the current game, oldest first.
+	journal                []Move          // Every card played in the current game, oldest first.
+	events                 []Event         // Events not yet collected by Events.
+	rng                    *rand.Rand      // Random number generator instance.
+	rngSource              *countingSource // Source of rng, counted so a saved state can restore it.
+	seeds                  *rand.Rand      // Picks the seed of each new game started with StartGame.
+	clock                  Clock           // Source of time for timestamps and scheduled turns.
+	seed                   int64           // Seed used to shuffle the current game, kept for replaying the same deal.
+	matchTarget            int             // Points that win the matches started from now on; 0 plays single games.
+	match                  matchState      // The match the current game is a deal of.
+	thinkTime              time.Duration   // How long the Expert may think about a move.
+	startedAt              time.Time       // When the current game was started, according to clock.
+	debug                  bool            // Validate after every move; see SetDebug.
+	mu                     sync.Mutex      // Mutex to protect concurrent access to game state.
+}
+
+// NewCasino initializes a new game instance. source picks the seed of each
+// game started with StartGame and clock provides the time; passing the same
+// source and clock makes a whole session repeatable. A nil source is seeded
+// from the current time and a nil clock means SystemClock.
+func NewCasino(source rand.Source, clock Clock) *Casino {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	if source == nil {
+		source = rand.NewSource(clock.Now().UnixNano())
+	}
+	c := &Casino{
+		gameState: StateNotStarted,
+		level:     LevelNotSelected,
+		variant:   Variants()[0],
+		rules:     Variants()[0].Rules,
+		seeds:     rand.New(source),
+		clock:     clock,
+		thinkTime: DefaultThinkTime,
+	}
+	c.rngSource = newCountingSource(c.seeds.Int63())
+	c.rng = rand.New(c.rngSource) // Replaced with a seeded RNG for every game.
+	c.deck = make([]*Card, DeckSize)
+	c.resetDeckOrder()
+	return c
+}
+
+// resetDeckOrder puts the deck back into its canonical, unshuffled order.
+// This guarantees that the same seed always produces the same shuffle.
+func (c *Casino) resetDeckOrder() {
+	for i := 0; i < DeckSize; i++ {
+		c.deck[i] = NewCard(Rank(i%numRanks), Suit(i/numRanks), strconv.Itoa(i+1))
+	}
+}
+
+// shuffle shuffles the deck of cards.
+func (c *Casino) shuffle() {
+	for i := 0; i < DeckSize; i++ {
+		swapped := c.rng.Intn(DeckSize)
+		c.deck[i], c.deck[swapped] = c.deck[swapped], c.deck[i]
+	}
+}
+
+// deal deals 4 cards to the player and 4 to the CPU.
+func (c *Casino) deal() {
+	if c.currentCard >= DeckSize {
+		// This should ideally not happen if CheckEndOfHand correctly sets StateGameOver,
+		// but as a safeguard, prevent out-of-bounds access if the deck is exhausted.
+		return
+	}
+	// Only subsequent hands are dealt as a command; the initial one is part of the start.
+	if c.isInitialPile {
+		c.dealHands()
+		return
+	}
+	c.canUndo = false // Undo never reaches back into the previous hand.
+	c.do(&dealCommand{})
+}
+
+// dealHands gives the next cards of the deck to the player and the CPU.
+func (c *Casino) dealHands() {
+	for i := 0; i < HandSize; i++ {
+		c.playerCards.Push(c.deck[c.currentCard])
+		c.currentCard++
+	}
+	for i := 0; i < HandSize; i++ {
+		c.cpuCards.Push(c.deck[c.currentCard])
+		c.currentCard++
+	}
+}
+
+// StartGame initializes a new game with the next seed from the casino's source.
+func (c *Casino) StartGame() bool {
+	c.mu.Lock()
+	seed := c.seeds.Int63()
+	c.mu.Unlock()
+	return c.StartGameWithSeed(seed)
+}
+
+// StartGameWithSeed initializes a new game whose shuffle and CPU choices are
+// driven by the given seed. Starting twice with the same seed deals the same cards.
+func (c *Casino) StartGameWithSeed(seed int64) bool {
+	c.mu.Lock() // Lock the entire StartGame operation.
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	if !c.startGame(seed) {
+		return false
+	}
+	c.match = matchState{target: c.matchTarget, deal: 1} // Every new game opens a match.
+	return true
+}
+
+// startGame deals the game for the seed. The caller must hold the mutex.
+func (c *Casino) startGame(seed int64) bool {
+	// If a game is already in progress or finished, reset it first.
+	if c.gameState != StateNotStarted {
+		c.resetGameInternal()
+	}
+	if c.level == LevelNotSelected {
+		return false // Cannot start without a level.
+	}
+	c.seed = seed
+	c.rules = c.variant.Rules
+	c.startedAt = c.clock.Now()
+	c.rngSource = newCountingSource(seed)
+	c.rng = rand.New(c.rngSource)
+	c.currentCard = 0 // Crucial: Ensure currentCard is reset before shuffle sets it.
+	c.resetDeckOrder()
+	c.shuffle()
+	// Reset scores and counters.
+	c.playerPoint = 0
+	c.cpuPoint = 0
+	c.cardsCollectedByPlayer = 0
+	c.safeDiscardCandidate = nil
+	c.initialHiddenCards = nil
+	c.cardsCollectedByCPU = 0
+	c.currentHandMemory.Clear()
+	c.allPlayedCardsMemory.Clear()
+	// Set initial game state.
+	c.setState(StatePlayerTurn) // Game starts with the player's turn.
+	c.isInitialPile = true      // This is the initial pile before any move is made.
+	c.lastPlayedCPUCardIdx = -1
+	c.lastPlayedPlayerCard = -1
+	// Deal initial 4 cards to the table.
+	c.tableCards.Clear()
+	for i := 0; i < HandSize; i++ {
+		c.tableCards.Push(c.deck[i])
+	}
+	// Store the initial three "hidden" cards to be revealed later.
+	c.initialHiddenCards = []*Card{c.deck[0], c.deck[1], c.deck[2]}
+	c.currentCard = HandSize // Advance the deck pointer past the 4 table cards.
+	c.deal()                 // Deal player and CPU hands.
+	c.clearHistory()         // The history starts with the first move.
+	c.emit(EventGameStarted, NoPlayer, -1)
+	return true
+}
+
+// ResetGame resets the game to its initial state without starting a new one.
+func (c *Casino) ResetGame() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	c.resetGameInternal()
+	c.emit(EventGameReset, NoPlayer, -1)
+}
+
+// resetGameInternal clears all game-specific state to prepare for a new game.
+// This is an internal helper and assumes the mutex is already held by the caller.
+func (c *Casino) resetGameInternal() {
+	c.setState(StateNotStarted)
+	c.level = LevelNotSelected // Crucial: Reset the selected level.
+	c.cardsCollectedByPlayer = 0
+	c.cardsCollectedByCPU = 0
+	c.cpuPoint = 0
+	c.playerPoint = 0
+	c.lastPlayedCPUCardIdx = -1
+	c.lastPlayedPlayerCard = -1
+	c.lastScorer = NoPlayer
+	c.isInitialPile = false
+	c.canUndo = false
+	c.currentCard = 0 // Crucial: Reset deck pointer.
+	c.safeDiscardCandidate = nil
+	c.initialHiddenCards = nil
+	c.initialPileCaptureMsg = ""
+	c.captureHistory = nil
+	c.journal = nil
+	c.events = nil
+	// Clear the hands, the table and the AI's memory.
+	c.playerCards = Hand{}
+	c.cpuCards = Hand{}
+	c.tableCards.Clear()
+	c.currentHandMemory.Clear()
+	c.allPlayedCardsMemory.Clear()
+	c.clearHistory()
+}
+
+// Seed returns the seed used to shuffle the current (or last finished) game.
+func (c *Casino) Seed() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seed
+}
+
+// RecentCaptures returns up to n of the most recent captures, oldest first.
+func (c *Casino) RecentCaptures(n int) []CaptureEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	start := len(c.captureHistory) - n
+	if start < 0 {
+		start = 0
+	}
+	recent := make([]CaptureEvent, len(c.captureHistory)-start)
+	copy(recent, c.captureHistory[start:])
+	return recent
+}
+
+// CapturesSince returns the captures recorded after the first n, together
+// with the total number of captures in the current game.
+func (c *Casino) CapturesSince(n int) ([]CaptureEvent, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := len(c.captureHistory)
+	if n >= total {
+		return nil, total
+	}
+	events := make([]CaptureEvent, total-n)
+	copy(events, c.captureHistory[n:])
+	return events, total
+}
+
+// State returns the current game state.
+func (c *Casino) State() GameState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gameState
+}
+
+// Level returns the selected difficulty level.
+func (c *Casino) Level() GameLevel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.level
+}
+
+// PlayerHand returns a copy of the player's hand. Played slots are nil.
+func (c *Casino) PlayerHand() []*Card {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.playerCards.Cards()
+}
+
+// CPUHand returns a copy of the CPU's hand. Played slots are nil.
+func (c *Casino) CPUHand() []*Card {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cpuCards.Cards()
+}
+
+// TableCards returns a copy of the table pile, with the top card last.
+func (c *Casino) TableCards() []*Card {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tableCards.Cards()
+}
+
+// PlayerPoints returns the player's score.
+func (c *Casino) PlayerPoints() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.playerPoint
+}
+
+// CPUPoints returns the CPU's score.
+func (c *Casino) CPUPoints() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cpuPoint
+}
+
+// CanUndo reports whether the last pair of plays can be undone.
+func (c *Casino) CanUndo() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.canUndo
+}
+
+// IsInitialPile reports whether the face-down starting pile is still on the
+// table untouched, in which case only its top card is shown.
+func (c *Casino) IsInitialPile() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isInitialPile
+}
+
+// InitialPileCaptureMessage returns the message describing the hidden cards
+// once the player has captured the starting pile, or "" otherwise.
+func (c *Casino) InitialPileCaptureMessage() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initialPileCaptureMsg
+}
+
+// ClearInitialPileCaptureMessage dismisses the initial pile capture message.
+func (c *Casino) ClearInitialPileCaptureMessage() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.initialPileCaptureMsg = ""
+}
+
+// SetLevel sets the game difficulty level, which must be LevelNotSelected
+// or have a registered Strategy.
+func (c *Casino) SetLevel(level GameLevel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := strategyFor(level); ok || level == LevelNotSelected {
+		c.level = level
+	} else {
+		fmt.Println("Invalid level selected.")
+	}
+}
+
+// SetVariant selects the registered rule variant with the given name for
+// the games started from now on. It reports false for an unknown name.
+func (c *Casino) SetVariant(name string) bool {
+	v, ok := variantNamed(name)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.variant = v
+	return true
+}
+
+// Variant returns the name of the rule variant selected for the next game.
+func (c *Casino) Variant() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.variant.Name
+}
+
+// undoAllowed reports whether the current level lets the player take back
+// moves. The caller must hold the mutex.
+func (c *Casino) undoAllowed() bool {
+	s, ok := strategyFor(c.level)
+	return ok && s.Undo
+}
+
+// PlayerPlays plays the card in the given slot of the player's hand. It
+// fails if it is not the player's turn or the slot is empty.
+func (c *Casino) PlayerPlays(playedCardIdx int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	if err := c.requireState(StatePlayerTurn); err != nil {
+		return err
+	}
+	if c.playerCards.Peek(playedCardIdx) == nil {
+		return ErrEmptySlot
+	}
+	defer c.endTurn()
+	c.lastPlayedPlayerCard = playedCardIdx
+	return c.processTurn(Player, playedCardIdx)
+}
+
+// CPUPlays lets the CPU choose and play a card. A capture still awaiting
+// FinalizeCapture is completed first. It fails if it is not the CPU's turn.
+func (c *Casino) CPUPlays() error {
+	return c.CPUPlaysContext(context.Background())
+}
+
+// CPUPlaysContext is CPUPlays for a caller that may stop wanting the move.
+// The strategy chooses without the mutex held, so the game can be read while
+// it thinks, and is told to stop once ctx is done. No card is played then,
+// nor if the game has moved on while the CPU was thinking.
+func (c *Casino) CPUPlaysContext(ctx context.Context) error {
+	pos, level, err := c.cpuTurn()
+	if err != nil || pos == nil {
+		return err
+	}
+	pos.Context = ctx
+	slot := cpuChoice(pos, level)
+	if ctx.Err() != nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	defer c.endTurn()
+	if now := c.position(); c.gameState != StateCPUTurn || !now.sameAs(pos) {
+		return nil // Taken back, restarted or loaded meanwhile.
+	}
+	c.lastPlayedCPUCardIdx = slot
+	c.canUndo = c.undoAllowed() // Levels without undo never take moves back.
+	return c.processTurn(CPU, slot)
+}
+
+// cpuTurn completes a capture still awaiting FinalizeCapture and returns
+// what the CPU knows for its move at the game's level, or nil if it has no
+// card to play.
+func (c *Casino) cpuTurn() (*Position, GameLevel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	err := c.settleCapture()
+	c.endTurn()
+	if err != nil {
+		return nil, LevelNotSelected, err
+	}
+	if err := c.requireState(StateCPUTurn); err != nil {
+		return nil, LevelNotSelected, err
+	}
+	// Check if the CPU has any cards to play. If not, do nothing.
+	// This prevents a crash at the end of a hand.
+	if c.cpuCards.Len() == 0 {
+		return nil, LevelNotSelected, nil
+	}
+	pos := c.position()
+	return &pos, c.level, nil
+}
+
+// OpponentPlays plays the card in the given slot of the CPU's hand for a
+// second person sharing the device, as in a hot-seat game. It fails if it is
+// not the CPU's turn or the slot is empty.
+func (c *Casino) OpponentPlays(slot int) error {
+	if slot < 0 {
+		return ErrEmptySlot
+	}
+	return c.cpuPlays(slot)
+}
+
+// cpuPlays plays the CPU's card in the given slot, as when a saved game is
+// replayed.
+func (c *Casino) cpuPlays(slot int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	defer c.endTurn()
+	if err := c.settleCapture(); err != nil {
+		return err
+	}
+	if err := c.requireState(StateCPUTurn); err != nil {
+		return err
+	}
+	// Check if the CPU has any cards to play. If not, do nothing.
+	// This prevents a crash at the end of a hand.
+	if c.cpuCards.Len() == 0 {
+		return nil
+	}
+	if c.cpuCards.Peek(slot) == nil {
+		return ErrEmptySlot
+	}
+	c.lastPlayedCPUCardIdx = slot
+	c.canUndo = c.undoAllowed() // Levels without undo never take moves back.
+	return c.processTurn(CPU, c.lastPlayedCPUCardIdx)
+}
+
+// processTurn plays the card in a hand slot, for either the player or CPU,
+// and scores any capture it makes.
+func (c *Casino) processTurn(playerID PlayerID, slot int) error {
+	if c.handOf(playerID).Peek(slot) == nil {
+		return ErrEmptySlot
+	}
+	c.do(&playCardCommand{by: playerID, slot: slot})
+	// Check for scoring.
+	if c.tableCards.Len() > 1 {
+		topCardOnTable := c.tableCards.Peek(0)
+		secondToTopCard := c.tableCards.Peek(1)
+		if topCardOnTable.GetFace() == secondToTopCard.GetFace() || topCardOnTable.GetFace() == Jack {
+			capture := &captureCommand{slot: slot, event: CaptureEvent{By: playerID}}
+			// If player captures with a Jack, the card underneath is a safe discard candidate for the AI.
+			if playerID == Player && topCardOnTable.GetFace() == Jack {
+				capture.safeDiscard = secondToTopCard
+			}
+			// If the initial hidden cards are still being tracked, this capture is for the initial pile.
+			if c.initialHiddenCards != nil {
+				capture.revealsHidden = true
+				// Only show the message if the player is the one capturing.
+				if playerID == Player {
+					capture.message = fmt.Sprintf("You captured the hidden cards: %s, %s, and %s!",
+						c.initialHiddenCards[0].GetFace(), c.initialHiddenCards[1].GetFace(), c.initialHiddenCards[2].GetFace())
+				}
+			}
+			if c.tableCards.Len() == 2 && topCardOnTable.GetFace() == secondToTopCard.GetFace() {
+				capture.event.Pisti = true
+				capture.event.Cards = 2
+				if topCardOnTable.GetFace() == Jack {
+					capture.event.Jack = true
+					capture.event.Points = c.rules.JackPistiPoints // 20 under the standard house rule.
+				} else {
+					capture.event.Points = c.rules.PistiPoints
+				}
+			} else {
+				// Normal pile collection.
+				capture.event.Points = c.pointCalculator()
+				capture.event.Cards = c.tableCards.Len()
+			}
+			c.do(capture)
+			// Instead of clearing the table immediately, set a new state
+			// to allow the UI to show the captured pile for a moment.
+			return c.setState(StatePileCaptured) // Return early to prevent gameState from being overwritten.
+		}
+	}
+	// If no capture, set the turn to the other player.
+	if playerID == Player {
+		return c.setState(StateCPUTurn)
+	}
+	return c.setState(StatePlayerTurn)
+}
+
+// FinalizeCapture completes a capture by clearing the table. Front ends call
+// it after pausing in StatePileCaptured so the captured pile can be seen.
+// Outside that state it does nothing, so a late call is harmless.
+func (c *Casino) FinalizeCapture() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	defer c.endTurn()
+	if err := c.settleCapture(); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+	}
+}
+
+// settleCapture clears a captured pile and passes the turn to the other side.
+// It does nothing unless the game is in StatePileCaptured. The caller must
+// hold the mutex.
+func (c *Casino) settleCapture() error {
+	if c.gameState != StatePileCaptured {
+		return nil
+	}
+	c.do(&clearTableCommand{by: c.lastScorer})
+	// Do not clear the initialPileCaptureMsg here. It should persist until the player's next move.
+	if c.lastScorer == Player {
+		return c.setState(StateCPUTurn)
+	}
+	return c.setState(StatePlayerTurn)
+}
+
+// IsHandFinished reports whether both hands have been played out.
+func (c *Casino) IsHandFinished() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isHandFinished()
+}
+
+// isHandFinished is a read-only helper to check if the current hand is over.
+func (c *Casino) isHandFinished() bool {
+	// Both hands are checked: after a capture with the player's last card the
+	// CPU still holds its final card until the capture has been settled.
+	// This is an internal helper; the caller must hold the mutex.
+	return c.playerCards.Len() == 0 && c.cpuCards.Len() == 0
+}
+
+// CheckEndOfHand deals a new hand once both hands are empty, or ends the game
+// when the deck is exhausted. A capture still awaiting FinalizeCapture is
+// completed first, so the captured cards are never awarded twice. Calling it
+// again after the game is over does nothing.
+func (c *Casino) CheckEndOfHand() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	defer c.endTurn()
+	if c.gameState == StateGameOver {
+		return nil
+	}
+	if err := c.settleCapture(); err != nil {
+		return err
+	}
+	if err := c.requireState(StatePlayerTurn, StateCPUTurn); err != nil {
+		return err
+	}
+	// isHandFinished() checks if both players have run out of cards.
+	if !c.isHandFinished() {
+		return nil
+	}
+	if c.currentCard == DeckSize {
+		return c.handleEndOfGame()
+	}
+	return c.handleEndOfHand()
+}
+
+// handleEndOfHand is called when a hand is over but the deck is not empty.
+// It deals a new hand and continues the game. Assumes caller holds the mutex.
+func (c *Casino) handleEndOfHand() error {
+	c.deal()
+	return c.setState(StatePlayerTurn) // Resume play.
+}
+
+// handleEndOfGame is called when the final hand is played and the deck is empty.
+// It finalizes scores and sets the game state to GameOver. Assumes caller holds the mutex.
+func (c *Casino) handleEndOfGame() error {
+	c.awardFinalPile()
+	// Award the card count bonus after the final pile is collected.
+	// If it's a tie (26-26), no one gets points.
+	if bonus := c.rules.MajorityPoints; bonus > 0 {
+		if c.cardsCollectedByPlayer > c.cardsCollectedByCPU {
+			c.do(&bonusCommand{by: Player, points: bonus})
+		} else if c.cardsCollectedByCPU > c.cardsCollectedByPlayer {
+			c.do(&bonusCommand{by: CPU, points: bonus})
+		}
+	}
+	c.emit(EventScoreChanged, NoPlayer, -1)
+	return c.setState(StateGameOver)
+}
+
+// awardFinalPile gives the remaining cards on the table to the last player
+// who scored, unless the rules leave them there for nobody. This is an
+// internal helper that assumes the caller holds the mutex.
+func (c *Casino) awardFinalPile() {
+	cards := c.tableCards.Len()
+	if cards == 0 || c.rules.DiscardLastPile {
+		return // Nothing to award.
+	}
+	receiver := CPU             // CPU or no scorer yet (CPU gets it by default).
+	if c.lastScorer == Player { // Player gets the last pile.
+		receiver = Player
+	}
+	c.do(&captureCommand{slot: -1, event: CaptureEvent{
+		By: receiver, Cards: cards, Points: c.pointCalculator(), Final: true,
+	}})
+	c.do(&clearTableCommand{by: receiver})
+}
+
+// Undo reverts the last two plays (player and CPU). It reports false when
+// there is nothing to undo.
+func (c *Casino) Undo() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	if !c.canUndo || c.requireState(StatePlayerTurn, StateCPUTurn) != nil {
+		// Cannot undo if a turn hasn't been played, or while a capture is shown.
+		return false
+	}
+	before := c.gameState
+	// Going back to the player's last play reverts the CPU's reply with it.
+	for len(c.history) > 0 {
+		if p := c.undoTurn().play(); p != nil && p.by == Player {
+			break
+		}
+	}
+	// An undo can only be performed once per turn.
+	c.canUndo = false
+	c.emit(EventUndone, NoPlayer, -1)
+	if c.gameState != before {
+		c.emit(EventStateChanged, NoPlayer, -1)
+	}
+	return true
+}
+
+// CanRedo reports whether plays taken back by Undo can be made again.
+func (c *Casino) CanRedo() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.redoTurns) > 0
+}
+
+// Redo makes the plays taken back by the last Undo again, as they were
+// first made. Any other move in between forgets them. It reports false when
+// there is nothing to redo.
+func (c *Casino) Redo() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	if len(c.redoTurns) == 0 {
+		return false
+	}
+	before, events := c.gameState, len(c.events)
+	c.redoTurn()
+	// Stop where the player is to move again, as Undo did.
+	for len(c.redoTurns) > 0 {
+		if p := c.redoTurns[len(c.redoTurns)-1].play(); p != nil && p.by == Player {
+			break
+		}
+		c.redoTurn()
+	}
+	// The plays were reported when first made; one event says they are back.
+	c.events = c.events[:events]
+	c.canUndo = c.undoAllowed()
+	c.emit(EventRedone, NoPlayer, -1)
+	if c.gameState != before {
+		c.emit(EventStateChanged, NoPlayer, -1)
+	}
+	return true
+}
@@ -0,0 +1,637 @@
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// card returns a card for hand-built test positions.
+func card(face Rank, suit Suit) *Card {
+	return NewCard(face, suit, "")
+}
+
+// newTestCasino starts a game at the given level with a fixed seed and drops
+// the opening events.
+func newTestCasino(t testing.TB, level GameLevel, seed int64) *Casino {
+	t.Helper()
+	c := NewCasino(rand.NewSource(seed), nil)
+	c.SetLevel(level)
+	if !c.StartGameWithSeed(seed) {
+		t.Fatal("StartGameWithSeed failed")
+	}
+	c.Events()
+	return c
+}
+
+// setTable replaces the table pile, bottom card first.
+func setTable(c *Casino, cards ...*Card) {
+	c.tableCards.Clear()
+	for _, card := range cards {
+		c.tableCards.Push(card)
+	}
+}
+
+// setHand replaces a hand. Slots without a card, or given as nil, are empty.
+func setHand(hand *Hand, cards ...*Card) {
+	*hand = Hand{}
+	copy(hand[:], cards)
+}
+
+// rig sets up a position after the opening pile with the player to move.
+func rig(c *Casino, table, player, cpu []*Card) {
+	c.isInitialPile = false
+	c.initialHiddenCards = nil
+	setTable(c, table...)
+	setHand(&c.playerCards, player...)
+	setHand(&c.cpuCards, cpu...)
+	c.gameState = StatePlayerTurn
+	c.Events()
+}
+
+// hasEvent reports whether events contains one of the given kind.
+func hasEvent(events []Event, kind EventKind) bool {
+	for _, e := range events {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// sameCards reports whether two card lists hold the same cards in the same order.
+func sameCards(a, b []*Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// playOut plays a game to the end without pauses, the player always playing
+// their first card.
+func playOut(t testing.TB, c *Casino) {
+	t.Helper()
+	for moves := 0; c.State() != StateGameOver; moves++ {
+		if moves > 1000 {
+			t.Fatal("game did not finish")
+		}
+		err := c.CheckEndOfHand()
+		switch c.State() {
+		case StatePlayerTurn:
+			err = errors.Join(err, c.PlayerPlays(firstHeld(c.PlayerHand())))
+		case StateCPUTurn:
+			err = errors.Join(err, c.CPUPlays())
+		case StatePileCaptured:
+			c.FinalizeCapture()
+		}
+		if err != nil {
+			t.Fatalf("move %d: %v", moves, err)
+		}
+	}
+}
+
+// fixedClock is a Clock stopped at one moment.
+type fixedClock struct {
+	now time.Time
+}
+
+func (f fixedClock) Now() time.Time {
+	return f.now
+}
+
+func (f fixedClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return time.AfterFunc(d, fn)
+}
+
+func TestPlayerCaptures(t *testing.T) {
+	tests := []struct {
+		name          string
+		table         []*Card
+		play          *Card
+		wantState     GameState
+		wantEvent     EventKind
+		wantPoints    int
+		wantCollected int
+		wantOnTable   int
+		wantPisti     bool
+		wantJack      bool
+	}{
+		{
+			name:      "empty table",
+			play:      card(Three, Spades),
+			wantState: StateCPUTurn, wantEvent: EventCardPlayed, wantOnTable: 1,
+		},
+		{
+			name:      "no match",
+			table:     []*Card{card(Five, Hearts), card(Nine, Clubs)},
+			play:      card(Three, Spades),
+			wantState: StateCPUTurn, wantEvent: EventCardPlayed, wantOnTable: 3,
+		},
+		{
+			name:      "match below the top card",
+			table:     []*Card{card(Seven, Hearts), card(Nine, Clubs)},
+			play:      card(Seven, Spades),
+			wantState: StateCPUTurn, wantEvent: EventCardPlayed, wantOnTable: 3,
+		},
+		{
+			name:      "matching face takes the pile",
+			table:     []*Card{card(Ten, Diamonds), card(Five, Clubs), card(Ace, Hearts)},
+			play:      card(Ace, Spades),
+			wantState: StatePileCaptured, wantEvent: EventCapture,
+			wantPoints: 5, wantCollected: 4, wantOnTable: 4,
+		},
+		{
+			name:      "jack takes the pile",
+			table:     []*Card{card(Deuce, Clubs), card(Seven, Hearts)},
+			play:      card(Jack, Spades),
+			wantState: StatePileCaptured, wantEvent: EventCapture,
+			wantPoints: 3, wantCollected: 3, wantOnTable: 3,
+		},
+		{
+			name:      "jack on a single card is not a pişti",
+			table:     []*Card{card(Seven, Hearts)},
+			play:      card(Jack, Clubs),
+			wantState: StatePileCaptured, wantEvent: EventCapture,
+			wantPoints: 1, wantCollected: 2, wantOnTable: 2,
+		},
+		{
+			name:      "pişti",
+			table:     []*Card{card(Seven, Hearts)},
+			play:      card(Seven, Spades),
+			wantState: StatePileCaptured, wantEvent: EventPisti,
+			wantPoints: 10, wantCollected: 2, wantOnTable: 2, wantPisti: true,
+		},
+		{
+			name:      "jack pişti",
+			table:     []*Card{card(Jack, Hearts)},
+			play:      card(Jack, Spades),
+			wantState: StatePileCaptured, wantEvent: EventJackPisti,
+			wantPoints: 20, wantCollected: 2, wantOnTable: 2, wantPisti: true, wantJack: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, LevelBeginner, 1)
+			rig(c, tt.table, []*Card{tt.play}, nil)
+			if err := c.PlayerPlays(0); err != nil {
+				t.Fatalf("PlayerPlays: %v", err)
+			}
+			if c.gameState != tt.wantState {
+				t.Errorf("state = %s, want %s", c.gameState, tt.wantState)
+			}
+			if !hasEvent(c.Events(), tt.wantEvent) {
+				t.Errorf("no event of kind %d", tt.wantEvent)
+			}
+			if c.playerPoint != tt.wantPoints {
+				t.Errorf("points = %d, want %d", c.playerPoint, tt.wantPoints)
+			}
+			if c.cardsCollectedByPlayer != tt.wantCollected {
+				t.Errorf("cards collected = %d, want %d", c.cardsCollectedByPlayer, tt.wantCollected)
+			}
+			if c.tableCards.Len() != tt.wantOnTable {
+				t.Errorf("cards on table = %d, want %d", c.tableCards.Len(), tt.wantOnTable)
+			}
+			if tt.wantState != StatePileCaptured {
+				if len(c.captureHistory) != 0 {
+					t.Errorf("capture recorded without a capture: %+v", c.captureHistory)
+				}
+				return
+			}
+			if len(c.captureHistory) != 1 {
+				t.Fatalf("%d captures recorded, want 1", len(c.captureHistory))
+			}
+			want := CaptureEvent{By: Player, Cards: tt.wantCollected, Points: tt.wantPoints, Pisti: tt.wantPisti, Jack: tt.wantJack}
+			if got := c.captureHistory[0]; got != want {
+				t.Errorf("capture = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCPUCaptureScoresForCPU(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 1)
+	rig(c, []*Card{card(Seven, Hearts)}, nil, []*Card{card(Seven, Spades)})
+	c.gameState = StateCPUTurn
+	if err := c.CPUPlays(); err != nil {
+		t.Fatalf("CPUPlays: %v", err)
+	}
+	if c.cpuPoint != 10 || c.cardsCollectedByCPU != 2 || c.playerPoint != 0 {
+		t.Errorf("CPU points/cards = %d/%d, player points = %d; want 10/2, 0",
+			c.cpuPoint, c.cardsCollectedByCPU, c.playerPoint)
+	}
+	if c.lastScorer != CPU {
+		t.Errorf("last scorer = %d, want CPU", c.lastScorer)
+	}
+}
+
+func TestFinalizeCapturePassesTurn(t *testing.T) {
+	tests := []struct {
+		scorer PlayerID
+		want   GameState
+	}{
+		{Player, StateCPUTurn},
+		{CPU, StatePlayerTurn},
+	}
+	for _, tt := range tests {
+		c := newTestCasino(t, LevelBeginner, 1)
+		rig(c, []*Card{card(Four, Clubs), card(Seven, Hearts)}, nil, nil)
+		c.gameState, c.lastScorer = StatePileCaptured, tt.scorer
+		c.FinalizeCapture()
+		if c.gameState != tt.want {
+			t.Errorf("scorer %d: state = %s, want %s", tt.scorer, c.gameState, tt.want)
+		}
+		if c.tableCards.Len() != 0 {
+			t.Errorf("scorer %d: %d cards left on the table", tt.scorer, c.tableCards.Len())
+		}
+		if events := c.Events(); !slices.ContainsFunc(events, func(e Event) bool { return e.Kind == EventPileCleared && e.By == tt.scorer }) {
+			t.Errorf("scorer %d: no EventPileCleared by the scorer in %v", tt.scorer, events)
+		}
+		// A second call is ignored.
+		c.FinalizeCapture()
+		if c.gameState != tt.want || len(c.Events()) != 0 {
+			t.Errorf("scorer %d: repeated FinalizeCapture changed the game", tt.scorer)
+		}
+	}
+}
+
+func TestMoveErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(c *Casino)
+		action func(c *Casino) error
+		want   error
+	}{
+		{
+			name:   "player moves during the CPU's turn",
+			setup:  func(c *Casino) { c.gameState = StateCPUTurn },
+			action: func(c *Casino) error { return c.PlayerPlays(0) },
+			want:   ErrWrongState,
+		},
+		{
+			name:   "player plays an empty slot",
+			setup:  func(c *Casino) { rig(c, nil, []*Card{nil, card(Five, Hearts)}, nil) },
+			action: func(c *Casino) error { return c.PlayerPlays(0) },
+			want:   ErrEmptySlot,
+		},
+		{
+			name:   "player plays a slot outside the hand",
+			setup:  func(c *Casino) {},
+			action: func(c *Casino) error { return c.PlayerPlays(HandSize) },
+			want:   ErrEmptySlot,
+		},
+		{
+			name:   "CPU moves during the player's turn",
+			setup:  func(c *Casino) {},
+			action: func(c *Casino) error { return c.CPUPlays() },
+			want:   ErrWrongState,
+		},
+		{
+			name:   "player moves before the game starts",
+			setup:  func(c *Casino) { c.ResetGame() },
+			action: func(c *Casino) error { return c.PlayerPlays(0) },
+			want:   ErrWrongState,
+		},
+		{
+			name:   "hand checked before the game starts",
+			setup:  func(c *Casino) { c.ResetGame() },
+			action: func(c *Casino) error { return c.CheckEndOfHand() },
+			want:   ErrWrongState,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, LevelBeginner, 1)
+			tt.setup(c)
+			if err := tt.action(c); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndoRestoresPosition(t *testing.T) {
+	tests := []struct {
+		name               string
+		table, player, cpu []*Card
+	}{
+		{
+			name:   "plain plays",
+			table:  []*Card{card(Five, Hearts), card(Nine, Clubs)},
+			player: []*Card{card(Three, Spades), card(Eight, Diamonds)},
+			cpu:    []*Card{card(King, Hearts), card(Queen, Diamonds)},
+		},
+		{
+			name:   "player capture",
+			table:  []*Card{card(Ten, Diamonds), card(Ace, Hearts)},
+			player: []*Card{card(Ace, Spades), card(Eight, Diamonds)},
+			cpu:    []*Card{card(King, Hearts), card(Queen, Diamonds)},
+		},
+		{
+			name:   "CPU pişti",
+			player: []*Card{card(Eight, Diamonds), card(Three, Spades)},
+			cpu:    []*Card{card(Eight, Clubs), card(King, Hearts)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, LevelBeginner, 1)
+			rig(c, tt.table, tt.player, tt.cpu)
+			before := c.Snapshot()
+			if err := c.PlayerPlays(0); err != nil {
+				t.Fatalf("PlayerPlays: %v", err)
+			}
+			if err := c.CPUPlays(); err != nil {
+				t.Fatalf("CPUPlays: %v", err)
+			}
+			c.FinalizeCapture()
+			c.Events()
+			if moves := c.Snapshot().Moves[len(before.Moves):]; len(moves) != 2 || moves[0].By != Player || moves[0].Slot != 0 || moves[1].By != CPU {
+				t.Errorf("journal recorded %v, want the player's and the CPU's card", moves)
+			}
+			if !c.Undo() {
+				t.Fatal("Undo refused")
+			}
+			after := c.Snapshot()
+			if after.State != StatePlayerTurn {
+				t.Errorf("state = %s, want %s", after.State, StatePlayerTurn)
+			}
+			if after.PlayerPoints != before.PlayerPoints || after.CPUPoints != before.CPUPoints {
+				t.Errorf("points = %d/%d, want %d/%d", after.PlayerPoints, after.CPUPoints, before.PlayerPoints, before.CPUPoints)
+			}
+			if after.PlayerCaptured != before.PlayerCaptured || after.CPUCaptured != before.CPUCaptured {
+				t.Errorf("cards collected = %d/%d, want %d/%d", after.PlayerCaptured, after.CPUCaptured, before.PlayerCaptured, before.CPUCaptured)
+			}
+			if !sameCards(after.Table, before.Table) {
+				t.Errorf("table = %v, want %v", after.Table, before.Table)
+			}
+			if !sameCards(after.PlayerHand, before.PlayerHand) || !sameCards(after.CPUHand, before.CPUHand) {
+				t.Errorf("hands = %v/%v, want %v/%v", after.PlayerHand, after.CPUHand, before.PlayerHand, before.CPUHand)
+			}
+			if len(after.Captures) != len(before.Captures) {
+				t.Errorf("%d captures recorded, want %d", len(after.Captures), len(before.Captures))
+			}
+			if len(after.Moves) != len(before.Moves) {
+				t.Errorf("%d moves in the journal, want %d", len(after.Moves), len(before.Moves))
+			}
+			if !hasEvent(c.Events(), EventUndone) {
+				t.Error("no EventUndone")
+			}
+			if c.CanUndo() || c.Undo() {
+				t.Error("the same plays could be undone twice")
+			}
+		})
+	}
+}
+
+func TestUndoRefused(t *testing.T) {
+	tests := []struct {
+		name  string
+		level GameLevel
+		setup func(t *testing.T, c *Casino)
+	}{
+		{
+			name:  "nothing played yet",
+			level: LevelBeginner,
+			setup: func(t *testing.T, c *Casino) {},
+		},
+		{
+			name:  "advanced level",
+			level: LevelAdvanced,
+			setup: func(t *testing.T, c *Casino) {
+				rig(c, nil, []*Card{card(Three, Spades)}, []*Card{card(King, Hearts)})
+				if c.PlayerPlays(0) != nil || c.CPUPlays() != nil {
+					t.Fatal("setup plays failed")
+				}
+			},
+		},
+		{
+			name:  "capture still shown",
+			level: LevelBeginner,
+			setup: func(t *testing.T, c *Casino) {
+				rig(c, []*Card{card(Five, Hearts)}, []*Card{card(Three, Spades)}, []*Card{card(Three, Clubs)})
+				if c.PlayerPlays(0) != nil || c.CPUPlays() != nil {
+					t.Fatal("setup plays failed")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, tt.level, 1)
+			tt.setup(t, c)
+			before := c.Snapshot()
+			if c.Undo() {
+				t.Fatal("Undo succeeded")
+			}
+			if after := c.Snapshot(); after.State != before.State || !sameCards(after.Table, before.Table) {
+				t.Error("refused Undo changed the game")
+			}
+		})
+	}
+}
+
+func TestEndOfHandDealsNextHand(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 1)
+	rig(c, []*Card{card(Five, Hearts)}, []*Card{nil, nil, nil, card(Three, Spades)},
+		[]*Card{nil, nil, nil, card(King, Hearts)})
+	c.currentCard = 12
+	if err := c.PlayerPlays(3); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if err := c.CheckEndOfHand(); err != nil || c.currentCard != 12 {
+		t.Fatalf("dealt while the CPU still held a card (err %v)", err)
+	}
+	if err := c.CPUPlays(); err != nil {
+		t.Fatalf("CPUPlays: %v", err)
+	}
+	c.Events()
+	if err := c.CheckEndOfHand(); err != nil {
+		t.Fatalf("CheckEndOfHand: %v", err)
+	}
+	if c.currentCard != 12+2*HandSize {
+		t.Errorf("deck position = %d, want %d", c.currentCard, 12+2*HandSize)
+	}
+	if firstHeld(c.playerCards[:]) != 0 || firstHeld(c.cpuCards[:]) != 0 || c.isHandFinished() {
+		t.Errorf("hands not dealt: %v / %v", c.playerCards, c.cpuCards)
+	}
+	if c.gameState != StatePlayerTurn {
+		t.Errorf("state = %s, want %s", c.gameState, StatePlayerTurn)
+	}
+	if !hasEvent(c.Events(), EventDeal) {
+		t.Error("no EventDeal")
+	}
+}
+
+func TestCaptureWithLastCardIsSettledBeforeDealing(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 1)
+	rig(c, []*Card{card(Seven, Hearts)}, []*Card{nil, nil, nil, card(Seven, Spades)},
+		[]*Card{nil, nil, nil, card(King, Hearts)})
+	c.currentCard = 12
+	if err := c.PlayerPlays(3); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if c.IsHandFinished() {
+		t.Fatal("hand reported finished while the CPU still holds a card")
+	}
+	if err := c.CheckEndOfHand(); err != nil {
+		t.Fatalf("CheckEndOfHand: %v", err)
+	}
+	if c.gameState != StateCPUTurn || c.tableCards.Len() != 0 || c.currentCard != 12 {
+		t.Errorf("state %s, %d cards on table, deck at %d; want the capture settled and the CPU to move",
+			c.gameState, c.tableCards.Len(), c.currentCard)
+	}
+	if c.playerPoint != 10 || c.cardsCollectedByPlayer != 2 {
+		t.Errorf("points/cards = %d/%d, want 10/2", c.playerPoint, c.cardsCollectedByPlayer)
+	}
+}
+
+func TestEndOfGame(t *testing.T) {
+	tests := []struct {
+		name                     string
+		lastScorer               PlayerID
+		playerCards, cpuCards    int
+		table                    []*Card
+		wantPlayer, wantCPU      int
+		wantFinalBy              PlayerID
+		wantPlayerCardsCollected int
+	}{
+		{
+			name:       "last pile goes to the player",
+			lastScorer: Player, playerCards: 20, cpuCards: 30,
+			table:      []*Card{card(Ace, Hearts), card(Five, Clubs)},
+			wantPlayer: 1, wantCPU: 3, wantFinalBy: Player, wantPlayerCardsCollected: 22,
+		},
+		{
+			name:       "last pile goes to the CPU",
+			lastScorer: CPU, playerCards: 30, cpuCards: 18,
+			table:      []*Card{card(Ten, Diamonds), card(Jack, Clubs), card(Four, Hearts), card(Six, Spades)},
+			wantPlayer: 3, wantCPU: 4, wantFinalBy: CPU, wantPlayerCardsCollected: 30,
+		},
+		{
+			name:       "nobody scored, so the CPU gets the pile",
+			lastScorer: NoPlayer, playerCards: 0, cpuCards: 48,
+			table:      []*Card{card(Deuce, Clubs), card(Three, Hearts), card(Four, Hearts), card(Five, Hearts)},
+			wantPlayer: 0, wantCPU: 5, wantFinalBy: CPU, wantPlayerCardsCollected: 0,
+		},
+		{
+			name:       "a 26-26 tie earns no card bonus",
+			lastScorer: Player, playerCards: 24, cpuCards: 26,
+			table:      []*Card{card(Seven, Hearts), card(Eight, Hearts)},
+			wantPlayer: 0, wantCPU: 0, wantFinalBy: Player, wantPlayerCardsCollected: 26,
+		},
+		{
+			name:       "empty table",
+			lastScorer: CPU, playerCards: 27, cpuCards: 25,
+			wantPlayer: 3, wantCPU: 0, wantPlayerCardsCollected: 27,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, LevelBeginner, 1)
+			rig(c, tt.table, nil, nil)
+			c.currentCard = DeckSize
+			c.lastScorer = tt.lastScorer
+			c.cardsCollectedByPlayer, c.cardsCollectedByCPU = tt.playerCards, tt.cpuCards
+			c.playerPoint, c.cpuPoint = 0, 0
+			c.captureHistory = nil
+			if err := c.CheckEndOfHand(); err != nil {
+				t.Fatalf("CheckEndOfHand: %v", err)
+			}
+			if c.gameState != StateGameOver {
+				t.Fatalf("state = %s, want %s", c.gameState, StateGameOver)
+			}
+			if c.playerPoint != tt.wantPlayer || c.cpuPoint != tt.wantCPU {
+				t.Errorf("score = %d-%d, want %d-%d", c.playerPoint, c.cpuPoint, tt.wantPlayer, tt.wantCPU)
+			}
+			if c.cardsCollectedByPlayer != tt.wantPlayerCardsCollected {
+				t.Errorf("player collected %d cards, want %d", c.cardsCollectedByPlayer, tt.wantPlayerCardsCollected)
+			}
+			if c.cardsCollectedByPlayer+c.cardsCollectedByCPU != DeckSize || c.tableCards.Len() != 0 {
+				t.Errorf("cards collected %d+%d with %d left on the table", c.cardsCollectedByPlayer, c.cardsCollectedByCPU, c.tableCards.Len())
+			}
+			if len(tt.table) == 0 {
+				if len(c.captureHistory) != 0 {
+					t.Errorf("final pile recorded for an empty table: %+v", c.captureHistory)
+				}
+			} else if len(c.captureHistory) != 1 || !c.captureHistory[0].Final || c.captureHistory[0].By != tt.wantFinalBy {
+				t.Errorf("captures = %+v, want one final capture by %d", c.captureHistory, tt.wantFinalBy)
+			}
+			// Checking again once the game is over changes nothing.
+			if err := c.CheckEndOfHand(); err != nil || c.playerPoint != tt.wantPlayer || c.cpuPoint != tt.wantCPU {
+				t.Errorf("repeated CheckEndOfHand changed the score to %d-%d (err %v)", c.playerPoint, c.cpuPoint, err)
+			}
+		})
+	}
+}
+
+func TestSameSeedDealsSameGame(t *testing.T) {
+	c := newTestCasino(t, LevelIntermediate, 99)
+	first := c.Snapshot()
+	c.ResetGame()
+	c.SetLevel(LevelIntermediate)
+	if !c.StartGameWithSeed(99) {
+		t.Fatal("restart failed")
+	}
+	second := c.Snapshot()
+	if first.Seed != 99 || second.Seed != 99 {
+		t.Errorf("seeds = %d, %d; want 99", first.Seed, second.Seed)
+	}
+	for i := range first.PlayerHand {
+		if first.PlayerHand[i].String() != second.PlayerHand[i].String() ||
+			first.CPUHand[i].String() != second.CPUHand[i].String() ||
+			first.Table[i].String() != second.Table[i].String() {
+			t.Fatalf("deals differ: %v %v %v / %v %v %v", first.PlayerHand, first.CPUHand, first.Table,
+				second.PlayerHand, second.CPUHand, second.Table)
+		}
+	}
+}
+
+func TestInjectedSourceAndClock(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var seeds [2][]int64
+	for i := range seeds {
+		c := NewCasino(rand.NewSource(7), fixedClock{start})
+		for game := 0; game < 3; game++ {
+			c.ResetGame()
+			c.SetLevel(LevelBeginner)
+			if !c.StartGame() {
+				t.Fatal("StartGame failed")
+			}
+			s := c.Snapshot()
+			if !s.StartedAt.Equal(start) {
+				t.Errorf("StartedAt = %v, want %v", s.StartedAt, start)
+			}
+			seeds[i] = append(seeds[i], s.Seed)
+		}
+	}
+	for game := range seeds[0] {
+		if seeds[0][game] != seeds[1][game] {
+			t.Fatalf("same source picked seeds %v and %v", seeds[0], seeds[1])
+		}
+	}
+	if seeds[0][0] == seeds[0][1] {
+		t.Errorf("consecutive games reused seed %d", seeds[0][0])
+	}
+}
+
+func TestFullGamesCollectWholeDeck(t *testing.T) {
+	for _, level := range []GameLevel{LevelBeginner, LevelIntermediate, LevelAdvanced} {
+		for seed := int64(0); seed < 20; seed++ {
+			c := newTestCasino(t, level, seed)
+			c.SetDebug(true)
+			playOut(t, c)
+			s := c.Snapshot()
+			if s.PlayerCaptured+s.CPUCaptured != DeckSize || s.CardsDealt != DeckSize || len(s.Table) != 0 {
+				t.Errorf("level %d seed %d: collected %d+%d, dealt %d, %d left on the table",
+					level, seed, s.PlayerCaptured, s.CPUCaptured, s.CardsDealt, len(s.Table))
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package engine
+
+import "time"
+
+// Clock is the engine's source of time. The desktop game uses SystemClock;
+// tests and replays can supply a clock they advance by hand, so timestamps
+// and scheduled turns happen exactly when the test says so.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f in its own goroutine once d has passed.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call created by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from running and reports whether it was still pending.
+	Stop() bool
+}
+
+// SystemClock is the Clock backed by the time package.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc wraps time.AfterFunc.
+func (SystemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
@@ -0,0 +1,23 @@
+// Package engine implements the rules of Pişti, the CPU opponent and the game
+// flow, independent of any user interface or audio. A front end drives a
+// Casino by starting a game, playing the player's cards, letting the CPU move
+// and reading back the state to display. Interactive front ends hand the
+// Casino to a Loop, which carries out every change on one goroutine and
+// paces the CPU's moves. Either way the front end learns what happened from
+// Events, collected after each call or from the callback a Loop makes after
+// each step of its own.
+//
+// Every change a move makes is a reversible command kept in the game's
+// history, so Undo and Redo step back and forth through whole turns.
+//
+// The CPU levels and the rule variants are looked up in registries; the
+// built-in ones register themselves at init, and RegisterStrategy and
+// RegisterVariant let other packages add more.
+//
+// Casino.Save writes a game in a versioned format, and Load migrates saves
+// from earlier releases before replaying them, so stored games keep loading
+// as the engine's internals change. Verify replays a save and checks that it
+// still ends with the recorded scores and StateHash. For an exact copy of a
+// game, down to the CPU's memory and the move history, a Casino encodes
+// itself with encoding/json or encoding/gob.
+package engine
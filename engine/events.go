@@ -0,0 +1,89 @@
+package engine
+
+import "fmt"
+
+// EventKind names something that happened in the game that a front end may
+// want to react to, for example with a sound or an animation.
+type EventKind int
+
+const (
+	EventDeal         EventKind = iota // A new hand was dealt (not the opening deal).
+	EventCardPlayed                    // A card was played onto the table.
+	EventCapture                       // The table pile was captured without a Pişti.
+	EventPisti                         // A standard Pişti.
+	EventJackPisti                     // A Pişti made with a Jack on a Jack.
+	EventPileCleared                   // The table was emptied after a capture or at the end of the game; By took the cards.
+	EventScoreChanged                  // Scores changed other than by a capture, e.g. the card bonus.
+	EventStateChanged                  // The game moved to another GameState.
+	EventGameStarted                   // A new game was set up; everything changed.
+	EventGameReset                     // The game was cleared back to the start screen.
+	EventUndone                        // The last plays were undone; everything may have changed.
+	EventRedone                        // Undone plays were made again; everything may have changed.
+)
+
+// String returns a short name for the event kind, for logs and debugging.
+func (k EventKind) String() string {
+	switch k {
+	case EventDeal:
+		return "deal"
+	case EventCardPlayed:
+		return "card played"
+	case EventCapture:
+		return "capture"
+	case EventPisti:
+		return "pişti"
+	case EventJackPisti:
+		return "jack pişti"
+	case EventPileCleared:
+		return "pile cleared"
+	case EventScoreChanged:
+		return "score changed"
+	case EventStateChanged:
+		return "state changed"
+	case EventGameStarted:
+		return "game started"
+	case EventGameReset:
+		return "game reset"
+	case EventUndone:
+		return "undone"
+	case EventRedone:
+		return "redone"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event is a single game event. By and Slot tell who played and from which hand
+// slot; they are NoPlayer and -1 for events not tied to a card.
+type Event struct {
+	Kind EventKind
+	By   PlayerID
+	Slot int
+}
+
+// String describes the event, including who played from which slot if known.
+func (e Event) String() string {
+	switch {
+	case e.By == NoPlayer:
+		return e.Kind.String()
+	case e.Slot < 0:
+		return fmt.Sprintf("%s by %s", e.Kind, e.By)
+	default:
+		return fmt.Sprintf("%s by %s from slot %d", e.Kind, e.By, e.Slot)
+	}
+}
+
+// emit queues an event for Events. The caller must hold the mutex.
+func (c *Casino) emit(kind EventKind, by PlayerID, slot int) {
+	c.events = append(c.events, Event{Kind: kind, By: by, Slot: slot})
+}
+
+// Events returns the events that happened since the last call, oldest first,
+// and clears the queue. Front ends call it after driving the game; headless
+// users can simply ignore it.
+func (c *Casino) Events() []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	events := c.events
+	c.events = nil
+	return events
+}
@@ -0,0 +1,39 @@
+package engine_test
+
+import (
+	"fmt"
+
+	"pishti/engine"
+)
+
+// A headless front end plays a whole game by stepping the Casino itself,
+// reading its events back after each step.
+func ExampleCasino() {
+	c := engine.NewCasino(nil, nil)
+	c.SetLevel(engine.LevelIntermediate)
+	c.StartGameWithSeed(42)
+	pistis := 0
+	for c.State() != engine.StateGameOver {
+		c.CheckEndOfHand() // Deals the next hand, or scores the game, once both hands are empty.
+		switch c.State() {
+		case engine.StatePlayerTurn:
+			for slot, card := range c.PlayerHand() {
+				if card != nil {
+					c.PlayerPlays(slot)
+					break
+				}
+			}
+		case engine.StateCPUTurn:
+			c.CPUPlays()
+		case engine.StatePileCaptured:
+			c.FinalizeCapture()
+		}
+		for _, e := range c.Events() {
+			if e.Kind == engine.EventPisti {
+				pistis++
+			}
+		}
+	}
+	fmt.Printf("%s: you %d - %d CPU, %d Piştis\n", c.State(), c.PlayerPoints(), c.CPUPoints(), pistis)
+	// Output: game over: you 19 - 5 CPU, 1 Piştis
+}
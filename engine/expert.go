@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DefaultThinkTime is how long the Expert may think about a move unless
+// SetThinkTime says otherwise.
+const DefaultThinkTime = 300 * time.Millisecond
+
+// The Expert's search: expertSamples guesses at the unseen cards, split into
+// expertBatches so the batches can run side by side and still add up to the
+// same choice on any machine that finishes them in time.
+const (
+	expertSamples = 800
+	expertBatches = 8
+)
+
+// SetThinkTime sets how long the Expert may think about a move. It stops
+// searching early when the time is up and plays the best card found so
+// far; at 0 it plays like Advanced.
+func (c *Casino) SetThinkTime(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.thinkTime = max(d, 0)
+}
+
+// ThinkTime returns how long the Expert may think about a move.
+func (c *Casino) ThinkTime() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.thinkTime
+}
+
+// cpuActionExpert deals the unseen cards at random many times, plays each
+// card of the hand out to the end of the game in every deal, and picks the
+// card with the best point difference on average. The deals respect what
+// the CPU remembers: only cards it has not seen are handed out.
+func (p *Position) cpuActionExpert() int {
+	var slots []int
+	for i, card := range p.Hand {
+		if card != nil {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) < 2 || p.ThinkTime <= 0 {
+		return p.cpuActionAdvanced()
+	}
+	deadline := time.Now().Add(p.ThinkTime)
+	var seeds [expertBatches]int64
+	for i := range seeds {
+		seeds[i] = p.Rand.Int63() // Drawn up front, so the batches do not share the game's RNG.
+	}
+	var (
+		mu      sync.Mutex
+		total   = make([]int, len(slots))
+		samples int
+		wg      sync.WaitGroup
+	)
+	batches := make(chan int64, expertBatches)
+	for _, seed := range seeds {
+		batches <- seed
+	}
+	close(batches)
+	for w := 0; w < min(runtime.GOMAXPROCS(0), expertBatches); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range batches {
+				sums, n := p.searchBatch(slots, rand.New(rand.NewSource(seed)), deadline)
+				mu.Lock()
+				for i, s := range sums {
+					total[i] += s
+				}
+				samples += n
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if samples == 0 {
+		return p.cpuActionAdvanced() // No time for a single deal.
+	}
+	// Every slot was tried in the same deals, so the totals compare as averages.
+	best := 0
+	for i := range slots {
+		if total[i] > total[best] {
+			best = i
+		}
+	}
+	return slots[best]
+}
+
+// searchBatch plays each slot out in its share of the deals, until the
+// deadline or until the move is no longer wanted. It returns the summed
+// point difference per slot and the number of deals.
+func (p *Position) searchBatch(slots []int, r *rand.Rand, deadline time.Time) ([]int, int) {
+	sums := make([]int, len(slots))
+	unseen := append([]*Card(nil), p.Unseen...)
+	n := 0
+	for ; n < expertSamples/expertBatches && time.Now().Before(deadline) && !p.stopped(); n++ {
+		r.Shuffle(len(unseen), func(i, j int) { unseen[i], unseen[j] = unseen[j], unseen[i] })
+		for i, slot := range slots {
+			g := p.guess(unseen)
+			g.play(ownSide, g.removeSlot(slot))
+			sums[i] += g.finish(r)
+		}
+	}
+	return sums, n
+}
+
+// The sides in a playout: the one choosing, and its opponent.
+const (
+	ownSide   = 0
+	otherSide = 1
+)
+
+// playout is one guess at the rest of a game, played out quickly. Hands are
+// kept without empty slots; only the first card is picked by slot.
+type playout struct {
+	rules  Rules
+	hands  [2][]*Card
+	table  []*Card
+	deck   []*Card // Next card first.
+	points [2]int  // Points won from the guess on.
+	taken  [2]int
+	last   int // Side that gets the last pile.
+	leader int // Side that plays first in each new hand.
+}
+
+// guess fills the position's unknowns with the shuffled unseen cards: the
+// face-down cards of the table first, then the opponent's hand, then the
+// deck. Any cards left over were taken face down and are out of the game.
+func (p *Position) guess(unseen []*Card) *playout {
+	g := &playout{rules: p.Rules, taken: [2]int{p.Taken, p.OpponentTaken}, last: otherSide, leader: otherSide}
+	if p.LastPile {
+		g.last = ownSide
+	}
+	if p.Leads {
+		g.leader = ownSide
+	}
+	g.table = append(g.table, unseen[:p.Hidden]...)
+	g.table = append(g.table, p.Table[p.Hidden:]...)
+	rest := unseen[p.Hidden:]
+	g.hands[otherSide] = append([]*Card(nil), rest[:p.OpponentHeld]...)
+	g.deck = rest[p.OpponentHeld : p.OpponentHeld+p.DeckLeft]
+	g.hands[ownSide] = append([]*Card(nil), p.Hand[:]...) // Empty slots too, until removeSlot.
+	return g
+}
+
+// removeSlot takes the card in the slot out of the choosing side's hand,
+// dropping the empty slots with it.
+func (g *playout) removeSlot(slot int) *Card {
+	card := g.hands[ownSide][slot]
+	held := g.hands[ownSide][:0]
+	for i, c := range g.hands[ownSide] {
+		if c != nil && i != slot {
+			held = append(held, c)
+		}
+	}
+	g.hands[ownSide] = held
+	return card
+}
+
+// play puts the side's card on the table and scores any capture, as
+// processTurn does.
+func (g *playout) play(side int, card *Card) {
+	g.table = append(g.table, card)
+	n := len(g.table)
+	if n < 2 {
+		return
+	}
+	under := g.table[n-2]
+	if card.face != under.face && card.face != Jack {
+		return
+	}
+	switch {
+	case n == 2 && card.face == under.face && card.face == Jack:
+		g.points[side] += g.rules.JackPistiPoints
+	case n == 2 && card.face == under.face:
+		g.points[side] += g.rules.PistiPoints
+	default:
+		g.points[side] += g.pileValue()
+	}
+	g.taken[side] += n
+	g.table = g.table[:0]
+	g.last = side
+}
+
+// pileValue returns what the cards on the table are worth.
+func (g *playout) pileValue() int {
+	points := 0
+	for _, card := range g.table {
+		points += g.rules.CardValue(card)
+	}
+	return points
+}
+
+// finish plays the rest of the game after the first card, both sides
+// picking quickly, and returns the choosing side's points minus the
+// opponent's.
+func (g *playout) finish(r *rand.Rand) int {
+	side := otherSide
+	for {
+		if len(g.hands[ownSide]) == 0 && len(g.hands[otherSide]) == 0 {
+			if len(g.deck) == 0 {
+				break
+			}
+			// The deck is shuffled, so who is dealt first makes no difference.
+			g.hands[ownSide] = append(g.hands[ownSide][:0], g.deck[:HandSize]...)
+			g.hands[otherSide] = append(g.hands[otherSide][:0], g.deck[HandSize:2*HandSize]...)
+			g.deck = g.deck[2*HandSize:]
+			side = g.leader
+		}
+		hand := g.hands[side]
+		if len(hand) == 0 {
+			side = 1 - side // Only in a made-up position, where the hands are uneven.
+			continue
+		}
+		i := g.pick(hand, r)
+		card := hand[i]
+		hand[i] = hand[len(hand)-1]
+		g.hands[side] = hand[:len(hand)-1]
+		g.play(side, card)
+		side = 1 - side
+	}
+	if len(g.table) > 0 && !g.rules.DiscardLastPile {
+		g.points[g.last] += g.pileValue()
+		g.taken[g.last] += len(g.table)
+	}
+	switch {
+	case g.taken[ownSide] > g.taken[otherSide]:
+		g.points[ownSide] += g.rules.MajorityPoints
+	case g.taken[otherSide] > g.taken[ownSide]:
+		g.points[otherSide] += g.rules.MajorityPoints
+	}
+	return g.points[ownSide] - g.points[otherSide]
+}
+
+// pick chooses a card of the hand for the playouts: a match for the top
+// card, else a Jack if the pile has more than one card, else the least
+// valuable card that is not a Jack.
+func (g *playout) pick(hand []*Card, r *rand.Rand) int {
+	if n := len(g.table); n > 0 {
+		top, jack := g.table[n-1], -1
+		for i, card := range hand {
+			if card.face == top.face {
+				return i
+			}
+			if card.face == Jack {
+				jack = i
+			}
+		}
+		if jack >= 0 && n > 1 {
+			return jack
+		}
+	}
+	least := -1
+	for i, card := range hand {
+		if card.face != Jack && (least < 0 || g.rules.CardValue(card) < g.rules.CardValue(hand[least])) {
+			least = i
+		}
+	}
+	if least < 0 {
+		return r.Intn(len(hand))
+	}
+	return least
+}
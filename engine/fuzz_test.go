@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// FuzzGameInvariants plays games chosen by the fuzzer and validates the game
+// after every step, also checking that scores never fall except by undo.
+// Each byte of moves picks the player's next card; bytes with the high bit
+// set ask for an undo instead, where allowed.
+func FuzzGameInvariants(f *testing.F) {
+	f.Add(int64(1), uint8(0), []byte{0, 1, 2, 3})
+	f.Add(int64(42), uint8(1), []byte{0x80, 3, 0x81, 2, 0x80})
+	f.Add(int64(7), uint8(2), []byte{})
+	f.Add(int64(-3), uint8(0), []byte{3, 3, 0x80, 0x80, 1, 0x82, 0})
+	f.Fuzz(func(t *testing.T, seed int64, level uint8, moves []byte) {
+		c := NewCasino(rand.NewSource(seed), nil)
+		c.SetLevel(LevelBeginner + GameLevel(level%3))
+		if !c.StartGameWithSeed(seed) {
+			t.Fatal("StartGameWithSeed failed")
+		}
+		if err := c.Validate(); err != nil {
+			t.Fatalf("after the deal: %v", err)
+		}
+		playerPoints, cpuPoints := 0, 0
+		// Every undo rewinds a round, so allow a few extra steps for each byte.
+		maxSteps := 400 + 4*len(moves)
+		for steps := 0; c.State() != StateGameOver; steps++ {
+			if steps > maxSteps {
+				t.Fatalf("game did not finish after %d steps", steps)
+			}
+			var choice byte
+			if len(moves) > 0 && c.State() == StatePlayerTurn {
+				choice, moves = moves[0], moves[1:]
+			}
+			undone := false
+			var err error
+			switch state := c.State(); {
+			case c.IsHandFinished() && (state == StatePlayerTurn || state == StateCPUTurn):
+				err = c.CheckEndOfHand()
+			case state == StatePlayerTurn && choice&0x80 != 0 && c.CanUndo():
+				undone = c.Undo()
+				if !undone {
+					t.Fatal("Undo refused although CanUndo was true")
+				}
+			case state == StatePlayerTurn:
+				hand := c.PlayerHand()
+				if firstHeld(hand) == -1 {
+					t.Fatalf("step %d: the player must move with an empty hand", steps)
+				}
+				slot := int(choice & 0x7f % HandSize)
+				for hand[slot] == nil {
+					slot = (slot + 1) % HandSize
+				}
+				err = c.PlayerPlays(slot)
+			case state == StateCPUTurn:
+				err = c.CPUPlays()
+			case state == StatePileCaptured:
+				c.FinalizeCapture()
+			}
+			if err != nil {
+				t.Fatalf("step %d: %v", steps, err)
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("step %d: %v", steps, err)
+			}
+			// Scores only ever go down through an undo.
+			newPlayer, newCPU := c.PlayerPoints(), c.CPUPoints()
+			if !undone && (newPlayer < playerPoints || newCPU < cpuPoints) {
+				t.Fatalf("step %d: score fell from %d-%d to %d-%d", steps, playerPoints, cpuPoints, newPlayer, newCPU)
+			}
+			playerPoints, cpuPoints = newPlayer, newCPU
+		}
+		if s := c.Snapshot(); s.PlayerCaptured+s.CPUCaptured != DeckSize {
+			t.Fatalf("game ended with %d+%d cards collected", s.PlayerCaptured, s.CPUCaptured)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// gameCodePrefix starts every game code and names its format, so a later
+// format can be told apart. houseRulesCodePrefix starts the codes of games
+// whose rules differ from the standard ones in more than the Pişti values.
+const (
+	gameCodePrefix       = "P1-"
+	houseRulesCodePrefix = "P2-"
+)
+
+// ErrBadGameCode is returned for a game code that is mistyped or cut short.
+var ErrBadGameCode = errors.New("engine: not a valid game code")
+
+// A game code packs a save into a short string that can be pasted into a
+// chat. After the prefix comes base64url without padding of:
+//
+//	uvarint level, varint seed, uvarint Pişti points, uvarint Jack Pişti
+//	points, in P2 codes only uvarint majority points, uvarint Deuce of
+//	Clubs points and uvarint Ten of Diamonds points, uvarint flags
+//	(1: finished, 2: last pile discarded), uvarint number of moves,
+//	the moves two to a byte, low nibble first: bit 2 set for the CPU,
+//	bits 0-1 the slot,
+//	the low two bytes of the CRC-32 of everything before them.
+
+// GameCode returns the current game, or the last finished one, as a game
+// code.
+func (c *Casino) GameCode() (string, error) {
+	data, err := c.Save()
+	if err != nil {
+		return "", err
+	}
+	g, err := DecodeSave(data)
+	if err != nil {
+		return "", err
+	}
+	b := binary.AppendUvarint(nil, uint64(g.Level))
+	b = binary.AppendVarint(b, g.Seed)
+	b = binary.AppendUvarint(b, uint64(g.Rules.PistiPoints))
+	b = binary.AppendUvarint(b, uint64(g.Rules.JackPistiPoints))
+	prefix := gameCodePrefix
+	if g.Rules.houseRules() {
+		prefix = houseRulesCodePrefix
+		b = binary.AppendUvarint(b, uint64(g.Rules.MajorityPoints))
+		b = binary.AppendUvarint(b, uint64(g.Rules.DeuceOfClubsPoints))
+		b = binary.AppendUvarint(b, uint64(g.Rules.TenOfDiamondsPoints))
+	}
+	var flags uint64
+	if g.Finished {
+		flags |= 1
+	}
+	if g.Rules.DiscardLastPile {
+		flags |= 2
+	}
+	b = binary.AppendUvarint(b, flags)
+	b = binary.AppendUvarint(b, uint64(len(g.Moves)))
+	for i := 0; i < len(g.Moves); i += 2 {
+		packed := moveNibble(g.Moves[i])
+		if i+1 < len(g.Moves) {
+			packed |= moveNibble(g.Moves[i+1]) << 4
+		}
+		b = append(b, packed)
+	}
+	b = binary.LittleEndian.AppendUint16(b, uint16(crc32.ChecksumIEEE(b)))
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// moveNibble packs a move into four bits.
+func moveNibble(m SavedMove) byte {
+	n := byte(m.Slot) & 3
+	if m.By == CPU {
+		n |= 4
+	}
+	return n
+}
+
+// DecodeGameCode returns the save a game code stands for, to be passed to
+// Load. Spaces and line breaks a chat added are ignored.
+func DecodeGameCode(code string) ([]byte, error) {
+	code = strings.Join(strings.Fields(code), "")
+	body, ok := strings.CutPrefix(code, gameCodePrefix)
+	houseRules := false
+	if !ok {
+		body, houseRules = strings.CutPrefix(code, houseRulesCodePrefix)
+	}
+	if !ok && !houseRules {
+		return nil, fmt.Errorf("%w: it should start with %s", ErrBadGameCode, gameCodePrefix)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil || len(b) < 2 {
+		return nil, ErrBadGameCode
+	}
+	b, sum := b[:len(b)-2], binary.LittleEndian.Uint16(b[len(b)-2:])
+	if uint16(crc32.ChecksumIEEE(b)) != sum {
+		return nil, fmt.Errorf("%w: it was changed or not copied whole", ErrBadGameCode)
+	}
+	r := &codeReader{b: b}
+	g := SavedGame{Version: SchemaVersion, Rules: StandardRules}
+	g.Level = GameLevel(r.uvarint())
+	g.Seed = r.varint()
+	g.Rules.PistiPoints = int(r.uvarint())
+	g.Rules.JackPistiPoints = int(r.uvarint())
+	if houseRules {
+		g.Rules.MajorityPoints = int(r.uvarint())
+		g.Rules.DeuceOfClubsPoints = int(r.uvarint())
+		g.Rules.TenOfDiamondsPoints = int(r.uvarint())
+	}
+	flags := r.uvarint()
+	g.Finished = flags&1 != 0
+	g.Rules.DiscardLastPile = flags&2 != 0
+	n := r.uvarint()
+	if r.err != nil || n > DeckSize || uint64(len(r.b)) != (n+1)/2 {
+		return nil, ErrBadGameCode
+	}
+	g.Moves = make([]SavedMove, n)
+	for i := range g.Moves {
+		nibble := r.b[i/2] >> (4 * (i % 2))
+		g.Moves[i] = SavedMove{By: Player, Slot: int(nibble & 3)}
+		if nibble&4 != 0 {
+			g.Moves[i].By = CPU
+		}
+	}
+	return json.Marshal(g)
+}
+
+// codeReader reads the varints of a game code, remembering the first failure.
+type codeReader struct {
+	b   []byte
+	err error
+}
+
+// uvarint reads an unsigned varint.
+func (r *codeReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = ErrBadGameCode
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// varint reads a signed varint.
+func (r *codeReader) varint() int64 {
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.err = ErrBadGameCode
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
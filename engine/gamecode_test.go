@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGameCode(t *testing.T) {
+	for _, finished := range []bool{false, true} {
+		c := newTestCasino(t, LevelAdvanced, -77)
+		if finished {
+			playOut(t, c)
+		} else {
+			for i := 0; i < 7; i++ {
+				step(t, c)
+			}
+		}
+		code, err := c.GameCode()
+		if err != nil {
+			t.Fatalf("GameCode: %v", err)
+		}
+		if len(code) > 60 {
+			t.Errorf("code %q is %d characters long", code, len(code))
+		}
+		// Chats wrap long words.
+		data, err := DecodeGameCode(code[:len(code)/2] + "\n " + code[len(code)/2:])
+		if err != nil {
+			t.Fatalf("DecodeGameCode(%q): %v", code, err)
+		}
+		loaded := NewCasino(nil, nil)
+		if err := loaded.Load(data); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if loaded.StateHash() != c.StateHash() {
+			t.Errorf("finished %v: the loaded game differs from the coded one", finished)
+		}
+	}
+}
+
+func TestBadGameCode(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 3)
+	step(t, c)
+	code, err := c.GameCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := []byte(code)
+	if flipped[5] == 'A' {
+		flipped[5] = 'B'
+	} else {
+		flipped[5] = 'A'
+	}
+	for _, bad := range []string{"", "hello", strings.TrimPrefix(code, gameCodePrefix), code[:len(code)-3], string(flipped)} {
+		if _, err := DecodeGameCode(bad); !errors.Is(err, ErrBadGameCode) {
+			t.Errorf("DecodeGameCode(%q) = %v, want ErrBadGameCode", bad, err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package engine
+
+// gameCommand is one reversible change to a game, such as a card played or
+// a pile captured. Every change a move makes goes through Casino.do, which
+// records it, so undo, redo and stepping through a finished game all work
+// by running commands backwards or forwards.
+type gameCommand interface {
+	// execute applies the change, remembering whatever it overwrites.
+	execute(c *Casino)
+	// undo reverts an executed change. Commands are undone newest first,
+	// so the game is exactly as execute left it.
+	undo(c *Casino)
+}
+
+// turn is the commands executed by one call into the casino, such as a play
+// and the capture it made. The history is kept, undone and redone in turns.
+type turn []gameCommand
+
+// play returns the card play made in the turn, or nil if there was none.
+func (t turn) play() *playCardCommand {
+	for _, cmd := range t {
+		if p, ok := cmd.(*playCardCommand); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// do executes a command and records it in the current turn. The caller must
+// hold the mutex.
+func (c *Casino) do(cmd gameCommand) {
+	cmd.execute(c)
+	c.pendingTurn = append(c.pendingTurn, cmd)
+}
+
+// endTurn moves the commands executed since the last call into the history.
+// A new turn makes the undone ones impossible to redo. The caller must hold
+// the mutex.
+func (c *Casino) endTurn() {
+	if len(c.pendingTurn) == 0 {
+		return
+	}
+	c.history = append(c.history, c.pendingTurn)
+	c.pendingTurn = nil
+	c.redoTurns = nil
+}
+
+// clearHistory forgets the history, for a new or reset game. The caller
+// must hold the mutex.
+func (c *Casino) clearHistory() {
+	c.history, c.pendingTurn, c.redoTurns = nil, nil, nil
+}
+
+// undoTurn reverts the newest turn of the history and keeps it for redo.
+// The caller must hold the mutex.
+func (c *Casino) undoTurn() turn {
+	t := c.history[len(c.history)-1]
+	c.history = c.history[:len(c.history)-1]
+	for i := len(t) - 1; i >= 0; i-- {
+		t[i].undo(c)
+	}
+	c.redoTurns = append(c.redoTurns, t)
+	return t
+}
+
+// redoTurn executes the most recently undone turn again. The caller must
+// hold the mutex.
+func (c *Casino) redoTurn() turn {
+	t := c.redoTurns[len(c.redoTurns)-1]
+	c.redoTurns = c.redoTurns[:len(c.redoTurns)-1]
+	for _, cmd := range t {
+		cmd.execute(c)
+	}
+	c.history = append(c.history, t)
+	return t
+}
+
+// handOf returns the hand of the given side.
+func (c *Casino) handOf(by PlayerID) *Hand {
+	if by == Player {
+		return &c.playerCards
+	}
+	return &c.cpuCards
+}
+
+// playCardCommand moves a card from a hand slot onto the table.
+type playCardCommand struct {
+	by             PlayerID
+	slot           int
+	card           *Card
+	wasInitialPile bool
+}
+
+func (p *playCardCommand) execute(c *Casino) {
+	p.card = c.handOf(p.by).Pop(p.slot)
+	// The first card played ends the untouched starting pile.
+	p.wasInitialPile = c.isInitialPile
+	c.isInitialPile = false
+	// Update the AI's memory; each strategy decides what it makes use of.
+	c.currentHandMemory.Push(p.card)
+	c.allPlayedCardsMemory.Push(p.card)
+	c.emit(EventCardPlayed, p.by, p.slot)
+	c.journal = append(c.journal, Move{By: p.by, Card: p.card, Slot: p.slot})
+	c.tableCards.Push(p.card)
+}
+
+func (p *playCardCommand) undo(c *Casino) {
+	c.tableCards.Pop()
+	c.journal = c.journal[:len(c.journal)-1]
+	c.allPlayedCardsMemory.Pop()
+	c.currentHandMemory.Pop()
+	c.isInitialPile = p.wasInitialPile
+	c.handOf(p.by)[p.slot] = p.card
+}
+
+// captureCommand awards a captured pile, or the final pile, to one side.
+// The cards stay on the table until a clearTableCommand takes them.
+type captureCommand struct {
+	event         CaptureEvent
+	slot          int    // Hand slot of the capturing card, for the event.
+	safeDiscard   *Card  // New safe discard clue for the AI, if not nil.
+	revealsHidden bool   // The capture takes the face-down starting cards.
+	message       string // Message describing the hidden cards, if any.
+	// What execute overwrote.
+	prevScorer      PlayerID
+	prevSafeDiscard *Card
+	prevHidden      []*Card
+	prevMessage     string
+}
+
+func (cp *captureCommand) execute(c *Casino) {
+	e := cp.event
+	cp.prevScorer, cp.prevSafeDiscard = c.lastScorer, c.safeDiscardCandidate
+	cp.prevHidden, cp.prevMessage = c.initialHiddenCards, c.initialPileCaptureMsg
+	if cp.safeDiscard != nil {
+		c.safeDiscardCandidate = cp.safeDiscard
+	}
+	if cp.revealsHidden {
+		if cp.message != "" {
+			c.initialPileCaptureMsg = cp.message
+		}
+		c.initialHiddenCards = nil // The initial pile has been captured, so clear the tracker.
+	}
+	switch {
+	case e.Final:
+	case e.Jack:
+		c.emit(EventJackPisti, e.By, cp.slot)
+	case e.Pisti:
+		c.emit(EventPisti, e.By, cp.slot)
+	default:
+		c.emit(EventCapture, e.By, cp.slot)
+	}
+	cp.score(c, 1)
+	c.captureHistory = append(c.captureHistory, e)
+	if !e.Final {
+		c.lastScorer = e.By
+	}
+}
+
+func (cp *captureCommand) undo(c *Casino) {
+	c.captureHistory = c.captureHistory[:len(c.captureHistory)-1]
+	cp.score(c, -1)
+	c.lastScorer, c.safeDiscardCandidate = cp.prevScorer, cp.prevSafeDiscard
+	c.initialHiddenCards, c.initialPileCaptureMsg = cp.prevHidden, cp.prevMessage
+}
+
+// score adds the capture's points and cards to its side, or takes them
+// away again when sign is -1.
+func (cp *captureCommand) score(c *Casino, sign int) {
+	e := cp.event
+	if e.By == Player {
+		c.playerPoint += sign * e.Points
+		c.cardsCollectedByPlayer += sign * e.Cards
+	} else {
+		c.cpuPoint += sign * e.Points
+		c.cardsCollectedByCPU += sign * e.Cards
+	}
+}
+
+// bonusCommand awards points that come with no cards, such as the bonus
+// for collecting the most cards.
+type bonusCommand struct {
+	by     PlayerID
+	points int
+}
+
+func (b *bonusCommand) execute(c *Casino) {
+	b.add(c, b.points)
+}
+
+func (b *bonusCommand) undo(c *Casino) {
+	b.add(c, -b.points)
+}
+
+// add changes the side's score by points.
+func (b *bonusCommand) add(c *Casino, points int) {
+	if b.by == Player {
+		c.playerPoint += points
+	} else {
+		c.cpuPoint += points
+	}
+}
+
+// clearTableCommand takes every card off the table, to the side that took
+// them.
+type clearTableCommand struct {
+	by    PlayerID
+	cards []*Card
+}
+
+func (t *clearTableCommand) execute(c *Casino) {
+	t.cards = c.tableCards.Cards()
+	c.tableCards.Clear()
+	c.emit(EventPileCleared, t.by, -1)
+}
+
+func (t *clearTableCommand) undo(c *Casino) {
+	c.tableCards = Pile{cards: append([]*Card(nil), t.cards...)}
+}
+
+// dealCommand deals a new hand to both sides once the last one is played
+// out. The opening deal belongs to the start of the game and is not one.
+type dealCommand struct {
+	prevSafeDiscard *Card
+	prevHandMemory  Pile
+}
+
+func (d *dealCommand) execute(c *Casino) {
+	c.emit(EventDeal, NoPlayer, -1)
+	d.prevSafeDiscard = c.safeDiscardCandidate
+	d.prevHandMemory = c.currentHandMemory.clone()
+	c.safeDiscardCandidate = nil // Reset the safe discard clue for the new hand.
+	c.currentHandMemory.Clear()  // Reset the short-term memory for the new hand.
+	c.dealHands()
+}
+
+func (d *dealCommand) undo(c *Casino) {
+	// Hands are only dealt once both are empty again.
+	c.playerCards, c.cpuCards = Hand{}, Hand{}
+	c.currentCard -= 2 * HandSize
+	c.safeDiscardCandidate = d.prevSafeDiscard
+	c.currentHandMemory = d.prevHandMemory.clone()
+}
+
+// stateCommand moves the game to another state.
+type stateCommand struct {
+	from, to GameState
+}
+
+func (s *stateCommand) execute(c *Casino) {
+	c.gameState = s.to
+	c.emit(EventStateChanged, NoPlayer, -1)
+}
+
+func (s *stateCommand) undo(c *Casino) {
+	c.gameState = s.from
+}
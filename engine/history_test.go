@@ -0,0 +1,89 @@
+package engine
+
+import "testing"
+
+// playRound plays the player's first card and the CPU's reply, settling any
+// capture.
+func playRound(t *testing.T, c *Casino) {
+	t.Helper()
+	if err := c.PlayerPlays(firstHeld(c.PlayerHand())); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	c.FinalizeCapture()
+	if err := c.CPUPlays(); err != nil {
+		t.Fatalf("CPUPlays: %v", err)
+	}
+	c.FinalizeCapture()
+}
+
+func TestRedo(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 3)
+	playRound(t, c)
+	want := c.StateHash()
+	c.Events()
+	if c.CanRedo() || c.Redo() {
+		t.Fatal("Redo succeeded before any undo")
+	}
+	if !c.Undo() {
+		t.Fatal("Undo refused")
+	}
+	if !c.CanRedo() {
+		t.Fatal("CanRedo false after Undo")
+	}
+	c.Events()
+	if !c.Redo() {
+		t.Fatal("Redo refused")
+	}
+	if got := c.StateHash(); got != want {
+		t.Errorf("after redo:\n%s", c.DebugDump())
+	}
+	events := c.Events()
+	if !hasEvent(events, EventRedone) || hasEvent(events, EventCardPlayed) {
+		t.Errorf("redo reported %v, want EventRedone and no plays", events)
+	}
+	if c.CanRedo() {
+		t.Error("the same plays could be redone twice")
+	}
+	if !c.CanUndo() || !c.Undo() {
+		t.Error("redone plays cannot be undone")
+	}
+}
+
+func TestNewMoveForgetsRedo(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 3)
+	playRound(t, c)
+	if !c.Undo() {
+		t.Fatal("Undo refused")
+	}
+	if err := c.PlayerPlays(firstHeld(c.PlayerHand())); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if c.CanRedo() || c.Redo() {
+		t.Error("undone plays were redone after a new move")
+	}
+}
+
+// TestHistoryStepsThroughWholeGame takes back every turn of a finished game
+// and makes them all again, as an analysis mode would.
+func TestHistoryStepsThroughWholeGame(t *testing.T) {
+	c := newTestCasino(t, LevelAdvanced, 11)
+	start, startMemory := c.StateHash(), c.allPlayedCardsMemory.Cards()
+	playOut(t, c)
+	end, endMemory := c.StateHash(), c.allPlayedCardsMemory.Cards()
+	turns := len(c.history)
+	for len(c.history) > 0 {
+		c.undoTurn()
+		if err := c.validate(); err != nil {
+			t.Fatalf("%d turns back: %v", turns-len(c.history), err)
+		}
+	}
+	if c.StateHash() != start || !sameCards(c.allPlayedCardsMemory.Cards(), startMemory) {
+		t.Fatalf("rewound game differs from the start:\n%s", c.DebugDump())
+	}
+	for len(c.redoTurns) > 0 {
+		c.redoTurn()
+	}
+	if c.StateHash() != end || !sameCards(c.allPlayedCardsMemory.Cards(), endMemory) {
+		t.Fatalf("replayed game differs from the end:\n%s", c.DebugDump())
+	}
+}
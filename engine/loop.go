@@ -0,0 +1,488 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pacing holds the pauses a Loop inserts so a person can follow the game.
+type Pacing struct {
+	CPUDelay       time.Duration // From the player's card to the CPU's reply.
+	CapturePause   time.Duration // How long a captured pile stays visible.
+	EndOfHandPause time.Duration // From the last card of a hand to the next deal.
+}
+
+// DefaultPacing is the pacing used by the desktop game.
+var DefaultPacing = Pacing{
+	CPUDelay:       1000 * time.Millisecond,
+	CapturePause:   500 * time.Millisecond,
+	EndOfHandPause: 500 * time.Millisecond,
+}
+
+// ErrLoopClosed is returned for commands sent to a Loop after Close or after
+// its context was cancelled.
+var ErrLoopClosed = errors.New("engine: loop is closed")
+
+// command is a unit of work for the loop goroutine. done receives its result
+// when somebody is waiting for it.
+type command struct {
+	run  func() error
+	done chan error
+}
+
+// Loop runs a Casino on a single goroutine. Every change to the game, whether
+// asked for by the front end or due after a pause, is sent to that goroutine
+// as a command and carried out in order, so moves, timers and restarts can
+// never interleave. Between the player's moves the loop lets the CPU reply,
+// clears captured piles and deals new hands, each after a pause. These steps
+// share a context that restarting or undoing cancels, which drops whatever
+// was still pending; cancelling the loop's own context stops it altogether.
+//
+// Front ends change the game only through the Loop. Reading it through the
+// Casino's accessors or Snapshot is safe at any time.
+type Loop struct {
+	casino   *Casino
+	pacing   Pacing
+	onChange func() // Called after every paced step, from the loop goroutine.
+	commands chan command
+	ctx      context.Context // Done once the loop is closed.
+	closeCtx context.CancelFunc
+	busy     atomic.Bool // Mirrors timer != nil for readers on other goroutines.
+	stopMu   sync.Mutex  // Guards stopPending, which interrupt calls from other goroutines.
+
+	// Owned by the loop goroutine.
+	onPanic     func(value any, stack []byte)
+	onError     func(err error) // Told of paced steps that failed; nil logs them.
+	timer       Timer
+	pending     context.Context // Shared by the paced steps of the current sequence.
+	stopPending context.CancelFunc
+	autoPlayer  *Strategy // Plays the player's seat while spectating; nil otherwise.
+	autoRand    *rand.Rand
+	hotSeat     bool // The CPU's seat waits for OpponentPlays.
+}
+
+// NewLoop starts the loop goroutine for the given game, timed by the game's
+// clock. The loop stops when ctx is cancelled or Close is called. onChange,
+// if not nil, is called after each paced step so the caller can collect the
+// new events; commands sent by the caller are not reported, as the caller
+// knows when they finish.
+func NewLoop(ctx context.Context, c *Casino, pacing Pacing, onChange func()) *Loop {
+	l := &Loop{
+		casino:   c,
+		pacing:   pacing,
+		onChange: onChange,
+		commands: make(chan command),
+	}
+	l.ctx, l.closeCtx = context.WithCancel(ctx)
+	l.pending, l.stopPending = context.WithCancel(l.ctx)
+	go l.run()
+	return l
+}
+
+// run carries out commands until the loop is closed.
+func (l *Loop) run() {
+	for {
+		select {
+		case cmd := <-l.commands:
+			err := l.execute(cmd.run)
+			if cmd.done != nil {
+				cmd.done <- err
+			} else if err != nil && l.onError != nil {
+				l.onError(err)
+			} else if err != nil {
+				log.Printf("ERROR: Scheduled turn step failed: %v", err)
+			}
+		case <-l.ctx.Done():
+			l.cancel()
+			return
+		}
+	}
+}
+
+// execute runs a command. With a panic handler installed, a panic is passed
+// to the handler and the command fails instead of taking the loop down.
+func (l *Loop) execute(run func() error) (err error) {
+	if l.onPanic == nil {
+		return run()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			l.onPanic(r, debug.Stack())
+			err = fmt.Errorf("engine: command panicked: %v", r)
+		}
+	}()
+	return run()
+}
+
+// submit runs fn on the loop goroutine and waits for its result.
+func (l *Loop) submit(fn func() error) error {
+	done := make(chan error, 1)
+	select {
+	case l.commands <- command{run: fn, done: done}:
+		return <-done
+	case <-l.ctx.Done():
+		return ErrLoopClosed
+	}
+}
+
+// interrupting is submit for a command that drops the pending step. The
+// step is called off first, so a CPU still thinking about its move stops
+// instead of holding up the command.
+func (l *Loop) interrupting(fn func() error) error {
+	l.stopMu.Lock()
+	l.stopPending()
+	l.stopMu.Unlock()
+	return l.submit(fn)
+}
+
+// post queues fn for the loop goroutine without waiting for it. It gives up
+// once ctx is cancelled.
+func (l *Loop) post(ctx context.Context, fn func() error) {
+	select {
+	case l.commands <- command{run: fn}:
+	case <-ctx.Done():
+	}
+}
+
+// Close stops the loop and drops any pending step. Later commands fail with
+// ErrLoopClosed.
+func (l *Loop) Close() {
+	l.closeCtx()
+}
+
+// SetPanicHandler installs a function that is called, on the loop goroutine,
+// with the value and stack trace of any panic in a command or paced step,
+// for example one raised in debug mode. Without a handler such a panic
+// crashes the program as usual.
+func (l *Loop) SetPanicHandler(handler func(value any, stack []byte)) {
+	l.submit(func() error {
+		l.onPanic = handler
+		return nil
+	})
+}
+
+// SetErrorHandler installs a function that is called, on the loop goroutine,
+// with the error of any paced step that fails, as nobody waits for those.
+// Without a handler they are logged.
+func (l *Loop) SetErrorHandler(handler func(err error)) {
+	l.submit(func() error {
+		l.onError = handler
+		return nil
+	})
+}
+
+// SetPacing changes the pauses around the CPU's moves. Steps already
+// scheduled keep the pause they were given.
+func (l *Loop) SetPacing(pacing Pacing) {
+	l.submit(func() error {
+		l.pacing = pacing
+		return nil
+	})
+}
+
+// SetLevel selects the difficulty for the next game.
+func (l *Loop) SetLevel(level GameLevel) {
+	l.submit(func() error {
+		l.casino.SetLevel(level)
+		return nil
+	})
+}
+
+// SetVariant selects the rule variant for the next game; see Casino.SetVariant.
+func (l *Loop) SetVariant(name string) bool {
+	ok := false
+	l.submit(func() error {
+		ok = l.casino.SetVariant(name)
+		return nil
+	})
+	return ok
+}
+
+// SetRules selects custom rules for the next game; see Casino.SetRules.
+func (l *Loop) SetRules(r Rules) error {
+	return l.submit(func() error {
+		return l.casino.SetRules(r)
+	})
+}
+
+// SetMatchTarget sets the points that win the matches started from now on;
+// see Casino.SetMatchTarget.
+func (l *Loop) SetMatchTarget(points int) {
+	l.submit(func() error {
+		l.casino.SetMatchTarget(points)
+		return nil
+	})
+}
+
+// SetThinkTime sets how long the Expert may think about a move; see
+// Casino.SetThinkTime. It counts from the CPU's next move, so the caller
+// never waits for one being thought about.
+func (l *Loop) SetThinkTime(d time.Duration) {
+	l.casino.SetThinkTime(d)
+}
+
+// StartGame abandons any game in progress and starts a new one at the
+// selected level. It reports false when no level is selected.
+func (l *Loop) StartGame() bool {
+	return l.restart(func() bool { return l.casino.StartGame() })
+}
+
+// StartGameWithSeed is like StartGame but deals the game for the given seed,
+// for example to reproduce a reported game.
+func (l *Loop) StartGameWithSeed(seed int64) bool {
+	return l.restart(func() bool { return l.casino.StartGameWithSeed(seed) })
+}
+
+// Replay restarts the current game at the same level with the same seed, so
+// the player gets the identical cards again.
+func (l *Loop) Replay() bool {
+	return l.restart(func() bool { return l.casino.StartGameWithSeed(l.casino.Seed()) })
+}
+
+// NextDeal deals the next game of the match; see Casino.NextDeal.
+func (l *Loop) NextDeal() bool {
+	started := false
+	l.interrupting(func() error {
+		l.cancel()
+		started = l.casino.NextDeal()
+		l.scheduleNext(false)
+		return nil
+	})
+	return started
+}
+
+// restart cancels any pending step, resets the game while keeping its level,
+// and then calls start, all on the loop goroutine.
+func (l *Loop) restart(start func() bool) bool {
+	started := false
+	l.interrupting(func() error {
+		l.cancel()
+		if l.casino.State() != StateNotStarted {
+			// Resetting forgets the level, so carry it over.
+			level := l.casino.Level()
+			l.casino.ResetGame()
+			l.casino.SetLevel(level)
+		}
+		started = start()
+		l.scheduleNext(false) // Spectating, the first move is not the player's to make.
+		return nil
+	})
+	return started
+}
+
+// Spectate lets the strategy play the player's seat, paced like the CPU, so
+// two strategies can be watched playing each other; nil gives the seat back.
+// The step pending when it is called starts over.
+func (l *Loop) Spectate(s *Strategy) {
+	l.interrupting(func() error {
+		l.autoPlayer = s
+		if s != nil {
+			l.autoRand = rand.New(rand.NewSource(l.casino.clock.Now().UnixNano()))
+		}
+		l.cancel()
+		l.scheduleNext(false)
+		return nil
+	})
+}
+
+// SetHotSeat makes the loop wait for OpponentPlays on the CPU's turn instead
+// of letting the CPU move, so two people can share the device.
+func (l *Loop) SetHotSeat(on bool) {
+	l.interrupting(func() error {
+		l.hotSeat = on
+		l.cancel()
+		l.scheduleNext(false)
+		return nil
+	})
+}
+
+// Load replaces the game with a saved one and carries on from where it was
+// saved; see Casino.Load.
+func (l *Loop) Load(data []byte) error {
+	return l.interrupting(func() error {
+		l.cancel()
+		if err := l.casino.Load(data); err != nil {
+			return err
+		}
+		l.scheduleNext(false)
+		return nil
+	})
+}
+
+// Reset abandons the game and returns to the start screen.
+func (l *Loop) Reset() {
+	l.interrupting(func() error {
+		l.cancel()
+		l.casino.ResetGame()
+		return nil
+	})
+}
+
+// PlayerPlays plays a card from the player's hand and schedules what follows.
+func (l *Loop) PlayerPlays(slot int) error {
+	return l.submit(func() error {
+		if l.timer != nil {
+			return fmt.Errorf("%w (waiting for the CPU)", ErrWrongState)
+		}
+		if err := l.casino.PlayerPlays(slot); err != nil {
+			return err
+		}
+		l.scheduleNext(false)
+		return nil
+	})
+}
+
+// OpponentPlays plays the card in the given slot of the CPU's hand for the
+// second person in a hot-seat game; see SetHotSeat.
+func (l *Loop) OpponentPlays(slot int) error {
+	return l.submit(func() error {
+		if l.timer != nil {
+			return fmt.Errorf("%w (waiting for the table)", ErrWrongState)
+		}
+		if err := l.casino.OpponentPlays(slot); err != nil {
+			return err
+		}
+		l.scheduleNext(false)
+		return nil
+	})
+}
+
+// Undo takes back the player's last play and the CPU's reply, even while
+// the next CPU move is pending. It reports false when there was nothing to
+// undo, in which case the game carries on as before.
+func (l *Loop) Undo() bool {
+	undone := false
+	l.interrupting(func() error {
+		l.cancel()
+		if undone = l.casino.Undo(); !undone {
+			l.scheduleNext(false) // Start the dropped step again.
+		}
+		return nil
+	})
+	return undone
+}
+
+// Redo makes the plays taken back by the last Undo again. It reports false
+// when there is nothing to redo.
+func (l *Loop) Redo() bool {
+	redone := false
+	l.submit(func() error {
+		if redone = l.casino.Redo(); redone {
+			l.cancel()
+			l.scheduleNext(false)
+		}
+		return nil
+	})
+	return redone
+}
+
+// ClearInitialPileCaptureMessage dismisses the initial pile capture message.
+func (l *Loop) ClearInitialPileCaptureMessage() {
+	l.submit(func() error {
+		l.casino.ClearInitialPileCaptureMessage()
+		return nil
+	})
+}
+
+// Busy reports whether the loop is waiting to perform a step, during which
+// the player may not move.
+func (l *Loop) Busy() bool {
+	return l.busy.Load()
+}
+
+// cancel drops any pending step by cancelling the current sequence's
+// context, and starts a fresh one. It runs on the loop goroutine.
+func (l *Loop) cancel() {
+	l.stopMu.Lock()
+	l.stopPending()
+	l.pending, l.stopPending = context.WithCancel(l.ctx)
+	l.stopMu.Unlock()
+	if l.timer != nil {
+		l.timer.Stop()
+		l.setTimer(nil)
+	}
+}
+
+// setTimer records the pending step. It runs on the loop goroutine.
+func (l *Loop) setTimer(t Timer) {
+	l.timer = t
+	l.busy.Store(t != nil)
+}
+
+// scheduleNext picks the next step from the game state. afterCapture is set
+// when the previous step cleared a pile, whose pause counts towards the CPU's
+// delay. It runs on the loop goroutine.
+func (l *Loop) scheduleNext(afterCapture bool) {
+	state := l.casino.State()
+	switch {
+	case l.casino.IsHandFinished() && (state == StatePlayerTurn || state == StatePileCaptured):
+		// CheckEndOfHand also clears a pile captured with the last card.
+		l.after(l.pacing.EndOfHandPause, func(context.Context) error {
+			return l.casino.CheckEndOfHand()
+		}, false)
+	case state == StatePileCaptured:
+		l.after(l.pacing.CapturePause, func(context.Context) error {
+			l.casino.FinalizeCapture()
+			return nil
+		}, true)
+	case state == StateCPUTurn && l.hotSeat:
+		l.setTimer(nil) // The second person's move.
+	case state == StateCPUTurn:
+		l.after(l.moveDelay(afterCapture), func(ctx context.Context) error {
+			return l.casino.CPUPlaysContext(ctx)
+		}, false)
+	case state == StatePlayerTurn && l.autoPlayer != nil:
+		s, rng := *l.autoPlayer, l.autoRand
+		l.after(l.moveDelay(afterCapture), func(ctx context.Context) error {
+			hand := l.casino.PlayerHand()
+			slot := l.casino.playerChoice(ctx, s, rng)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if slot < 0 || hand[slot] == nil {
+				slot = firstHeld(hand)
+			}
+			return l.casino.PlayerPlays(slot)
+		}, false)
+	default:
+		l.setTimer(nil) // The player's move, or the game is over.
+	}
+}
+
+// moveDelay returns the pause before a move made by a strategy. It runs on
+// the loop goroutine.
+func (l *Loop) moveDelay(afterCapture bool) time.Duration {
+	if afterCapture {
+		return max(0, l.pacing.CPUDelay-l.pacing.CapturePause)
+	}
+	return l.pacing.CPUDelay
+}
+
+// after sends a step to the loop once the delay has passed. The step gets
+// the sequence's context, so slow work such as the Expert's search can give
+// up early; if the sequence is cancelled before the step starts it is
+// dropped, otherwise the one after it is scheduled. It runs on the loop
+// goroutine.
+func (l *Loop) after(delay time.Duration, step func(ctx context.Context) error, capture bool) {
+	ctx := l.pending
+	l.setTimer(l.casino.clock.AfterFunc(delay, func() {
+		l.post(ctx, func() error {
+			if ctx.Err() != nil {
+				return nil // Cancelled after the timer had already fired.
+			}
+			err := step(ctx)
+			l.scheduleNext(capture)
+			if l.onChange != nil {
+				l.onChange()
+			}
+			return err
+		})
+	}))
+}
@@ -0,0 +1,374 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock whose time only moves when Advance is called.
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// manualTimer is a call pending on a manualClock.
+type manualTimer struct {
+	clock   *manualClock
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (m *manualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+func (m *manualClock) AfterFunc(d time.Duration, f func()) Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := &manualTimer{clock: m, at: m.now.Add(d), f: f}
+	m.timers = append(m.timers, t)
+	return t
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	pending := !t.stopped
+	t.stopped = true
+	return pending
+}
+
+// Advance moves the clock forward and starts the calls that became due.
+func (m *manualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	m.now = m.now.Add(d)
+	var due, later []*manualTimer
+	for _, t := range m.timers {
+		switch {
+		case t.stopped:
+		case !t.at.After(m.now):
+			t.stopped = true
+			due = append(due, t)
+		default:
+			later = append(later, t)
+		}
+	}
+	m.timers = later
+	m.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, t := range due {
+		go t.f()
+	}
+}
+
+// newTestLoop returns a running loop over a rigged Beginner game in which
+// the player's Three of Spades neither captures nor can be captured, and a
+// channel that receives a value after every paced step.
+func newTestLoop(t *testing.T) (*Loop, *manualClock, <-chan struct{}) {
+	t.Helper()
+	clock := &manualClock{now: time.Unix(0, 0)}
+	c := NewCasino(nil, clock)
+	c.SetLevel(LevelBeginner)
+	c.StartGameWithSeed(5)
+	rig(c, []*Card{card(Five, Hearts)},
+		[]*Card{card(Three, Spades), card(Four, Spades)},
+		[]*Card{card(King, Hearts), card(Queen, Hearts)})
+	c.currentCard = 12
+	changed := make(chan struct{}, 16)
+	l := NewLoop(context.Background(), c, DefaultPacing, func() { changed <- struct{}{} })
+	t.Cleanup(l.Close)
+	return l, clock, changed
+}
+
+// waitChange waits for the loop to report a paced step.
+func waitChange(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("no step was carried out")
+	}
+}
+
+func TestLoopPacesTheCPUReply(t *testing.T) {
+	l, clock, changed := newTestLoop(t)
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if !l.Busy() || l.casino.State() != StateCPUTurn {
+		t.Fatalf("busy %v in %s right after the player's card", l.Busy(), l.casino.State())
+	}
+	if err := l.PlayerPlays(1); err == nil {
+		t.Error("second card accepted while the CPU was thinking")
+	}
+	clock.Advance(DefaultPacing.CPUDelay - time.Millisecond)
+	if l.casino.State() != StateCPUTurn {
+		t.Fatal("the CPU replied before its delay")
+	}
+	clock.Advance(time.Millisecond)
+	waitChange(t, changed)
+	if l.Busy() || l.casino.State() != StatePlayerTurn {
+		t.Errorf("busy %v in %s after the CPU's reply", l.Busy(), l.casino.State())
+	}
+}
+
+func TestLoopSetPacing(t *testing.T) {
+	l, clock, changed := newTestLoop(t)
+	l.SetPacing(Pacing{CPUDelay: 50 * time.Millisecond})
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	clock.Advance(50 * time.Millisecond)
+	waitChange(t, changed)
+	if l.casino.State() != StatePlayerTurn {
+		t.Errorf("in %s after the new, shorter CPU delay", l.casino.State())
+	}
+}
+
+func TestLoopRestartDropsPendingReply(t *testing.T) {
+	l, clock, changed := newTestLoop(t)
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if !l.StartGame() {
+		t.Fatal("StartGame failed")
+	}
+	if l.Busy() {
+		t.Error("still busy after a restart")
+	}
+	clock.Advance(time.Minute)
+	select {
+	case <-changed:
+		t.Fatal("the old game's CPU reply ran after the restart")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if s := l.casino.Snapshot(); s.State != StatePlayerTurn || len(s.Table) != HandSize {
+		t.Errorf("new game disturbed: %s with %d cards on the table", s.State, len(s.Table))
+	}
+}
+
+func TestLoopUndoWhileCPUThinking(t *testing.T) {
+	l, clock, changed := newTestLoop(t)
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	// Nothing can be undone before the CPU has replied, so the reply goes ahead.
+	if l.Undo() {
+		t.Fatal("Undo succeeded before the CPU's reply")
+	}
+	clock.Advance(DefaultPacing.CPUDelay)
+	waitChange(t, changed)
+	if !l.Undo() {
+		t.Fatal("Undo refused after the CPU's reply")
+	}
+	if s := l.casino.Snapshot(); s.State != StatePlayerTurn || len(s.Table) != 1 || l.Busy() {
+		t.Errorf("after undo: %s with %d cards on the table, busy %v", s.State, len(s.Table), l.Busy())
+	}
+}
+
+func TestLoopRestartStopsTheCPUThinking(t *testing.T) {
+	// A CPU that thinks until its move is called off.
+	thinking := make(chan struct{})
+	s := Strategy{Level: LevelExpert + 100, Name: "Ponderer", Choose: func(p *Position) int {
+		close(thinking)
+		select {
+		case <-p.Context.Done():
+		case <-time.After(10 * time.Second):
+		}
+		return -1
+	}}
+	RegisterStrategy(s)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(strategies, s.Level)
+		registryMu.Unlock()
+	})
+	l, clock, _ := newTestLoop(t)
+	l.casino.mu.Lock()
+	l.casino.level = s.Level
+	l.casino.mu.Unlock()
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	clock.Advance(DefaultPacing.CPUDelay)
+	select {
+	case <-thinking:
+	case <-time.After(time.Second):
+		t.Fatal("the CPU never started thinking")
+	}
+	// The game can be read meanwhile, and a new game does not wait for the move.
+	if st := l.casino.State(); st != StateCPUTurn {
+		t.Fatalf("in %s while the CPU thinks", st)
+	}
+	started := make(chan bool)
+	go func() { started <- l.StartGame() }()
+	select {
+	case ok := <-started:
+		if !ok {
+			t.Fatal("StartGame failed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the new game waited for the CPU to finish thinking")
+	}
+	if st := l.casino.Snapshot(); st.State != StatePlayerTurn || len(st.Table) != HandSize || len(st.Moves) != 0 {
+		t.Errorf("new game disturbed: %s with %d cards on the table, %d moves", st.State, len(st.Table), len(st.Moves))
+	}
+}
+
+func TestLoopRedo(t *testing.T) {
+	l, clock, changed := newTestLoop(t)
+	if err := l.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	clock.Advance(DefaultPacing.CPUDelay)
+	waitChange(t, changed)
+	if l.Redo() {
+		t.Fatal("Redo succeeded with nothing undone")
+	}
+	if !l.Undo() || !l.Redo() {
+		t.Fatal("Undo then Redo refused")
+	}
+	if s := l.casino.Snapshot(); s.State != StatePlayerTurn || len(s.Table) != 3 || l.Busy() {
+		t.Errorf("after redo: %s with %d cards on the table, busy %v", s.State, len(s.Table), l.Busy())
+	}
+}
+
+func TestLoopClose(t *testing.T) {
+	l, _, _ := newTestLoop(t)
+	l.Close()
+	if err := l.PlayerPlays(0); err != ErrLoopClosed {
+		t.Errorf("PlayerPlays after Close = %v, want %v", err, ErrLoopClosed)
+	}
+	l.Close() // Closing twice is harmless.
+}
+
+func TestLoopStopsWithItsContext(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	c := NewCasino(nil, clock)
+	c.SetLevel(LevelBeginner)
+	c.StartGameWithSeed(5)
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	l := NewLoop(ctx, c, DefaultPacing, func() { changed <- struct{}{} })
+	if err := l.PlayerPlays(firstHeld(c.PlayerHand())); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	cancel()
+	clock.Advance(time.Minute)
+	select {
+	case <-changed:
+		t.Fatal("a step ran after the context was cancelled")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := l.PlayerPlays(0); err != ErrLoopClosed {
+		t.Errorf("PlayerPlays after cancel = %v, want %v", err, ErrLoopClosed)
+	}
+}
+
+func TestLoopErrorHandler(t *testing.T) {
+	l, _, _ := newTestLoop(t)
+	errs := make(chan error, 1)
+	l.SetErrorHandler(func(err error) { errs <- err })
+	boom := errors.New("boom")
+	l.post(context.Background(), func() error { return boom })
+	select {
+	case err := <-errs:
+		if err != boom {
+			t.Errorf("handler got %v, want %v", err, boom)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the failed step was not reported")
+	}
+}
+
+func TestLoopPanicHandler(t *testing.T) {
+	l, _, _ := newTestLoop(t)
+	var value any
+	var stack []byte
+	l.SetPanicHandler(func(v any, s []byte) { value, stack = v, s })
+	if err := l.submit(func() error { panic("boom") }); err == nil {
+		t.Fatal("panicking command succeeded")
+	}
+	if value != "boom" || len(stack) == 0 {
+		t.Fatalf("handler got %v with %d bytes of stack", value, len(stack))
+	}
+	// The loop keeps serving commands.
+	if err := l.PlayerPlays(0); err != nil {
+		t.Errorf("PlayerPlays after the panic: %v", err)
+	}
+}
+
+func TestLoopSpectate(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	c := NewCasino(nil, clock)
+	c.SetLevel(LevelBeginner)
+	changed := make(chan struct{}, 16)
+	l := NewLoop(context.Background(), c, DefaultPacing, func() { changed <- struct{}{} })
+	t.Cleanup(l.Close)
+	s, _ := strategyFor(LevelAdvanced)
+	l.Spectate(&s)
+	l.StartGameWithSeed(3)
+	if !l.Busy() {
+		t.Fatal("nothing scheduled for the player's seat")
+	}
+	for steps := 0; c.State() != StateGameOver; steps++ {
+		if steps > 4*DeckSize {
+			t.Fatal("the game did not finish")
+		}
+		clock.Advance(time.Minute)
+		waitChange(t, changed)
+	}
+	// Handed back, the seat waits for the player again.
+	l.Spectate(nil)
+	l.StartGameWithSeed(3)
+	if l.Busy() || c.State() != StatePlayerTurn {
+		t.Errorf("busy %v in %s after spectating stopped", l.Busy(), c.State())
+	}
+}
+
+func TestLoopHotSeat(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	c := NewCasino(nil, clock)
+	c.SetLevel(LevelBeginner)
+	changed := make(chan struct{}, 16)
+	l := NewLoop(context.Background(), c, DefaultPacing, func() { changed <- struct{}{} })
+	t.Cleanup(l.Close)
+	l.SetHotSeat(true)
+	l.StartGameWithSeed(3)
+	if err := l.OpponentPlays(0); !errors.Is(err, ErrWrongState) {
+		t.Errorf("OpponentPlays on the player's turn = %v, want ErrWrongState", err)
+	}
+	opponentMoves := 0
+	for steps := 0; c.State() != StateGameOver; steps++ {
+		if steps > 4*DeckSize {
+			t.Fatal("the game did not finish")
+		}
+		switch {
+		case l.Busy():
+			clock.Advance(time.Minute)
+			waitChange(t, changed)
+		case c.State() == StatePlayerTurn:
+			if err := l.PlayerPlays(firstHeld(c.PlayerHand())); err != nil {
+				t.Fatal(err)
+			}
+		case c.State() == StateCPUTurn:
+			if err := l.OpponentPlays(firstHeld(c.CPUHand())); err != nil {
+				t.Fatal(err)
+			}
+			opponentMoves++
+		default:
+			t.Fatalf("stuck in %s", c.State())
+		}
+	}
+	if opponentMoves != DeckSize/2-2 {
+		t.Errorf("the second seat made %d moves, want %d", opponentMoves, DeckSize/2-2)
+	}
+}
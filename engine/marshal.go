@@ -0,0 +1,454 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// StateVersion is the version of the full state format written by
+// MarshalJSON and GobEncode. Unlike a save, which is replayed, the state
+// mirrors the casino's fields, so it changes whenever they do.
+const StateVersion = 3
+
+// countingSource is a seeded random source that counts the values drawn, so
+// its position can be saved as the seed and a count and restored by drawing
+// the same number of values again.
+type countingSource struct {
+	src   rand.Source64
+	seed  int64
+	draws uint64
+}
+
+// newCountingSource returns a source seeded with seed.
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+}
+
+// Int63 implements rand.Source.
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+// Uint64 implements rand.Source64.
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+// Seed implements rand.Source.
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.seed, s.draws = seed, 0
+}
+
+// restoreSource returns a source at the position of one seeded with seed
+// after draws values. Both methods advance the generator by one step, so
+// it does not matter which were used.
+func restoreSource(seed int64, draws uint64) *countingSource {
+	s := newCountingSource(seed)
+	for ; s.draws < draws; s.draws++ {
+		s.src.Uint64()
+	}
+	return s
+}
+
+// casinoState is the serialized form of a Casino. Cards are stored by
+// their place in the canonical deck order (see resetDeckOrder), -1 for none.
+type casinoState struct {
+	Version         int
+	Seed            int64
+	RandSeed        int64
+	RandDraws       uint64
+	StartedAt       time.Time
+	State           GameState
+	Level           GameLevel
+	Variant         string
+	VariantRules    Rules // The rules of the variant, needed for CustomVariant.
+	Rules           Rules
+	Deck            []int
+	NextCard        int
+	PlayerHand      []int
+	CPUHand         []int
+	Table           []int
+	HandMemory      []int
+	GameMemory      []int
+	InitialHidden   []int
+	SafeDiscard     int
+	InitialPileMsg  string
+	PlayerPoints    int
+	CPUPoints       int
+	PlayerCollected int
+	CPUCollected    int
+	LastPlayerSlot  int
+	LastCPUSlot     int
+	LastScorer      PlayerID
+	InitialPile     bool
+	CanUndo         bool
+	History         [][]commandData // Oldest turn first.
+	Redo            [][]commandData // Most recently undone turn last.
+	Captures        []CaptureEvent
+	Moves           []moveData
+}
+
+// commandData is the serialized form of a gameCommand. Kind says which
+// one it is; each kind uses only some of the other fields.
+type commandData struct {
+	Kind    string
+	By      PlayerID
+	Slot    int
+	Card    int   // The played card or the safe discard clue.
+	Cards   []int // Cards cleared from the table.
+	Flag    bool  // The play ended the starting pile, or the capture revealed it.
+	Points  int
+	Capture CaptureEvent
+	Message string
+	From    GameState
+	To      GameState
+	// What the command overwrote.
+	PrevScorer  PlayerID
+	PrevCard    int
+	PrevCards   []int // The hidden starting cards, or the hand memory.
+	PrevMessage string
+}
+
+// Command kinds in commandData.
+const (
+	playCommandKind    = "play"
+	captureCommandKind = "capture"
+	bonusCommandKind   = "bonus"
+	clearCommandKind   = "clear"
+	dealCommandKind    = "deal"
+	stateCommandKind   = "state"
+)
+
+// commandsData converts turns to their serialized form.
+func commandsData(turns []turn) [][]commandData {
+	data := make([][]commandData, len(turns))
+	for i, t := range turns {
+		data[i] = make([]commandData, len(t))
+		for j, cmd := range t {
+			data[i][j] = encodeCommand(cmd)
+		}
+	}
+	return data
+}
+
+// encodeCommand returns the serialized form of a command.
+func encodeCommand(cmd gameCommand) commandData {
+	switch cmd := cmd.(type) {
+	case *playCardCommand:
+		return commandData{Kind: playCommandKind, By: cmd.by, Slot: cmd.slot, Card: cardID(cmd.card), Flag: cmd.wasInitialPile}
+	case *captureCommand:
+		return commandData{Kind: captureCommandKind, Slot: cmd.slot, Capture: cmd.event,
+			Card: cardID(cmd.safeDiscard), Flag: cmd.revealsHidden, Message: cmd.message,
+			PrevScorer: cmd.prevScorer, PrevCard: cardID(cmd.prevSafeDiscard),
+			PrevCards: cardIDs(cmd.prevHidden), PrevMessage: cmd.prevMessage}
+	case *bonusCommand:
+		return commandData{Kind: bonusCommandKind, By: cmd.by, Points: cmd.points}
+	case *clearTableCommand:
+		return commandData{Kind: clearCommandKind, By: cmd.by, Cards: cardIDs(cmd.cards)}
+	case *dealCommand:
+		return commandData{Kind: dealCommandKind, PrevCard: cardID(cmd.prevSafeDiscard), PrevCards: cardIDs(cmd.prevHandMemory.cards)}
+	case *stateCommand:
+		return commandData{Kind: stateCommandKind, From: cmd.from, To: cmd.to}
+	}
+	panic(fmt.Sprintf("engine: cannot encode %T", cmd))
+}
+
+// moveData is the serialized form of a Move.
+type moveData struct {
+	By   PlayerID
+	Card int
+	Slot int
+}
+
+// cardID returns a card's place in the canonical deck order, or -1 for nil.
+func cardID(card *Card) int {
+	if card == nil {
+		return -1
+	}
+	return int(card.suit)*numRanks + int(card.face)
+}
+
+// cardIDs converts cards to their IDs.
+func cardIDs(cards []*Card) []int {
+	ids := make([]int, len(cards))
+	for i, card := range cards {
+		ids[i] = cardID(card)
+	}
+	return ids
+}
+
+// state captures the casino as a casinoState. The caller must hold the mutex.
+func (c *Casino) state() casinoState {
+	moves := make([]moveData, len(c.journal))
+	for i, m := range c.journal {
+		moves[i] = moveData{By: m.By, Card: cardID(m.Card), Slot: m.Slot}
+	}
+	return casinoState{
+		Version:         StateVersion,
+		Seed:            c.seed,
+		RandSeed:        c.rngSource.seed,
+		RandDraws:       c.rngSource.draws,
+		StartedAt:       c.startedAt,
+		State:           c.gameState,
+		Level:           c.level,
+		Variant:         c.variant.Name,
+		VariantRules:    c.variant.Rules,
+		Rules:           c.rules,
+		Deck:            cardIDs(c.deck),
+		NextCard:        c.currentCard,
+		PlayerHand:      cardIDs(c.playerCards[:]),
+		CPUHand:         cardIDs(c.cpuCards[:]),
+		Table:           cardIDs(c.tableCards.cards),
+		HandMemory:      cardIDs(c.currentHandMemory.cards),
+		GameMemory:      cardIDs(c.allPlayedCardsMemory.cards),
+		InitialHidden:   cardIDs(c.initialHiddenCards),
+		SafeDiscard:     cardID(c.safeDiscardCandidate),
+		InitialPileMsg:  c.initialPileCaptureMsg,
+		PlayerPoints:    c.playerPoint,
+		CPUPoints:       c.cpuPoint,
+		PlayerCollected: c.cardsCollectedByPlayer,
+		CPUCollected:    c.cardsCollectedByCPU,
+		LastPlayerSlot:  c.lastPlayedPlayerCard,
+		LastCPUSlot:     c.lastPlayedCPUCardIdx,
+		LastScorer:      c.lastScorer,
+		InitialPile:     c.isInitialPile,
+		CanUndo:         c.canUndo,
+		History:         commandsData(c.history),
+		Redo:            commandsData(c.redoTurns),
+		Captures:        append([]CaptureEvent(nil), c.captureHistory...),
+		Moves:           moves,
+	}
+}
+
+// cardDecoder turns card IDs back into cards, handing out one Card per ID
+// so that every place holding a card shares it, as in a live game.
+type cardDecoder struct {
+	cards [DeckSize]*Card
+	err   error
+}
+
+// newCardDecoder returns a decoder over a fresh canonical deck.
+func newCardDecoder() *cardDecoder {
+	d := &cardDecoder{}
+	for i := range d.cards {
+		d.cards[i] = NewCard(Rank(i%numRanks), Suit(i/numRanks), strconv.Itoa(i+1))
+	}
+	return d
+}
+
+// card returns the card with the given ID, nil for -1. An invalid ID is
+// remembered as the decoder's error.
+func (d *cardDecoder) card(id int) *Card {
+	if id == -1 {
+		return nil
+	}
+	if id < 0 || id >= DeckSize {
+		if d.err == nil {
+			d.err = fmt.Errorf("engine: invalid card %d in saved state", id)
+		}
+		return nil
+	}
+	return d.cards[id]
+}
+
+// list returns the cards with the given IDs, nil for an empty list.
+func (d *cardDecoder) list(ids []int) []*Card {
+	if len(ids) == 0 {
+		return nil
+	}
+	cards := make([]*Card, len(ids))
+	for i, id := range ids {
+		cards[i] = d.card(id)
+	}
+	return cards
+}
+
+// pile returns a pile of the cards with the given IDs.
+func (d *cardDecoder) pile(ids []int) Pile {
+	return Pile{cards: d.list(ids)}
+}
+
+// turns returns the turns of serialized commands. An unknown kind is
+// remembered as the decoder's error.
+func (d *cardDecoder) turns(data [][]commandData) []turn {
+	if len(data) == 0 {
+		return nil
+	}
+	turns := make([]turn, len(data))
+	for i, t := range data {
+		turns[i] = make(turn, len(t))
+		for j, cd := range t {
+			turns[i][j] = d.command(cd)
+		}
+	}
+	return turns
+}
+
+// command returns the command in its serialized form.
+func (d *cardDecoder) command(cd commandData) gameCommand {
+	switch cd.Kind {
+	case playCommandKind:
+		return &playCardCommand{by: cd.By, slot: cd.Slot, card: d.card(cd.Card), wasInitialPile: cd.Flag}
+	case captureCommandKind:
+		return &captureCommand{slot: cd.Slot, event: cd.Capture,
+			safeDiscard: d.card(cd.Card), revealsHidden: cd.Flag, message: cd.Message,
+			prevScorer: cd.PrevScorer, prevSafeDiscard: d.card(cd.PrevCard),
+			prevHidden: d.list(cd.PrevCards), prevMessage: cd.PrevMessage}
+	case bonusCommandKind:
+		return &bonusCommand{by: cd.By, points: cd.Points}
+	case clearCommandKind:
+		return &clearTableCommand{by: cd.By, cards: d.list(cd.Cards)}
+	case dealCommandKind:
+		return &dealCommand{prevSafeDiscard: d.card(cd.PrevCard), prevHandMemory: d.pile(cd.PrevCards)}
+	case stateCommandKind:
+		return &stateCommand{from: cd.From, to: cd.To}
+	}
+	if d.err == nil {
+		d.err = fmt.Errorf("engine: unknown command %q in saved state", cd.Kind)
+	}
+	return &bonusCommand{} // Does nothing; the state is refused anyway.
+}
+
+// hand returns a hand holding the cards with the given IDs by slot.
+func (d *cardDecoder) hand(ids []int) Hand {
+	var h Hand
+	if len(ids) > HandSize {
+		if d.err == nil {
+			d.err = fmt.Errorf("engine: hand of %d cards in saved state", len(ids))
+		}
+		return h
+	}
+	for i, id := range ids {
+		h[i] = d.card(id)
+	}
+	return h
+}
+
+// restoreState replaces the casino's fields with a decoded state. A state
+// that is malformed or inconsistent is refused and the casino is left as it
+// was. The caller must hold the mutex.
+func (c *Casino) restoreState(s casinoState) error {
+	if s.Version != StateVersion {
+		return fmt.Errorf("engine: saved state has format %d, this release reads %d", s.Version, StateVersion)
+	}
+	if len(s.Deck) != DeckSize {
+		return fmt.Errorf("engine: saved deck has %d cards", len(s.Deck))
+	}
+	if _, ok := variantNamed(s.Variant); !ok && s.Variant != CustomVariant {
+		return fmt.Errorf("engine: unknown rule variant %q in saved state", s.Variant)
+	}
+	if _, ok := strategyFor(s.Level); !ok && s.Level != LevelNotSelected {
+		return fmt.Errorf("engine: unknown level %d in saved state", s.Level)
+	}
+	backup := c.state()
+	err := c.applyState(s)
+	if err == nil {
+		if err = c.validate(); err != nil {
+			err = fmt.Errorf("engine: saved state is inconsistent: %w", err)
+		}
+	}
+	if err != nil {
+		c.applyState(backup) // Already known to be good.
+		return err
+	}
+	c.events = nil
+	c.emit(EventGameStarted, NoPlayer, -1) // Front ends redraw everything.
+	return nil
+}
+
+// applyState sets the casino's fields from a state, and reports a card ID
+// out of range. The clock, the session's seed source and the debug setting
+// are kept. The caller must hold the mutex.
+func (c *Casino) applyState(s casinoState) error {
+	d := newCardDecoder()
+	variant, ok := variantNamed(s.Variant)
+	if !ok {
+		variant = customVariant(s.VariantRules)
+	}
+	c.rngSource = restoreSource(s.RandSeed, s.RandDraws)
+	c.rng = rand.New(c.rngSource)
+	c.seed = s.Seed
+	c.startedAt = s.StartedAt
+	c.gameState = s.State
+	c.level = s.Level
+	c.variant = variant
+	c.rules = s.Rules
+	c.deck = d.list(s.Deck)
+	c.currentCard = s.NextCard
+	c.playerCards = d.hand(s.PlayerHand)
+	c.cpuCards = d.hand(s.CPUHand)
+	c.tableCards = d.pile(s.Table)
+	c.currentHandMemory = d.pile(s.HandMemory)
+	c.allPlayedCardsMemory = d.pile(s.GameMemory)
+	c.initialHiddenCards = d.list(s.InitialHidden)
+	c.safeDiscardCandidate = d.card(s.SafeDiscard)
+	c.initialPileCaptureMsg = s.InitialPileMsg
+	c.playerPoint = s.PlayerPoints
+	c.cpuPoint = s.CPUPoints
+	c.cardsCollectedByPlayer = s.PlayerCollected
+	c.cardsCollectedByCPU = s.CPUCollected
+	c.lastPlayedPlayerCard = s.LastPlayerSlot
+	c.lastPlayedCPUCardIdx = s.LastCPUSlot
+	c.lastScorer = s.LastScorer
+	c.isInitialPile = s.InitialPile
+	c.canUndo = s.CanUndo
+	c.history = d.turns(s.History)
+	c.pendingTurn = nil
+	c.redoTurns = d.turns(s.Redo)
+	c.captureHistory = append([]CaptureEvent(nil), s.Captures...)
+	c.journal = make([]Move, len(s.Moves))
+	for i, m := range s.Moves {
+		c.journal[i] = Move{By: m.By, Card: d.card(m.Card), Slot: m.Slot}
+	}
+	return d.err
+}
+
+// MarshalJSON encodes the full state of the casino, including the CPU's
+// memory and the history of commands, so a game can be restored exactly.
+func (c *Casino) MarshalJSON() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return json.Marshal(c.state())
+}
+
+// UnmarshalJSON restores a state written by MarshalJSON. The casino keeps
+// its clock and seed source. A state that is inconsistent is refused and
+// the casino is left unchanged.
+func (c *Casino) UnmarshalJSON(data []byte) error {
+	var s casinoState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.restoreState(s)
+}
+
+// GobEncode is the gob counterpart of MarshalJSON.
+func (c *Casino) GobEncode() ([]byte, error) {
+	c.mu.Lock()
+	s := c.state()
+	c.mu.Unlock()
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(s)
+	return buf.Bytes(), err
+}
+
+// GobDecode is the gob counterpart of UnmarshalJSON.
+func (c *Casino) GobDecode(data []byte) error {
+	var s casinoState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.restoreState(s)
+}
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// codecs encode a casino and decode it into another.
+var codecs = map[string]func(t *testing.T, from, to *Casino){
+	"JSON": func(t *testing.T, from, to *Casino) {
+		data, err := json.Marshal(from)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if err := json.Unmarshal(data, to); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+	},
+	"gob": func(t *testing.T, from, to *Casino) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(from); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		if err := gob.NewDecoder(&buf).Decode(to); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+	},
+}
+
+// step makes the next move of a game, the player always playing their first card.
+func step(t *testing.T, c *Casino) {
+	t.Helper()
+	var err error
+	switch c.State() {
+	case StatePlayerTurn:
+		if c.IsHandFinished() {
+			err = c.CheckEndOfHand()
+		} else {
+			err = c.PlayerPlays(firstHeld(c.PlayerHand()))
+		}
+	case StateCPUTurn:
+		err = c.CPUPlays()
+	case StatePileCaptured:
+		err = c.CheckEndOfHand()
+	}
+	if err != nil {
+		t.Fatalf("in %s: %v", c.State(), err)
+	}
+}
+
+// sameGame fails the test if two casinos are not in the same position.
+func sameGame(t *testing.T, got, want *Casino) {
+	t.Helper()
+	if got.StateHash() != want.StateHash() {
+		t.Fatalf("restored game differs:\n got %s\nwant %s", got.DebugDump(), want.DebugDump())
+	}
+	gs, ws := got.Snapshot(), want.Snapshot()
+	if fmt.Sprint(gs.Captures) != fmt.Sprint(ws.Captures) || !gs.StartedAt.Equal(ws.StartedAt) || got.CanUndo() != want.CanUndo() {
+		t.Fatalf("restored history differs:\n got %+v\nwant %+v", gs, ws)
+	}
+}
+
+// TestStateRoundTrip restores a game after every move and checks it then
+// plays on exactly like the original, CPU choices and undo included.
+func TestStateRoundTrip(t *testing.T) {
+	for name, codec := range codecs {
+		for _, level := range []GameLevel{LevelIntermediate, LevelAdvanced} {
+			t.Run(fmt.Sprintf("%s level %d", name, level), func(t *testing.T) {
+				original := newTestCasino(t, level, 21)
+				for moves := 0; original.State() != StateGameOver; moves++ {
+					if moves > 200 {
+						t.Fatal("game did not finish")
+					}
+					restored := NewCasino(nil, nil)
+					codec(t, original, restored)
+					if err := restored.Validate(); err != nil {
+						t.Fatalf("move %d: %v", moves, err)
+					}
+					sameGame(t, restored, original)
+					if restored.CanUndo() {
+						// Undoing must give the same position on both.
+						undone := NewCasino(nil, nil)
+						codec(t, original, undone)
+						check := NewCasino(nil, nil)
+						codec(t, original, check)
+						undone.Undo()
+						check.Undo()
+						sameGame(t, undone, check)
+					}
+					step(t, original)
+					step(t, restored)
+					sameGame(t, restored, original)
+				}
+			})
+		}
+	}
+}
+
+func TestStateRoundTripBeforeAnyGame(t *testing.T) {
+	restored := NewCasino(nil, nil)
+	codecs["JSON"](t, NewCasino(nil, nil), restored)
+	if restored.State() != StateNotStarted {
+		t.Errorf("restored an empty casino in %s", restored.State())
+	}
+	restored.SetLevel(LevelBeginner)
+	if !restored.StartGame() {
+		t.Error("could not start a game after restoring an empty casino")
+	}
+}
+
+func TestUnmarshalRefusesBadState(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 4)
+	good, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]func(s map[string]any){
+		"newer format":    func(s map[string]any) { s["Version"] = StateVersion + 1 },
+		"unknown variant": func(s map[string]any) { s["Variant"] = "No Such Rules" },
+		"card off deck":   func(s map[string]any) { s["Table"] = []any{60} },
+		"duplicate card":  func(s map[string]any) { s["Table"] = append(s["Table"].([]any), s["Table"].([]any)[0]) },
+		"short deck":      func(s map[string]any) { s["Deck"] = s["Deck"].([]any)[1:] },
+	}
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s map[string]any
+			if err := json.Unmarshal(good, &s); err != nil {
+				t.Fatal(err)
+			}
+			corrupt(s)
+			data, _ := json.Marshal(s)
+			target := newTestCasino(t, LevelAdvanced, 8)
+			before := target.StateHash()
+			if err := json.Unmarshal(data, target); err == nil {
+				t.Fatal("accepted")
+			}
+			if target.StateHash() != before {
+				t.Error("a refused state changed the casino")
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package engine
+
+// MatchScore is where a match stands: the deals are played one after the
+// other, with the scores carried over, until a side reaches the target.
+type MatchScore struct {
+	Target       int      // Points that win the match; 0 when single games are played.
+	Deal         int      // The current or last deal, counted from 1.
+	PlayerPoints int      // The player's points over every deal, the current one included.
+	CPUPoints    int      // The CPU's points, counted the same way.
+	Over         bool     // The last deal is done and a side has won.
+	Winner       PlayerID // Who won the match, NoPlayer until it is over.
+}
+
+// matchState keeps the deals of a match finished before the current one.
+type matchState struct {
+	target                  int
+	deal                    int
+	playerPoints, cpuPoints int // Scored in the earlier deals.
+}
+
+// SetMatchTarget makes the games started from now on matches, won by the
+// first side to reach the points at the end of a deal. Zero, or less, plays
+// single games again.
+func (c *Casino) SetMatchTarget(points int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.matchTarget = max(points, 0)
+}
+
+// MatchTarget returns the points that win the matches started from now on.
+func (c *Casino) MatchTarget() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.matchTarget
+}
+
+// Match returns the score of the current or last match.
+func (c *Casino) Match() MatchScore {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.matchScore()
+}
+
+// matchScore adds the current deal to the match. The caller must hold the
+// mutex.
+func (c *Casino) matchScore() MatchScore {
+	m := MatchScore{
+		Target:       c.match.target,
+		Deal:         c.match.deal,
+		PlayerPoints: c.match.playerPoints + c.playerPoint,
+		CPUPoints:    c.match.cpuPoints + c.cpuPoint,
+	}
+	// A tie at or over the target is played off with another deal.
+	if m.Target > 0 && c.gameState == StateGameOver && max(m.PlayerPoints, m.CPUPoints) >= m.Target && m.PlayerPoints != m.CPUPoints {
+		m.Over = true
+		m.Winner = CPU
+		if m.PlayerPoints > m.CPUPoints {
+			m.Winner = Player
+		}
+	}
+	return m
+}
+
+// NextDeal deals the next game of the match once the current one is over,
+// keeping the level and the scores. It reports false when the game is not
+// over, is not part of a match or ended the match.
+func (c *Casino) NextDeal() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.debugCheck()
+	if c.gameState != StateGameOver || c.match.target == 0 || c.matchScore().Over {
+		return false
+	}
+	m := c.match
+	m.deal++
+	m.playerPoints += c.playerPoint
+	m.cpuPoints += c.cpuPoint
+	level := c.level
+	c.resetGameInternal() // Resetting forgets the level, so carry it over.
+	c.level = level
+	if !c.startGame(c.seeds.Int63()) {
+		return false
+	}
+	c.match = m
+	return true
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatchCarriesScoresOver(t *testing.T) {
+	c := NewCasino(rand.NewSource(1), nil)
+	c.SetLevel(LevelAdvanced)
+	c.SetMatchTarget(101)
+	c.StartGame()
+	if m := c.Match(); m.Target != 101 || m.Deal != 1 || m.Over {
+		t.Fatalf("new match %+v", m)
+	}
+	if c.NextDeal() {
+		t.Error("dealt again before the game was over")
+	}
+	player, cpu := 0, 0
+	for deal := 1; ; deal++ {
+		if deal > 50 {
+			t.Fatal("the match never ended")
+		}
+		playOut(t, c)
+		player += c.PlayerPoints()
+		cpu += c.CPUPoints()
+		m := c.Match()
+		if m.Deal != deal || m.PlayerPoints != player || m.CPUPoints != cpu {
+			t.Fatalf("deal %d: got %+v, want %d - %d", deal, m, player, cpu)
+		}
+		if m.Over {
+			if max(player, cpu) < 101 || player == cpu || (m.Winner == Player) != (player > cpu) {
+				t.Errorf("match over at %d - %d, won by %s", player, cpu, m.Winner)
+			}
+			break
+		}
+		if !c.NextDeal() {
+			t.Fatalf("no next deal at %d - %d", player, cpu)
+		}
+		if c.Level() != LevelAdvanced || c.PlayerPoints() != 0 || c.State() != StatePlayerTurn {
+			t.Fatalf("next deal at level %d, %d points, state %s", c.Level(), c.PlayerPoints(), c.State())
+		}
+	}
+	if c.NextDeal() {
+		t.Error("dealt again after the match was won")
+	}
+	c.ResetGame()
+	c.SetLevel(LevelAdvanced)
+	c.StartGameWithSeed(7)
+	if m := c.Match(); m.Deal != 1 || m.PlayerPoints != 0 {
+		t.Errorf("a new game kept the match: %+v", m)
+	}
+}
+
+func TestSingleGames(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 3)
+	playOut(t, c)
+	if m := c.Match(); m.Target != 0 || m.Over || c.NextDeal() {
+		t.Errorf("a single game was played as a match: %+v", m)
+	}
+}
@@ -0,0 +1,107 @@
+package engine
+
+// CardOdds is what playing one card of the player's hand would lead to, as
+// far as the player can know: only cards the player has seen are counted.
+type CardOdds struct {
+	Card   *Card   // nil for an empty slot.
+	Takes  bool    // Playing it takes the pile, leaving the CPU nothing to capture.
+	Risk   float64 // Chance, from 0 to 1, that the CPU captures it with its reply.
+	Unseen int     // Cards of its face the player has not seen yet.
+}
+
+// PlayerOdds returns the odds of each slot of the player's hand, for
+// practice. The CPU is assumed to capture whenever it holds a card that can;
+// its memory and the rules it plays by are not taken into account.
+func (c *Casino) PlayerOdds() []CardOdds {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unseen := c.unseenByPlayer()
+	jacks := 0
+	for _, card := range unseen {
+		if card.face == Jack {
+			jacks++
+		}
+	}
+	cpuHeld := c.cpuCards.Len()
+	top := c.tableCards.Peek(0)
+	odds := make([]CardOdds, HandSize)
+	for i, card := range c.playerCards {
+		if card == nil {
+			continue
+		}
+		o := CardOdds{Card: card}
+		for _, u := range unseen {
+			if u.face == card.face {
+				o.Unseen++
+			}
+		}
+		o.Takes = top != nil && (card.face == Jack || card.face == top.face)
+		if !o.Takes {
+			capturers := o.Unseen + jacks
+			if card.face == Jack {
+				capturers = jacks
+			}
+			o.Risk = atLeastOne(len(unseen), capturers, cpuHeld)
+		}
+		odds[i] = o
+	}
+	return odds
+}
+
+// unseenByPlayer returns the cards the player has not seen: the rest of the
+// deck, the CPU's hand, and the face-down cards of the first pile unless the
+// player took them. The caller must hold the mutex.
+func (c *Casino) unseenByPlayer() []*Card {
+	return c.unseenBy(Player)
+}
+
+// unseenBy returns the cards the side has not seen: the rest of the deck,
+// the other side's hand, and the face-down cards of the first pile unless
+// the side took them. The caller must hold the mutex.
+func (c *Casino) unseenBy(side PlayerID) []*Card {
+	seen := make(map[*Card]bool)
+	for _, card := range c.handOf(side) {
+		seen[card] = true
+	}
+	for _, m := range c.journal {
+		seen[m.Card] = true
+	}
+	if c.currentCard > 3 {
+		seen[c.deck[3]] = true // The first pile's top card was dealt face up.
+	}
+	hidden := make(map[*Card]bool)
+	if c.initialHiddenCards != nil || len(c.captureHistory) == 0 || c.captureHistory[0].By != side {
+		for _, card := range c.deck[:3] { // Dealt face down under the first pile's top card.
+			hidden[card] = true
+		}
+	}
+	for _, card := range c.tableCards.Cards() {
+		if !hidden[card] {
+			seen[card] = true
+		}
+	}
+	var unseen []*Card
+	for _, card := range c.deck[:c.currentCard] {
+		if !seen[card] {
+			unseen = append(unseen, card)
+		}
+	}
+	return append(unseen, c.deck[c.currentCard:]...)
+}
+
+// atLeastOne returns the chance that k cards drawn from n, of which m are
+// hits, include at least one hit.
+func atLeastOne(n, m, k int) float64 {
+	if m <= 0 || k <= 0 {
+		return 0
+	}
+	// The chance of no hit is the product of (n-m-i)/(n-i) over the k draws.
+	miss := 1.0
+	for i := 0; i < k; i++ {
+		if n-m-i <= 0 {
+			return 1
+		}
+		miss *= float64(n-m-i) / float64(n-i)
+	}
+	return 1 - miss
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAtLeastOne(t *testing.T) {
+	// 1 - C(40,4)/C(47,4): four cards from 47 holding 7 hits.
+	if got, want := atLeastOne(47, 7, 4), 1-91390.0/178365.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("atLeastOne(47, 7, 4) = %v, want %v", got, want)
+	}
+	if atLeastOne(10, 0, 4) != 0 || atLeastOne(10, 3, 0) != 0 || atLeastOne(5, 3, 4) != 1 {
+		t.Error("edge cases wrong")
+	}
+}
+
+func TestPlayerOddsMatchTheCPUHands(t *testing.T) {
+	// Averaged over many deals, the risk of a card matches how often the
+	// CPU's hand could in fact capture it.
+	const games = 400
+	var risk, captured float64
+	for seed := int64(1); seed <= games; seed++ {
+		c := newTestCasino(t, LevelBeginner, seed)
+		odds := c.PlayerOdds()
+		if len(c.unseenByPlayer()) != DeckSize-HandSize-1 {
+			t.Fatalf("seed %d: %d unseen cards at the start", seed, len(c.unseenByPlayer()))
+		}
+		for _, o := range odds {
+			if o.Card == nil || o.Takes {
+				continue
+			}
+			risk += o.Risk
+			for _, cpu := range c.CPUHand() {
+				if cpu.face == o.Card.face || cpu.face == Jack {
+					captured++
+					break
+				}
+			}
+		}
+	}
+	if math.Abs(risk-captured)/captured > 0.1 {
+		t.Errorf("expected %.1f captures, the CPU could make %.0f", risk, captured)
+	}
+}
+
+func TestPlayerOddsTakes(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 1)
+	top := c.TableCards()[HandSize-1]
+	for i, o := range c.PlayerOdds() {
+		want := o.Card.face == Jack || o.Card.face == top.face
+		if o.Takes != want || (o.Takes && o.Risk != 0) || o.Unseen > 3 {
+			t.Errorf("slot %d, %v on %v: %+v", i, o.Card, top, o)
+		}
+	}
+}
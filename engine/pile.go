@@ -0,0 +1,107 @@
+package engine
+
+// Pile is a stack of cards with the top card last, such as the table pile or
+// the cards the CPU remembers. It only ever holds the cards pushed onto it,
+// so there are no counters to keep in step and no stale slots above the top.
+// The zero value is an empty pile.
+type Pile struct {
+	cards []*Card
+}
+
+// Push puts a card on top of the pile.
+func (p *Pile) Push(card *Card) {
+	p.cards = append(p.cards, card)
+}
+
+// Pop removes and returns the top card, or nil if the pile is empty.
+func (p *Pile) Pop() *Card {
+	n := len(p.cards)
+	if n == 0 {
+		return nil
+	}
+	card := p.cards[n-1]
+	p.cards[n-1] = nil
+	p.cards = p.cards[:n-1]
+	return card
+}
+
+// Peek returns the card depth places below the top, so Peek(0) is the top
+// card, or nil if the pile is not that high.
+func (p *Pile) Peek(depth int) *Card {
+	i := len(p.cards) - 1 - depth
+	if depth < 0 || i < 0 {
+		return nil
+	}
+	return p.cards[i]
+}
+
+// Len returns the number of cards in the pile.
+func (p *Pile) Len() int {
+	return len(p.cards)
+}
+
+// Cards returns a copy of the pile, bottom card first.
+func (p *Pile) Cards() []*Card {
+	return append([]*Card(nil), p.cards...)
+}
+
+// Clear empties the pile.
+func (p *Pile) Clear() {
+	clear(p.cards)
+	p.cards = p.cards[:0]
+}
+
+// clone returns an independent copy of the pile, for the undo snapshot.
+func (p *Pile) clone() Pile {
+	return Pile{cards: p.Cards()}
+}
+
+// Hand holds the cards dealt to one side, by slot. A played slot stays nil
+// until the next deal so the remaining cards keep their places on screen.
+type Hand [HandSize]*Card
+
+// Len returns the number of cards still held.
+func (h *Hand) Len() int {
+	n := 0
+	for _, card := range h {
+		if card != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Peek returns the card in the given slot, or nil if the slot is empty or
+// out of range.
+func (h *Hand) Peek(slot int) *Card {
+	if slot < 0 || slot >= HandSize {
+		return nil
+	}
+	return h[slot]
+}
+
+// Pop removes and returns the card in the given slot, or nil if there is none.
+func (h *Hand) Pop(slot int) *Card {
+	card := h.Peek(slot)
+	if card != nil {
+		h[slot] = nil
+	}
+	return card
+}
+
+// Push puts a card into the first empty slot. It reports false if the hand
+// is full.
+func (h *Hand) Push(card *Card) bool {
+	for i := range h {
+		if h[i] == nil {
+			h[i] = card
+			return true
+		}
+	}
+	return false
+}
+
+// Cards returns a copy of the slots; played slots are nil.
+func (h *Hand) Cards() []*Card {
+	return append([]*Card(nil), h[:]...)
+}
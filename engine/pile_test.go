@@ -0,0 +1,51 @@
+package engine
+
+import "testing"
+
+func TestPile(t *testing.T) {
+	var p Pile
+	if p.Len() != 0 || p.Pop() != nil || p.Peek(0) != nil {
+		t.Fatal("zero pile is not empty")
+	}
+	five, jack := card(Five, Hearts), card(Jack, Clubs)
+	p.Push(five)
+	p.Push(jack)
+	if p.Len() != 2 || p.Peek(0) != jack || p.Peek(1) != five || p.Peek(2) != nil || p.Peek(-1) != nil {
+		t.Fatalf("after two pushes: %v", p.Cards())
+	}
+	saved := p.clone()
+	if p.Pop() != jack || p.Len() != 1 {
+		t.Fatalf("Pop did not remove the top card: %v", p.Cards())
+	}
+	if saved.Len() != 2 || saved.Peek(0) != jack {
+		t.Errorf("clone changed with the original: %v", saved.Cards())
+	}
+	p.Clear()
+	if p.Len() != 0 || p.Peek(0) != nil {
+		t.Errorf("Clear left %v", p.Cards())
+	}
+}
+
+func TestHand(t *testing.T) {
+	var h Hand
+	for i := 0; i < HandSize; i++ {
+		if !h.Push(card(Five, Hearts)) {
+			t.Fatalf("hand full after %d cards", i)
+		}
+	}
+	if h.Push(card(Six, Hearts)) {
+		t.Error("pushed a fifth card")
+	}
+	played := h.Peek(1)
+	if h.Pop(1) != played || h.Len() != HandSize-1 || h.Peek(1) != nil {
+		t.Fatalf("after playing slot 1: %v", h.Cards())
+	}
+	if h.Pop(1) != nil || h.Pop(-1) != nil || h.Peek(HandSize) != nil {
+		t.Error("empty or missing slot returned a card")
+	}
+	// Refilling uses the free slot, so the other cards keep their places.
+	h.Push(played)
+	if h.Peek(1) != played {
+		t.Errorf("refilled hand: %v", h.Cards())
+	}
+}
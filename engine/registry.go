@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Strategy is a CPU opponent, offered to the player as a level. The built-in
+// levels register themselves when the package is loaded; other packages can
+// add opponents the same way, from an init function, with a level above
+// LevelExpert.
+type Strategy struct {
+	Level       GameLevel
+	Name        string // Shown in level selectors, e.g. "Beginner".
+	Description string
+	Undo        bool // Whether the player may take back moves against it.
+	// Choose returns the hand slot to play, or -1 to let the game pick a
+	// harmless card instead.
+	Choose func(p *Position) int
+}
+
+// Variant is a named set of rules a game can be played with.
+type Variant struct {
+	Name        string // Shown in rule selectors and used to select the variant.
+	Description string
+	Rules       Rules
+}
+
+// The registries, guarded by registryMu.
+var (
+	registryMu sync.RWMutex
+	strategies = make(map[GameLevel]Strategy)
+	variants   []Variant // In registration order; the first is the default.
+)
+
+// RegisterStrategy makes a CPU strategy available as its level. It panics if
+// the level is not above LevelNotSelected, is taken, or Choose is nil.
+func RegisterStrategy(s Strategy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if s.Level <= LevelNotSelected || s.Choose == nil {
+		panic(fmt.Sprintf("engine: invalid strategy %q for level %d", s.Name, s.Level))
+	}
+	if prev, ok := strategies[s.Level]; ok {
+		panic(fmt.Sprintf("engine: level %d is taken by %q", s.Level, prev.Name))
+	}
+	strategies[s.Level] = s
+}
+
+// Strategies returns the registered strategies, easiest level first.
+func Strategies() []Strategy {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	list := make([]Strategy, 0, len(strategies))
+	for _, s := range strategies {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Level < list[j].Level })
+	return list
+}
+
+// strategyFor returns the strategy registered for the level.
+func strategyFor(level GameLevel) (Strategy, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := strategies[level]
+	return s, ok
+}
+
+// RegisterVariant makes a rule variant available under its name. It panics
+// if the name is empty or taken.
+func RegisterVariant(v Variant) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if v.Name == "" {
+		panic("engine: variant without a name")
+	}
+	for _, prev := range variants {
+		if prev.Name == v.Name {
+			panic(fmt.Sprintf("engine: variant %q registered twice", v.Name))
+		}
+	}
+	variants = append(variants, v)
+}
+
+// Variants returns the registered rule variants; the first is the default.
+func Variants() []Variant {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Variant(nil), variants...)
+}
+
+// variantNamed returns the variant registered under the name.
+func variantNamed(name string) (Variant, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, v := range variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltInStrategies(t *testing.T) {
+	want := []struct {
+		level GameLevel
+		name  string
+		undo  bool
+	}{
+		{LevelBeginner, "Beginner", true},
+		{LevelIntermediate, "Intermediate", true},
+		{LevelAdvanced, "Advanced", false},
+		{LevelExpert, "Expert", false},
+	}
+	got := Strategies()
+	if len(got) < len(want) {
+		t.Fatalf("%d strategies registered, want at least %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Level != w.level || got[i].Name != w.name || got[i].Undo != w.undo {
+			t.Errorf("strategy %d = %d %q undo %v, want %d %q undo %v",
+				i, got[i].Level, got[i].Name, got[i].Undo, w.level, w.name, w.undo)
+		}
+	}
+}
+
+func TestRegisterStrategyRejectsTakenLevel(t *testing.T) {
+	defer func() {
+		if msg, _ := recover().(string); !strings.Contains(msg, "taken") {
+			t.Errorf("panic = %q, want a taken level", msg)
+		}
+	}()
+	RegisterStrategy(Strategy{Level: LevelBeginner, Name: "Copy", Choose: (*Position).cpuActionBeginner})
+}
+
+func TestSetLevelNeedsAStrategy(t *testing.T) {
+	c := NewCasino(nil, nil)
+	c.SetLevel(LevelIntermediate)
+	c.SetLevel(GameLevel(99))
+	if c.Level() != LevelIntermediate {
+		t.Errorf("level = %d after selecting an unregistered one, want %d", c.Level(), LevelIntermediate)
+	}
+}
+
+func TestVariantRules(t *testing.T) {
+	c := NewCasino(nil, nil)
+	if c.Variant() != "Standard" {
+		t.Errorf("default variant = %q, want Standard", c.Variant())
+	}
+	if c.SetVariant("No such rules") {
+		t.Error("unknown variant accepted")
+	}
+	if !c.SetVariant("Plain Pişti") {
+		t.Fatal("Plain Pişti not registered")
+	}
+	c.SetLevel(LevelBeginner)
+	c.StartGameWithSeed(1)
+	rig(c, []*Card{card(Jack, Hearts)}, []*Card{card(Jack, Spades)}, nil)
+	if err := c.PlayerPlays(0); err != nil {
+		t.Fatalf("PlayerPlays: %v", err)
+	}
+	if c.PlayerPoints() != 10 {
+		t.Errorf("Jack Pişti scored %d under plain rules, want 10", c.PlayerPoints())
+	}
+	if s := c.Snapshot(); s.Rules.JackPistiPoints != 10 {
+		t.Errorf("snapshot rules = %+v", s.Rules)
+	}
+}
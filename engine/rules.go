@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rules are the scoring rules a game is played with.
+type Rules struct {
+	PistiPoints         int  // Points for a Pişti.
+	JackPistiPoints     int  // Points for a Pişti made by a Jack on a Jack.
+	MajorityPoints      int  // Bonus for taking more cards than the other side.
+	DeuceOfClubsPoints  int  // Points of the Deuce of Clubs.
+	TenOfDiamondsPoints int  // Points of the Ten of Diamonds.
+	DiscardLastPile     bool // The cards left at the end stay on the table instead of going to the last side to capture.
+}
+
+// StandardRules pay 10 points for a Pişti and, as a house rule, 20 for a
+// Jack Pişti, 3 for the majority of the cards, 2 for the Deuce of Clubs and
+// 3 for the Ten of Diamonds. The last side to capture takes the cards left.
+var StandardRules = Rules{PistiPoints: 10, JackPistiPoints: 20, MajorityPoints: 3, DeuceOfClubsPoints: 2, TenOfDiamondsPoints: 3}
+
+// CustomVariant is the name Variant reports for rules set with SetRules.
+const CustomVariant = "Custom"
+
+func init() {
+	RegisterVariant(Variant{
+		Name: "Standard", Rules: StandardRules,
+		Description: "A Pişti scores 10 points and a Jack Pişti 20.",
+	})
+	plain := StandardRules
+	plain.JackPistiPoints = 10
+	RegisterVariant(Variant{
+		Name: "Plain Pişti", Rules: plain,
+		Description: "Every Pişti scores 10 points, Jacks included.",
+	})
+}
+
+// Check reports rules that cannot be played, such as negative points.
+func (r Rules) Check() error {
+	for _, p := range []int{r.PistiPoints, r.JackPistiPoints, r.MajorityPoints, r.DeuceOfClubsPoints, r.TenOfDiamondsPoints} {
+		if p < 0 {
+			return errors.New("engine: rules with negative points")
+		}
+	}
+	return nil
+}
+
+// fingerprint describes the rules for StateHash. The rules added after the
+// Pişti values are only written when they are not the standard ones, so the
+// hashes recorded in older saves still match.
+func (r Rules) fingerprint() string {
+	s := fmt.Sprintf("{PistiPoints:%d JackPistiPoints:%d}", r.PistiPoints, r.JackPistiPoints)
+	if r.houseRules() {
+		s += fmt.Sprintf(" majority %d deuce %d ten %d discard %v", r.MajorityPoints, r.DeuceOfClubsPoints, r.TenOfDiamondsPoints, r.DiscardLastPile)
+	}
+	return s
+}
+
+// houseRules reports whether anything but the Pişti values differs from
+// StandardRules.
+func (r Rules) houseRules() bool {
+	std := StandardRules
+	std.PistiPoints, std.JackPistiPoints = r.PistiPoints, r.JackPistiPoints
+	return r != std
+}
+
+// SetRules selects custom rules for the games started from now on; Variant
+// then reports CustomVariant. Rules that fail Check are refused.
+func (c *Casino) SetRules(r Rules) error {
+	if err := r.Check(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.variant = customVariant(r)
+	return nil
+}
+
+// customVariant is the variant Variant reports for the rules of SetRules.
+func customVariant(r Rules) Variant {
+	return Variant{Name: CustomVariant, Description: "House rules of your own.", Rules: r}
+}
+
+// Rules returns the rules selected for the next game.
+func (c *Casino) Rules() Rules {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.variant.Rules
+}
+
+// rankPoints are the points every card of a rank is worth.
+var rankPoints = [numRanks]int{Ace: 1, Jack: 1}
+
+// pointCalculator calculates points from cards currently on the table.
+func (c *Casino) pointCalculator() int {
+	// This is an internal helper that calculates points from the current table pile.
+	// It assumes the caller has already acquired the mutex lock.
+	point := 0
+	for _, card := range c.tableCards.Cards() {
+		point += c.rules.CardValue(card)
+	}
+	return point
+}
+
+// CardValue returns the point value of a single card under the rules.
+func (r Rules) CardValue(card *Card) int {
+	if card == nil {
+		return 0
+	}
+	points := rankPoints[card.face]
+	switch {
+	case card.face == Deuce && card.suit == Clubs:
+		points += r.DeuceOfClubsPoints
+	case card.face == Ten && card.suit == Diamonds:
+		points += r.TenOfDiamondsPoints
+	}
+	return points
+}
@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCardValue(t *testing.T) {
+	tests := []struct {
+		card *Card
+		want int
+	}{
+		{nil, 0},
+		{card(Ace, Hearts), 1},
+		{card(Jack, Spades), 1},
+		{card(Deuce, Clubs), 2},
+		{card(Deuce, Hearts), 0},
+		{card(Ten, Diamonds), 3},
+		{card(Ten, Clubs), 0},
+		{card(King, Diamonds), 0},
+	}
+	for _, tt := range tests {
+		if got := StandardRules.CardValue(tt.card); got != tt.want {
+			t.Errorf("CardValue(%v) = %d, want %d", tt.card, got, tt.want)
+		}
+	}
+}
+
+func TestPointCalculator(t *testing.T) {
+	tests := []struct {
+		name  string
+		table []*Card
+		want  int
+	}{
+		{"empty table", nil, 0},
+		{"no scoring cards", []*Card{card(Five, Hearts), card(King, Clubs)}, 0},
+		{"aces and jacks", []*Card{card(Ace, Hearts), card(Jack, Clubs), card(Ace, Spades)}, 3},
+		{"two of clubs and ten of diamonds", []*Card{card(Deuce, Clubs), card(Ten, Diamonds)}, 5},
+		{"other twos and tens", []*Card{card(Deuce, Spades), card(Ten, Hearts)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCasino(nil, nil)
+			setTable(c, tt.table...)
+			if got := c.pointCalculator(); got != tt.want {
+				t.Errorf("pointCalculator() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointCalculatorWholeDeck(t *testing.T) {
+	c := NewCasino(nil, nil)
+	setTable(c, c.deck...)
+	// Four aces, four jacks, the two of clubs and the ten of diamonds.
+	if got := c.pointCalculator(); got != 13 {
+		t.Errorf("points in a full deck = %d, want 13", got)
+	}
+}
+
+func TestHouseRules(t *testing.T) {
+	house := Rules{PistiPoints: 10, JackPistiPoints: 10, MajorityPoints: 0, DeuceOfClubsPoints: 5, DiscardLastPile: true}
+	if got := house.CardValue(card(Deuce, Clubs)) + house.CardValue(card(Ten, Diamonds)); got != 5 {
+		t.Errorf("the Deuce of Clubs and the Ten of Diamonds are worth %d, want 5", got)
+	}
+	c := NewCasino(rand.NewSource(5), nil)
+	if err := c.SetRules(Rules{PistiPoints: -1}); err == nil {
+		t.Error("accepted negative points")
+	}
+	if err := c.SetRules(house); err != nil {
+		t.Fatal(err)
+	}
+	if c.Variant() != CustomVariant || c.Rules() != house {
+		t.Fatalf("selected %q with %+v", c.Variant(), c.Rules())
+	}
+	c.SetLevel(LevelAdvanced)
+	c.SetDebug(true) // Validates the scores under the rules after every move.
+	c.StartGameWithSeed(5)
+	playOut(t, c)
+	s := c.Snapshot()
+	if len(s.Table) == 0 || s.PlayerCaptured+s.CPUCaptured+len(s.Table) != DeckSize {
+		t.Errorf("collected %d+%d with %d left on the table", s.PlayerCaptured, s.CPUCaptured, len(s.Table))
+	}
+	for _, e := range s.Captures {
+		if e.Final {
+			t.Errorf("the last pile was awarded: %+v", e)
+		}
+	}
+	code, err := c.GameCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := DecodeGameCode(code)
+	if err != nil {
+		t.Fatalf("DecodeGameCode(%q): %v", code, err)
+	}
+	loaded := NewCasino(nil, nil)
+	if err := loaded.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(code, houseRulesCodePrefix) || loaded.Snapshot().Rules != house || loaded.StateHash() != c.StateHash() {
+		t.Errorf("code %q loaded with %+v", code, loaded.Snapshot().Rules)
+	}
+}
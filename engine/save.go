@@ -0,0 +1,236 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SchemaVersion is the version of the saved game format written by Save. A
+// change to SavedGame that older code could misread bumps it and appends a
+// migration from the previous version to migrations.
+const SchemaVersion = 2
+
+// SavedGame is a game in the form Save writes it: the deal, the rules and
+// the slot of every card played. Replaying the moves on the same deal
+// rebuilds everything else, so the format stays small and does not change
+// when the casino's own fields do.
+type SavedGame struct {
+	Version   int         `json:"version"`
+	Seed      int64       `json:"seed"`
+	Level     GameLevel   `json:"level"`
+	Rules     Rules       `json:"rules"`
+	StartedAt time.Time   `json:"started_at"`
+	Moves     []SavedMove `json:"moves"`
+	Finished  bool        `json:"finished,omitempty"` // The game was over, its last pile awarded.
+	// Result is where the game stood when saved; older saves have none.
+	Result *SavedResult `json:"result,omitempty"`
+}
+
+// SavedMove is one card played, identified by the hand slot it came from.
+type SavedMove struct {
+	By   PlayerID `json:"by"`
+	Slot int      `json:"slot"`
+}
+
+// ErrNewerSave is returned when a save was written by a newer release.
+var ErrNewerSave = errors.New("engine: saved by a newer version of the game")
+
+// migrations upgrade a decoded save document one version at a time:
+// migrations[v] turns version v into version v+1.
+var migrations = []func(doc map[string]any) (map[string]any, error){
+	migrateSnapshot,
+	migrateHouseRules,
+}
+
+// migrateSnapshot upgrades a version 0 document, a Snapshot encoded as JSON
+// before saves had a format of their own, keeping what a replay needs.
+func migrateSnapshot(doc map[string]any) (map[string]any, error) {
+	moves, _ := doc["Moves"].([]any)
+	saved := make([]any, 0, len(moves))
+	for i, m := range moves {
+		move, ok := m.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("move %d is not an object", i+1)
+		}
+		saved = append(saved, map[string]any{"by": move["By"], "slot": move["Slot"]})
+	}
+	return map[string]any{
+		"version":    1,
+		"seed":       doc["Seed"],
+		"level":      doc["Level"],
+		"rules":      doc["Rules"],
+		"started_at": doc["StartedAt"],
+		"moves":      saved,
+		"finished":   doc["State"] == json.Number(strconv.Itoa(int(StateGameOver))),
+	}, nil
+}
+
+// migrateHouseRules upgrades a version 1 document, written when only the
+// Pişti values could be changed, to the standard values of the other rules.
+func migrateHouseRules(doc map[string]any) (map[string]any, error) {
+	rules, ok := doc["rules"].(map[string]any)
+	if !ok && doc["rules"] != nil {
+		return nil, errors.New("rules are not an object")
+	}
+	if rules == nil {
+		rules = make(map[string]any)
+		doc["rules"] = rules
+	}
+	rules["MajorityPoints"] = StandardRules.MajorityPoints
+	rules["DeuceOfClubsPoints"] = StandardRules.DeuceOfClubsPoints
+	rules["TenOfDiamondsPoints"] = StandardRules.TenOfDiamondsPoints
+	rules["DiscardLastPile"] = StandardRules.DiscardLastPile
+	doc["version"] = 2
+	return doc, nil
+}
+
+// Save encodes the current game, or the last finished one, as JSON.
+func (c *Casino) Save() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gameState == StateNotStarted {
+		return nil, fmt.Errorf("%w (no game to save)", ErrWrongState)
+	}
+	g := SavedGame{
+		Version:   SchemaVersion,
+		Seed:      c.seed,
+		Level:     c.level,
+		Rules:     c.rules,
+		StartedAt: c.startedAt,
+		Moves:     make([]SavedMove, len(c.journal)),
+		Finished:  c.gameState == StateGameOver,
+	}
+	for i, m := range c.journal {
+		g.Moves[i] = SavedMove{By: m.By, Slot: m.Slot}
+	}
+	g.Result = &SavedResult{PlayerPoints: c.playerPoint, CPUPoints: c.cpuPoint, Hash: c.stateHash()}
+	return json.Marshal(g)
+}
+
+// DecodeSave reads a save written by this or any earlier release, migrating
+// it to the current SavedGame.
+func DecodeSave(data []byte) (SavedGame, error) {
+	// Numbers are kept as written: a seed does not fit in a float64.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return SavedGame{}, fmt.Errorf("engine: reading save: %w", err)
+	}
+	version := 0 // Documents without a version are encoded snapshots.
+	if v, ok := doc["version"].(json.Number); ok {
+		n, err := v.Int64()
+		if err != nil {
+			return SavedGame{}, fmt.Errorf("engine: reading save: invalid format %s", v)
+		}
+		version = int(n)
+	}
+	if version > SchemaVersion {
+		return SavedGame{}, fmt.Errorf("%w (format %d, this release reads up to %d)", ErrNewerSave, version, SchemaVersion)
+	}
+	if version < 0 {
+		return SavedGame{}, fmt.Errorf("engine: reading save: invalid format %d", version)
+	}
+	for ; version < SchemaVersion; version++ {
+		var err error
+		if doc, err = migrations[version](doc); err != nil {
+			return SavedGame{}, fmt.Errorf("engine: upgrading save from format %d: %w", version, err)
+		}
+	}
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return SavedGame{}, fmt.Errorf("engine: reading save: %w", err)
+	}
+	var g SavedGame
+	if err := json.Unmarshal(upgraded, &g); err != nil {
+		return SavedGame{}, fmt.Errorf("engine: reading save: %w", err)
+	}
+	return g, nil
+}
+
+// Branch returns the save of the game cut short before its move with the
+// given index, so an alternative line can be played from there after Load.
+// The move must be the player's, or the end of the game's moves, so the
+// player has the next card once the CPU's last capture clears.
+func Branch(data []byte, moves int) ([]byte, error) {
+	g, err := DecodeSave(data)
+	if err != nil {
+		return nil, err
+	}
+	if moves < 0 || moves > len(g.Moves) {
+		return nil, fmt.Errorf("engine: no move %d to branch at in a game of %d", moves, len(g.Moves))
+	}
+	if moves < len(g.Moves) && g.Moves[moves].By != Player {
+		return nil, fmt.Errorf("engine: move %d is not the player's", moves+1)
+	}
+	g.Version = SchemaVersion
+	g.Moves = g.Moves[:moves]
+	g.Finished, g.Result = false, nil
+	return json.Marshal(g)
+}
+
+// Load replaces the game with a saved one, dealing the same cards and
+// replaying its moves. The level must still be registered. On error the
+// casino is left without a game. Load does not compare the outcome with the
+// recorded result; Verify does.
+func (c *Casino) Load(data []byte) error {
+	return c.Replay(data, nil)
+}
+
+// Replay is Load, calling step after the deal, after every replayed move
+// and once a finished game is scored, so the game can be shown as it was
+// played. step may read the casino but
+// must not change it.
+func (c *Casino) Replay(data []byte, step func()) error {
+	if step == nil {
+		step = func() {}
+	}
+	g, err := DecodeSave(data)
+	if err != nil {
+		return err
+	}
+	if _, ok := strategyFor(g.Level); !ok {
+		return fmt.Errorf("engine: saved game has unknown level %d", g.Level)
+	}
+	c.ResetGame()
+	c.SetLevel(g.Level)
+	c.StartGameWithSeed(g.Seed)
+	c.mu.Lock()
+	c.rules = g.Rules // Scored as it was saved, even if the variant has changed since.
+	c.startedAt = g.StartedAt
+	c.mu.Unlock()
+	step()
+	for i, m := range g.Moves {
+		err := c.CheckEndOfHand()
+		switch {
+		case err != nil:
+		case m.By == Player:
+			err = c.PlayerPlays(m.Slot)
+		case m.By == CPU:
+			err = c.cpuPlays(m.Slot)
+		default:
+			err = fmt.Errorf("played by %s", m.By)
+		}
+		if err != nil {
+			c.ResetGame()
+			return fmt.Errorf("engine: replaying saved move %d: %w", i+1, err)
+		}
+		step()
+	}
+	if g.Finished {
+		err := c.CheckEndOfHand()
+		if err == nil && c.State() != StateGameOver {
+			err = errors.New("its moves do not finish it")
+		}
+		if err != nil {
+			c.ResetGame()
+			return fmt.Errorf("engine: finishing saved game: %w", err)
+		}
+		step()
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	c := newTestCasino(t, LevelAdvanced, 11)
+	for i := 0; i < 15; i++ {
+		c.CheckEndOfHand()
+		switch c.State() {
+		case StatePlayerTurn:
+			c.PlayerPlays(firstHeld(c.PlayerHand()))
+		case StateCPUTurn:
+			c.CPUPlays()
+		case StatePileCaptured:
+			c.FinalizeCapture()
+		}
+	}
+	data, err := c.Save()
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded := NewCasino(nil, nil)
+	if err := loaded.Load(data); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want, got := c.Snapshot(), loaded.Snapshot()
+	if got.Seed != want.Seed || got.Level != want.Level || got.State != want.State ||
+		got.PlayerPoints != want.PlayerPoints || got.CPUPoints != want.CPUPoints ||
+		got.CardsDealt != want.CardsDealt || len(got.Moves) != len(want.Moves) {
+		t.Errorf("loaded game differs:\n got %+v\nwant %+v", got, want)
+	}
+	// The cards are compared by name, as every casino has a deck of its own.
+	if fmt.Sprint(got.PlayerHand, got.CPUHand, got.Table) != fmt.Sprint(want.PlayerHand, want.CPUHand, want.Table) {
+		t.Error("loaded game holds different cards")
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("loaded game is inconsistent: %v", err)
+	}
+}
+
+// TestLoadOlderFormats loads a save of every format ever written. Each file
+// holds the same game, nine moves into seed 42 at Intermediate.
+func TestLoadOlderFormats(t *testing.T) {
+	for version := 0; version <= SchemaVersion; version++ {
+		name := "testdata/save_v" + string(rune('0'+version)) + ".json"
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("no save of format %d: %v", version, err)
+		}
+		c := NewCasino(nil, nil)
+		if err := c.Load(data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		s := c.Snapshot()
+		if s.Seed != 42 || s.Level != LevelIntermediate || len(s.Moves) != 9 || s.CardsDealt != 20 ||
+			s.PlayerPoints != 3 || s.CPUPoints != 0 || s.State != StateCPUTurn || s.Rules != StandardRules {
+			t.Errorf("%s loaded as %+v", name, s)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"not JSON", `{"version": 1,`, nil},
+		{"newer format", `{"version": 99}`, ErrNewerSave},
+		{"unknown level", `{"version": 1, "seed": 1, "level": 42}`, nil},
+		{"empty slot", `{"version": 1, "seed": 1, "level": 1, "moves": [{"by": 1, "slot": 0}, {"by": 2, "slot": 0}, {"by": 1, "slot": 0}]}`, ErrEmptySlot},
+		{"out of turn", `{"version": 1, "seed": 1, "level": 1, "moves": [{"by": 2, "slot": 0}]}`, ErrWrongState},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCasino(nil, nil)
+			err := c.Load([]byte(tc.data))
+			if err == nil {
+				t.Fatal("loaded")
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("got %v, want %v", err, tc.want)
+			}
+			if c.State() != StateNotStarted {
+				t.Errorf("left in %s after a failed load", c.State())
+			}
+		})
+	}
+}
+
+func TestReplaySteps(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 5)
+	playOut(t, c)
+	data, err := c.Save()
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	replayed := NewCasino(nil, nil)
+	var moves []int
+	if err := replayed.Replay(data, func() { moves = append(moves, len(replayed.Snapshot().Moves)) }); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	played := len(c.Snapshot().Moves)
+	if len(moves) != played+2 || moves[0] != 0 || moves[played] != played || moves[played+1] != played {
+		t.Fatalf("steps saw %v moves, want 0 to %d and the scored end", moves, played)
+	}
+	if replayed.State() != StateGameOver || replayed.PlayerPoints() != c.PlayerPoints() {
+		t.Fatalf("replay ended in %v at %d points, want game over at %d", replayed.State(), replayed.PlayerPoints(), c.PlayerPoints())
+	}
+}
+
+func TestLoadKeepsLargeSeeds(t *testing.T) {
+	const seed = 8674665223082153551 // Not a float64.
+	c := newTestCasino(t, LevelBeginner, seed)
+	step(t, c)
+	data, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewCasino(nil, nil)
+	if err := loaded.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Seed() != seed {
+		t.Fatalf("loaded seed %d, want %d", loaded.Seed(), int64(seed))
+	}
+}
+
+func TestBranch(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 5)
+	playOut(t, c)
+	data, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := c.Snapshot().Moves
+	for i, m := range moves {
+		branched, err := Branch(data, i)
+		if m.By != Player {
+			if err == nil {
+				t.Errorf("branched at the CPU's move %d", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Branch at %d: %v", i, err)
+		}
+		loaded := NewCasino(nil, nil)
+		if err := loaded.Load(branched); err != nil {
+			t.Fatalf("Load of the branch at %d: %v", i, err)
+		}
+		// The CPU's last card may have left a pile to clear or a hand to deal.
+		if loaded.State() == StatePileCaptured {
+			loaded.FinalizeCapture()
+		}
+		loaded.CheckEndOfHand()
+		if s := loaded.Snapshot(); s.State != StatePlayerTurn || len(s.Moves) != i {
+			t.Fatalf("branch at %d: %s after %d moves", i, s.State, len(s.Moves))
+		}
+	}
+	if _, err := Branch(data, len(moves)+1); err == nil {
+		t.Error("branched past the end")
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// SimResult is the outcome of one simulated game.
+type SimResult struct {
+	Seed         int64
+	PlayerPoints int
+	CPUPoints    int
+	PlayerPistis int // Pişti captures, Jack Piştis included.
+	CPUPistis    int
+}
+
+// Winner returns who scored more, or NoPlayer for a draw.
+func (r SimResult) Winner() PlayerID {
+	switch {
+	case r.PlayerPoints > r.CPUPoints:
+		return Player
+	case r.CPUPoints > r.PlayerPoints:
+		return CPU
+	}
+	return NoPlayer
+}
+
+// Simulate plays a whole game dealt from seed against the CPU at the given
+// level under the named rule variant, without pauses or a screen. The player
+// side always plays its first card, a fixed baseline for comparing levels.
+// An empty variant name picks the default rules.
+func Simulate(level GameLevel, variant string, seed int64) (SimResult, error) {
+	return SimulateMatch(LevelNotSelected, level, variant, seed)
+}
+
+// SimulateMatch is Simulate with the strategy at playerLevel taking the
+// player's seat, so two CPU levels can be played against each other.
+// LevelNotSelected seats Simulate's baseline player instead. The player's
+// strategy draws from its own random source and is not given the safe
+// discard clue, which the casino only keeps for the CPU.
+func SimulateMatch(playerLevel, cpuLevel GameLevel, variant string, seed int64) (SimResult, error) {
+	if _, ok := strategyFor(cpuLevel); !ok {
+		return SimResult{}, fmt.Errorf("no CPU strategy for level %d", cpuLevel)
+	}
+	player, ok := strategyFor(playerLevel)
+	if !ok && playerLevel != LevelNotSelected {
+		return SimResult{}, fmt.Errorf("no CPU strategy for level %d", playerLevel)
+	}
+	c := NewCasino(nil, nil)
+	if variant != "" && !c.SetVariant(variant) {
+		return SimResult{}, fmt.Errorf("unknown rule variant %q", variant)
+	}
+	c.SetLevel(cpuLevel)
+	c.StartGameWithSeed(seed)
+	playerRand := rand.New(rand.NewSource(seed))
+	for moves := 0; c.State() != StateGameOver; moves++ {
+		if moves > 4*DeckSize {
+			return SimResult{}, errors.New("game did not finish")
+		}
+		err := c.CheckEndOfHand()
+		switch c.State() {
+		case StatePlayerTurn:
+			slot := -1
+			if player.Choose != nil {
+				slot = c.PlayerChoice(player, playerRand)
+			}
+			if slot < 0 || c.PlayerHand()[slot] == nil {
+				slot = firstHeld(c.PlayerHand())
+			}
+			err = errors.Join(err, c.PlayerPlays(slot))
+		case StateCPUTurn:
+			err = errors.Join(err, c.CPUPlays())
+		case StatePileCaptured:
+			c.FinalizeCapture()
+		}
+		if err != nil {
+			return SimResult{}, fmt.Errorf("seed %d, move %d: %w", seed, moves, err)
+		}
+	}
+	result := SimResult{Seed: seed, PlayerPoints: c.PlayerPoints(), CPUPoints: c.CPUPoints()}
+	captures, _ := c.CapturesSince(0)
+	for _, e := range captures {
+		switch {
+		case !e.Pisti:
+		case e.By == Player:
+			result.PlayerPistis++
+		default:
+			result.CPUPistis++
+		}
+	}
+	return result, nil
+}
+
+// PlayerChoice returns the slot the strategy s would play from the player's
+// seat, or -1, for simulations and move hints. The strategy draws from rng,
+// so asking leaves the CPU's choices in the game unchanged.
+func (c *Casino) PlayerChoice(s Strategy, rng *rand.Rand) int {
+	return c.playerChoice(context.Background(), s, rng)
+}
+
+// playerChoice is PlayerChoice for a move that is called off once ctx is
+// done. The strategy chooses without the mutex held.
+func (c *Casino) playerChoice(ctx context.Context, s Strategy, rng *rand.Rand) int {
+	c.mu.Lock()
+	p := c.positionOf(Player)
+	c.mu.Unlock()
+	p.Rand, p.Context = rng, ctx
+	return s.Choose(&p)
+}
+
+// firstHeld returns the first occupied slot of a hand, or -1 if it is empty.
+func firstHeld(hand []*Card) int {
+	for i, card := range hand {
+		if card != nil {
+			return i
+		}
+	}
+	return -1
+}
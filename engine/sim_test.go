@@ -0,0 +1,55 @@
+package engine
+
+import "testing"
+
+func TestSimulate(t *testing.T) {
+	for _, s := range Strategies() {
+		first, err := Simulate(s.Level, "", 7)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		again, err := Simulate(s.Level, "", 7)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		if first != again {
+			t.Errorf("%s: seed 7 gave %+v, then %+v", s.Name, first, again)
+		}
+		if first.PlayerPoints+first.CPUPoints == 0 {
+			t.Errorf("%s: nobody scored in %+v", s.Name, first)
+		}
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	if _, err := Simulate(LevelNotSelected, "", 1); err == nil {
+		t.Error("simulated a game without a level")
+	}
+	if _, err := Simulate(LevelBeginner, "No Such Rules", 1); err == nil {
+		t.Error("simulated a game under unknown rules")
+	}
+}
+
+func TestSimulateMatch(t *testing.T) {
+	result, err := SimulateMatch(LevelAdvanced, LevelBeginner, "", 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := SimulateMatch(LevelAdvanced, LevelBeginner, "", 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != again {
+		t.Errorf("seed 11 gave %+v, then %+v", result, again)
+	}
+	baseline, err := SimulateMatch(LevelNotSelected, LevelBeginner, "", 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := Simulate(LevelBeginner, "", 11); baseline != want {
+		t.Errorf("baseline seat gave %+v, Simulate %+v", baseline, want)
+	}
+	if _, err := SimulateMatch(GameLevel(99), LevelBeginner, "", 1); err == nil {
+		t.Error("simulated a game with an unknown player level")
+	}
+}
@@ -0,0 +1,47 @@
+package engine
+
+import "time"
+
+// Snapshot is a consistent, read-only copy of a game taken at one moment.
+// Together with the level and rules, Seed is enough to deal the same game again with
+// StartGameWithSeed, so a snapshot can be saved to replay or report a game.
+type Snapshot struct {
+	Seed           int64     // Seed the current (or last finished) game was shuffled with.
+	StartedAt      time.Time // When that game was started, according to the casino's clock.
+	State          GameState
+	Level          GameLevel
+	Rules          Rules // The rules the game is played with.
+	PlayerPoints   int
+	CPUPoints      int
+	PlayerCaptured int     // Cards collected by the player so far.
+	CPUCaptured    int     // Cards collected by the CPU so far.
+	CardsDealt     int     // Cards taken from the deck, including the starting pile.
+	PlayerHand     []*Card // Played slots are nil.
+	CPUHand        []*Card // Played slots are nil.
+	Table          []*Card // Top card last.
+	Captures       []CaptureEvent
+	Moves          []Move // Every card played so far, oldest first.
+}
+
+// Snapshot returns a copy of the current game state.
+func (c *Casino) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Snapshot{
+		Seed:           c.seed,
+		StartedAt:      c.startedAt,
+		State:          c.gameState,
+		Level:          c.level,
+		Rules:          c.rules,
+		PlayerPoints:   c.playerPoint,
+		CPUPoints:      c.cpuPoint,
+		PlayerCaptured: c.cardsCollectedByPlayer,
+		CPUCaptured:    c.cardsCollectedByCPU,
+		CardsDealt:     c.currentCard,
+		PlayerHand:     c.playerCards.Cards(),
+		CPUHand:        c.cpuCards.Cards(),
+		Table:          c.tableCards.Cards(),
+		Captures:       append([]CaptureEvent(nil), c.captureHistory...),
+		Moves:          append([]Move(nil), c.journal...),
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GameState defines the possible states of the game.
+type GameState int
+
+const (
+	StateNotStarted GameState = iota
+	StatePlayerTurn
+	StateCPUTurn
+	StateGameOver
+	StatePileCaptured
+)
+
+// String returns the name of the state, for error messages and logs.
+func (s GameState) String() string {
+	switch s {
+	case StateNotStarted:
+		return "not started"
+	case StatePlayerTurn:
+		return "player's turn"
+	case StateCPUTurn:
+		return "CPU's turn"
+	case StateGameOver:
+		return "game over"
+	case StatePileCaptured:
+		return "pile captured"
+	}
+	return fmt.Sprintf("GameState(%d)", int(s))
+}
+
+// transitions lists the states each state may move to. Any state may also go
+// back to StateNotStarted when the game is reset. A captured pile must be
+// cleared (StatePileCaptured to a turn) before the hand or the game can end.
+var transitions = map[GameState][]GameState{
+	StateNotStarted:   {StatePlayerTurn},
+	StatePlayerTurn:   {StateCPUTurn, StatePileCaptured, StateGameOver},
+	StateCPUTurn:      {StatePlayerTurn, StatePileCaptured, StateGameOver},
+	StatePileCaptured: {StatePlayerTurn, StateCPUTurn},
+	StateGameOver:     {},
+}
+
+// ErrWrongState is returned when an action is not allowed in the current state,
+// such as the player moving during the CPU's turn.
+var ErrWrongState = errors.New("engine: action not allowed in the current state")
+
+// ErrEmptySlot is returned when the player tries to play an empty hand slot.
+var ErrEmptySlot = errors.New("engine: no card in that hand slot")
+
+// canTransition reports whether the game may move from one state to another.
+func canTransition(from, to GameState) bool {
+	if from == to || to == StateNotStarted {
+		return true
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// setState is the single place the game state changes. Illegal changes are
+// refused with an error; legal ones are recorded in the history and reported
+// as an event. The caller must hold the mutex.
+func (c *Casino) setState(state GameState) error {
+	if !canTransition(c.gameState, state) {
+		return fmt.Errorf("engine: illegal transition from %s to %s", c.gameState, state)
+	}
+	if c.gameState != state {
+		c.do(&stateCommand{from: c.gameState, to: state})
+	}
+	return nil
+}
+
+// requireState returns ErrWrongState unless the game is in one of the given states.
+// The caller must hold the mutex.
+func (c *Casino) requireState(allowed ...GameState) error {
+	for _, state := range allowed {
+		if c.gameState == state {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w (%s)", ErrWrongState, c.gameState)
+}
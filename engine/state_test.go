@@ -0,0 +1,42 @@
+package engine
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to GameState
+		want     bool
+	}{
+		{StateNotStarted, StatePlayerTurn, true},
+		{StateNotStarted, StateCPUTurn, false},
+		{StatePlayerTurn, StateCPUTurn, true},
+		{StatePlayerTurn, StatePileCaptured, true},
+		{StateCPUTurn, StatePlayerTurn, true},
+		{StateCPUTurn, StateGameOver, true},
+		{StatePileCaptured, StateCPUTurn, true},
+		{StatePileCaptured, StateGameOver, false},
+		{StateGameOver, StatePlayerTurn, false},
+		{StateGameOver, StateNotStarted, true},
+		{StatePileCaptured, StatePileCaptured, true},
+	}
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestSetStateRejectsIllegalTransition(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 1)
+	c.gameState = StatePileCaptured
+	c.Events()
+	if err := c.setState(StateGameOver); err == nil {
+		t.Fatal("setState(StateGameOver) from StatePileCaptured succeeded")
+	}
+	if c.gameState != StatePileCaptured {
+		t.Errorf("state changed to %s after a refused transition", c.gameState)
+	}
+	if events := c.Events(); len(events) != 0 {
+		t.Errorf("refused transition emitted %v", events)
+	}
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetDebug turns the debug mode on or off. In debug mode the casino audits
+// itself with Validate after every move and panics with a full state dump
+// on the first inconsistency, so a corrupting bug is caught where it happens
+// rather than several moves later.
+func (c *Casino) SetDebug(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debug = enabled
+}
+
+// Validate checks that every card is in exactly one place and that the
+// counters agree with each other and with the capture history. It returns
+// nil when the game is consistent.
+func (c *Casino) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.validate()
+}
+
+// debugCheck validates the game in debug mode and panics on a violation.
+// Mutating methods defer it right after taking the mutex.
+func (c *Casino) debugCheck() {
+	if !c.debug {
+		return
+	}
+	if err := c.validate(); err != nil {
+		panic(fmt.Sprintf("engine: invariant violated: %v\n%s", err, c.dump()))
+	}
+}
+
+// validate implements Validate. The caller must hold the mutex.
+func (c *Casino) validate() error {
+	if c.gameState == StateNotStarted {
+		return nil // Nothing has been dealt.
+	}
+	if c.currentCard < 0 || c.currentCard > DeckSize {
+		return fmt.Errorf("deck counter out of range at %d", c.currentCard)
+	}
+	seen := make(map[*Card]string)
+	place := func(where string, card *Card) error {
+		if prev, ok := seen[card]; ok {
+			return fmt.Errorf("%v is both in %s and in %s", card, prev, where)
+		}
+		seen[card] = where
+		return nil
+	}
+	playerLeft, cpuLeft := 0, 0
+	for i := 0; i < HandSize; i++ {
+		if card := c.playerCards[i]; card != nil {
+			if err := place("the player's hand", card); err != nil {
+				return err
+			}
+			playerLeft++
+		}
+		if card := c.cpuCards[i]; card != nil {
+			if err := place("the CPU's hand", card); err != nil {
+				return err
+			}
+			cpuLeft++
+		}
+	}
+	for i, card := range c.tableCards.Cards() {
+		if card == nil {
+			return fmt.Errorf("nil card at table position %d of %d", i, c.tableCards.Len())
+		}
+		if err := place("the table pile", card); err != nil {
+			return err
+		}
+	}
+	for _, card := range c.deck[c.currentCard:] {
+		if err := place("the deck", card); err != nil {
+			return err
+		}
+	}
+	// A captured pile is already credited to its taker while it is shown.
+	onTable := c.tableCards.Len()
+	if c.gameState == StatePileCaptured {
+		if onTable < 2 {
+			return fmt.Errorf("captured pile of %d cards", onTable)
+		}
+		onTable = 0
+	}
+	inHands := playerLeft + cpuLeft
+	total := inHands + onTable + c.cardsCollectedByPlayer + c.cardsCollectedByCPU + DeckSize - c.currentCard
+	if total != DeckSize {
+		return fmt.Errorf("%d cards accounted for (hands %d, table %d, collected %d+%d, deck %d), want %d", total,
+			inHands, onTable, c.cardsCollectedByPlayer, c.cardsCollectedByCPU, DeckSize-c.currentCard, DeckSize)
+	}
+	// The player leads every round, so the CPU holds as many cards or one more.
+	if cpuLeft != playerLeft && cpuLeft != playerLeft+1 {
+		return fmt.Errorf("player holds %d cards and the CPU %d", playerLeft, cpuLeft)
+	}
+	return c.validateScores()
+}
+
+// validateScores checks the collected cards and points against the capture
+// history, which records every card and point a side has won except the
+// card bonus at the end. The caller must hold the mutex.
+func (c *Casino) validateScores() error {
+	var cards, points [CPU + 1]int
+	for _, e := range c.captureHistory {
+		if e.By != Player && e.By != CPU {
+			return fmt.Errorf("capture by unknown side %d", e.By)
+		}
+		cards[e.By] += e.Cards
+		points[e.By] += e.Points
+	}
+	if c.gameState == StateGameOver {
+		if c.cardsCollectedByPlayer > c.cardsCollectedByCPU {
+			points[Player] += c.rules.MajorityPoints
+		} else if c.cardsCollectedByCPU > c.cardsCollectedByPlayer {
+			points[CPU] += c.rules.MajorityPoints
+		}
+	}
+	if cards[Player] != c.cardsCollectedByPlayer || cards[CPU] != c.cardsCollectedByCPU {
+		return fmt.Errorf("collected %d+%d cards but the history records %d+%d",
+			c.cardsCollectedByPlayer, c.cardsCollectedByCPU, cards[Player], cards[CPU])
+	}
+	if points[Player] != c.playerPoint || points[CPU] != c.cpuPoint {
+		return fmt.Errorf("score is %d-%d but the history adds up to %d-%d",
+			c.playerPoint, c.cpuPoint, points[Player], points[CPU])
+	}
+	return nil
+}
+
+// DebugDump describes the whole game state, including the CPU's memory, for
+// debugging tools and crash reports.
+func (c *Casino) DebugDump() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dump()
+}
+
+// dump describes the whole game state for debugging. The caller must hold the mutex.
+func (c *Casino) dump() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "state %s, level %d, seed %d, rules %+v, deck at %d/%d\n", c.gameState, c.level, c.seed, c.rules, c.currentCard, DeckSize)
+	fmt.Fprintf(&b, "score %d-%d, collected %d+%d, last scorer %s\n",
+		c.playerPoint, c.cpuPoint, c.cardsCollectedByPlayer, c.cardsCollectedByCPU, c.lastScorer)
+	fmt.Fprintf(&b, "player hand: %v\n", c.playerCards)
+	fmt.Fprintf(&b, "CPU hand:    %v\n", c.cpuCards)
+	fmt.Fprintf(&b, "table (%d):  %v\n", c.tableCards.Len(), c.tableCards.Cards())
+	fmt.Fprintf(&b, "deck:        %v\n", c.deck[min(max(c.currentCard, 0), DeckSize):])
+	fmt.Fprintf(&b, "captures:    %+v\n", c.captureHistory)
+	fmt.Fprintf(&b, "CPU memory:  hand %v, game %v, safe discard %v\n", c.currentHandMemory.Cards(),
+		c.allPlayedCardsMemory.Cards(), c.safeDiscardCandidate)
+	fmt.Fprintf(&b, "can undo %v, initial pile %v", c.canUndo, c.isInitialPile)
+	return b.String()
+}
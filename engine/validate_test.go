@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDetectsCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(c *Casino)
+		want    string
+	}{
+		{
+			name:    "card in two places",
+			corrupt: func(c *Casino) { c.playerCards[0] = c.cpuCards[0] },
+			want:    "both in",
+		},
+		{
+			name:    "card dropped from the table",
+			corrupt: func(c *Casino) { c.tableCards.Pop() },
+			want:    "accounted for",
+		},
+		{
+			name: "lost card",
+			corrupt: func(c *Casino) {
+				c.playerCards[1], c.cpuCards[1] = nil, nil
+			},
+			want: "accounted for",
+		},
+		{
+			name:    "score without a capture",
+			corrupt: func(c *Casino) { c.playerPoint += 10 },
+			want:    "history",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCasino(t, LevelBeginner, 3)
+			if err := c.Validate(); err != nil {
+				t.Fatalf("fresh game invalid: %v", err)
+			}
+			tt.corrupt(c)
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugModePanicsWithDump(t *testing.T) {
+	c := newTestCasino(t, LevelBeginner, 3)
+	c.SetDebug(true)
+	c.cpuCards[0] = c.playerCards[0]
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "invariant violated") || !strings.Contains(msg, "player hand:") {
+			t.Errorf("panic = %q, want a violation with a state dump", msg)
+		}
+	}()
+	c.PlayerPlays(1)
+	t.Error("corrupted game did not panic")
+}
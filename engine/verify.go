@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// ErrReplayMismatch is returned by Verify when replaying a recorded game
+// does not end where the recording says it did.
+var ErrReplayMismatch = errors.New("engine: replay does not match the recording")
+
+// SavedResult is where a recorded game stood when it was saved, for Verify.
+type SavedResult struct {
+	PlayerPoints int    `json:"player_points"`
+	CPUPoints    int    `json:"cpu_points"`
+	Hash         string `json:"hash"` // StateHash of the game.
+}
+
+// StateHash returns a fingerprint of the game: the deal, the rules, the
+// moves and every card's place and the scores they led to. Two casinos that
+// played the same game have the same hash, whatever their clocks said.
+func (c *Casino) StateHash() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stateHash()
+}
+
+// stateHash implements StateHash. The caller must hold the mutex.
+func (c *Casino) stateHash() string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "seed %d level %d rules %s state %d dealt %d\n", c.seed, c.level, c.rules.fingerprint(), c.gameState, c.currentCard)
+	fmt.Fprintf(h, "points %d %d captured %d %d\n", c.playerPoint, c.cpuPoint, c.cardsCollectedByPlayer, c.cardsCollectedByCPU)
+	fmt.Fprintf(h, "hands %v %v table %v\n", c.playerCards.Cards(), c.cpuCards.Cards(), c.tableCards.Cards())
+	for _, m := range c.journal {
+		fmt.Fprintf(h, "%s\n", m)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Verify replays a saved game from its seed and checks that it ends with the
+// recorded scores and state hash. A mismatch, reported as ErrReplayMismatch,
+// means the recording was corrupted or the rules have changed since it was
+// made. Saves without a recorded result are only checked to replay cleanly.
+func Verify(data []byte) error {
+	g, err := DecodeSave(data)
+	if err != nil {
+		return err
+	}
+	c := NewCasino(nil, nil)
+	if err := c.Load(data); err != nil {
+		return err
+	}
+	if g.Result == nil {
+		return nil
+	}
+	c.mu.Lock()
+	got := SavedResult{PlayerPoints: c.playerPoint, CPUPoints: c.cpuPoint, Hash: c.stateHash()}
+	c.mu.Unlock()
+	if got != *g.Result {
+		return fmt.Errorf("%w: recorded %d-%d (%s), replayed %d-%d (%s)", ErrReplayMismatch,
+			g.Result.PlayerPoints, g.Result.CPUPoints, g.Result.Hash, got.PlayerPoints, got.CPUPoints, got.Hash)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	c := newTestCasino(t, LevelIntermediate, 3)
+	playOut(t, c)
+	data, err := c.Save()
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := Verify(data); err != nil {
+		t.Fatalf("Verify of an untouched save: %v", err)
+	}
+	var g SavedGame
+	if err := json.Unmarshal(data, &g); err != nil {
+		t.Fatal(err)
+	}
+
+	rescored := g
+	rescored.Result = &SavedResult{PlayerPoints: g.Result.PlayerPoints + 1, CPUPoints: g.Result.CPUPoints, Hash: g.Result.Hash}
+	if err := Verify(mustMarshal(t, rescored)); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("changed score: got %v, want ErrReplayMismatch", err)
+	}
+	rerules := g
+	rerules.Rules = Rules{PistiPoints: 10, JackPistiPoints: 10}
+	if rerules.Rules != g.Rules {
+		if err := Verify(mustMarshal(t, rerules)); !errors.Is(err, ErrReplayMismatch) {
+			t.Errorf("changed rules: got %v, want ErrReplayMismatch", err)
+		}
+	}
+}
+
+func TestVerifyWithoutResult(t *testing.T) {
+	data, err := os.ReadFile("testdata/save_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(data); err != nil {
+		t.Errorf("save without a result: %v", err)
+	}
+}
+
+func TestStateHash(t *testing.T) {
+	a := newTestCasino(t, LevelBeginner, 9)
+	b := newTestCasino(t, LevelBeginner, 9)
+	if a.StateHash() != b.StateHash() {
+		t.Fatal("the same deal hashes differently")
+	}
+	a.PlayerPlays(0)
+	if a.StateHash() == b.StateHash() {
+		t.Error("a move did not change the hash")
+	}
+	b.PlayerPlays(0)
+	if a.StateHash() != b.StateHash() {
+		t.Error("the same move hashes differently")
+	}
+}
+
+// mustMarshal encodes a saved game.
+func mustMarshal(t *testing.T, g SavedGame) []byte {
+	t.Helper()
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2/theme"
+
+	"pishti/engine"
+)
+
+// launchOptions are the command-line flags, for scripting launches and
+// reproducing games without clicking through the UI.
+type launchOptions struct {
+	seed        int64
+	seedSet     bool   // True when --seed was given, so seed 0 can be asked for.
+	daily       bool   // Start today's Daily Deal.
+	level       string // Name of the CPU level to select, matched case-insensitively.
+	mute        bool   // Mute for this session without changing the saved setting.
+	fullscreen  bool
+	lang        string // Language tag such as "tr" or "en-GB".
+	headlessSim int    // Number of games to simulate without opening a window.
+	tui         bool   // Play in the terminal instead of a window.
+	serve       string // Address to serve the game API on instead of opening a window.
+	grpc        string // Address to serve the gRPC engine service on instead of opening a window.
+	dev         bool   // Read the assets from disk and reload them as they change.
+	pprof       string // Address of the profiling endpoint, e.g. "localhost:6060".
+	crashReport string // Crash report to show, passed by a crashed instance.
+}
+
+// parseLaunchOptions parses the command-line arguments, without the program name.
+func parseLaunchOptions(args []string) (launchOptions, error) {
+	var opts launchOptions
+	fs := flag.NewFlagSet("pishti", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // The error is reported by the caller, with the usage.
+	fs.Int64Var(&opts.seed, "seed", 0, "start a game dealt from this seed (needs --level)")
+	fs.BoolVar(&opts.daily, "daily", false, "start today's Daily Deal, the same deal for everyone (needs --level)")
+	fs.StringVar(&opts.level, "level", "", "select the CPU level by name, e.g. Advanced")
+	fs.BoolVar(&opts.mute, "mute", false, "start with the sound muted")
+	fs.BoolVar(&opts.fullscreen, "fullscreen", false, "start in full screen")
+	fs.StringVar(&opts.lang, "lang", "", "interface language, e.g. tr or en-GB")
+	fs.BoolVar(&opts.dev, "dev", false, "read images and sounds from ./assets and reload them when they change")
+	fs.StringVar(&opts.pprof, "pprof", "", "serve pprof and UI timings on this `address`, e.g. localhost:6060")
+	fs.IntVar(&opts.headlessSim, "headless-sim", 0, "simulate `N` games per level without a window and print the results")
+	fs.BoolVar(&opts.tui, "tui", false, "play in the terminal, e.g. over SSH, instead of opening a window")
+	fs.BoolVar(&opts.tui, "cli", false, "same as --tui")
+	fs.StringVar(&opts.serve, "serve", "", "serve games over HTTP on this `address`, e.g. localhost:8080 (needs "+apiKeyEnv+")")
+	fs.StringVar(&opts.grpc, "grpc", "", "serve games over gRPC on this `address`, e.g. localhost:9090 (needs "+apiKeyEnv+")")
+	fs.StringVar(&opts.crashReport, strings.TrimPrefix(crashReportArg, "--"), "", "show a crash report instead of playing")
+	if err := fs.Parse(args); err != nil {
+		return opts, fmt.Errorf("%w\n\n%s", err, flagUsage(fs))
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q\n\n%s", fs.Arg(0), flagUsage(fs))
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.seedSet = true
+		}
+	})
+	if opts.level != "" {
+		if _, ok := strategyNamed(opts.level); !ok {
+			return opts, fmt.Errorf("unknown level %q; choose one of %s", opts.level, strings.Join(strategyNames(), ", "))
+		}
+	}
+	if opts.daily && opts.seedSet {
+		return opts, errors.New("--daily deals from its own seed; leave out --seed")
+	}
+	if opts.headlessSim < 0 {
+		return opts, errors.New("--headless-sim needs a positive number of games")
+	}
+	return opts, nil
+}
+
+// flagUsage returns the list of flags for error messages and --help.
+func flagUsage(fs *flag.FlagSet) string {
+	var b strings.Builder
+	b.WriteString("Usage of " + fs.Name() + ":\n")
+	fs.SetOutput(&b)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+	return b.String()
+}
+
+// strategyNamed finds a registered CPU strategy by name, ignoring case.
+func strategyNamed(name string) (engine.Strategy, bool) {
+	for _, s := range engine.Strategies() {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return engine.Strategy{}, false
+}
+
+// levelName returns the name of the strategy playing at level, or "" if
+// there is none.
+func levelName(level engine.GameLevel) string {
+	for _, s := range engine.Strategies() {
+		if s.Level == level {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// strategyNames returns the names of the registered CPU strategies.
+func strategyNames() []string {
+	var names []string
+	for _, s := range engine.Strategies() {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// setLanguage asks for the given interface language. The game's own text
+// changes at once; the toolkit's dialogs follow the choice on Linux and BSD,
+// where the locale is read from the environment.
+func setLanguage(tag string) {
+	setCardLanguage(tag)
+	setMessageLanguage(tag)
+	locale := strings.ReplaceAll(tag, "-", "_")
+	os.Setenv("LANGUAGE", locale)
+	os.Setenv("LC_ALL", locale)
+}
+
+// runHeadlessSim plays games games at each level, or only at opts.level,
+// and prints how the CPU fared against the simulator's baseline player. The
+// seeds count up from --seed, so a run can be repeated exactly.
+func runHeadlessSim(opts launchOptions, games int) error {
+	strategies := engine.Strategies()
+	if opts.level != "" {
+		s, _ := strategyNamed(opts.level)
+		strategies = []engine.Strategy{s}
+	}
+	variant := appConfig.Rules.Variant
+	fmt.Printf("Simulating %d games per level under %s rules, seeds %d to %d.\n", games, variant, opts.seed, opts.seed+int64(games)-1)
+	for _, s := range strategies {
+		var cpuWins, playerWins, draws, cpuPoints, playerPoints int
+		for i := 0; i < games; i++ {
+			result, err := engine.Simulate(s.Level, variant, opts.seed+int64(i))
+			if err != nil {
+				return fmt.Errorf("%s: %w", s.Name, err)
+			}
+			switch result.Winner() {
+			case engine.CPU:
+				cpuWins++
+			case engine.Player:
+				playerWins++
+			default:
+				draws++
+			}
+			cpuPoints += result.CPUPoints
+			playerPoints += result.PlayerPoints
+		}
+		fmt.Printf("%-13s CPU won %d, player won %d, drawn %d; average score %.1f to %.1f\n",
+			s.Name+":", cpuWins, playerWins, draws, float64(cpuPoints)/float64(games), float64(playerPoints)/float64(games))
+	}
+	return nil
+}
+
+// applyLaunchOptions carries out the flags that act on the running game,
+// once the UI and the saved settings are in place.
+func (ui *AppUI) applyLaunchOptions(opts launchOptions) {
+	if opts.mute {
+		SetMuted(true)
+		ui.muteButton.SetIcon(theme.VolumeMuteIcon())
+	}
+	if opts.fullscreen {
+		ui.window.SetFullScreen(true)
+	}
+	if opts.level != "" {
+		s, _ := strategyNamed(opts.level)
+		ui.levelSelect.SetSelected(s.Name)
+	}
+	if opts.seedSet {
+		ui.startGameWith(func() bool { return ui.loop.StartGameWithSeed(opts.seed) })
+	}
+	if opts.daily {
+		ui.startDailyDeal()
+	}
+}
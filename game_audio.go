@@ -0,0 +1,22 @@
+package main
+
+import "pishti/engine"
+
+// playEventSounds plays the effects for events collected from the game engine.
+// Card sounds are panned by the side and hand slot they came from.
+func playEventSounds(events []engine.Event) {
+	for _, e := range events {
+		switch e.Kind {
+		case engine.EventDeal:
+			PlaySound(SoundDeal)
+		case engine.EventCardPlayed:
+			PlaySoundPanned(SoundCardPlay, cardSoundPan(e.By, e.Slot))
+		case engine.EventCapture:
+			PlaySoundPanned(SoundCapture, cardSoundPan(e.By, e.Slot))
+		case engine.EventPisti:
+			PlaySound(SoundPisti)
+		case engine.EventJackPisti:
+			PlaySound(SoundPistiJack)
+		}
+	}
+}
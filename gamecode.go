@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// copyGameCode puts the code of the current game, or the last finished one,
+// on the clipboard and shows it, so the exact game can be pasted into a chat.
+func (ui *AppUI) copyGameCode() {
+	code, err := ui.casino.GameCode()
+	if err != nil {
+		dialog.ShowInformation(T("Game Code"), T("Start a game first; its code can then be shared."), ui.window)
+		return
+	}
+	fyne.CurrentApp().Clipboard().SetContent(code)
+	entry := widget.NewEntry()
+	entry.SetText(code)
+	entry.Wrapping = fyne.TextWrapBreak
+	entry.MultiLine = true
+	label := widget.NewLabel(T("Copied to the clipboard. Anyone can load it to get the same cards and moves."))
+	label.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustom(T("Game Code"), T("Close"), container.NewVBox(label, entry), ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// showLoadGameCode asks for a game code and loads its game in place of the
+// current one.
+func (ui *AppUI) showLoadGameCode() {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("P1-...")
+	if clip := fyne.CurrentApp().Clipboard().Content(); clip != "" {
+		if _, err := engine.DecodeGameCode(clip); err == nil {
+			entry.SetText(clip) // Most likely what the player came to load.
+		}
+	}
+	entry.Validator = func(code string) error {
+		_, err := engine.DecodeGameCode(code)
+		return err
+	}
+	items := []*widget.FormItem{widget.NewFormItem(T("Code"), entry)}
+	items[0].HintText = T("This ends the game in progress.")
+	d := dialog.NewForm(T("Load Game Code"), T("Load"), T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		data, err := engine.DecodeGameCode(entry.Text)
+		if err == nil {
+			err = ui.loadGame(data)
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to load game code: %v", err)
+			dialog.ShowError(err, ui.window)
+		}
+	}, ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// loadGame replaces the game with a saved one and shows it as it stands,
+// without replaying the sounds of its moves.
+func (ui *AppUI) loadGame(data []byte) error {
+	ui.stopSpectating()
+	ui.stopHotSeat()
+	ui.stopOnline()
+	if err := ui.loop.Load(data); err != nil {
+		if errors.Is(err, engine.ErrNewerSave) {
+			return errors.New(T("this game comes from a newer version of Pishti"))
+		}
+		return err
+	}
+	ui.casino.Events()           // Everything changed; the screen is redrawn whole.
+	ui.gameStarted = time.Time{} // How long the game took is not known.
+	ui.gameOverSoundPlayed = false
+	ui.skipAnnouncements()
+	// A game that was finished elsewhere goes into no statistics.
+	ui.unrated = ui.practice || ui.casino.State() == engine.StateGameOver
+	ui.levelSelect.Selected = levelName(ui.casino.Level())
+	ui.levelSelect.Refresh()
+	ui.levelSelect.Disable()
+	ui.startButton.SetText(T("New Game"))
+	if ui.casino.State() != engine.StateGameOver {
+		SwitchMusic(SoundBackground)
+	}
+	ui.updateUI()
+	if ui.casino.State() != engine.StateGameOver {
+		ui.view.info.Set(T("Game loaded from its code."))
+	}
+	return nil
+}
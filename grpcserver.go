@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pishti/engine"
+	pishtiv1 "pishti/proto/pishti/v1"
+)
+
+// watchBuffer is how many events a WatchGame stream may fall behind before
+// it is ended, so a stalled client cannot hold up the game.
+const watchBuffer = 64
+
+// grpcServer serves the Engine service of proto/pishti/v1. Its games are
+// kept and played as the HTTP API's are; clients send the same key, as
+// "authorization: Bearer <key>" metadata.
+type grpcServer struct {
+	pishtiv1.UnimplementedEngineServer
+	api   *apiServer
+	mu    sync.Mutex // Guards feeds.
+	feeds map[string]*gameFeed
+}
+
+// gameFeed is what the watchers of a game have been sent. It is only
+// changed with the game's lock held, as well as the server's.
+type gameFeed struct {
+	moves, captures int // Of the game's history, the ones sent.
+	over            bool
+	watchers        map[*gameWatcher]bool
+}
+
+// gameWatcher is one WatchGame stream.
+type gameWatcher struct {
+	events chan *pishtiv1.GameEvent // Closed when the game is deleted or the watcher falls behind.
+	lagged bool
+}
+
+// runGRPCServer serves the Engine service on addr until it fails. It refuses
+// to start without a key.
+func runGRPCServer(addr, key string) error {
+	if key == "" {
+		return errors.New("set " + apiKeyEnv + " to the key clients must send")
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("Serving the gRPC engine service on %s", addr)
+	return newGRPCServer(key).Serve(lis)
+}
+
+// newGRPCServer returns a server with no games that accepts the given key.
+func newGRPCServer(key string) *grpc.Server {
+	authorized := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			if got, ok := strings.CutPrefix(v, "Bearer "); ok && subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "missing or wrong API key")
+	}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if err := authorized(ctx); err != nil {
+				return nil, err
+			}
+			return h(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if err := authorized(ss.Context()); err != nil {
+				return err
+			}
+			return h(srv, ss)
+		}),
+	)
+	pishtiv1.RegisterEngineServer(srv, &grpcServer{api: newAPIServer(key), feeds: make(map[string]*gameFeed)})
+	return srv
+}
+
+// CreateGame deals a new game.
+func (s *grpcServer) CreateGame(_ context.Context, req *pishtiv1.CreateGameRequest) (*pishtiv1.GameState, error) {
+	g, err := s.api.newGame(req.GetLevel(), req.Seed, req.GetVariant())
+	switch {
+	case errors.Is(err, errTooManyGames):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.c.Events() // The deal is the game's first state, not an event.
+	snap := g.c.Snapshot()
+	s.mu.Lock()
+	s.feeds[g.id] = &gameFeed{moves: len(snap.Moves), captures: len(snap.Captures), watchers: make(map[*gameWatcher]bool)}
+	s.mu.Unlock()
+	return newProtoState(g.state()), nil
+}
+
+// GetState returns a game as the player sees it.
+func (s *grpcServer) GetState(_ context.Context, ref *pishtiv1.GameRef) (*pishtiv1.GameState, error) {
+	g, err := s.game(ref.GetId())
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return newProtoState(g.state()), nil
+}
+
+// PlayCard plays a card of the player's hand and returns once the CPU has
+// replied, sending each step to the game's watchers.
+func (s *grpcServer) PlayCard(_ context.Context, req *pishtiv1.PlayCardRequest) (*pishtiv1.GameState, error) {
+	g, err := s.game(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.play(int(req.GetSlot()), func() { s.publish(g) }); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return newProtoState(g.state()), nil
+}
+
+// GetHistory returns the cards played and the captures made so far.
+func (s *grpcServer) GetHistory(_ context.Context, ref *pishtiv1.GameRef) (*pishtiv1.History, error) {
+	g, err := s.game(ref.GetId())
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	snap := g.c.Snapshot()
+	h := &pishtiv1.History{}
+	for _, m := range snap.Moves {
+		h.Moves = append(h.Moves, newProtoMove(m))
+	}
+	for _, e := range snap.Captures {
+		h.Captures = append(h.Captures, newProtoCapture(e))
+	}
+	return h, nil
+}
+
+// DeleteGame forgets a game and ends its watchers' streams.
+func (s *grpcServer) DeleteGame(_ context.Context, ref *pishtiv1.GameRef) (*pishtiv1.DeleteGameResponse, error) {
+	if !s.api.remove(ref.GetId()) {
+		return nil, status.Error(codes.NotFound, "no such game")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f := s.feeds[ref.GetId()]; f != nil {
+		for w := range f.watchers {
+			close(w.events)
+		}
+		delete(s.feeds, ref.GetId())
+	}
+	return &pishtiv1.DeleteGameResponse{}, nil
+}
+
+// WatchGame sends the game's current state, then its events as they happen,
+// until the game is over or deleted.
+func (s *grpcServer) WatchGame(ref *pishtiv1.GameRef, stream grpc.ServerStreamingServer[pishtiv1.GameEvent]) error {
+	g, err := s.game(ref.GetId())
+	if err != nil {
+		return err
+	}
+	w := &gameWatcher{events: make(chan *pishtiv1.GameEvent, watchBuffer)}
+	// Under the game's lock no step is published between the state and the
+	// first event.
+	g.mu.Lock()
+	st := g.state()
+	s.mu.Lock()
+	f := s.feeds[g.id]
+	if f != nil {
+		f.watchers[w] = true
+	}
+	s.mu.Unlock()
+	g.mu.Unlock()
+	if f == nil {
+		return status.Error(codes.NotFound, "no such game")
+	}
+	defer func() {
+		s.mu.Lock()
+		delete(f.watchers, w)
+		s.mu.Unlock()
+	}()
+	if err := stream.Send(&pishtiv1.GameEvent{State: newProtoState(st)}); err != nil {
+		return err
+	}
+	if st.State == engine.StateGameOver.String() {
+		return nil
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-w.events:
+			switch {
+			case !ok && w.lagged:
+				return status.Error(codes.ResourceExhausted, "the stream fell too far behind the game")
+			case !ok:
+				return nil // Deleted.
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+			if e.GetGameOver() != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// game returns the game with the ID, or a NotFound error.
+func (s *grpcServer) game(id string) (*apiGame, error) {
+	if g := s.api.game(id); g != nil {
+		return g, nil
+	}
+	return nil, status.Error(codes.NotFound, "no such game")
+}
+
+// publish sends what happened in the game since the last call to its
+// watchers: the cards played and the piles taken, a new deal, the end of
+// the game. The caller must hold the game's lock.
+func (s *grpcServer) publish(g *apiGame) {
+	deals := 0
+	for _, e := range g.c.Events() {
+		if e.Kind == engine.EventDeal {
+			deals++
+		}
+	}
+	snap := g.c.Snapshot()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.feeds[g.id]
+	if f == nil {
+		return // Deleted while the CPU played.
+	}
+	st := newProtoState(g.state())
+	var events []*pishtiv1.GameEvent
+	for _, m := range snap.Moves[f.moves:] {
+		events = append(events, &pishtiv1.GameEvent{Event: &pishtiv1.GameEvent_CardPlayed{CardPlayed: newProtoMove(m)}, State: st})
+	}
+	for _, c := range snap.Captures[f.captures:] {
+		events = append(events, &pishtiv1.GameEvent{Event: &pishtiv1.GameEvent_Capture{Capture: newProtoCapture(c)}, State: st})
+	}
+	for range deals {
+		events = append(events, &pishtiv1.GameEvent{Event: &pishtiv1.GameEvent_Deal{Deal: &pishtiv1.DealEvent{}}, State: st})
+	}
+	if snap.State == engine.StateGameOver && !f.over {
+		over := &pishtiv1.GameOverEvent{}
+		switch {
+		case snap.PlayerPoints > snap.CPUPoints:
+			over.Winner = pishtiv1.Side_SIDE_PLAYER
+		case snap.CPUPoints > snap.PlayerPoints:
+			over.Winner = pishtiv1.Side_SIDE_CPU
+		}
+		events = append(events, &pishtiv1.GameEvent{Event: &pishtiv1.GameEvent_GameOver{GameOver: over}, State: st})
+		f.over = true
+	}
+	f.moves, f.captures = len(snap.Moves), len(snap.Captures)
+	for w := range f.watchers {
+		for _, e := range events {
+			if w.lagged {
+				break
+			}
+			select {
+			case w.events <- e:
+			default:
+				w.lagged = true
+				close(w.events)
+			}
+		}
+	}
+}
+
+// newProtoState returns the gRPC form of a game's state.
+func newProtoState(st apiState) *pishtiv1.GameState {
+	p := &pishtiv1.GameState{
+		Id:             st.ID,
+		State:          st.State,
+		Level:          st.Level,
+		Variant:        st.Variant,
+		Seed:           st.Seed,
+		PlayerPoints:   int32(st.PlayerPoints),
+		CpuPoints:      int32(st.CPUPoints),
+		PlayerCaptured: int32(st.PlayerCaptured),
+		CpuCaptured:    int32(st.CPUCaptured),
+		DeckLeft:       int32(st.DeckLeft),
+		CpuCards:       int32(st.CPUCards),
+		TableTop:       newProtoCard(st.TableTop),
+		TableCards:     int32(st.TableCards),
+		FaceDown:       int32(st.FaceDown),
+	}
+	for _, c := range st.Hand {
+		p.Hand = append(p.Hand, &pishtiv1.HandSlot{Card: newProtoCard(c)})
+	}
+	return p
+}
+
+// newProtoCard returns the gRPC form of a card, nil for none.
+func newProtoCard(c *apiCard) *pishtiv1.Card {
+	if c == nil {
+		return nil
+	}
+	return &pishtiv1.Card{Rank: c.Rank, Suit: c.Suit}
+}
+
+// newProtoMove returns the gRPC form of a card played.
+func newProtoMove(m engine.Move) *pishtiv1.Move {
+	return &pishtiv1.Move{By: newProtoSide(m.By), Card: newProtoCard(newAPICard(m.Card)), Slot: int32(m.Slot)}
+}
+
+// newProtoCapture returns the gRPC form of a pile taken.
+func newProtoCapture(e engine.CaptureEvent) *pishtiv1.Capture {
+	return &pishtiv1.Capture{By: newProtoSide(e.By), Cards: int32(e.Cards), Points: int32(e.Points), Pisti: e.Pisti, Jack: e.Jack, Final: e.Final}
+}
+
+// newProtoSide returns the gRPC form of a side.
+func newProtoSide(p engine.PlayerID) pishtiv1.Side {
+	switch p {
+	case engine.Player:
+		return pishtiv1.Side_SIDE_PLAYER
+	case engine.CPU:
+		return pishtiv1.Side_SIDE_CPU
+	}
+	return pishtiv1.Side_SIDE_UNSPECIFIED
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pishtiv1 "pishti/proto/pishti/v1"
+)
+
+// newGRPCTestClient serves the engine service in memory and returns a
+// client of it.
+func newGRPCTestClient(t *testing.T) pishtiv1.EngineClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer("secret")
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pishtiv1.NewEngineClient(conn)
+}
+
+func TestGRPCWatchesAGame(t *testing.T) {
+	appConfig = defaultConfig()
+	client := newGRPCTestClient(t)
+	wrong := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	if _, err := client.CreateGame(wrong, &pishtiv1.CreateGameRequest{Level: "Beginner"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("wrong key: %v, want Unauthenticated", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	seed := int64(5)
+	st, err := client.CreateGame(ctx, &pishtiv1.CreateGameRequest{Level: "Intermediate", Seed: &seed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Seed != 5 || st.Level != "Intermediate" || st.FaceDown != 3 || len(st.Hand) != 4 {
+		t.Fatalf("new game = %v", st)
+	}
+	ref := &pishtiv1.GameRef{Id: st.Id}
+	stream, err := client.WatchGame(ctx, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := stream.Recv()
+	if err != nil || first.Event != nil || first.State.GetId() != st.Id {
+		t.Fatalf("the stream starts with %v, %v, want the state alone", first, err)
+	}
+	// The events are counted as they come, while the game is played.
+	var plays, captures, deals, overs int
+	done := make(chan error)
+	go func() {
+		for {
+			e, err := stream.Recv()
+			if err != nil {
+				done <- err
+				return
+			}
+			switch {
+			case e.GetCardPlayed() != nil:
+				plays++
+			case e.GetCapture() != nil:
+				captures++
+			case e.GetDeal() != nil:
+				deals++
+			case e.GetGameOver() != nil:
+				overs++
+			}
+		}
+	}()
+	for moves := 0; st.State != "game over"; moves++ {
+		if moves > 60 {
+			t.Fatal("the game never ended")
+		}
+		slot := 0
+		for st.Hand[slot].GetCard() == nil {
+			slot++
+		}
+		if st, err = client.PlayCard(ctx, &pishtiv1.PlayCardRequest{Id: ref.Id, Slot: int32(slot)}); err != nil {
+			t.Fatalf("move %d: %v", moves, err)
+		}
+	}
+	if err := <-done; err != io.EOF {
+		t.Fatalf("the stream ended with %v, want its end after the game", err)
+	}
+	if _, err := client.PlayCard(ctx, &pishtiv1.PlayCardRequest{Id: ref.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("move after the game: %v, want FailedPrecondition", err)
+	}
+	h, err := client.GetHistory(ctx, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Moves) != 48 || plays != 48 || captures != len(h.Captures) || deals != 5 || overs != 1 {
+		t.Errorf("streamed %d plays, %d captures, %d deals and %d ends for %d moves and %d captures",
+			plays, captures, deals, overs, len(h.Moves), len(h.Captures))
+	}
+	if _, err := client.DeleteGame(ctx, ref); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if _, err := client.GetState(ctx, ref); status.Code(err) != codes.NotFound {
+		t.Errorf("deleted game: %v, want NotFound", err)
+	}
+}
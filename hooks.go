@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"pishti/engine"
+)
+
+// Event hooks let players react to game events without changing the code,
+// with the Lua scripts of hooks_lua.go or, for the simplest hooks, rules.
+// Every *.toml file in the scripts folder holds rules such as:
+//
+//	[[hook]]
+//	event = "pişti"            # an event name, as shown in the debug console
+//	by = "player"              # optional: only when the player (or "CPU") caused it
+//	sound = "pisti_jack"       # optional: a sound to play, by its sound pack name
+//	log = "{by} made a Pişti"  # optional: a line for the game log
+//	message = "Nicely done!"   # optional: replaces the message under the table
+//
+// In log and message, {by}, {player_score} and {cpu_score} are filled in.
+
+// hookRule is one [[hook]] table of a script.
+type hookRule struct {
+	Event   string `toml:"event"`
+	By      string `toml:"by"`
+	Sound   string `toml:"sound"`
+	Log     string `toml:"log"`
+	Message string `toml:"message"`
+	// Resolved when the script is loaded.
+	kind   engine.EventKind
+	by     engine.PlayerID // NoPlayer matches anyone.
+	effect SoundEffect
+	sound  bool
+	script string // File the rule came from, for the log.
+}
+
+// hookScript is the contents of a script file.
+type hookScript struct {
+	Hooks []hookRule `toml:"hook"`
+}
+
+// eventHooks are the rules and Lua scripts loaded from the scripts folder.
+// A nil *eventHooks has none.
+type eventHooks struct {
+	rules   []hookRule
+	scripts []*luaScript
+}
+
+// loadEventHooks reads the scripts in dir. Broken scripts and rules are
+// logged and skipped; nil is returned if there are no usable hooks.
+func loadEventHooks(dir string) *eventHooks {
+	if dir == "" {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.toml"))
+	luaPaths, _ := filepath.Glob(filepath.Join(dir, "*.lua"))
+	if len(paths) == 0 && len(luaPaths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+	sort.Strings(luaPaths)
+	kinds := eventKindsByName()
+	h := &eventHooks{}
+	for _, path := range luaPaths {
+		s, err := loadLuaScript(path, kinds)
+		if err != nil {
+			log.Printf("ERROR: Skipping script %s: %v", path, err)
+			continue
+		}
+		h.scripts = append(h.scripts, s)
+	}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("ERROR: Failed to read script %s: %v", path, err)
+			continue
+		}
+		var script hookScript
+		if err := toml.Unmarshal(b, &script); err != nil {
+			log.Printf("ERROR: Failed to parse script %s: %v", path, err)
+			continue
+		}
+		for i, r := range script.Hooks {
+			if err := r.resolve(kinds); err != nil {
+				log.Printf("ERROR: Skipping hook %d of script %s: %v", i+1, path, err)
+				continue
+			}
+			r.script = filepath.Base(path)
+			h.rules = append(h.rules, r)
+		}
+	}
+	if len(h.rules) == 0 && len(h.scripts) == 0 {
+		return nil
+	}
+	log.Printf("Loaded %d event hooks and %d Lua scripts from %s", len(h.rules), len(h.scripts), dir)
+	return h
+}
+
+// eventKindsByName maps the names of the engine's event kinds to them.
+func eventKindsByName() map[string]engine.EventKind {
+	kinds := make(map[string]engine.EventKind)
+	for k := engine.EventKind(0); !strings.HasPrefix(k.String(), "EventKind("); k++ {
+		kinds[k.String()] = k
+	}
+	return kinds
+}
+
+// resolve checks a rule and looks up the names it uses.
+func (r *hookRule) resolve(kinds map[string]engine.EventKind) error {
+	kind, ok := kinds[strings.ToLower(r.Event)]
+	if !ok {
+		return fmt.Errorf("unknown event %q", r.Event)
+	}
+	r.kind = kind
+	switch strings.ToLower(r.By) {
+	case "":
+		r.by = engine.NoPlayer
+	case "player":
+		r.by = engine.Player
+	case "cpu":
+		r.by = engine.CPU
+	default:
+		return fmt.Errorf("by must be player or CPU, not %q", r.By)
+	}
+	if r.Sound != "" {
+		if r.effect, r.sound = soundNamed(r.Sound); !r.sound {
+			return fmt.Errorf("unknown sound %q", r.Sound)
+		}
+	}
+	if !r.sound && r.Log == "" && r.Message == "" {
+		return fmt.Errorf("the hook does nothing; give a sound, log or message")
+	}
+	return nil
+}
+
+// soundNamed returns the sound effect with the sound pack name.
+func soundNamed(name string) (SoundEffect, bool) {
+	for effect, n := range soundNames {
+		if n == name {
+			return effect, true
+		}
+	}
+	return 0, false
+}
+
+// run carries out the rules matching the events and calls the scripts'
+// handlers of them, after the screen has been updated for them.
+func (h *eventHooks) run(ui *AppUI, events []engine.Event) {
+	if h == nil {
+		return
+	}
+	for _, e := range events {
+		for _, r := range h.rules {
+			if r.kind != e.Kind || (r.by != engine.NoPlayer && r.by != e.By) {
+				continue
+			}
+			fill := strings.NewReplacer(
+				"{by}", e.By.String(),
+				"{player_score}", strconv.Itoa(ui.casino.PlayerPoints()),
+				"{cpu_score}", strconv.Itoa(ui.casino.CPUPoints()),
+			)
+			if r.sound {
+				PlaySound(r.effect)
+			}
+			if r.Log != "" {
+				log.Printf("%s: %s", r.script, fill.Replace(r.Log))
+			}
+			if r.Message != "" {
+				ui.view.info.Set(fill.Replace(r.Message))
+			}
+		}
+		for _, s := range h.scripts {
+			s.run(ui, e)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"pishti/engine"
+)
+
+// Every *.lua file in the scripts folder is a Lua 5.1 script that registers
+// handlers for events when it is loaded:
+//
+//	pishti.on("pişti", function(e)
+//		if e.by == "player" then
+//			pishti.sound("pisti_jack")
+//			pishti.message("Nicely done! " .. e.player_score .. " points")
+//		end
+//		pishti.log(e.by .. " made a Pişti")
+//	end)
+//
+// A handler is given the event's name, by ("player", "CPU" or ""), the hand
+// slot played from (-1 for none) and both scores. The scripts are
+// sandboxed: only the base, string, table and math libraries are there,
+// without the functions that load files, so a downloaded script cannot read
+// or change anything on the machine. A script or handler that runs for
+// longer than luaTimeLimit is stopped.
+
+const (
+	// luaTimeLimit bounds a script's loading and each run of a handler;
+	// they run on the UI goroutine.
+	luaTimeLimit = 100 * time.Millisecond
+	// luaMaxString bounds the strings string.rep builds, the easiest way for
+	// a script to run the game out of memory.
+	luaMaxString = 1 << 20
+)
+
+// luaScript is a loaded Lua script and its handlers. It is only used on the
+// goroutine that loaded it, then the UI goroutine.
+type luaScript struct {
+	name     string // The file, for the log.
+	state    *lua.LState
+	handlers map[engine.EventKind][]*lua.LFunction
+	ui       *AppUI // While its handlers run.
+}
+
+// loadLuaScript runs the script at path to register its handlers.
+func loadLuaScript(path string, kinds map[string]engine.EventKind) (*luaScript, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &luaScript{name: filepath.Base(path), state: newLuaSandbox(), handlers: make(map[engine.EventKind][]*lua.LFunction)}
+	s.state.SetGlobal("print", s.state.NewFunction(func(L *lua.LState) int {
+		args := make([]string, L.GetTop())
+		for i := range args {
+			args[i] = L.ToStringMeta(L.Get(i + 1)).String()
+		}
+		log.Printf("%s: %s", s.name, strings.Join(args, "\t"))
+		return 0
+	}))
+	s.state.SetGlobal("pishti", s.state.SetFuncs(s.state.NewTable(), map[string]lua.LGFunction{
+		"on": func(L *lua.LState) int {
+			name, fn := L.CheckString(1), L.CheckFunction(2)
+			kind, ok := kinds[strings.ToLower(name)]
+			if !ok {
+				L.ArgError(1, fmt.Sprintf("unknown event %q", name))
+			}
+			s.handlers[kind] = append(s.handlers[kind], fn)
+			return 0
+		},
+		"sound": func(L *lua.LState) int {
+			effect, ok := soundNamed(L.CheckString(1))
+			if !ok {
+				L.ArgError(1, fmt.Sprintf("unknown sound %q", L.CheckString(1)))
+			}
+			PlaySound(effect)
+			return 0
+		},
+		"log": func(L *lua.LState) int {
+			log.Printf("%s: %s", s.name, L.CheckString(1))
+			return 0
+		},
+		"message": func(L *lua.LState) int {
+			if s.ui != nil {
+				s.ui.view.info.Set(L.CheckString(1))
+			}
+			return 0
+		},
+	}))
+	fn, err := s.state.Load(bytes.NewReader(b), "@"+s.name)
+	if err != nil {
+		s.state.Close()
+		return nil, err
+	}
+	if err := s.call(fn); err != nil {
+		s.state.Close()
+		return nil, err
+	}
+	if len(s.handlers) == 0 {
+		s.state.Close()
+		return nil, fmt.Errorf("the script handles no events; call pishti.on")
+	}
+	return s, nil
+}
+
+// newLuaSandbox returns a Lua state with only the libraries that cannot
+// reach outside it.
+func newLuaSandbox() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// The base library can still read files and load modules from disk.
+	for _, name := range []string{"dofile", "loadfile", "require", "module"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	if str, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		rep := str.RawGetString("rep").(*lua.LFunction)
+		str.RawSetString("rep", L.NewFunction(func(L *lua.LState) int {
+			// Compared as a count, not a length, so a huge count cannot
+			// overflow past the check; !(n <= limit) also catches NaN.
+			n := float64(L.CheckNumber(2))
+			if limit := luaMaxString / max(len(L.CheckString(1)), 1); !(n <= float64(limit)) {
+				L.RaiseError("string.rep: the result would be longer than %d bytes", luaMaxString)
+			}
+			return rep.GFunction(L)
+		}))
+	}
+	return L
+}
+
+// call runs fn with the arguments, stopping it after luaTimeLimit.
+func (s *luaScript) call(fn *lua.LFunction, args ...lua.LValue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), luaTimeLimit)
+	defer cancel()
+	s.state.SetContext(ctx)
+	defer s.state.RemoveContext()
+	return s.state.CallByParam(lua.P{Fn: fn, Protect: true}, args...)
+}
+
+// run calls the script's handlers of the event. A handler that fails is
+// logged; the others still run.
+func (s *luaScript) run(ui *AppUI, e engine.Event) {
+	handlers := s.handlers[e.Kind]
+	if len(handlers) == 0 {
+		return
+	}
+	by := ""
+	if e.By != engine.NoPlayer {
+		by = e.By.String()
+	}
+	event := s.state.NewTable()
+	event.RawSetString("event", lua.LString(e.Kind.String()))
+	event.RawSetString("by", lua.LString(by))
+	event.RawSetString("slot", lua.LNumber(e.Slot))
+	event.RawSetString("player_score", lua.LNumber(ui.casino.PlayerPoints()))
+	event.RawSetString("cpu_score", lua.LNumber(ui.casino.CPUPoints()))
+	s.ui = ui
+	defer func() { s.ui = nil }()
+	for _, fn := range handlers {
+		if err := s.call(fn, event); err != nil {
+			log.Printf("ERROR: Script %s failed on %s: %v", s.name, e.Kind, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// hotSeatState is a Pass & Play game: two people share the window, the
+// first in the player's seat at the bottom and the second in the CPU's at
+// the top.
+type hotSeatState struct {
+	shown        engine.PlayerID // Whose hand is face up; NoPlayer while the device changes hands.
+	revealBefore bool            // Whether the CPU's hand was shown before the game.
+}
+
+// hotSeatName names a seat in a Pass & Play game.
+func hotSeatName(seat engine.PlayerID) string {
+	if seat == engine.CPU {
+		return T("Player 2")
+	}
+	return T("Player 1")
+}
+
+// passOverlay covers the table while the device is passed on, so neither
+// player sees the other's cards.
+type passOverlay struct {
+	overlay *fyne.Container
+	title   *widget.Label
+	ready   *widget.Button
+}
+
+// newPassOverlay returns the overlay, hidden. onReady is called when the
+// next player says they have the device.
+func newPassOverlay(onReady func()) *passOverlay {
+	p := &passOverlay{title: widget.NewLabel("")}
+	p.title.Alignment = fyne.TextAlignCenter
+	p.title.TextStyle.Bold = true
+	p.title.SizeName = theme.SizeNameSubHeadingText
+	p.ready = widget.NewButtonWithIcon("", theme.VisibilityIcon(), onReady)
+	p.ready.Importance = widget.HighImportance
+	shade := canvas.NewRectangle(color.NRGBA{A: 0xe0})
+	p.overlay = container.NewStack(shade, container.NewCenter(container.NewVBox(p.title, p.ready)))
+	p.overlay.Hide()
+	return p
+}
+
+// show asks for the device to be passed to the given seat.
+func (p *passOverlay) show(seat engine.PlayerID) {
+	p.title.SetText(Tf("Pass the device to %s", hotSeatName(seat)))
+	p.ready.SetText(Tf("I'm %s, Show My Cards", hotSeatName(seat)))
+	p.overlay.Show()
+	p.overlay.Refresh() // Lays the longer texts out again.
+}
+
+// startHotSeat starts a Pass & Play game in place of the current one. It
+// uses the Beginner level's rules, and does not count for achievements.
+func (ui *AppUI) startHotSeat() {
+	ui.stopSpectating()
+	ui.stopHotSeat()
+	ui.stopOnline()
+	ui.hotSeat = &hotSeatState{revealBefore: ui.view.revealCPU}
+	ui.levelSelect.SetSelected(levelName(engine.LevelBeginner))
+	ui.loop.SetHotSeat(true)
+	for i, w := range ui.cpuCardWidgets {
+		w.SetOnTapped(func() {
+			if ui.canPlayOpponentCard(i) {
+				ui.opponentPlays(i)
+			}
+		})
+	}
+	ui.view.setFaceUp(false, false)
+	ui.cancelTurnReminder()
+	if ui.startGameWith(ui.loop.StartGame) {
+		ui.view.info.Set(T("Pass & Play: Player 1 has the bottom hand, Player 2 the top."))
+	}
+}
+
+// stopHotSeat gives the CPU its seat back.
+func (ui *AppUI) stopHotSeat() {
+	if ui.hotSeat == nil {
+		return
+	}
+	revealed := ui.hotSeat.revealBefore
+	ui.hotSeat = nil
+	ui.loop.SetHotSeat(false)
+	for _, w := range ui.cpuCardWidgets {
+		w.SetOnTapped(nil)
+	}
+	ui.pass.overlay.Hide()
+	ui.view.setFaceUp(true, revealed)
+}
+
+// updateHotSeat hides both hands and asks for the device to be passed on
+// whenever the turn goes to the seat whose cards are not shown.
+func (ui *AppUI) updateHotSeat() {
+	if ui.hotSeat == nil {
+		return
+	}
+	var seat engine.PlayerID
+	switch ui.view.currentState() {
+	case engine.StatePlayerTurn:
+		seat = engine.Player
+	case engine.StateCPUTurn:
+		seat = engine.CPU
+	default:
+		return // Between turns the hand that was played from stays up.
+	}
+	if seat == ui.hotSeat.shown {
+		return
+	}
+	ui.hotSeat.shown = engine.NoPlayer
+	ui.view.setFaceUp(false, false)
+	ui.pass.show(seat)
+}
+
+// passedTo shows the hand of the seat whose turn it is, once its player has
+// the device.
+func (ui *AppUI) passedTo() {
+	if ui.hotSeat == nil {
+		return
+	}
+	seat := engine.Player
+	if ui.view.currentState() == engine.StateCPUTurn {
+		seat = engine.CPU
+	}
+	ui.hotSeat.shown = seat
+	ui.pass.overlay.Hide()
+	ui.view.setFaceUp(seat == engine.Player, seat == engine.CPU)
+}
+
+// canPlayOpponentCard reports whether the second player may play the card
+// in the given slot of the top hand.
+func (ui *AppUI) canPlayOpponentCard(cardIndex int) bool {
+	return ui.hotSeat != nil && ui.hotSeat.shown == engine.CPU && ui.casino.CPUHand()[cardIndex] != nil &&
+		!ui.loop.Busy() && ui.view.currentState() == engine.StateCPUTurn
+}
+
+// opponentPlays plays the second player's card from the top hand.
+func (ui *AppUI) opponentPlays(cardIndex int) {
+	if err := ui.loop.OpponentPlays(cardIndex); err != nil {
+		log.Printf("ERROR: Player 2 move rejected: %v", err)
+		return
+	}
+	ui.applyEvents()
+}
+
+// hotSeatResult is the message at the end of a Pass & Play game.
+func hotSeatResult(player, cpu int) string {
+	headline := T("It's a Tie!")
+	switch {
+	case player > cpu:
+		headline = T("Player 1 Wins!")
+	case cpu > player:
+		headline = T("Player 2 Wins!")
+	}
+	return Tf("%s Final Score: Player 1 %d - %d Player 2", headline, player, cpu)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"log"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// savedHouseRules returns the custom rules kept in the preferences, or the
+// standard ones if none were saved.
+func savedHouseRules(prefs fyne.Preferences) engine.Rules {
+	r := engine.StandardRules
+	if s := prefs.String(prefHouseRules); s != "" {
+		if err := json.Unmarshal([]byte(s), &r); err != nil {
+			log.Printf("ERROR: Failed to read the house rules in preferences: %v", err)
+			return engine.StandardRules
+		}
+	}
+	return r
+}
+
+// describeRules sums up the rules for the settings, e.g. "Pişti 10, Jack
+// Pişti 20, majority 3, Deuce of Clubs 2, Ten of Diamonds 3."
+func describeRules(r engine.Rules) string {
+	s := Tf("Pişti %d, Jack Pişti %d, majority %d, Deuce of Clubs %d, Ten of Diamonds %d.",
+		r.PistiPoints, r.JackPistiPoints, r.MajorityPoints, r.DeuceOfClubsPoints, r.TenOfDiamondsPoints)
+	if r.DiscardLastPile {
+		s += " " + T("The cards left at the end score for nobody.")
+	}
+	return s
+}
+
+// showHouseRules opens a dialog for custom scoring rules. Saving selects
+// them for the next game and calls onSaved.
+func (ui *AppUI) showHouseRules(onSaved func()) {
+	prefs := fyne.CurrentApp().Preferences()
+	r := savedHouseRules(prefs)
+	fields := []struct {
+		label string
+		value *int
+	}{
+		{"Pişti", &r.PistiPoints},
+		{"Jack Pişti", &r.JackPistiPoints},
+		{"Most cards", &r.MajorityPoints},
+		{"Deuce of Clubs", &r.DeuceOfClubsPoints},
+		{"Ten of Diamonds", &r.TenOfDiamondsPoints},
+	}
+	entries := make([]*widget.Entry, len(fields))
+	items := make([]*widget.FormItem, 0, len(fields)+1)
+	for i, f := range fields {
+		entries[i] = widget.NewEntry()
+		entries[i].SetText(strconv.Itoa(*f.value))
+		entries[i].Validator = validatePoints
+		items = append(items, widget.NewFormItem(T(f.label), entries[i]))
+	}
+	lastPileCheck := widget.NewCheck(T("The last capture takes the cards left"), nil)
+	lastPileCheck.SetChecked(!r.DiscardLastPile)
+	items = append(items, widget.NewFormItem("", lastPileCheck))
+	items[2].HintText = T("Bonus for taking more cards")
+	d := dialog.NewForm(T("House Rules"), T("Save"), T("Cancel"), items, func(save bool) {
+		if !save {
+			return
+		}
+		for i, f := range fields {
+			*f.value, _ = strconv.Atoi(entries[i].Text) // Checked by the validator.
+		}
+		r.DiscardLastPile = !lastPileCheck.Checked
+		if err := ui.loop.SetRules(r); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		b, _ := json.Marshal(r)
+		prefs.SetString(prefHouseRules, string(b))
+		onSaved()
+	}, ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// validatePoints accepts a whole number of points, zero or more.
+func validatePoints(text string) error {
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return errors.New(T("use a whole number"))
+	}
+	if n < 0 {
+		return errors.New(T("must not be negative"))
+	}
+	return nil
+}
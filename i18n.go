@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+// The interface text is written in English in the code and passed through T,
+// or Tf for a format, which look it up in the catalog of the chosen language.
+// The English text is the key, so a message without a translation shows in
+// English. Level, rule variant, skin and table design names are names, like
+// the card codes of saves and the engine protocol, and are shown as they are.
+
+// messages lists the message catalogs by language code. English is the
+// code's own text and has no catalog.
+var messages = map[string]map[string]string{
+	"tr": messagesTR,
+}
+
+// messageLanguage is the language code of the catalog in use, "" for
+// English. It is set at startup and changed from the settings.
+var messageLanguage string
+
+// setMessageLanguage picks the catalog for the language of the given tag,
+// such as "tr" or "en-GB", falling back to English for languages without
+// one. An empty tag follows the system's language.
+func setMessageLanguage(tag string) {
+	if tag == "" {
+		tag = lang.SystemLocale().LanguageString()
+	}
+	code, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(tag, "_", "-")), "-")
+	if _, ok := messages[code]; !ok {
+		code = ""
+	}
+	messageLanguage = code
+}
+
+// T returns the text in the current language.
+func T(text string) string {
+	if tr, ok := messages[messageLanguage][text]; ok {
+		return tr
+	}
+	return text
+}
+
+// Tf formats the arguments with the format in the current language, as
+// fmt.Sprintf does. A translation may take them in another order with
+// explicit indexes such as %[2]d.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
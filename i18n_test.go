@@ -0,0 +1,125 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"pishti/engine"
+)
+
+// TestTurkishCatalogComplete checks that every text the code passes to T or
+// Tf, and the labels of the lists translated when shown, has a Turkish
+// translation.
+func TestTurkishCatalogComplete(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || (fn.Name != "T" && fn.Name != "Tf") {
+				return true
+			}
+			// Literals, joined with + or not; the texts in variables are
+			// checked below.
+			tv, err := types.Eval(fset, nil, call.Args[0].Pos(), types.ExprString(call.Args[0]))
+			if err != nil || tv.Value == nil || tv.Value.Kind() != constant.String {
+				return true
+			}
+			if text := constant.StringVal(tv.Value); messagesTR[text] == "" {
+				t.Errorf("%s: no Turkish for %q", fset.Position(call.Pos()), text)
+			}
+			return true
+		})
+	}
+
+	var labels []string
+	for _, e := range adjustableEffects {
+		labels = append(labels, e.label)
+	}
+	for _, e := range announcementLabels {
+		labels = append(labels, e.label)
+	}
+	for _, a := range achievements {
+		labels = append(labels, a.title, a.description)
+	}
+	for _, v := range engine.Variants() {
+		labels = append(labels, v.Description)
+	}
+	for _, s := range gameSpeeds {
+		labels = append(labels, s.label)
+	}
+	for _, s := range replaySpeeds {
+		labels = append(labels, s.label)
+	}
+	for rank := engine.Ace; rank <= engine.King; rank++ {
+		labels = append(labels, rank.String())
+	}
+	labels = append(labels, noDefaultLevel, builtInSkin, languageOptions[0].label, announcerOptions[0].label)
+	for _, label := range labels {
+		if messagesTR[label] == "" {
+			t.Errorf("no Turkish for %q", label)
+		}
+	}
+}
+
+// formatVerb matches a verb of a format, with its flags, width and index.
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// TestTurkishFormats checks that each translation takes the same arguments
+// as its English text, in whatever order.
+func TestTurkishFormats(t *testing.T) {
+	verbs := func(format string) []string {
+		var found []string
+		for _, v := range formatVerb.FindAllString(format, -1) {
+			if v != "%%" {
+				found = append(found, regexp.MustCompile(`\[\d+\]`).ReplaceAllString(v, ""))
+			}
+		}
+		slices.Sort(found)
+		return found
+	}
+	for en, tr := range messagesTR {
+		if want, got := verbs(en), verbs(tr); !slices.Equal(got, want) {
+			t.Errorf("%q is translated with verbs %v, want %v", en, got, want)
+		}
+	}
+}
+
+func TestMessageLanguage(t *testing.T) {
+	defer setMessageLanguage("en")
+	setMessageLanguage("tr_TR")
+	if got := Tf("Your Score: %d", 7); got != "Senin Puanın: 7" {
+		t.Errorf("Turkish score %q", got)
+	}
+	if got := Tf("Before your move %d of %d", 3, 9); got != "9 hamlenden 3. hamlenin öncesi" {
+		t.Errorf("Turkish with reordered arguments %q", got)
+	}
+	if got := T("not in the catalog"); got != "not in the catalog" {
+		t.Errorf("a text without a translation shows as %q", got)
+	}
+	setMessageLanguage("de-DE")
+	if got := Tf("Your Score: %d", 7); got != "Your Score: 7" {
+		t.Errorf("a language without a catalog shows %q", got)
+	}
+}
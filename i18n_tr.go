@@ -0,0 +1,338 @@
+package main
+
+// messagesTR is the Turkish catalog. The computer opponent is "Bilgisayar"
+// and a capture is "alma", as players say at the table; Pişti keeps its name.
+var messagesTR = map[string]string{
+	// The main screen and its messages.
+	"Exit":                           "Çıkış",
+	"Are you sure you want to quit?": "Çıkmak istediğine emin misin?",
+	"Select Level":                   "Seviye Seç",
+	"Start":                          "Başla",
+	"Leave":                          "Ayrıl",
+	"Are you sure you want to leave the online game?": "Çevrimiçi oyundan ayrılmak istediğine emin misin?",
+	"Are you sure you want to start a new game?":      "Yeni bir oyun başlatmak istediğine emin misin?",
+	"Are you sure you want to end the current game?":  "Bu oyunu bitirmek istediğine emin misin?",
+	"Undo":           "Geri Al",
+	"Replay":         "Tekrar Oyna",
+	"Your Score: %d": "Senin Puanın: %d",
+	"CPU Score: %d":  "Bilgisayarın Puanı: %d",
+	"Welcome to Pishti! Select a level and start the game.": "Pişti'ye hoş geldin! Bir seviye seç ve oyunu başlat.",
+	"Please select a level first!":                          "Önce bir seviye seç!",
+	"Replaying the same deal.":                              "Aynı dağıtım yeniden oynanıyor.",
+	"Select a level and press Start.":                       "Bir seviye seç ve Başla'ya bas.",
+	"Next Deal":                                             "Sonraki Dağıtım",
+	"You Win! Final Score: You %d - %d CPU":                 "Kazandın! Son Durum: Sen %d - %d Bilgisayar",
+	"CPU Wins! Final Score: You %d - %d CPU":                "Bilgisayar Kazandı! Son Durum: Sen %d - %d Bilgisayar",
+	"It's a Tie! Final Score: You %d - %d CPU":              "Berabere! Son Durum: Sen %d - %d Bilgisayar",
+	"You":          "Sen",
+	"CPU":          "Bilgisayar",
+	"Analysis: %s": "Analiz: %s",
+	"You captured the hidden cards: %s, %s, and %s!": "Kapalı kartları aldın: %s, %s ve %s!",
+	"You do not hold that card.":                     "O kart elinde yok.",
+	"Your move!":                                     "Sıra sende!",
+	"Loading sounds...":                              "Sesler yükleniyor...",
+
+	// The rank names of the hidden cards message.
+	"Ace":   "As",
+	"Deuce": "İkili",
+	"Three": "Üçlü",
+	"Four":  "Dörtlü",
+	"Five":  "Beşli",
+	"Six":   "Altılı",
+	"Seven": "Yedili",
+	"Eight": "Sekizli",
+	"Nine":  "Dokuzlu",
+	"Ten":   "Onlu",
+	"Jack":  "Vale",
+	"Queen": "Kız",
+	"King":  "Papaz",
+
+	// The capture ticker and the replays.
+	"CPU: Pişti! +%d":                                         "Bilgisayar: Pişti! +%d",
+	"CPU took the last %d cards (+%d)":                        "Bilgisayar son %d kartı aldı (+%d)",
+	"CPU captured %d cards (+%d)":                             "Bilgisayar %d kart aldı (+%d)",
+	"You: Pişti! +%d":                                         "Sen: Pişti! +%d",
+	"You took the last %d cards (+%d)":                        "Son %d kartı aldın (+%d)",
+	"You captured %d cards (+%d)":                             "%d kart aldın (+%d)",
+	"You played the %s":                                       "Oynadığın kart: %s",
+	"CPU played the %s":                                       "Bilgisayarın oynadığı kart: %s",
+	"The deal":                                                "Dağıtım",
+	"Final score":                                             "Son durum",
+	"CPU made a Jack Pişti! +%d":                              "Bilgisayar Vale Pişti yaptı! +%d",
+	"CPU made a Pişti! +%d":                                   "Bilgisayar Pişti yaptı! +%d",
+	"CPU took the last %d cards":                              "Bilgisayar son %d kartı aldı",
+	"CPU took %d cards, +%d":                                  "Bilgisayar %d kart aldı, +%d",
+	"You made a Jack Pişti! +%d":                              "Vale Pişti yaptın! +%d",
+	"You made a Pişti! +%d":                                   "Pişti yaptın! +%d",
+	"You took the last %d cards":                              "Son %d kartı aldın",
+	"You took %d cards, +%d":                                  "%d kart aldın, +%d",
+	"You %d - %d CPU":                                         "Sen %d - %d Bilgisayar",
+	"MP4 export needs ffmpeg on the PATH; save a GIF instead": "MP4 için PATH üzerinde ffmpeg gerekir; bunun yerine GIF kaydet",
+	"Whole game":                                              "Tüm oyun",
+	"Captures only":                                           "Yalnızca almalar",
+	"Show":                                                    "Göster",
+	"Format":                                                  "Biçim",
+	"Export Replay":                                           "Tekrarı Dışa Aktar",
+	"Export":                                                  "Dışa Aktar",
+	"Step %d of %d: %s":                                       "Adım %d / %d: %s",
+	"Final score: You %d - %d CPU":                            "Son durum: Sen %d - %d Bilgisayar",
+
+	// The move history overlay and practice.
+	"New hand dealt":             "Yeni el dağıtıldı",
+	"New game":                   "Yeni oyun",
+	"Move taken back":            "Hamle geri alındı",
+	"Game over: You %d - %d CPU": "Oyun bitti: Sen %d - %d Bilgisayar",
+	"Takes the pile":             "Yığını alır",
+	"%.0f%% risk · %d left":      "%%%.0f risk · %d kaldı",
+
+	// Reviewing a game.
+	"Before your move %d of %d": "%[2]d hamlenden %[1]d. hamlenin öncesi",
+	"Your move %d":              "%d. hamlen",
+	"Review":                    "İncele",
+	"Play from Here":            "Buradan Oyna",
+	"Analysis from your move %d: the game does not count.": "%d. hamlenden analiz: bu oyun sayılmaz.",
+
+	// Matches.
+	"Single game":                               "Tek oyun",
+	"First to %d":                               "%d puana ilk ulaşan",
+	"Deal %d — You %d, CPU %d":                  "%d. dağıtım — Sen %d, Bilgisayar %d",
+	"Match to %d after deal %d: %s":             "%[1]d puanlık maç, %[2]d. dağıtımdan sonra: %[3]s",
+	"%s wins the match to %d! %s after deal %d": "%[2]d puanlık maçı %[1]s kazandı! %[4]d. dağıtımdan sonra %[3]s",
+
+	// Game codes.
+	"Open Folder": "Klasörü Aç",
+	"Close":       "Kapat",
+	"Game Code":   "Oyun Kodu",
+	"Start a game first; its code can then be shared.":                             "Önce bir oyun başlat; sonra kodunu paylaşabilirsin.",
+	"Copied to the clipboard. Anyone can load it to get the same cards and moves.": "Panoya kopyalandı. Yükleyen herkes aynı kartları ve hamleleri görür.",
+	"Code":                            "Kod",
+	"This ends the game in progress.": "Bu, süren oyunu bitirir.",
+	"Load Game Code":                  "Oyun Kodu Yükle",
+	"Load":                            "Yükle",
+	"Cancel":                          "İptal",
+	"this game comes from a newer version of Pishti": "bu oyun Pishti'nin daha yeni bir sürümünden",
+	"New Game":                   "Yeni Oyun",
+	"Game loaded from its code.": "Oyun kodundan yüklendi.",
+	"Your game was restored where you left it.": "Oyunun kaldığın yerden sürüyor.",
+
+	// The Daily Deal.
+	"Daily Deal of %s: everyone gets these cards today.":     "%s Günün Dağıtımı: bugün herkes bu kartları alıyor.",
+	"Daily Deal of %s at %s: %d points, your best today %d.": "%s Günün Dağıtımı, %s: %d puan, bugünkü en iyin %d.",
+
+	// Pass & Play.
+	"Player 2":              "Oyuncu 2",
+	"Player 1":              "Oyuncu 1",
+	"Pass the device to %s": "Cihazı %s oyuncusuna ver",
+	"I'm %s, Show My Cards": "Ben %s, Kartlarımı Göster",
+	"Pass & Play: Player 1 has the bottom hand, Player 2 the top.": "Elden Ele: Oyuncu 1 alttaki, Oyuncu 2 üstteki eli oynar.",
+	"It's a Tie!":    "Berabere!",
+	"Player 1 Wins!": "Oyuncu 1 Kazandı!",
+	"Player 2 Wins!": "Oyuncu 2 Kazandı!",
+	"%s Final Score: Player 1 %d - %d Player 2": "%s Son Durum: Oyuncu 1 %d - %d Oyuncu 2",
+
+	// House rules.
+	"Pişti %d, Jack Pişti %d, majority %d, Deuce of Clubs %d, Ten of Diamonds %d.": "Pişti %d, Vale Pişti %d, çoğunluk %d, Sinek İkilisi %d, Karo Onlusu %d.",
+	"The cards left at the end score for nobody.":                                  "Sonda kalan kartlar kimseye sayılmaz.",
+	"The last capture takes the cards left":                                        "Son alan, kalan kartları alır",
+	"Bonus for taking more cards":                                                  "Çok kart alana ikramiye",
+	"House Rules":                                                                  "Ev Kuralları",
+	"Save":                                                                         "Kaydet",
+	"use a whole number":                                                           "tam sayı gir",
+	"must not be negative":                                                         "eksi olamaz",
+	"Most cards":                                                                   "En çok kart",
+	"Deuce of Clubs":                                                               "Sinek İkilisi",
+	"Ten of Diamonds":                                                              "Karo Onlusu",
+
+	// Online play.
+	"New room": "Yeni oda",
+	"Server":   "Sunucu",
+	"Room":     "Oda",
+	"Leave empty to open a room and pass its code on.": "Bir oda açıp kodunu paylaşmak için boş bırak.",
+	"Play Online":                            "Çevrimiçi Oyna",
+	"Connect":                                "Bağlan",
+	"Connecting...":                          "Bağlanılıyor...",
+	"Joined room %s.":                        "%s odasına katıldın.",
+	"The connection to the server was lost.": "Sunucuyla bağlantı koptu.",
+	"It's a Tie! You %d - %d Opponent":       "Berabere! Sen %d - %d Rakip",
+	"You Win! You %d - %d Opponent":          "Kazandın! Sen %d - %d Rakip",
+	"You Lose. You %d - %d Opponent":         "Kaybettin. Sen %d - %d Rakip",
+	"Room %s: waiting for your opponent. Give them the code to join.": "%s odası: rakip bekleniyor. Katılması için kodu ona ver.",
+	"Room %s: your move.":            "%s odası: sıra sende.",
+	"Room %s: your opponent's move.": "%s odası: sıra rakipte.",
+
+	// Settings.
+	"Animated background":                                    "Hareketli arka plan",
+	"Load all card images at startup":                        "Tüm kart resimlerini açılışta yükle",
+	"Pause music when in background":                         "Arka plandayken müziği duraklat",
+	"Lower music under important effects":                    "Önemli efektlerde müziğin sesini kıs",
+	"Practice: show the odds of each card (no achievements)": "Alıştırma: her kartın olasılıklarını göster (başarım yok)",
+	"Voice control":                                          "Sesli komut",
+	"Voice control (set a recognizer in config.toml)":        "Sesli komut (config.toml içinde bir tanıyıcı ayarla)",
+	"Notify me of my move when in background":                "Arka plandayken sıra bana gelince bildir",
+	"Play sound effects":                                     "Ses efektlerini çal",
+	"Play music":                                             "Müzik çal",
+	"Master":                                                 "Ana ses",
+	"Music":                                                  "Müzik",
+	"Effects":                                                "Efektler",
+	"Announcer":                                              "Anons",
+	"Individual Sounds...":                                   "Tek Tek Sesler...",
+	"Test Sounds...":                                         "Sesleri Dene...",
+	"Advanced Audio...":                                      "Gelişmiş Ses...",
+	"Game Tuning...":                                         "Oyun Ayarları...",
+	"Copy Game Code":                                         "Oyun Kodunu Kopyala",
+	"Load Game Code...":                                      "Oyun Kodu Yükle...",
+	"Watch AI vs AI...":                                      "Bilgisayara Karşı Bilgisayarı İzle...",
+	"Pass & Play":                                            "Elden Ele",
+	"Play Online...":                                         "Çevrimiçi Oyna...",
+	"Daily Deal":                                             "Günün Dağıtımı",
+	"Number":                                                 "Sayı",
+	"Deal":                                                   "Dağıtım",
+	"Edit...":                                                "Düzenle...",
+	"Rules":                                                  "Kurallar",
+	"Match":                                                  "Maç",
+	"Level":                                                  "Seviye",
+	"Language":                                               "Dil",
+	"Seed":                                                   "Tohum",
+	"Selected for each new game":                             "Her yeni oyunda seçili gelir",
+	"Cards and messages change at once, the rest after a restart": "Kartlar ve mesajlar hemen, geri kalanı yeniden başlatınca değişir",
+	"Deals the same cards for the same number":                    "Aynı sayı için aynı kartları dağıtır",
+	"Cards":                       "Kartlar",
+	"Table":                       "Masa",
+	"Card back":                   "Kart arkası",
+	"Frames":                      "Çerçeveler",
+	"Classic":                     "Klasik",
+	"Settings":                    "Ayarlar",
+	"Applies from the next game.": "Sonraki oyundan itibaren geçerli.",
+	"Individual Sounds":           "Tek Tek Sesler",
+	"Automatic":                   "Otomatik",
+	"Restore Safe Defaults":       "Güvenli Varsayılanlara Dön",
+	"Sample rate":                 "Örnekleme hızı",
+	"Buffer":                      "Arabellek",
+	"Raise the buffer if sounds crackle, lower it if card sounds lag.\nChanges take effect after restarting Pishti.": "Sesler cızırdarsa arabelleği büyüt, kart sesleri gecikirse küçült.\nDeğişiklikler Pishti yeniden başlatılınca geçerli olur.",
+	"Output device":                     "Çıkış aygıtı",
+	"System default":                    "Sistem varsayılanı",
+	"Advanced Audio":                    "Gelişmiş Ses",
+	"0s turns the animations off":       "0s animasyonları kapatır",
+	"0s turns the reminder off":         "0s hatırlatıcıyı kapatır",
+	"Game Tuning":                       "Oyun Ayarları",
+	"use a duration like 500ms or 1.5s": "500ms ya da 1.5s gibi bir süre gir",
+	"Off":                               "Kapalı",
+	"System":                            "Sistem",
+	"Slow":                              "Yavaş",
+	"Normal":                            "Normal",
+	"Fast":                              "Hızlı",
+	"None":                              "Yok",
+	"Built-in":                          "Yerleşik",
+	"CPU delay":                         "Bilgisayar bekleme süresi",
+	"Capture pause":                     "Almada duraklama",
+	"End of hand pause":                 "El sonunda duraklama",
+	"Expert thinking time":              "Uzman düşünme süresi",
+	"Card animation":                    "Kart animasyonu",
+	"Turn reminder":                     "Sıra hatırlatıcı",
+
+	// The rule variants.
+	"A Pişti scores 10 points and a Jack Pişti 20.": "Pişti 10, Vale Pişti 20 puan.",
+	"Every Pişti scores 10 points, Jacks included.": "Valeler dahil her Pişti 10 puan.",
+	"House rules of your own.":                      "Kendi ev kuralların.",
+
+	// The sounds, in the settings and the sound test.
+	"Card play":             "Kart oynama",
+	"Capture":               "Alma",
+	"Pişti":                 "Pişti",
+	"Jack Pişti":            "Vale Pişti",
+	"Game start":            "Oyun başlangıcı",
+	"You win":               "Kazanma",
+	"CPU wins":              "Bilgisayar kazanır",
+	"Tie":                   "Beraberlik",
+	"Announcer: Pişti":      "Anons: Pişti",
+	"Announcer: Jack Pişti": "Anons: Vale Pişti",
+	"Announcer: You win":    "Anons: Kazandın",
+	"Announcer: CPU wins":   "Anons: Bilgisayar kazandı",
+	"Announcer: Tie":        "Anons: Berabere",
+	"Announcer: 50 points":  "Anons: 50 puan",
+	"Announcer: 100 points": "Anons: 100 puan",
+	"Sound Test":            "Ses Denemesi",
+
+	// Sharing a result.
+	"You Win!":                       "Kazandın!",
+	"CPU Wins!":                      "Bilgisayar Kazandı!",
+	"Played in %s, Daily Deal of %s": "%[1]s sürdü, %[2]s Günün Dağıtımı",
+	"Played in %s, seed %d":          "%s sürdü, tohum %d",
+	"Daily Deal of %s":               "%s Günün Dağıtımı",
+	"Seed %d":                        "Tohum %d",
+	"%s level, %s rules":             "%s seviyesi, %s kuralları",
+	"Piştis: you %d, CPU %d":         "Piştiler: sen %d, Bilgisayar %d",
+	"Save Image...":                  "Resmi Kaydet...",
+	"Copy Text":                      "Metni Kopyala",
+	"Export Replay...":               "Tekrarı Dışa Aktar...",
+	"Share Result":                   "Sonucu Paylaş",
+
+	// Watching the AI.
+	"Show both hands": "İki eli de göster",
+	"Bottom":          "Alt",
+	"Top":             "Üst",
+	"Speed":           "Hız",
+	"Plays in your seat. This ends the game in progress.": "Senin yerine oynar. Bu, süren oyunu bitirir.",
+	"Watch AI vs AI":                         "Bilgisayara Karşı Bilgisayarı İzle",
+	"Watch":                                  "İzle",
+	"Watching %s (bottom) against %s (top).": "%s (alt) ile %s (üst) izleniyor.",
+
+	// Statistics.
+	"All":        "Tümü",
+	"Statistics": "İstatistikler",
+	"Statistics need a user data directory, which this device does not have.":      "İstatistikler için kullanıcı veri klasörü gerekir; bu cihazda yok.",
+	"No games finished yet. Games watched, practiced or analysed are not counted.": "Henüz biten oyun yok. İzlenen, alıştırma ya da analiz oyunları sayılmaz.",
+	"Played":        "Oynanan",
+	"Won":           "Kazanılan",
+	"Lost":          "Kaybedilen",
+	"Tied":          "Berabere",
+	"Piştis":        "Piştiler",
+	"Jack Piştis":   "Vale Piştiler",
+	"Average score": "Ortalama puan",
+
+	// Achievements.
+	"Achievement unlocked: %s":            "Başarım açıldı: %s",
+	"First Win":                           "İlk Galibiyet",
+	"Pişti!":                              "Pişti!",
+	"Jack of All Trades":                  "Her İşin Valesi",
+	"Hat Trick":                           "Hat-trick",
+	"Half Century":                        "Yarım Asır",
+	"Card Shark":                          "Kart Kurdu",
+	"Grandmaster":                         "Büyük Usta",
+	"Clean Sheet":                         "Gol Yemeden",
+	"Regular":                             "Müdavim",
+	"Win a game.":                         "Bir oyun kazan.",
+	"Make a Pişti.":                       "Bir Pişti yap.",
+	"Make a Jack Pişti.":                  "Bir Vale Pişti yap.",
+	"Make three Piştis in one game.":      "Bir oyunda üç Pişti yap.",
+	"Score 50 points in one game.":        "Bir oyunda 50 puan al.",
+	"Beat the Advanced level.":            "Advanced seviyesini yen.",
+	"Beat the Expert level.":              "Expert seviyesini yen.",
+	"Win a game without the CPU scoring.": "Bilgisayar hiç puan almadan bir oyun kazan.",
+	"Win 10 games at the Advanced level.": "Advanced seviyesinde 10 oyun kazan.",
+	"Achievements":                        "Başarımlar",
+	"Achievements...":                     "Başarımlar...",
+	"Achievements need a user data directory, which this device does not have.": "Başarımlar için kullanıcı veri klasörü gerekir; bu cihazda yok.",
+	"Locked":            "Kilitli",
+	"Unlocked on %s":    "%s tarihinde açıldı",
+	"%d of %d unlocked": "%[2]d başarımdan %[1]d tanesi açıldı",
+
+	// The system tray.
+	"No move pending":  "Bekleyen hamle yok",
+	"Show Pishti":      "Pishti'yi Göster",
+	"Minimize to Tray": "Tepsiye Küçült",
+
+	// Problems.
+	"Some card images are missing; their cards are shown face down.":                   "Bazı kart resimleri eksik; o kartlar kapalı gösteriliyor.",
+	"Some images are missing; placeholders are shown instead.":                         "Bazı resimler eksik; yerlerine boş resimler gösteriliyor.",
+	"Sound is off: this browser does not support Web Audio.":                           "Ses kapalı: bu tarayıcı Web Audio desteklemiyor.",
+	"Sound is off: no audio device could be opened.":                                   "Ses kapalı: hiçbir ses aygıtı açılamadı.",
+	"Some sounds are missing and will stay silent.":                                    "Bazı sesler eksik ve çalınmayacak.",
+	"Sound stopped because the audio device was lost. Restart Pishti to get it back.":  "Ses aygıtı kaybolduğu için ses durdu. Geri getirmek için Pishti'yi yeniden başlat.",
+	"The chosen card skin is missing; the built-in cards are shown.":                   "Seçilen kart görünümü yok; yerleşik kartlar gösteriliyor.",
+	"The chosen card skin has images of the wrong size; the built-in cards are shown.": "Seçilen kart görünümünün resimleri yanlış boyutta; yerleşik kartlar gösteriliyor.",
+	"The chosen card skin has no card images; the built-in cards are shown.":           "Seçilen kart görünümünde kart resmi yok; yerleşik kartlar gösteriliyor.",
+
+	// The crash report.
+	"Sorry, Pishti ran into a problem and had to close.\nA report describing the game was saved to:": "Üzgünüz, Pishti bir sorunla karşılaştı ve kapanmak zorunda kaldı.\nOyunu anlatan bir rapor şuraya kaydedildi:",
+}
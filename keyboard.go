@@ -0,0 +1,25 @@
+package main
+
+import "fyne.io/fyne/v2"
+
+// handleKey processes keyboard shortcuts typed anywhere in the window.
+func (ui *AppUI) handleKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyM:
+		ui.toggleMute()
+	}
+}
+
+// focusPlayerCard moves the keyboard focus from the given slot of the
+// player's hand to the next card held in the direction of step, wrapping
+// around the hand.
+func (ui *AppUI) focusPlayerCard(from, step int) {
+	n := len(ui.playerCardWidgets)
+	for i := 1; i < n; i++ {
+		slot := ((from+i*step)%n + n) % n
+		if ui.view.playerCard(slot) != nil {
+			ui.window.Canvas().Focus(ui.playerCardWidgets[slot])
+			return
+		}
+	}
+}
@@ -20,3 +20,30 @@ func (m *minSizeLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
 func (m *minSizeLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
 	return m.min
 }
+
+// safeAreaLayout keeps its content inside the canvas's interactive area, away
+// from the notches, rounded corners and system bars of phones. On desktops
+// the area is the whole window.
+type safeAreaLayout struct {
+	canvas fyne.Canvas
+}
+
+// Layout fits the content to the part of the interactive area the container
+// covers.
+func (s *safeAreaLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	pos, area := s.canvas.InteractiveArea()
+	area = area.Min(size.SubtractWidthHeight(pos.X, pos.Y))
+	for _, o := range objects {
+		o.Move(pos)
+		o.Resize(area)
+	}
+}
+
+// MinSize returns the content's minimum size.
+func (s *safeAreaLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	min := fyne.NewSize(0, 0)
+	for _, o := range objects {
+		min = min.Max(o.MinSize())
+	}
+	return min
+}
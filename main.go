@@ -1,45 +1,196 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"image/color"
+	"log"
+	"os"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
 )
 
 // --- GUI Specific Code ---
 // AppUI holds all the GUI widgets and the game state.
 type AppUI struct {
-	casino              *Casino
-	isAnimating         bool // Flag to prevent clicks during CPU "turn" animation.
-	gameOverSoundPlayed bool // Flag to ensure win/loss sound plays only once.
+	casino              *engine.Casino
+	loop                *engine.Loop  // Carries out every game change; busy while the player must wait.
+	gameOverSoundPlayed bool          // Flag to ensure win/loss sound plays only once.
+	gameStarted         time.Time     // When the current game was dealt, for its duration.
+	gameDuration        time.Duration // How long the last finished game took.
 	// UI Components.
 	window fyne.Window
 	// Top bar.
-	levelSelect *widget.Select
-	startButton *widget.Button
-	undoButton  *widget.Button
+	levelSelect  *widget.Select
+	startButton  *widget.Button
+	undoButton   *widget.Button
+	replayButton *widget.Button
+	shareButton  *widget.Button
+	reviewButton *widget.Button
+	watchButton  *widget.Button
+	muteButton   *widget.Button
+	// Background.
+	background      *animatedBackground
+	backgroundImage *canvas.Image
+	frameImages     []*canvas.Image // Behind every hand slot, kept for reloading the art.
+	tray            *trayState      // Nil when the platform has no system tray.
 	// Center display.
 	tableCardWidget *clickableImage
 	tablePileImage  *canvas.Image
+	tableStack      *fyne.Container
 	infoLabel       *widget.Label
+	// Score labels, where captured piles slide off to.
+	playerScoreLabel *widget.Label
+	cpuScoreLabel    *widget.Label
+	// Cards sliding from the hands to the table and off it.
+	cards *cardAnimator
+	// Sizes the table to the window.
+	scaler *layoutScaler
+	// What the widgets show, kept up to date from the engine's events.
+	view *gameView
 	// Player hands.
 	playerCardWidgets []*clickableImage
 	cpuCardWidgets    []*clickableImage
-	// Score labels.
-	playerScoreLabel *widget.Label
-	cpuScoreLabel    *widget.Label
+	// Turn reminder.
+	handHighlight *canvas.Rectangle // Pulses behind the player's hand when they are idle.
+	reminderTimer *time.Timer
+	reminderPulse *fyne.Animation
+	inBackground  bool // The window is minimized or another one has the focus.
+	turnNotified  bool // A notification was sent for the player's current turn.
+	// The AI match being watched, nil while the player plays.
+	spectator              *spectateOptions
+	revealBeforeSpectating bool // Whether the CPU's hand was shown before the match.
+	practice               bool // Show the odds of each card in the player's hand.
+	oddsBadges             []*oddsBadge
+	unrated                bool           // The game earns no achievements: it is watched, practiced or analysis.
+	analysis               bool           // The game was branched off a finished one in review.
+	voice                  *voiceListener // nil while voice control is off.
+	// The Pass & Play game, nil while the CPU has its seat.
+	hotSeat *hotSeatState
+	pass    *passOverlay
+	// The date of the Daily Deal being played, "" in any other game.
+	daily string
+	// The online game, nil while playing on this device.
+	remote *remoteGame
+	// Steps through the finished game; its bar is hidden until then.
+	replay    *replayViewer
+	replayBar *fyne.Container
+	// Recent capture events above the player's hand.
+	ticker *captureTicker
+	// Hidden console for reproducing bugs, toggled with debugShortcut.
+	debug *debugConsole
+	// Live state for stream overlays; nil unless the config file asks for it.
+	overlay *overlayExport
+	// Rules from the scripts folder run on game events; nil if there are none.
+	hooks *eventHooks
+	// Unlocked achievements; nil without a data directory.
+	achievements *achievementStore
+	toast        *achievementToast // Announces the achievements unlocked over the table.
+	// Lifetime statistics; nil without a data directory.
+	stats *statsStore
+	// Announcer progress, so each event is only announced once.
+	announcedCaptures int
+	milestonesReached int
 }
 
 func main() {
-	myApp := app.New()
+	if len(os.Args) > 1 && os.Args[1] == simulateCommand {
+		appConfig = loadConfig()
+		registerBots(appConfig.AI.Bots)
+		if err := runSimulate(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == engineCommand {
+		appConfig = loadConfig()
+		registerBots(appConfig.AI.Bots)
+		if err := runEngineProtocol(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	opts, err := parseLaunchOptions(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Print(strings.TrimPrefix(err.Error(), flag.ErrHelp.Error()+"\n\n"))
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if opts.crashReport != "" {
+		showCrashReport(opts.crashReport)
+		return
+	}
+	setupAppDirs()
+	appConfig = loadConfig()
+	registerBots(appConfig.AI.Bots)
+	if opts.headlessSim > 0 {
+		if err := runHeadlessSim(opts, opts.headlessSim); err != nil {
+			log.Printf("ERROR: Simulation failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.tui {
+		if err := runTUI(opts, os.Stdin, os.Stdout); err != nil {
+			log.Printf("ERROR: Terminal game failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+	startLogFile()
+	if opts.serve != "" {
+		if err := runAPIServer(opts.serve, os.Getenv(apiKeyEnv)); err != nil {
+			log.Printf("ERROR: API server failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.grpc != "" {
+		if err := runGRPCServer(opts.grpc, os.Getenv(apiKeyEnv)); err != nil {
+			log.Printf("ERROR: gRPC server failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.dev {
+		if err := useDevAssets(); err != nil {
+			log.Printf("ERROR: Development mode unavailable: %v", err)
+			opts.dev = false
+		}
+	}
+	if opts.pprof != "" {
+		startProfiling(opts.pprof)
+	}
+	myApp := app.NewWithID("io.github.ser7ach.pishti") // The ID is required for persistent preferences.
+	if opts.lang == "" {
+		opts.lang = myApp.Preferences().String(prefLanguage) // The one picked in settings.
+	}
+	if opts.lang != "" {
+		setLanguage(opts.lang) // Before the app starts, so the toolkit picks it up.
+	} else {
+		setMessageLanguage("") // The system's.
+	}
 	myWindow := myApp.NewWindow("Pishti")
 	// Set icon from file
 	icon, err := fyne.LoadResourceFromPath("assets/ui/icon.png")
@@ -47,98 +198,194 @@ func main() {
 		myWindow.SetIcon(icon)
 	}
 	// Apply custom theme to make buttons and selects transparent.
-	myApp.Settings().SetTheme(newTransparentTheme(myApp.Settings().Theme()))
-	myWindow.SetFixedSize(true)
-	myWindow.Resize(fyne.NewSize(440, 600))
+	myApp.Settings().SetTheme(newTransparentTheme(myApp.Settings().Theme(), touchScreen()))
+	myWindow.Resize(baseWindowSize)
 	// Initialize all resources after the app is created to avoid deadlocks with Go tooling.
-	loadResources()
-	initAudio()
-	ui := &AppUI{
-		casino: NewCasino(),
-		window: myWindow,
+	useSkin(myApp.Preferences().String(prefCardSkin))
+	for _, part := range tableParts {
+		useTableDesign(part, myApp.Preferences().String(part.pref))
 	}
+	loadResources(myApp.Preferences().Bool(prefPreloadCards))
+	initAudio(audioSettings(myApp.Preferences()))
+	// Quitting cancels this context, which stops the game loop and any pause in flight.
+	gameCtx, stopGame := context.WithCancel(context.Background())
+	ui := newAppUI(gameCtx, myWindow, engine.NewCasino(nil, nil), appConfig.AI.pacing())
+	// A panic on the UI goroutine unwinds to here; the loop reports its own.
+	defer func() {
+		if r := recover(); r != nil {
+			reportCrash(ui.casino, r, debug.Stack())
+		}
+	}()
 	content := ui.buildLayout()
+	ui.applySettings()
+	ui.setupSystemTray(myApp)
+	ui.setupFocusHandling(myApp)
 	ui.updateUI() // Initial UI state.
+	ui.applyLaunchOptions(opts)
+	ui.resumeSuspendedGame()
+	if opts.dev {
+		ui.watchAssets()
+	}
+	profiler.watchFrames()
 	myWindow.SetContent(content)
+	myWindow.Canvas().SetOnTypedKey(ui.handleKey)
+	myWindow.Canvas().AddShortcut(debugShortcut, func(fyne.Shortcut) { ui.debug.toggle() })
 	myWindow.CenterOnScreen()
 	// Add a confirmation dialog when the user tries to close the window.
 	myWindow.SetCloseIntercept(func() {
-		dialog.ShowConfirm("Exit", "Are you sure you want to quit?", func(confirmed bool) {
+		dialog.ShowConfirm(T("Exit"), T("Are you sure you want to quit?"), func(confirmed bool) {
 			if confirmed {
-				myApp.Quit() // Quit the entire application.
+				stopGame() // Nothing may move while the music fades out.
+				// Let the music fade out before quitting the entire application.
+				go func() {
+					<-FadeOutMusic()
+					fyne.Do(myApp.Quit)
+				}()
 			}
 		}, myWindow)
 	})
-	myWindow.ShowAndRun()
+	showSplash(myApp, myWindow)
+	myApp.Run()
+}
+
+// newAppUI sets up the casino's game for the window, with the engine loop
+// running until ctx is cancelled. The screen is made separately by
+// buildLayout.
+func newAppUI(ctx context.Context, window fyne.Window, casino *engine.Casino, pacing engine.Pacing) *AppUI {
+	ui := &AppUI{
+		casino: casino,
+		window: window,
+	}
+	ui.view = newGameView(ui.casino)
+	ui.overlay = newOverlayExport(appConfig.Overlay.File)
+	ui.hooks = loadEventHooks(dataPath(scriptsDir))
+	ui.achievements = loadAchievements()
+	ui.stats = loadStats()
+	// PISHTI_DEBUG=1 makes the engine audit itself after every move.
+	if os.Getenv("PISHTI_DEBUG") != "" {
+		ui.casino.SetDebug(true)
+	}
+	ui.loop = engine.NewLoop(ctx, ui.casino, pacing, func() {
+		fyne.Do(ui.applyEvents)
+	})
+	ui.loop.SetPanicHandler(func(value any, stack []byte) {
+		reportCrash(ui.casino, value, stack)
+	})
+	return ui
 }
 
+// buildLayout creates the widgets of the game screen and returns its content.
 func (ui *AppUI) buildLayout() fyne.CanvasObject {
-	levelOptions := []string{"Beginner", "Intermediate", "Advanced"}
+	// The sizes below are for the base window; the scaler resizes them to the window's.
+	ui.scaler = newLayoutScaler()
+	// The levels on offer are the registered CPU strategies.
+	strategies := engine.Strategies()
+	levelOptions := make([]string, len(strategies))
+	for i, s := range strategies {
+		levelOptions[i] = s.Name
+	}
 	// Top Bar.
-	ui.levelSelect = widget.NewSelect(levelOptions, func(s string) {
-		for i, label := range levelOptions {
-			if s == label {
-				// The GameLevel enum starts at 1 for Beginner, so add 1 to the index.
-				ui.casino.SetLevel(GameLevel(i + 1))
+	ui.levelSelect = widget.NewSelect(levelOptions, func(name string) {
+		for _, s := range strategies {
+			if s.Name == name {
+				ui.loop.SetLevel(s.Level)
 				break
 			}
 		}
 	})
-	ui.levelSelect.PlaceHolder = "Select Level"
+	ui.levelSelect.PlaceHolder = T("Select Level")
 	// Fix for truncated text: Use a "probe" widget to get the correct size.
 	// Create a temporary Select widget with the longest text to measure its minimum required size.
-	// "Select Level" is the longest string in this case.
+	// The placeholder is the longest string in this case.
 	probe := widget.NewSelect([]string{ui.levelSelect.PlaceHolder}, nil)
 	probe.PlaceHolder = ui.levelSelect.PlaceHolder
 	minWidgetWidth := probe.MinSize().Width
 	// To set a minimum size, wrap the widget in a sized container.
 	minWidgetSize := fyne.NewSize(minWidgetWidth, ui.levelSelect.MinSize().Height)
 	// Use a container with a minSizeLayout to ensure the select widget meets a minimum width.
-	sizedSelect := container.New(&minSizeLayout{min: minWidgetSize}, ui.levelSelect)
-	ui.startButton = widget.NewButton("Start", func() {
+	selectSize := &minSizeLayout{}
+	ui.scaler.add(minWidgetSize, func(size fyne.Size) { selectSize.min = size })
+	sizedSelect := container.New(selectSize, ui.levelSelect)
+	ui.startButton = widget.NewButton(T("Start"), func() {
+		if ui.remote != nil {
+			dialog.ShowConfirm(T("Leave"), T("Are you sure you want to leave the online game?"), func(confirmed bool) {
+				if confirmed {
+					ui.resetGameUI()
+				}
+			}, ui.window)
+			return
+		}
 		// If no game has started yet, just try to start one directly.
 		// This handles both the very first game and subsequent games after a reset.
-		if ui.casino.gameState == StateNotStarted {
+		if ui.casino.State() == engine.StateNotStarted {
 			ui.attemptToStartGame()
 			return
 		}
+		// Between the deals of a match, the next one is dealt straight away.
+		if ui.matchContinues() {
+			ui.nextDeal()
+			return
+		}
 		// If a game is over, the confirmation text should reflect that.
-		if ui.casino.gameState == StateGameOver {
-			dialog.ShowConfirm("New Game", "Are you sure you want to start a new game?", func(confirmed bool) {
+		if ui.casino.State() == engine.StateGameOver {
+			dialog.ShowConfirm(T("New Game"), T("Are you sure you want to start a new game?"), func(confirmed bool) {
 				if confirmed {
 					ui.resetGameUI()
 				}
 			}, ui.window)
 		} else { // For any other state (an in-progress game).
-			dialog.ShowConfirm("New Game", "Are you sure you want to end the current game?", func(confirmed bool) {
+			dialog.ShowConfirm(T("New Game"), T("Are you sure you want to end the current game?"), func(confirmed bool) {
 				if confirmed {
 					ui.resetGameUI()
 				}
 			}, ui.window)
 		}
 	})
-	ui.undoButton = widget.NewButton("Undo", func() {
-		if ui.casino.undoImplementation() {
+	ui.undoButton = widget.NewButton(T("Undo"), func() {
+		// The loop drops a CPU reply still pending for the undone play.
+		if ui.loop.Undo() {
 			// If a special message (like the initial pile capture) was being shown,
 			// clear it now that the player has undone the action.
-			if ui.casino.initialPileCaptureMsg != "" {
-				ui.casino.initialPileCaptureMsg = ""
-				ui.infoLabel.SetText("")
+			if ui.casino.InitialPileCaptureMessage() != "" {
+				ui.loop.ClearInitialPileCaptureMessage()
+				ui.view.info.Set("")
 			}
 			PlaySound(SoundUndo)
-			ui.updateUI()
+			ui.applyEvents()
 		}
 	})
+	// The replay button takes the undo button's place once a game is over.
+	ui.replayButton = widget.NewButton(T("Replay"), ui.replaySameDeal)
+	ui.replayButton.Hide()
+	// The finished game's result can be shared as an image.
+	ui.shareButton = widget.NewButtonWithIcon("", theme.MailForwardIcon(), ui.showShareResult)
+	ui.shareButton.Hide()
+	// So can it be reviewed, and played on from an earlier move.
+	ui.reviewButton = widget.NewButtonWithIcon("", theme.HistoryIcon(), ui.showReview)
+	ui.reviewButton.Hide()
+	// And watched again move by move.
+	ui.watchButton = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), ui.showReplayViewer)
+	ui.watchButton.Hide()
 	// Score Labels are part of the top bar.
-	ui.playerScoreLabel = widget.NewLabel("Your Score: 0")
-	ui.playerScoreLabel.Alignment = fyne.TextAlignTrailing // Right-align for visual stability.
-	ui.cpuScoreLabel = widget.NewLabel("CPU Score: 0")
-	ui.cpuScoreLabel.Alignment = fyne.TextAlignTrailing // Right-align for visual stability.
-	scoreBox := container.New(layout.NewVBoxLayout(), ui.playerScoreLabel, ui.cpuScoreLabel)
+	playerScoreLabel := widget.NewLabelWithData(binding.IntToStringWithFormat(ui.view.playerScore, T("Your Score: %d")))
+	playerScoreLabel.Alignment = fyne.TextAlignTrailing // Right-align for visual stability.
+	cpuScoreLabel := widget.NewLabelWithData(binding.IntToStringWithFormat(ui.view.cpuScore, T("CPU Score: %d")))
+	cpuScoreLabel.Alignment = fyne.TextAlignTrailing // Right-align for visual stability.
+	scoreBox := container.New(layout.NewVBoxLayout(), playerScoreLabel, cpuScoreLabel)
+	ui.playerScoreLabel, ui.cpuScoreLabel = playerScoreLabel, cpuScoreLabel
 	// A Border layout is used here to get a thinner bar than HBox.
 	// Group the left-side buttons together.
-	leftButtons := container.New(layout.NewHBoxLayout(), sizedSelect, ui.startButton, ui.undoButton)
+	settingsButton := widget.NewButtonWithIcon("", theme.SettingsIcon(), ui.showSettings)
+	statsButton := widget.NewButtonWithIcon("", theme.ListIcon(), ui.showStats)
+	ui.muteButton = widget.NewButtonWithIcon("", theme.VolumeUpIcon(), ui.toggleMute)
+	leftButtons := container.New(layout.NewHBoxLayout(), sizedSelect, ui.startButton, ui.undoButton, ui.replayButton, ui.reviewButton, ui.watchButton, ui.shareButton, statsButton, settingsButton, ui.muteButton)
 	topBarContent := container.New(layout.NewBorderLayout(nil, nil, leftButtons, scoreBox), leftButtons, scoreBox)
+	if touchScreen() {
+		// A phone held upright is too narrow for both, so the scores go below the enlarged buttons.
+		playerScoreLabel.Alignment = fyne.TextAlignCenter
+		cpuScoreLabel.Alignment = fyne.TextAlignCenter
+		topBarContent = container.NewVBox(leftButtons, container.NewGridWithColumns(2, playerScoreLabel, cpuScoreLabel))
+	}
 	// Create a semi-transparent background for the top bar.
 	topBarBackground := canvas.NewRectangle(color.NRGBA{R: 0, G: 0, B: 0, A: 40}) // Barely visible black filter (~15% opacity).
 	topBar := container.NewStack(topBarBackground, topBarContent)
@@ -150,267 +397,481 @@ func (ui *AppUI) buildLayout() fyne.CanvasObject {
 	// Give it a nil tap handler so it's not interactive.
 	ui.tableCardWidget = newClickableImage(nil)
 	ui.tableCardWidget.FillMode = canvas.ImageFillStretch // Stretch to fill the defined size.
-	ui.infoLabel = widget.NewLabel("Welcome to Pishti! Select a level and start the game.")
+	ui.view.info.Set(T("Welcome to Pishti! Select a level and start the game."))
+	ui.infoLabel = widget.NewLabelWithData(ui.view.info)
 	ui.infoLabel.Alignment = fyne.TextAlignCenter
 	// To create the "peeking" card effect, use a container without a layout
 	// and manually position the card images. Place the images directly in the container,
 	// not inside other layout containers.
 	tableStack := container.NewWithoutLayout(ui.tablePileImage, ui.tableCardWidget)
-	tableStack.Resize(fyne.NewSize(76, 101)) // Card size (71x96) + 5px offset.
-	// Position and size the top card.
-	ui.tableCardWidget.Resize(fyne.NewSize(71, 96))
-	ui.tableCardWidget.Move(fyne.NewPos(0, 0))
-	// Position and size the peeking card underneath.
-	ui.tablePileImage.Resize(fyne.NewSize(71, 96))
-	ui.tablePileImage.Move(fyne.NewPos(5, 5)) // Offset by 5px down and right.
+	ui.tableStack = tableStack
+	// Wrap the tableStack in a container with a fixed minSize to prevent the outer
+	// layout from overriding the manual card positions.
+	tableSize := &minSizeLayout{}
+	sizedTableStack := container.New(tableSize, tableStack)
+	ui.scaler.add(fyne.NewSize(71, 96), func(card fyne.Size) {
+		offset := 5 * card.Width / 71 // The peeking card is offset by 5px at the base size.
+		tableStack.Resize(card.AddWidthHeight(offset, offset))
+		tableSize.min = tableStack.Size()
+		// Position and size the top card.
+		ui.tableCardWidget.SetMinSize(card)
+		ui.tableCardWidget.Resize(card)
+		ui.tableCardWidget.Move(fyne.NewPos(0, 0))
+		// Position and size the peeking card underneath, offset down and right.
+		ui.tablePileImage.Resize(card)
+		ui.tablePileImage.Move(fyne.NewPos(offset, offset))
+	})
+	ui.view.bindCard(ui.view.tableTop, showOnCard(ui.tableCardWidget))
+	ui.view.bindCard(ui.view.tableUnder, showOnImage(ui.tablePileImage))
 	// CPU Hand Area.
 	ui.cpuCardWidgets = make([]*clickableImage, 4)
 	cpuHandObjects := []fyne.CanvasObject{} // Use a slice to dynamically add cards and spacers.
-	for i := 0; i < HandSize; i++ {
+	for i := 0; i < engine.HandSize; i++ {
 		// Use the custom widget, which now correctly reports its minimum size.
 		// It's not clickable, so the onTapped handler is nil.
 		ui.cpuCardWidgets[i] = newClickableImage(nil)
 		ui.cpuCardWidgets[i].FillMode = canvas.ImageFillContain
+		ui.scaler.add(fyne.NewSize(71, 96), ui.cpuCardWidgets[i].SetMinSize)
+		ui.view.bindCard(ui.view.cpuHand[i], showOnCard(ui.cpuCardWidgets[i]))
 		cardContainer := container.New(layout.NewCenterLayout(), ui.cpuCardWidgets[i])
 		frameImage := canvas.NewImageFromResource(resourceFrame)
-		frameImage.SetMinSize(fyne.NewSize(91, 116))
+		ui.scaler.add(fyne.NewSize(91, 116), frameImage.SetMinSize)
+		ui.frameImages = append(ui.frameImages, frameImage)
 		cardSlot := container.NewStack(frameImage, cardContainer)
 		cpuHandObjects = append(cpuHandObjects, cardSlot)
 		// Add a spacer after each card, except the last one.
-		if i < HandSize-1 {
-			cpuHandObjects = append(cpuHandObjects, container.New(&minSizeLayout{min: fyne.NewSize(5, 0)}))
+		if i < engine.HandSize-1 {
+			cpuHandObjects = append(cpuHandObjects, ui.scaler.spacer(fyne.NewSize(5, 0)))
 		}
 	}
 	cpuHandContainer := container.New(layout.NewHBoxLayout(), cpuHandObjects...)
 	// The centerStack holds the vertically aligned game elements, without a background.
 	// Add struts to create vertical space around the elements.
-	topSpacer := container.New(&minSizeLayout{min: fyne.NewSize(0, 20)}, layout.NewSpacer())
-	cpuArea := container.NewVBox(topSpacer, container.New(layout.NewCenterLayout(), cpuHandContainer))
+	topSpacer := ui.scaler.spacer(fyne.NewSize(0, 20))
+	// During a match, its running score is shown under the CPU's hand.
+	matchLabel := widget.NewLabelWithData(ui.view.match)
+	matchLabel.Alignment = fyne.TextAlignCenter
+	matchLabel.Hide()
+	cpuArea := container.NewVBox(topSpacer, container.New(layout.NewCenterLayout(), cpuHandContainer), matchLabel)
+	ui.view.match.AddListener(binding.NewDataListener(func() {
+		if line, _ := ui.view.match.Get(); line == "" {
+			matchLabel.Hide()
+		} else {
+			matchLabel.Show()
+		}
+		cpuArea.Refresh() // Lays the label out again for the new text.
+	}))
 	// Use a BorderLayout to perfectly center the table pile between the CPU hand and the info label.
 	// A small spacer is added above the pile to push it down slightly for better visual balance.
 	// Create a 40px high spacer using a container with a custom minSizeLayout.
-	pileSpacer := container.New(&minSizeLayout{min: fyne.NewSize(0, 40)}, layout.NewSpacer())
-	// Wrap the tableStack in a container with a fixed minSize to prevent the outer
-	// layout from overriding the manual card positions.
-	sizedTableStack := container.New(&minSizeLayout{min: tableStack.Size()}, tableStack)
+	pileSpacer := ui.scaler.spacer(fyne.NewSize(0, 40))
 	centerPileGroup := container.NewVBox(pileSpacer, container.New(layout.NewCenterLayout(), sizedTableStack)) // The VBox places the spacer above the pile
 	// Use the NewBorder convenience function for a cleaner layout definition.
 	centerStack := container.NewBorder(
 		cpuArea, ui.infoLabel, nil, nil, // Top, Bottom, Left, Right.
 		container.New(layout.NewCenterLayout(), centerPileGroup)) // Center.
 	// Bottom Area (Player Hand).
-	ui.playerCardWidgets = make([]*clickableImage, HandSize)
+	ui.playerCardWidgets = make([]*clickableImage, engine.HandSize)
 	playerHandObjects := []fyne.CanvasObject{} // Use a slice to dynamically add cards and spacers.
-	for i := 0; i < HandSize; i++ {
+	for i := 0; i < engine.HandSize; i++ {
 		cardIndex := i
 		frameImage := canvas.NewImageFromResource(resourceFrame)
-		frameImage.SetMinSize(fyne.NewSize(91, 116))
+		ui.scaler.add(fyne.NewSize(91, 116), frameImage.SetMinSize)
+		ui.frameImages = append(ui.frameImages, frameImage)
 		ui.playerCardWidgets[i] = newClickableImage(func() {
-			// Only allow a play if:
-			// 1. The card slot is not empty.
-			// 2. No animation is in progress.
-			// 3. It is currently the player's turn.
-			if ui.casino.playerCards[cardIndex] != nil && !ui.isAnimating && ui.casino.gameState == StatePlayerTurn {
+			if ui.canPlayCard(cardIndex) {
 				ui.playerPlays(cardIndex)
 			}
 		})
+		ui.playerCardWidgets[i].SetCursorFunc(func() desktop.Cursor {
+			return ui.playerCardCursor(cardIndex)
+		})
+		ui.playerCardWidgets[i].SetOnArrow(func(step int) {
+			ui.focusPlayerCard(cardIndex, step)
+		})
 		ui.playerCardWidgets[i].FillMode = canvas.ImageFillContain
+		ui.scaler.add(fyne.NewSize(71, 96), ui.playerCardWidgets[i].SetMinSize)
+		ui.view.bindCard(ui.view.playerHand[i], showOnCard(ui.playerCardWidgets[i]))
 		// Use a CenterLayout to position the card widget in the middle of the frame.
-		cardSlot := container.NewStack(frameImage, container.NewCenter(ui.playerCardWidgets[i]))
+		badge := newOddsBadge()
+		ui.oddsBadges = append(ui.oddsBadges, badge)
+		cardSlot := container.NewStack(frameImage, container.NewCenter(ui.playerCardWidgets[i]), badge.content)
 		playerHandObjects = append(playerHandObjects, cardSlot)
 		// Add a spacer after each card, except the last one.
-		if i < HandSize-1 {
-			playerHandObjects = append(playerHandObjects, container.New(&minSizeLayout{min: fyne.NewSize(5, 0)}))
+		if i < engine.HandSize-1 {
+			playerHandObjects = append(playerHandObjects, ui.scaler.spacer(fyne.NewSize(5, 0)))
 		}
 	}
 	// The playerHand is a simple grid of card containers, without its own background.
-	playerHand := container.New(layout.NewHBoxLayout(), playerHandObjects...)
+	// The highlight sits behind the hand so the turn reminder can pulse it.
+	ui.handHighlight = newHandHighlight()
+	playerHand := container.NewStack(ui.handHighlight, container.New(layout.NewHBoxLayout(), playerHandObjects...))
 	// Create a single background image for the entire window.
-	backgroundImage := canvas.NewImageFromResource(resourceBackground)
+	ui.backgroundImage = canvas.NewImageFromResource(resourceBackground)
 	// Wrap the player hand in a CenterLayout to prevent it from being stretched by the BorderLayout.
 	// Also add a strut below it for vertical spacing.
-	bottomSpacer := container.New(&minSizeLayout{min: fyne.NewSize(0, 20)}, layout.NewSpacer())
-	// Group the info label with the player's hand and the bottom spacer.
-	bottomArea := container.NewVBox(playerHand, bottomSpacer)
+	bottomSpacer := ui.scaler.spacer(fyne.NewSize(0, 20))
+	// Group the capture ticker with the player's hand and the bottom spacer.
+	ui.ticker = newCaptureTicker()
+	bottomArea := container.NewVBox(ui.ticker.content, playerHand, bottomSpacer)
 	centeredPlayerHand := container.New(layout.NewCenterLayout(), bottomArea)
 	// The mainLayout organizes all interactive elements.
 	mainLayout := container.New(layout.NewBorderLayout(topBar, centeredPlayerHand, nil, nil),
 		topBar, centeredPlayerHand, centerStack)
-	return container.NewStack(backgroundImage, mainLayout)
+	// The game grows and shrinks with the window.
+	scaledContent := container.New(&scaledLayout{scaler: ui.scaler}, mainLayout)
+	ui.scaler.content = scaledContent
+	// The background reaches the screen's edges, but the game stays clear of a phone's notch and system bars.
+	safeArea := container.New(&safeAreaLayout{canvas: ui.window.Canvas()}, scaledContent)
+	// The particle layer sits between the static image and the game; it stays hidden when disabled.
+	ui.background = newAnimatedBackground()
+	// Degraded features are listed over the table, below the top bar.
+	banner := newProblemBanner(ui.scaler.spacer(fyne.NewSize(0, topBar.MinSize().Height)))
+	// Unlocked achievements are announced just below the top bar too.
+	ui.toast = newAchievementToast(ui.scaler.spacer(fyne.NewSize(0, topBar.MinSize().Height+8)))
+	// The debug console covers everything while it is open.
+	ui.debug = newDebugConsole(ui)
+	// Between the turns of a Pass & Play game, the table is covered.
+	ui.pass = newPassOverlay(ui.passedTo)
+	ui.replayBar = ui.newReplayBar()
+	ui.cards = newCardAnimator()
+	return container.NewStack(ui.backgroundImage, ui.background.layer, safeArea, ui.cards.layer, ui.replayBar, banner.overlay, ui.toast.overlay, ui.pass.overlay, ui.debug.overlay)
 }
 
-// playerPlays orchestrates the sequence of events for a player's turn.
+// canPlayCard reports whether the player may play the card in the given slot.
+func (ui *AppUI) canPlayCard(cardIndex int) bool {
+	// Only allow a play if:
+	// 1. The card slot is not empty.
+	// 2. No move is pending and no card is sliding.
+	// 3. It is currently the player's turn.
+	// 4. A strategy is not playing for the player.
+	// 5. In Pass & Play, the player has the device.
+	if ui.remote != nil {
+		return ui.canPlayRemote(cardIndex)
+	}
+	return ui.view.playerCard(cardIndex) != nil && !ui.loop.Busy() && !ui.isAnimating() && ui.view.currentState() == engine.StatePlayerTurn && ui.spectator == nil &&
+		(ui.hotSeat == nil || ui.hotSeat.shown == engine.Player)
+}
+
+// playerCardCursor picks the hover cursor for a player card slot: a pointing
+// hand when the card can be played, and a "blocked" cursor while waiting.
+func (ui *AppUI) playerCardCursor(cardIndex int) desktop.Cursor {
+	switch {
+	case ui.view.playerCard(cardIndex) == nil:
+		return desktop.DefaultCursor // Empty slots are not interactive.
+	case ui.canPlayCard(cardIndex):
+		return desktop.PointerCursor
+	default:
+		return cursorBlocked
+	}
+}
+
+// playerPlays plays the player's card; the engine loop then lets the CPU reply
+// and updates the screen after each of its steps.
 func (ui *AppUI) playerPlays(cardIndex int) {
 	// If a special message (like the initial pile capture) is being shown,
 	// clear it now that the player is taking a new action.
-	if ui.casino.initialPileCaptureMsg != "" {
-		ui.casino.initialPileCaptureMsg = ""
-		ui.infoLabel.SetText("")
-	}
-	// 1. Lock the UI to prevent further clicks.
-	ui.isAnimating = true
-	// 2. Player makes their move in the game logic.
-	ui.casino.playerPlays(cardIndex)
-	fyne.Do(ui.updateUI) // Update UI to show player's card on the table.
-	// 3. Wait briefly before the CPU makes its move.
-	time.AfterFunc(1000*time.Millisecond, func() {
-		ui.handleCPUTurn()
-	})
-}
-
-// handleCPUTurn orchestrates the CPU's move and the subsequent state check.
-func (ui *AppUI) handleCPUTurn() {
-	// 1. CPU makes its move.
-	ui.casino.cpuPlays()
-	fyne.Do(ui.updateUI) // Update UI to show CPU's card.
-	// 2. Decide what to do next based on the game state.
-	if ui.casino.isHandFinished() {
-		// The hand is over. Pause briefly, then process the end of the hand.
-		// The UI remains locked until this is complete.
-		time.AfterFunc(500*time.Millisecond, func() {
-			ui.casino.checkEndOfHand()
-			fyne.Do(ui.updateUI)
-			ui.isAnimating = false // Unlock UI after new hand is dealt or game ends.
-		})
-	} else if ui.casino.gameState == StatePileCaptured {
-		// A pile was captured. The updateUI function's timer will handle
-		// finalizing the capture and unlocking the UI.
-	} else {
-		// It's a normal turn. Unlock the UI for the player's next move.
-		ui.isAnimating = false
+	if ui.casino.InitialPileCaptureMessage() != "" {
+		ui.loop.ClearInitialPileCaptureMessage()
+		ui.view.info.Set("")
+	}
+	ui.cancelTurnReminder()
+	if ui.remote != nil {
+		ui.playRemote(cardIndex)
+		return
+	}
+	if err := ui.loop.PlayerPlays(cardIndex); err != nil {
+		log.Printf("ERROR: Player move rejected: %v", err)
+		return
 	}
+	ui.applyEvents() // Update UI to show player's card on the table.
 }
 
 // resetGameUI resets the game state and UI to the initial "welcome" screen.
 func (ui *AppUI) resetGameUI() {
-	ui.casino.ResetGame()
+	ui.stopSpectating()
+	ui.stopHotSeat()
+	ui.stopOnline()
+	ui.closeReplayViewer()
+	ui.daily = ""
+	ui.loop.Reset()
 	ui.levelSelect.Enable()
-	ui.levelSelect.ClearSelected()
-	ui.startButton.SetText("Start")
+	ui.selectDefaultLevel()
+	ui.startButton.SetText(T("Start"))
 	ui.gameOverSoundPlayed = false // Reset the flag for the next game.
-	ui.updateUI()
+	SwitchMusic(SoundMenuMusic)
+	ui.applyEvents()
 }
 
 // attemptToStartGame tries to start a new game, showing a warning if no level is selected.
 func (ui *AppUI) attemptToStartGame() {
-	if ui.casino.level == LevelNotSelected {
-		ui.infoLabel.SetText("Please select a level first!")
-		return
+	ui.startGameWith(ui.loop.StartGame)
+}
+
+// startGameWith starts a game through the given loop command, such as a
+// restart with a chosen seed. It reports false, after showing a warning, if
+// no level is selected.
+func (ui *AppUI) startGameWith(start func() bool) bool {
+	if ui.casino.Level() == engine.LevelNotSelected {
+		ui.view.info.Set(T("Please select a level first!"))
+		return false
 	}
 	PlaySound(SoundGameStart)
-	ui.casino.StartGame()
+	SwitchMusic(SoundBackground)
+	ui.daily = "" // startDailyDeal sets it again for its own game.
+	start()
+	ui.gameOverSoundPlayed = false
 	ui.levelSelect.Disable()
-	ui.startButton.SetText("New Game")
-	ui.infoLabel.SetText("") // Clear the "Select a level..." message.
-	ui.updateUI()
+	ui.startButton.SetText(T("New Game"))
+	ui.view.info.Set("") // Clear the "Select a level..." message.
+	ui.applyEvents()
+	return true
 }
 
-// updateHandUI is a helper to refresh the card widgets for a given hand.
-func (ui *AppUI) updateHandUI(hand []*Card, widgets []*clickableImage, showFaceUp bool) {
-	for i := 0; i < HandSize; i++ {
-		card := hand[i]
-		widget := widgets[i]
-		if card != nil {
-			if showFaceUp {
-				widget.Resource = getCardResource(card)
+// replaySameDeal restarts the finished game at the same level with the same seed,
+// so the player gets the identical cards and can try a different line.
+func (ui *AppUI) replaySameDeal() {
+	ui.gameOverSoundPlayed = false // Reset the flag for the replayed game.
+	PlaySound(SoundGameStart)
+	SwitchMusic(SoundBackground)
+	ui.loop.Replay()
+	ui.view.info.Set(T("Replaying the same deal."))
+	ui.applyEvents()
+}
+
+// updateUI redraws the whole screen from the game state, for the initial
+// display. Afterwards applyEvents updates only the parts that changed.
+func (ui *AppUI) updateUI() {
+	ui.view.refresh(allParts)
+	ui.updateScores()
+	ui.updateControls()
+}
+
+// applyEvents collects the engine's events, plays their sounds and updates
+// only the parts of the screen they affect.
+func (ui *AppUI) applyEvents() {
+	events := ui.casino.Events()
+	defer profiler.timeUpdate(time.Now(), events)
+	playEventSounds(events)
+	if len(events) > 0 {
+		lines := make([]string, len(events))
+		for i, e := range events {
+			lines[i] = e.String()
+		}
+		ui.debug.record(lines)
+	}
+	var parts viewParts
+	// The cards to slide are read off the screen before it is refreshed.
+	type play struct {
+		by   engine.PlayerID
+		slot int
+		card *engine.Card
+	}
+	var plays []play
+	var taken *engine.Card
+	takenBy := engine.NoPlayer
+	for _, e := range events {
+		switch e.Kind {
+		case engine.EventGameStarted, engine.EventGameReset, engine.EventUndone, engine.EventRedone:
+			parts = allParts
+			if e.Kind == engine.EventGameStarted {
+				ui.gameStarted = time.Now()
+				ui.unrated = ui.spectator != nil || ui.practice || ui.hotSeat != nil
+				ui.analysis = false
+			}
+		case engine.EventCardPlayed:
+			parts.table = true
+			hand := ui.view.playerHand
+			if e.By == engine.Player {
+				parts.playerHand = true
 			} else {
-				widget.Resource = resourceCardBack
+				parts.cpuHand = true
+				hand = ui.view.cpuHand
 			}
-		} else {
-			widget.Resource = nil // Make card layer transparent.
+			if e.Slot >= 0 && e.Slot < engine.HandSize {
+				shown, _ := hand[e.Slot].Get()
+				plays = append(plays, play{e.By, e.Slot, shown.card})
+			}
+		case engine.EventDeal:
+			parts.playerHand, parts.cpuHand = true, true
+		case engine.EventCapture, engine.EventPisti, engine.EventJackPisti, engine.EventScoreChanged:
+			parts.scores = true
+		case engine.EventPileCleared:
+			// After the capture's pause, in a later batch than the capture.
+			parts.table = true
+			shown, _ := ui.view.tableTop.Get()
+			taken, takenBy = shown.card, e.By
+		case engine.EventStateChanged:
+			parts.controls = true
 		}
-		// Always refresh to ensure the renderer processes the change.
-		widget.Refresh()
-		// Force an additional canvas refresh for the widget.
-		canvas.Refresh(widget)
+	}
+	ui.view.refresh(parts)
+	if parts != allParts {
+		for _, p := range plays {
+			ui.animatePlay(p.by, p.slot, p.card)
+		}
+		ui.animateCapture(takenBy, taken)
+	}
+	if parts.scores {
+		ui.updateScores()
+	}
+	if parts.controls {
+		ui.updateControls()
+	}
+	ui.overlay.update(ui.casino, events)
+	ui.hooks.run(ui, events)
+	if len(events) > 0 && !ui.unrated {
+		ui.showAchievements(ui.achievements.check(ui.casino, ui.stats)) // After the stats count a finished game.
 	}
 }
 
-func (ui *AppUI) updateUI() {
-	c := ui.casino
-	// Update scores.
-	ui.playerScoreLabel.SetText(fmt.Sprintf("Your Score: %d", c.playerPoint))
-	ui.cpuScoreLabel.SetText(fmt.Sprintf("CPU Score: %d", c.cpuPoint))
-	// Update hands.
-	ui.updateHandUI(c.cpuCards, ui.cpuCardWidgets, false)      // CPU hand is face-down.
-	ui.updateHandUI(c.playerCards, ui.playerCardWidgets, true) // Player hand is face-up.
-	// Update table image.
-	if c.cardsOnTable > 0 {
-		topCard := c.tableCards[c.cardsOnTable-1]
-		res := getCardResource(topCard)
-		ui.tableCardWidget.Resource = res
-		// If there is more than one card, show the card underneath the top one
-		// to indicate a pile.
-		if c.cardsOnTable > 1 {
-			// At the very start of the game, the cards under the top card are face down.
-			if ui.casino.isInitialPile {
-				ui.tablePileImage.Resource = resourceCardBack
-			} else {
-				secondCard := c.tableCards[c.cardsOnTable-2]
-				ui.tablePileImage.Resource = getCardResource(secondCard)
-			}
-		} else {
-			ui.tablePileImage.Resource = nil
-			ui.tablePileImage.Image = nil
+// updateScores refreshes the capture ticker and the announcer; the score
+// labels follow the view's bindings.
+func (ui *AppUI) updateScores() {
+	ui.ticker.update(ui.casino.RecentCaptures(tickerLines))
+	ui.announceEvents()
+}
+
+// updateControls updates the buttons and info text for the current game
+// state, and starts whatever the state calls for, such as the capture pause.
+func (ui *AppUI) updateControls() {
+	state := ui.view.currentState()
+	ui.undoButton.Disable() // Disabled by default.
+	if state == engine.StateGameOver {
+		ui.undoButton.Hide()
+		ui.replayButton.Show()
+		if ui.casino.Match().Target > 0 {
+			ui.replayButton.Hide() // Replaying a deal would start the match over.
 		}
+		ui.shareButton.Show()
+		ui.reviewButton.Show()
+		ui.watchButton.Show()
 	} else {
-		ui.tableCardWidget.Resource = nil
-		ui.tablePileImage.Resource = nil
-		ui.tablePileImage.Image = nil // Clear the underlying image data.
-	}
-	ui.tableCardWidget.Refresh()
-	ui.tablePileImage.Refresh()
-	canvas.Refresh(ui.tablePileImage)
-	// Update info label and button states.
-	ui.undoButton.Disable() // Disabled by default.
-	switch c.gameState {
-	case StateNotStarted:
-		ui.infoLabel.SetText("Select a level and press Start.")
-	case StateGameOver:
+		ui.replayButton.Hide()
+		ui.shareButton.Hide()
+		ui.reviewButton.Hide()
+		ui.watchButton.Hide()
+		ui.undoButton.Show()
+	}
+	switch state {
+	case engine.StateNotStarted:
+		ui.view.info.Set(T("Select a level and press Start."))
+	case engine.StateGameOver:
 		ui.startButton.Enable()
+		if ui.matchContinues() {
+			ui.startButton.SetText(T("Next Deal"))
+		}
 		// When the game is over, the user must click "New Game" to reset.
 		if !ui.gameOverSoundPlayed {
 			ui.handleGameOver()
 		}
 		ui.levelSelect.Disable()
-	case StatePlayerTurn, StateCPUTurn:
+	case engine.StatePlayerTurn, engine.StateCPUTurn:
 		// Don't clear the info label here automatically. This allows messages like the
 		// initial pile capture to persist until the player's next move clears it.
-		if c.level != LevelAdvanced && c.canUndo {
+		if canUndo, _ := ui.view.canUndo.Get(); canUndo && ui.spectator == nil && ui.hotSeat == nil { // Never true at levels without undo.
 			ui.undoButton.Enable()
 		}
-	case StatePileCaptured:
-		// This state is a brief pause to show the captured pile before it's cleared.
+	case engine.StatePileCaptured:
+		// This state is a brief pause to show the captured pile before the engine loop clears it.
 		// If the initial pile was captured, show the special message.
-		if c.initialPileCaptureMsg != "" {
-			ui.infoLabel.SetText(c.initialPileCaptureMsg)
+		if msg := ui.casino.InitialPileCaptureMessage(); msg != "" {
+			ui.view.info.Set(hiddenCardsMessage(ui.casino.Snapshot().Table, msg))
 		}
+	}
+	// Restart the idle reminder whenever the player is (still) expected to move.
+	ui.scheduleTurnReminder()
+	ui.updateTrayBadge()
+	ui.notifyTurn()
+	ui.updateOdds()
+	ui.updateHotSeat()
+}
 
-		time.AfterFunc(500*time.Millisecond, func() {
-			ui.casino.finalizeCapture()
-			// The finalizeCapture function now sets the correct next game state.
-			ui.isAnimating = false // Unlock the UI after the capture is complete.
-			fyne.Do(ui.updateUI)
-		})
+// announceEvents announces new Piştis and score milestones reached since the last update.
+// Undos and new games only rewind the counters, so nothing is announced twice.
+func (ui *AppUI) announceEvents() {
+	events, total := ui.casino.CapturesSince(ui.announcedCaptures)
+	ui.announcedCaptures = total
+	for _, e := range events {
+		if !e.Pisti {
+			continue
+		}
+		if e.Jack {
+			Announce(SoundAnnounceJackPisti)
+		} else {
+			Announce(SoundAnnouncePisti)
+		}
+	}
+	reached := 0
+	for _, m := range scoreMilestones {
+		if ui.casino.PlayerPoints() >= m.points {
+			reached++
+		}
+	}
+	if reached > ui.milestonesReached {
+		Announce(scoreMilestones[reached-1].effect)
+	}
+	ui.milestonesReached = reached
+}
+
+// skipAnnouncements counts everything so far as announced, for a game that
+// was loaded rather than watched.
+func (ui *AppUI) skipAnnouncements() {
+	_, ui.announcedCaptures = ui.casino.CapturesSince(0)
+	ui.milestonesReached = 0
+	for _, m := range scoreMilestones {
+		if ui.casino.PlayerPoints() >= m.points {
+			ui.milestonesReached++
+		}
 	}
 }
 
 // handleGameOver sets the final game message, plays the win/loss sound, and sets a flag to prevent repeats.
 func (ui *AppUI) handleGameOver() {
-	c := ui.casino
+	playerPoint, cpuPoint := ui.casino.PlayerPoints(), ui.casino.CPUPoints()
 	var gameOverMsg string
-	var soundToPlay SoundEffect
-	if c.playerPoint > c.cpuPoint {
-		gameOverMsg = fmt.Sprintf("You Win! Final Score: You %d - %d CPU", c.playerPoint, c.cpuPoint)
-		soundToPlay = SoundPlayerWins
-	} else if c.cpuPoint > c.playerPoint {
-		gameOverMsg = fmt.Sprintf("CPU Wins! Final Score: You %d - %d CPU", c.playerPoint, c.cpuPoint)
-		soundToPlay = SoundCPUWins
+	var soundToPlay, announcement SoundEffect
+	if playerPoint > cpuPoint {
+		gameOverMsg = Tf("You Win! Final Score: You %d - %d CPU", playerPoint, cpuPoint)
+		soundToPlay, announcement = SoundPlayerWins, SoundAnnouncePlayerWins
+	} else if cpuPoint > playerPoint {
+		gameOverMsg = Tf("CPU Wins! Final Score: You %d - %d CPU", playerPoint, cpuPoint)
+		soundToPlay, announcement = SoundCPUWins, SoundAnnounceCPUWins
 	} else { // Tie
-		gameOverMsg = fmt.Sprintf("It's a Tie! Final Score: You %d - %d CPU", c.playerPoint, c.cpuPoint)
-		soundToPlay = SoundTie
+		gameOverMsg = Tf("It's a Tie! Final Score: You %d - %d CPU", playerPoint, cpuPoint)
+		soundToPlay, announcement = SoundTie, SoundAnnounceTie
+	}
+	playerName, cpuName := T("You"), T("CPU")
+	if ui.hotSeat != nil {
+		gameOverMsg = hotSeatResult(playerPoint, cpuPoint)
+		playerName, cpuName = hotSeatName(engine.Player), hotSeatName(engine.CPU)
+	}
+	if m := ui.casino.Match(); m.Target > 0 {
+		gameOverMsg = matchResult(m, gameOverMsg, playerName, cpuName)
+		if m.Over {
+			// The match's result is the one to celebrate, whoever took the last deal.
+			soundToPlay, announcement = SoundCPUWins, SoundAnnounceCPUWins
+			if m.Winner == engine.Player {
+				soundToPlay, announcement = SoundPlayerWins, SoundAnnouncePlayerWins
+			}
+		}
+	}
+	if ui.daily != "" && !ui.analysis && ui.hotSeat == nil {
+		gameOverMsg += ui.dailyResult(playerPoint)
+	}
+	if ui.analysis {
+		gameOverMsg = Tf("Analysis: %s", gameOverMsg)
+	}
+	ui.gameDuration = 0 // Unknown if the start of the game was not seen.
+	if !ui.gameStarted.IsZero() {
+		ui.gameDuration = time.Since(ui.gameStarted)
 	}
 	PlaySound(soundToPlay)
-	ui.infoLabel.SetText(gameOverMsg)
+	Announce(announcement)
+	SwitchMusic(SoundMenuMusic)
+	ui.view.info.Set(gameOverMsg)
+	if !ui.unrated {
+		ui.stats.record(ui.casino)
+	}
 	ui.gameOverSoundPlayed = true // Set the flag to ensure this only runs once per game.
 }
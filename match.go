@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"pishti/engine"
+)
+
+// matchTargets are the points a match can be played to in settings; 0
+// plays single games.
+var matchTargets = []int{0, 51, 101, 151}
+
+// matchTargetLabel names a choice of matchTargets.
+func matchTargetLabel(points int) string {
+	if points == 0 {
+		return T("Single game")
+	}
+	return Tf("First to %d", points)
+}
+
+// matchLine is the running score of a match shown in the top bar, e.g.
+// "Deal 3 — You 84, CPU 97", or "" outside a match.
+func matchLine(m engine.MatchScore) string {
+	if m.Target == 0 {
+		return ""
+	}
+	return Tf("Deal %d — You %d, CPU %d", m.Deal, m.PlayerPoints, m.CPUPoints)
+}
+
+// matchContinues reports whether the finished game is a deal of a match
+// that is not won yet.
+func (ui *AppUI) matchContinues() bool {
+	m := ui.casino.Match()
+	return ui.casino.State() == engine.StateGameOver && m.Target > 0 && !m.Over
+}
+
+// matchResult adds the match to the message of a finished deal: the score
+// carried into the next deal, or the winner of the match.
+func matchResult(m engine.MatchScore, dealMsg, player, cpu string) string {
+	score := fmt.Sprintf("%s %d - %d %s", player, m.PlayerPoints, m.CPUPoints, cpu)
+	if !m.Over {
+		return dealMsg + "\n" + Tf("Match to %d after deal %d: %s", m.Target, m.Deal, score)
+	}
+	winner := player
+	if m.Winner == engine.CPU {
+		winner = cpu
+	}
+	return Tf("%s wins the match to %d! %s after deal %d", winner, m.Target, score, m.Deal)
+}
+
+// nextDeal deals the next game of the match.
+func (ui *AppUI) nextDeal() {
+	PlaySound(SoundGameStart)
+	SwitchMusic(SoundBackground)
+	ui.loop.NextDeal()
+	ui.daily = "" // The next deal is shuffled from another seed.
+	ui.gameOverSoundPlayed = false
+	ui.startButton.SetText(T("New Game"))
+	ui.view.info.Set("")
+	ui.applyEvents()
+}
@@ -0,0 +1,69 @@
+package online
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// Client is a seat at a room of a Server.
+type Client struct {
+	ws   *websocket.Conn
+	Room string // The room's code, to be passed on to the opponent.
+	Seat int    // 1 for the bottom seat, 2 for the top one.
+}
+
+// Dial connects to the server at the WebSocket URL, such as
+// ws://example.com:8080/play, and joins the room with the code, or opens a
+// new room if the code is empty.
+func Dial(serverURL, room string) (*Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("online: %s is not a ws:// or wss:// URL", serverURL)
+	}
+	origin := "http://" + u.Host + "/"
+	ws, err := websocket.Dial(serverURL, "", origin)
+	if err != nil {
+		return nil, err
+	}
+	if err := websocket.JSON.Send(ws, Message{Type: TypeJoin, Room: room}); err != nil {
+		ws.Close()
+		return nil, err
+	}
+	var m Message
+	if err := websocket.JSON.Receive(ws, &m); err != nil {
+		ws.Close()
+		return nil, err
+	}
+	if m.Type != TypeJoined {
+		ws.Close()
+		if m.Error != "" {
+			return nil, errors.New(m.Error)
+		}
+		return nil, fmt.Errorf("online: unexpected %q message", m.Type)
+	}
+	return &Client{ws: ws, Room: m.Room, Seat: m.Seat}, nil
+}
+
+// Play plays the card in the slot of the client's hand. The server answers
+// with an update, or an error message if the play is not allowed.
+func (c *Client) Play(slot int) error {
+	return websocket.JSON.Send(c.ws, Message{Type: TypePlay, Slot: slot})
+}
+
+// Receive waits for the next update or error message from the server.
+func (c *Client) Receive() (Message, error) {
+	var m Message
+	err := websocket.JSON.Receive(c.ws, &m)
+	return m, err
+}
+
+// Close leaves the room; the seat stays free for the player to take again.
+func (c *Client) Close() error {
+	return c.ws.Close()
+}
@@ -0,0 +1,21 @@
+// Package online plays Pişti between two people over WebSocket. A Server
+// holds the games in rooms named by a short code; the first to join a room
+// takes the bottom seat and deals once the second arrives. Each room's
+// engine Casino is the only copy of the game: clients send the slot they
+// play and get back the table as their seat sees it, so they never learn the
+// other hand or the deck order.
+//
+// Every message is a JSON object in its own WebSocket text frame, with its
+// kind in "type":
+//
+//	client: {"type": "join", "room": "K7QX"}  take a seat; an empty room opens a new one
+//	client: {"type": "play", "slot": 2}       play a card of your hand
+//	server: {"type": "joined", "room": "K7QX", "seat": 1}
+//	server: {"type": "update", "events": [...], "state": {...}}
+//	server: {"type": "error", "error": "it is not your turn"}
+//
+// An update follows every change to the room: the join of either seat, each
+// play, and a player leaving. Its events are those of the engine, told from
+// the receiver's side ("you" or "opponent"), and its state is a View. A
+// player who drops out can take their empty seat again with the room's code.
+package online
@@ -0,0 +1,138 @@
+package online
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pishti/engine"
+)
+
+// newTestServer serves a Server and returns its WebSocket URL.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+	s, err := NewServer(engine.Variants()[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// dial joins the room, closing the client at the end of the test.
+func dial(t *testing.T, url, room string) *Client {
+	t.Helper()
+	c, err := Dial(url, room)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// receive returns the next message, which must be of the given type.
+func receive(t *testing.T, c *Client, typ string) Message {
+	t.Helper()
+	m, err := c.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != typ {
+		t.Fatalf("got a %q message %+v, want %q", m.Type, m, typ)
+	}
+	return m
+}
+
+// firstCard returns the first slot of the hand holding a card.
+func firstCard(hand []*Card) int {
+	for i, c := range hand {
+		if c != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestOnlineGame(t *testing.T) {
+	url := newTestServer(t)
+	host := dial(t, url, "")
+	if host.Seat != 1 || len(host.Room) != roomCodeLength {
+		t.Fatalf("host got seat %d in room %q", host.Seat, host.Room)
+	}
+	if m := receive(t, host, TypeUpdate); !m.State.Waiting {
+		t.Error("the host is not waiting for an opponent")
+	}
+	guest := dial(t, url, strings.ToLower(host.Room))
+	if guest.Seat != 2 || guest.Room != host.Room {
+		t.Fatalf("guest got seat %d in room %q", guest.Seat, guest.Room)
+	}
+	if _, err := Dial(url, host.Room); err == nil || !strings.Contains(err.Error(), "full") {
+		t.Errorf("a third player joined: %v", err)
+	}
+	if _, err := Dial(url, "ZZZZ"); err == nil {
+		t.Error("joined a room that does not exist")
+	}
+	views := map[*Client]*View{
+		host:  receive(t, host, TypeUpdate).State,
+		guest: receive(t, guest, TypeUpdate).State,
+	}
+	if !views[host].YourTurn || views[guest].YourTurn || views[guest].Waiting {
+		t.Fatalf("host's turn %v, guest's turn %v", views[host].YourTurn, views[guest].YourTurn)
+	}
+	guest.Play(0)
+	if m := receive(t, guest, TypeError); !strings.Contains(m.Error, "not your turn") {
+		t.Errorf("out of turn play answered with %q", m.Error)
+	}
+	sawCapture := false
+	for moves := 0; !views[host].Over; moves++ {
+		if moves > engine.DeckSize {
+			t.Fatal("the game never ended")
+		}
+		mover, other := host, guest
+		if !views[host].YourTurn {
+			mover, other = other, mover
+		}
+		if err := mover.Play(firstCard(views[mover].Hand)); err != nil {
+			t.Fatal(err)
+		}
+		m := receive(t, mover, TypeUpdate)
+		views[mover], views[other] = m.State, receive(t, other, TypeUpdate).State
+		for _, e := range m.Events {
+			ev, ok := e.EngineEvent()
+			if !ok {
+				t.Fatalf("unknown event %+v", e)
+			}
+			if ev.Kind == engine.EventCardPlayed && ev.By != engine.Player {
+				t.Errorf("the mover's card was played by %s", ev.By)
+			}
+			sawCapture = sawCapture || ev.Kind == engine.EventCapture
+		}
+	}
+	if !sawCapture {
+		t.Error("no capture was reported")
+	}
+	h, g := views[host], views[guest]
+	if !g.Over || h.YourPoints != g.OpponentPoints || g.YourPoints != h.OpponentPoints || h.YourPoints+h.OpponentPoints == 0 {
+		t.Errorf("host sees %d - %d, guest sees %d - %d", h.YourPoints, h.OpponentPoints, g.YourPoints, g.OpponentPoints)
+	}
+	// The guest drops out; the host is told and the seat can be taken again.
+	guest.Close()
+	if m := receive(t, host, TypeUpdate); !m.State.Waiting {
+		t.Error("the host was not told the guest left")
+	}
+	back := dial(t, url, host.Room)
+	if back.Seat != 2 || !receive(t, back, TypeUpdate).State.Over {
+		t.Errorf("the guest came back in seat %d", back.Seat)
+	}
+}
+
+func TestCardRoundTrip(t *testing.T) {
+	c := NewCard(engine.NewCard(engine.Ten, engine.Diamonds, "23"))
+	if got := c.Engine(); got == nil || got.GetFace() != engine.Ten || got.GetSuit() != engine.Diamonds || got.GetIconPath() != "23" {
+		t.Errorf("%+v came back as %v", c, got)
+	}
+	if (&Card{Rank: "Eleven", Suit: "Hearts"}).Engine() != nil {
+		t.Error("an unknown rank made a card")
+	}
+}
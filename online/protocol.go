@@ -0,0 +1,104 @@
+package online
+
+import (
+	"strconv"
+
+	"pishti/engine"
+)
+
+// Message types.
+const (
+	TypeJoin   = "join"
+	TypePlay   = "play"
+	TypeJoined = "joined"
+	TypeUpdate = "update"
+	TypeError  = "error"
+)
+
+// Sides in events, seen from the receiver.
+const (
+	SideYou      = "you"
+	SideOpponent = "opponent"
+)
+
+// Message is everything sent either way; Type says which fields are used.
+type Message struct {
+	Type   string  `json:"type"`
+	Room   string  `json:"room,omitempty"`
+	Seat   int     `json:"seat,omitempty"` // 1 for the bottom seat, 2 for the top one.
+	Slot   int     `json:"slot"`
+	Events []Event `json:"events,omitempty"`
+	State  *View   `json:"state,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Event is an engine event as one seat sees it.
+type Event struct {
+	Kind string `json:"kind"`           // The engine's name for it, e.g. "card played".
+	By   string `json:"by,omitempty"`   // SideYou, SideOpponent, or empty.
+	Slot int    `json:"slot,omitempty"` // The hand slot of a card played.
+}
+
+// Card is a card face up.
+type Card struct {
+	Rank string `json:"rank"` // "Ace" to "King".
+	Suit string `json:"suit"` // "Hearts", "Diamonds", "Clubs" or "Spades".
+}
+
+// View is the table as one seat sees it.
+type View struct {
+	Room           string  `json:"room"`
+	Waiting        bool    `json:"waiting"` // The other seat is empty.
+	YourTurn       bool    `json:"your_turn"`
+	Over           bool    `json:"over"`
+	Hand           []*Card `json:"hand"`     // Played slots are null.
+	Opponent       []bool  `json:"opponent"` // Which slots of the other hand hold a card.
+	TableTop       *Card   `json:"table_top"`
+	TableUnder     *Card   `json:"table_under"` // The card under the top one, null while face down.
+	TableCards     int     `json:"table_cards"`
+	DeckLeft       int     `json:"deck_left"`
+	YourPoints     int     `json:"your_points"`
+	OpponentPoints int     `json:"opponent_points"`
+}
+
+// NewCard returns the wire form of a card, nil for none.
+func NewCard(c *engine.Card) *Card {
+	if c == nil {
+		return nil
+	}
+	return &Card{Rank: c.GetFace().String(), Suit: c.GetSuit().String()}
+}
+
+// Engine returns the engine's card, or nil if the card is none or unknown.
+func (c *Card) Engine() *engine.Card {
+	if c == nil {
+		return nil
+	}
+	for suit := engine.Hearts; suit <= engine.Spades; suit++ {
+		for rank := engine.Ace; rank <= engine.King; rank++ {
+			if rank.String() == c.Rank && suit.String() == c.Suit {
+				// The icon is named by the card's place in a fresh deck, from 1.
+				return engine.NewCard(rank, suit, strconv.Itoa(int(suit)*int(engine.King+1)+int(rank)+1))
+			}
+		}
+	}
+	return nil
+}
+
+// EngineEvent returns the engine event, with the receiver in the player's
+// seat. It reports false for a kind the engine does not know.
+func (e Event) EngineEvent() (engine.Event, bool) {
+	by := engine.NoPlayer
+	switch e.By {
+	case SideYou:
+		by = engine.Player
+	case SideOpponent:
+		by = engine.CPU
+	}
+	for k := engine.EventDeal; k <= engine.EventRedone; k++ {
+		if k.String() == e.Kind {
+			return engine.Event{Kind: k, By: by, Slot: e.Slot}, true
+		}
+	}
+	return engine.Event{}, false
+}
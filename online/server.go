@@ -0,0 +1,284 @@
+package online
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"pishti/engine"
+)
+
+// roomCodeLetters make up room codes; letters and digits that are easily
+// mixed up are left out.
+const roomCodeLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// roomCodeLength is the length of a room code.
+const roomCodeLength = 4
+
+// MaxRooms limits the rooms a Server keeps at once.
+const MaxRooms = 1000
+
+// Server hosts the rooms. Its handler speaks the protocol on every
+// WebSocket connection made to it.
+type Server struct {
+	variant string
+	mu      sync.Mutex // Guards rooms and codes.
+	rooms   map[string]*room
+	codes   *rand.Rand
+}
+
+// room is one game and the two seats at it.
+type room struct {
+	code   string
+	mu     sync.Mutex // Keeps a move and the updates it sends together.
+	c      *engine.Casino
+	seats  [2]*websocket.Conn // The bottom seat plays as engine.Player, the top one as engine.CPU.
+	closed bool               // Both seats were left and the room is going away.
+}
+
+// NewServer returns a server without rooms whose games use the named rule
+// variant.
+func NewServer(variant string) (*Server, error) {
+	if !engine.NewCasino(nil, nil).SetVariant(variant) {
+		return nil, fmt.Errorf("online: unknown rule variant %q", variant)
+	}
+	return &Server{
+		variant: variant,
+		rooms:   make(map[string]*room),
+		codes:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Handler returns the WebSocket handler. Any origin is accepted, as the
+// clients are programs rather than web pages.
+func (s *Server) Handler() http.Handler {
+	return websocket.Server{Handler: s.serve}
+}
+
+// serve seats the connection in the room it asks for and carries out its
+// plays until it closes.
+func (s *Server) serve(ws *websocket.Conn) {
+	defer ws.Close()
+	var join Message
+	if err := websocket.JSON.Receive(ws, &join); err != nil {
+		return
+	}
+	if join.Type != TypeJoin {
+		sendError(ws, "join a room first")
+		return
+	}
+	r, seat, err := s.join(strings.ToUpper(strings.TrimSpace(join.Room)), ws)
+	if err != nil {
+		sendError(ws, err.Error())
+		return
+	}
+	defer s.leave(r, seat)
+	for {
+		var m Message
+		if err := websocket.JSON.Receive(ws, &m); err != nil {
+			return
+		}
+		if m.Type != TypePlay {
+			sendError(ws, "unknown message type "+m.Type)
+			continue
+		}
+		if err := r.play(seat, m.Slot); err != nil {
+			sendError(ws, err.Error())
+		}
+	}
+}
+
+// join takes a seat in the room with the code, or in a new room if the code
+// is empty, and tells both seats.
+func (s *Server) join(code string, ws *websocket.Conn) (*room, int, error) {
+	s.mu.Lock()
+	r := s.rooms[code]
+	switch {
+	case code == "" && len(s.rooms) >= MaxRooms:
+		s.mu.Unlock()
+		return nil, 0, errors.New("the server is full; try again later")
+	case code == "":
+		r = s.newRoom()
+	case r == nil:
+		s.mu.Unlock()
+		return nil, 0, fmt.Errorf("there is no room %s", code)
+	}
+	s.mu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, 0, fmt.Errorf("there is no room %s", code)
+	}
+	seat := 0
+	for seat < len(r.seats) && r.seats[seat] != nil {
+		seat++
+	}
+	if seat == len(r.seats) {
+		return nil, 0, fmt.Errorf("room %s is full", r.code)
+	}
+	r.seats[seat] = ws
+	if err := websocket.JSON.Send(ws, Message{Type: TypeJoined, Room: r.code, Seat: seat + 1}); err != nil {
+		log.Printf("ERROR: Failed to seat a player in room %s: %v", r.code, err)
+	}
+	if r.c.State() == engine.StateNotStarted && r.seats[0] != nil && r.seats[1] != nil {
+		r.c.StartGame()
+	}
+	r.update()
+	return r, seat, nil
+}
+
+// newRoom opens a room under a code not in use. The caller must hold the
+// server's mutex.
+func (s *Server) newRoom() *room {
+	code := make([]byte, roomCodeLength)
+	for {
+		for i := range code {
+			code[i] = roomCodeLetters[s.codes.Intn(len(roomCodeLetters))]
+		}
+		if s.rooms[string(code)] == nil {
+			break
+		}
+	}
+	c := engine.NewCasino(nil, nil)
+	c.SetVariant(s.variant)
+	c.SetLevel(engine.LevelBeginner) // Only its rules matter; nobody plays for the CPU.
+	r := &room{code: string(code), c: c}
+	s.rooms[r.code] = r
+	return r
+}
+
+// leave frees the seat, and closes the room once both seats are empty.
+func (s *Server) leave(r *room, seat int) {
+	r.mu.Lock()
+	r.seats[seat] = nil
+	closed := r.seats[0] == nil && r.seats[1] == nil
+	r.closed = closed
+	if !closed {
+		r.update()
+	}
+	r.mu.Unlock()
+	if closed {
+		s.mu.Lock()
+		delete(s.rooms, r.code)
+		s.mu.Unlock()
+	}
+}
+
+// play plays the card in the slot of the seat's hand and tells both seats.
+func (r *room) play(seat, slot int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seats[0] == nil || r.seats[1] == nil {
+		return errors.New("wait for your opponent")
+	}
+	var err error
+	switch state := r.c.State(); {
+	case seat == 0 && state == engine.StatePlayerTurn:
+		err = r.c.PlayerPlays(slot)
+	case seat == 1 && state == engine.StateCPUTurn:
+		err = r.c.OpponentPlays(slot)
+	default:
+		return errors.New("it is not your turn")
+	}
+	if errors.Is(err, engine.ErrEmptySlot) {
+		return errors.New("there is no card in that slot")
+	}
+	if err != nil {
+		return err
+	}
+	// There are no pauses: a pile taken is cleared and the next hand dealt at
+	// once, and the clients show the events at their own pace.
+	if err := r.c.CheckEndOfHand(); err != nil {
+		log.Printf("ERROR: Game step failed in room %s: %v", r.code, err)
+	}
+	r.update()
+	return nil
+}
+
+// update sends each seat the events since the last update and the table as
+// it sees it. The caller must hold the room's mutex.
+func (r *room) update() {
+	events := r.c.Events()
+	for seat, ws := range r.seats {
+		if ws == nil {
+			continue
+		}
+		m := Message{Type: TypeUpdate, Events: r.events(seat, events), State: r.view(seat)}
+		if err := websocket.JSON.Send(ws, m); err != nil {
+			log.Printf("ERROR: Failed to update seat %d in room %s: %v", seat+1, r.code, err)
+		}
+	}
+}
+
+// seatPlayer is the engine's side for a seat.
+func seatPlayer(seat int) engine.PlayerID {
+	if seat == 0 {
+		return engine.Player
+	}
+	return engine.CPU
+}
+
+// events tells the engine's events from the seat's side.
+func (r *room) events(seat int, events []engine.Event) []Event {
+	var out []Event
+	for _, e := range events {
+		ev := Event{Kind: e.Kind.String(), Slot: e.Slot}
+		switch e.By {
+		case seatPlayer(seat):
+			ev.By = SideYou
+		case engine.NoPlayer:
+		default:
+			ev.By = SideOpponent
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+// view returns the table as the seat sees it.
+func (r *room) view(seat int) *View {
+	snap := r.c.Snapshot()
+	v := &View{
+		Room:       r.code,
+		Waiting:    r.seats[1-seat] == nil,
+		Over:       snap.State == engine.StateGameOver,
+		TableCards: len(snap.Table),
+		DeckLeft:   engine.DeckSize - snap.CardsDealt,
+	}
+	hand, other := snap.PlayerHand, snap.CPUHand
+	v.YourPoints, v.OpponentPoints = snap.PlayerPoints, snap.CPUPoints
+	turn := engine.StatePlayerTurn
+	if seat == 1 {
+		hand, other = other, hand
+		v.YourPoints, v.OpponentPoints = v.OpponentPoints, v.YourPoints
+		turn = engine.StateCPUTurn
+	}
+	v.YourTurn = snap.State == turn && !v.Waiting
+	for _, c := range hand {
+		v.Hand = append(v.Hand, NewCard(c))
+	}
+	for _, c := range other {
+		v.Opponent = append(v.Opponent, c != nil)
+	}
+	if n := len(snap.Table); n > 0 {
+		v.TableTop = NewCard(snap.Table[n-1])
+		if n > 1 && !r.c.IsInitialPile() {
+			v.TableUnder = NewCard(snap.Table[n-2])
+		}
+	}
+	return v
+}
+
+// sendError tells the connection what went wrong.
+func sendError(ws *websocket.Conn, msg string) {
+	if err := websocket.JSON.Send(ws, Message{Type: TypeError, Error: msg}); err != nil {
+		log.Printf("ERROR: Failed to send an error: %v", err)
+	}
+}
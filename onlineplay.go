@@ -0,0 +1,214 @@
+package main
+
+import (
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+	"pishti/online"
+)
+
+// defaultOnlineServer is offered in the Play Online dialog until another
+// server is used.
+const defaultOnlineServer = "ws://localhost:8080/play"
+
+// hiddenCard stands in for a card the server does not show; only its back
+// is drawn.
+var hiddenCard = engine.NewCard(engine.Ace, engine.Hearts, "")
+
+// remoteGame is a seat at a room of a pishti-server. The server keeps the
+// game; the window only shows the updates it sends.
+type remoteGame struct {
+	client     *online.Client
+	view       *online.View // The latest table, nil until the first update.
+	overPlayed bool         // The end of the game was announced.
+}
+
+// showOnline asks for the server and the room to join, then connects.
+func (ui *AppUI) showOnline() {
+	prefs := fyne.CurrentApp().Preferences()
+	serverEntry := widget.NewEntry()
+	serverEntry.SetText(prefs.StringWithFallback(prefOnlineServer, defaultOnlineServer))
+	roomEntry := widget.NewEntry()
+	roomEntry.SetPlaceHolder(T("New room"))
+	items := []*widget.FormItem{
+		widget.NewFormItem(T("Server"), serverEntry),
+		widget.NewFormItem(T("Room"), roomEntry),
+	}
+	items[1].HintText = T("Leave empty to open a room and pass its code on.")
+	d := dialog.NewForm(T("Play Online"), T("Connect"), T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		prefs.SetString(prefOnlineServer, serverEntry.Text)
+		ui.view.info.Set(T("Connecting..."))
+		server, room := serverEntry.Text, roomEntry.Text
+		// Connecting may take a while; the screen stays live.
+		go func() {
+			client, err := online.Dial(server, room)
+			fyne.Do(func() {
+				if err != nil {
+					log.Printf("ERROR: Failed to join an online game: %v", err)
+					ui.view.info.Set("")
+					dialog.ShowError(err, ui.window)
+					return
+				}
+				ui.startOnline(client)
+			})
+		}()
+	}, ui.window)
+	d.Resize(fyne.NewSize(400, d.MinSize().Height))
+	d.Show()
+}
+
+// startOnline leaves the current game for the room the client joined.
+func (ui *AppUI) startOnline(client *online.Client) {
+	ui.resetGameUI()
+	r := &remoteGame{client: client}
+	ui.remote = r
+	ui.levelSelect.Disable()
+	ui.startButton.SetText(T("Leave"))
+	ui.undoButton.Disable()
+	ui.cancelTurnReminder()
+	SwitchMusic(SoundBackground)
+	ui.view.info.Set(Tf("Joined room %s.", client.Room))
+	go func() {
+		for {
+			m, err := client.Receive()
+			if err != nil {
+				fyne.Do(func() {
+					if ui.remote == r {
+						log.Printf("ERROR: Lost the online game: %v", err)
+						ui.resetGameUI()
+						ui.view.info.Set(T("The connection to the server was lost."))
+					}
+				})
+				return
+			}
+			fyne.Do(func() {
+				if ui.remote == r {
+					ui.applyRemote(m)
+				}
+			})
+		}
+	}()
+}
+
+// stopOnline leaves the room, if any, and shows the local game again.
+func (ui *AppUI) stopOnline() {
+	if ui.remote == nil {
+		return
+	}
+	r := ui.remote
+	ui.remote = nil // The connection's goroutine ends quietly.
+	r.client.Close()
+	ui.view.refresh(allParts)
+	ui.updateControls()
+}
+
+// applyRemote shows a message from the server.
+func (ui *AppUI) applyRemote(m online.Message) {
+	r := ui.remote
+	if m.Type == online.TypeError {
+		ui.view.info.Set(m.Error)
+		return
+	}
+	if m.State == nil {
+		return
+	}
+	var events []engine.Event
+	for _, e := range m.Events {
+		if ev, ok := e.EngineEvent(); ok {
+			events = append(events, ev)
+		}
+	}
+	playEventSounds(events)
+	r.view = m.State
+	ui.view.showRemote(m.State)
+	v := m.State
+	switch {
+	case v.Over:
+		msg, sound := Tf("It's a Tie! You %d - %d Opponent", v.YourPoints, v.OpponentPoints), SoundTie
+		if v.YourPoints > v.OpponentPoints {
+			msg, sound = Tf("You Win! You %d - %d Opponent", v.YourPoints, v.OpponentPoints), SoundPlayerWins
+		} else if v.OpponentPoints > v.YourPoints {
+			msg, sound = Tf("You Lose. You %d - %d Opponent", v.YourPoints, v.OpponentPoints), SoundCPUWins
+		}
+		if !r.overPlayed {
+			r.overPlayed = true
+			PlaySound(sound)
+			SwitchMusic(SoundMenuMusic)
+		}
+		ui.view.info.Set(msg)
+	case v.Waiting:
+		ui.view.info.Set(Tf("Room %s: waiting for your opponent. Give them the code to join.", v.Room))
+	case v.YourTurn:
+		ui.view.info.Set(Tf("Room %s: your move.", v.Room))
+	default:
+		ui.view.info.Set(Tf("Room %s: your opponent's move.", v.Room))
+	}
+}
+
+// canPlayRemote reports whether the card in the slot may be played in the
+// online game.
+func (ui *AppUI) canPlayRemote(cardIndex int) bool {
+	v := ui.remote.view
+	return v != nil && v.YourTurn && !v.Over && cardIndex < len(v.Hand) && v.Hand[cardIndex] != nil
+}
+
+// playRemote sends the play to the server, which answers with an update.
+func (ui *AppUI) playRemote(cardIndex int) {
+	if err := ui.remote.client.Play(cardIndex); err != nil {
+		log.Printf("ERROR: Failed to send the move: %v", err)
+		return
+	}
+	ui.remote.view.YourTurn = false // Until the server's answer, no second card is played.
+}
+
+// showRemote copies the table of an online game into the bindings, with the
+// receiving seat at the bottom.
+func (v *gameView) showRemote(s *online.View) {
+	v.playerScore.Set(s.YourPoints)
+	v.cpuScore.Set(s.OpponentPoints)
+	v.match.Set("")
+	for i, slot := range v.playerHand {
+		var card *engine.Card
+		if i < len(s.Hand) {
+			card = s.Hand[i].Engine()
+		}
+		slot.Set(shownCard{card: card, faceUp: true})
+	}
+	for i, slot := range v.cpuHand {
+		var card *engine.Card
+		if i < len(s.Opponent) && s.Opponent[i] {
+			card = hiddenCard
+		}
+		slot.Set(shownCard{card: card})
+	}
+	var top, under shownCard
+	if s.TableTop != nil {
+		top = shownCard{card: s.TableTop.Engine(), faceUp: true}
+	}
+	if s.TableCards > 1 {
+		under = shownCard{card: hiddenCard}
+		if s.TableUnder != nil {
+			under = shownCard{card: s.TableUnder.Engine(), faceUp: true}
+		}
+	}
+	v.tableTop.Set(top)
+	v.tableUnder.Set(under)
+	state := engine.StateCPUTurn
+	switch {
+	case s.Over:
+		state = engine.StateGameOver
+	case s.Waiting:
+		state = engine.StateNotStarted
+	case s.YourTurn:
+		state = engine.StatePlayerTurn
+	}
+	v.state.Set(state)
+	v.canUndo.Set(false)
+}
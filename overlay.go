@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"pishti/engine"
+)
+
+// overlayState is the public state of the match written for stream overlays:
+// nothing the CPU would not show its opponent, nor the player's own cards.
+type overlayState struct {
+	State        string    `json:"state"`
+	Level        string    `json:"level"`
+	PlayerPoints int       `json:"player_points"`
+	CPUPoints    int       `json:"cpu_points"`
+	PlayerCards  int       `json:"player_cards"` // Cards in each hand.
+	CPUCards     int       `json:"cpu_cards"`
+	TableTop     *apiCard  `json:"table_top"` // Null while the table is empty.
+	TableCards   int       `json:"table_cards"`
+	DeckLeft     int       `json:"deck_left"`
+	LastEvent    string    `json:"last_event"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// overlayExport rewrites a JSON file with the overlayState after every
+// change, for streamers whose overlay (OBS, for example) polls the file. A nil
+// *overlayExport does nothing.
+type overlayExport struct {
+	path      string
+	lastEvent string
+	failed    bool // A write failed; it was logged once.
+}
+
+// newOverlayExport returns an export to path, or nil if path is empty.
+func newOverlayExport(path string) *overlayExport {
+	if path == "" {
+		return nil
+	}
+	return &overlayExport{path: path}
+}
+
+// update writes the state after the given events.
+func (o *overlayExport) update(c *engine.Casino, events []engine.Event) {
+	if o == nil || len(events) == 0 {
+		return
+	}
+	snap := c.Snapshot()
+	for i := len(events) - 1; i >= 0; i-- {
+		if text := describeOverlayEvent(snap, events[i]); text != "" {
+			o.lastEvent = text
+			break
+		}
+	}
+	st := overlayState{
+		State:        snap.State.String(),
+		Level:        levelName(snap.Level),
+		PlayerPoints: snap.PlayerPoints,
+		CPUPoints:    snap.CPUPoints,
+		TableCards:   len(snap.Table),
+		DeckLeft:     engine.DeckSize - snap.CardsDealt,
+		LastEvent:    o.lastEvent,
+		UpdatedAt:    time.Now(),
+	}
+	st.PlayerCards, st.CPUCards = heldCards(snap.PlayerHand), heldCards(snap.CPUHand)
+	if n := len(snap.Table); n > 0 {
+		st.TableTop = newAPICard(snap.Table[n-1])
+	}
+	if err := o.write(st); err != nil && !o.failed {
+		o.failed = true
+		log.Printf("ERROR: Failed to write the overlay file: %v", err)
+	}
+}
+
+// write replaces the file in one step, so an overlay never reads half of it.
+func (o *overlayExport) write(st overlayState) error {
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(o.path), ".pishti-overlay-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed.
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), o.path)
+}
+
+// describeOverlayEvent returns a line about the event for the overlay, or ""
+// for events viewers need not be told about.
+func describeOverlayEvent(snap engine.Snapshot, e engine.Event) string {
+	switch e.Kind {
+	case engine.EventCapture, engine.EventPisti, engine.EventJackPisti:
+		if n := len(snap.Captures); n > 0 {
+			return formatCaptureEvent(snap.Captures[n-1])
+		}
+	case engine.EventCardPlayed:
+		if n := len(snap.Moves); n > 0 {
+			m := snap.Moves[n-1]
+			if m.By == engine.Player {
+				return Tf("You played the %s", cardName(m.Card))
+			}
+			return Tf("CPU played the %s", cardName(m.Card))
+		}
+	case engine.EventDeal:
+		return T("New hand dealt")
+	case engine.EventGameStarted:
+		return T("New game")
+	case engine.EventUndone:
+		return T("Move taken back")
+	case engine.EventStateChanged:
+		if snap.State == engine.StateGameOver {
+			return Tf("Game over: You %d - %d CPU", snap.PlayerPoints, snap.CPUPoints)
+		}
+	}
+	return ""
+}
+
+// heldCards counts the cards left in a hand.
+func heldCards(hand []*engine.Card) int {
+	n := 0
+	for _, card := range hand {
+		if card != nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"runtime"
+
+	"fyne.io/fyne/v2"
+)
+
+// inBrowser reports whether the game runs as WebAssembly in a web page. The
+// page has no file system and cannot start processes, and browsers only let
+// it play sound once the player has tapped or pressed a key.
+func inBrowser() bool {
+	return runtime.GOOS == "js"
+}
+
+// touchScreen reports whether the game is played by touch, on a phone, a
+// tablet or a mobile browser, where controls need to be big enough for a
+// finger. It needs the app to have been created.
+func touchScreen() bool {
+	return fyne.CurrentDevice().IsMobile()
+}
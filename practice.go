@@ -0,0 +1,69 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+
+	"pishti/engine"
+)
+
+// oddsBadge is the practice overlay's label at the foot of a card slot.
+type oddsBadge struct {
+	text    *canvas.Text
+	content fyne.CanvasObject
+}
+
+// newOddsBadge creates a hidden badge, to be stacked over a card slot.
+func newOddsBadge() *oddsBadge {
+	b := &oddsBadge{text: canvas.NewText("", color.White)}
+	b.text.TextSize = 10
+	b.text.Alignment = fyne.TextAlignCenter
+	background := canvas.NewRectangle(color.NRGBA{A: 170})
+	background.CornerRadius = 4
+	label := container.NewStack(background, container.New(layout.NewCustomPaddedLayout(1, 1, 4, 4), b.text))
+	b.content = container.NewVBox(layout.NewSpacer(), container.NewCenter(label))
+	b.content.Hide()
+	return b
+}
+
+// describeOdds is the badge text for a card: whether it takes the pile, or
+// the chance the CPU takes it and how many of its face are still out.
+func describeOdds(o engine.CardOdds) string {
+	if o.Takes {
+		return T("Takes the pile")
+	}
+	return Tf("%.0f%% risk · %d left", 100*o.Risk, o.Unseen)
+}
+
+// setPractice turns the practice overlay on or off. Games played with it on,
+// even for a while, earn no achievements.
+func (ui *AppUI) setPractice(on bool) {
+	ui.practice = on
+	if on && ui.casino.State() != engine.StateNotStarted {
+		ui.unrated = true
+	}
+	ui.updateOdds()
+}
+
+// updateOdds shows the odds on the player's cards while practicing and it is
+// the player's move, and hides them otherwise.
+func (ui *AppUI) updateOdds() {
+	show := ui.practice && ui.spectator == nil && ui.hotSeat == nil && ui.casino.State() == engine.StatePlayerTurn
+	var odds []engine.CardOdds
+	if show {
+		odds = ui.casino.PlayerOdds()
+	}
+	for i, b := range ui.oddsBadges {
+		if !show || odds[i].Card == nil {
+			b.content.Hide()
+			continue
+		}
+		b.text.Text = describeOdds(odds[i])
+		b.content.Show()
+		b.content.Refresh() // Lays the label out again for the new text.
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Features that can degrade while the game carries on, each reported once.
+const (
+	problemAudio  = "audio"  // No sound at all.
+	problemSounds = "sounds" // Some sounds are silent.
+	problemSkin   = "skin"   // Some images show placeholders.
+)
+
+var (
+	problemsMutex sync.Mutex
+	problems      = make(map[string]string) // Message per feature.
+	problemOrder  []string                  // Features in the order they failed.
+	onProblem     func()                    // Called on the UI goroutine after a new problem.
+)
+
+// reportProblem records that a feature is degraded, replacing any earlier
+// message for it, and shows it in the banner. It may be called from any
+// goroutine, also before the UI exists.
+func reportProblem(feature, message string) {
+	problemsMutex.Lock()
+	if _, ok := problems[feature]; !ok {
+		problemOrder = append(problemOrder, feature)
+	}
+	changed := problems[feature] != message
+	problems[feature] = message
+	notify := onProblem
+	problemsMutex.Unlock()
+	if changed && notify != nil {
+		fyne.Do(notify)
+	}
+}
+
+// problemMessages returns the messages of every degraded feature.
+func problemMessages() []string {
+	problemsMutex.Lock()
+	defer problemsMutex.Unlock()
+	messages := make([]string, len(problemOrder))
+	for i, feature := range problemOrder {
+		messages[i] = problems[feature]
+	}
+	return messages
+}
+
+// problemBanner is a strip under the top bar listing what isn't working, so
+// a silent game or a placeholder card is explained rather than a mystery.
+type problemBanner struct {
+	overlay *fyne.Container
+	text    *widget.Label
+}
+
+// newProblemBanner builds the banner, placed below a gap as tall as the top
+// bar, and shows it whenever a problem is reported, including any reported
+// before it existed.
+func newProblemBanner(topBarGap fyne.CanvasObject) *problemBanner {
+	b := &problemBanner{text: widget.NewLabel("")}
+	b.text.Wrapping = fyne.TextWrapWord
+	shade := canvas.NewRectangle(color.NRGBA{R: 120, G: 60, B: 0, A: 220})
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), func() { b.overlay.Hide() })
+	strip := container.NewStack(shade, container.NewBorder(nil, nil,
+		widget.NewIcon(theme.WarningIcon()), closeButton, b.text))
+	b.overlay = container.NewVBox(topBarGap, strip) // The gap keeps the top bar usable.
+	b.overlay.Hide()
+	problemsMutex.Lock()
+	onProblem = b.refresh
+	problemsMutex.Unlock()
+	b.refresh()
+	return b
+}
+
+// refresh shows the current problems, or keeps the banner hidden if there are none.
+func (b *problemBanner) refresh() {
+	messages := problemMessages()
+	if len(messages) == 0 {
+		return
+	}
+	for i, msg := range messages {
+		messages[i] = T(msg) // Some are reported before the language is picked.
+	}
+	b.text.SetText(strings.Join(messages, "\n"))
+	b.overlay.Show()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"pishti/engine"
+)
+
+const (
+	timingSamples = 1000                  // Recent samples kept for each measurement.
+	slowUpdate    = 16 * time.Millisecond // An update longer than a frame at 60 Hz.
+	slowUpdates   = 20                    // Slow updates remembered with their events.
+)
+
+// profiler is set by --pprof; nil means profiling is off, and its methods do
+// nothing on a nil receiver so call sites need no checks.
+var profiler *uiProfiler
+
+// uiProfiler collects timings from the UI layer for the pprof endpoint.
+type uiProfiler struct {
+	mu      sync.Mutex
+	updates timingSeries // Time spent applying engine events to the screen.
+	frames  timingSeries // Time between animation frames.
+	slow    []string     // Latest slow updates and the events behind them.
+	frame   time.Time    // When the last frame ticked.
+}
+
+// timingSeries is a ring of recent durations with running totals.
+type timingSeries struct {
+	samples []time.Duration
+	next    int
+	count   int
+	max     time.Duration
+}
+
+// add records one duration.
+func (s *timingSeries) add(d time.Duration) {
+	if len(s.samples) < timingSamples {
+		s.samples = append(s.samples, d)
+	} else {
+		s.samples[s.next] = d
+		s.next = (s.next + 1) % timingSamples
+	}
+	s.count++
+	s.max = max(s.max, d)
+}
+
+// summary describes the recent samples, e.g. "512 samples, p50 1ms, ...".
+func (s *timingSeries) summary() string {
+	if len(s.samples) == 0 {
+		return "no samples"
+	}
+	sorted := append([]time.Duration(nil), s.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(q float64) time.Duration { return sorted[int(q*float64(len(sorted)-1))] }
+	return fmt.Sprintf("%d total, last %d: p50 %v, p95 %v, p99 %v; max ever %v",
+		s.count, len(sorted), at(0.5), at(0.95), at(0.99), s.max)
+}
+
+// startProfiling serves the Go pprof handlers and the UI timings on addr,
+// e.g. "localhost:6060", and starts collecting the timings.
+func startProfiling(addr string) {
+	p := &uiProfiler{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/timings", p.serveTimings)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("ERROR: Profiling endpoint stopped: %v", err)
+		}
+	}()
+	log.Printf("Profiling at http://%s/debug/pprof/ and http://%s/debug/timings", addr, addr)
+	profiler = p
+}
+
+// watchFrames measures the time between frames with an animation that runs
+// for as long as the app does. It must be called on the UI goroutine.
+func (p *uiProfiler) watchFrames() {
+	if p == nil {
+		return
+	}
+	frames := fyne.NewAnimation(time.Second, func(float32) {
+		now := time.Now()
+		p.mu.Lock()
+		if !p.frame.IsZero() {
+			p.frames.add(now.Sub(p.frame))
+		}
+		p.frame = now
+		p.mu.Unlock()
+	})
+	frames.RepeatCount = fyne.AnimationRepeatForever
+	frames.Start()
+}
+
+// timeUpdate records an update of the screen that began at start, noting
+// the events it applied when it took longer than a frame. Deferred at the
+// top of applyEvents.
+func (p *uiProfiler) timeUpdate(start time.Time, events []engine.Event) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates.add(d)
+	if d < slowUpdate {
+		return
+	}
+	kinds := make([]string, len(events))
+	for i, e := range events {
+		kinds[i] = e.String()
+	}
+	p.slow = append(p.slow, fmt.Sprintf("%s  %v  %s", start.Format("15:04:05.000"), d, strings.Join(kinds, ", ")))
+	if extra := len(p.slow) - slowUpdates; extra > 0 {
+		p.slow = p.slow[extra:]
+	}
+}
+
+// serveTimings writes the collected timings as plain text.
+func (p *uiProfiler) serveTimings(w http.ResponseWriter, _ *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "Updates: %s\n", p.updates.summary())
+	fmt.Fprintf(w, "Frames:  %s\n\n", p.frames.summary())
+	fmt.Fprintf(w, "Updates slower than %v, latest last:\n", slowUpdate)
+	for _, line := range p.slow {
+		fmt.Fprintln(w, line)
+	}
+}
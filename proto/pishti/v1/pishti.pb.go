@@ -0,0 +1,1104 @@
+// The Pişti engine as a gRPC service, for bots, analytics and other clients
+// that want typed messages and a stream of game events. It mirrors the HTTP
+// API of server.go: a game is played from the player's seat against the
+// built-in CPU levels, and the CPU's cards stay hidden.
+//
+// The game serves it with --grpc, in grpcserver.go. After changing this
+// file, generate the stubs again with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	    --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	    proto/pishti/v1/pishti.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.3
+// source: proto/pishti/v1/pishti.proto
+
+package pishtiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Side of the table.
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_PLAYER      Side = 1
+	Side_SIDE_CPU         Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_PLAYER",
+		2: "SIDE_CPU",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_PLAYER":      1,
+		"SIDE_CPU":         2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_pishti_v1_pishti_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_proto_pishti_v1_pishti_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{0}
+}
+
+type Card struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          string                 `protobuf:"bytes,1,opt,name=rank,proto3" json:"rank,omitempty"` // "Ace", "Deuce", ... "King".
+	Suit          string                 `protobuf:"bytes,2,opt,name=suit,proto3" json:"suit,omitempty"` // "Hearts", "Diamonds", "Clubs" or "Spades".
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Card) Reset() {
+	*x = Card{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Card) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Card) ProtoMessage() {}
+
+func (x *Card) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Card.ProtoReflect.Descriptor instead.
+func (*Card) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Card) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+func (x *Card) GetSuit() string {
+	if x != nil {
+		return x.Suit
+	}
+	return ""
+}
+
+type GameRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameRef) Reset() {
+	*x = GameRef{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRef) ProtoMessage() {}
+
+func (x *GameRef) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRef.ProtoReflect.Descriptor instead.
+func (*GameRef) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GameRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`      // A CPU level, e.g. "Beginner". Required.
+	Seed          *int64                 `protobuf:"varint,2,opt,name=seed,proto3,oneof" json:"seed,omitempty"` // Deal from this seed; random if unset.
+	Variant       string                 `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`  // Rule variant; the server's default if empty.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGameRequest) Reset() {
+	*x = CreateGameRequest{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameRequest) ProtoMessage() {}
+
+func (x *CreateGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameRequest.ProtoReflect.Descriptor instead.
+func (*CreateGameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateGameRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *CreateGameRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+type PlayCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Slot          int32                  `protobuf:"varint,2,opt,name=slot,proto3" json:"slot,omitempty"` // Hand slot, 0 to 3.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayCardRequest) Reset() {
+	*x = PlayCardRequest{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayCardRequest) ProtoMessage() {}
+
+func (x *PlayCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayCardRequest.ProtoReflect.Descriptor instead.
+func (*PlayCardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlayCardRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PlayCardRequest) GetSlot() int32 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+type DeleteGameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGameResponse) Reset() {
+	*x = DeleteGameResponse{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGameResponse) ProtoMessage() {}
+
+func (x *DeleteGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGameResponse.ProtoReflect.Descriptor instead.
+func (*DeleteGameResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{4}
+}
+
+type GameState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State          string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // "player's turn", "CPU's turn", "pile captured" or "game over".
+	Level          string                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`
+	Variant        string                 `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	Seed           int64                  `protobuf:"varint,5,opt,name=seed,proto3" json:"seed,omitempty"`
+	PlayerPoints   int32                  `protobuf:"varint,6,opt,name=player_points,json=playerPoints,proto3" json:"player_points,omitempty"`
+	CpuPoints      int32                  `protobuf:"varint,7,opt,name=cpu_points,json=cpuPoints,proto3" json:"cpu_points,omitempty"`
+	PlayerCaptured int32                  `protobuf:"varint,8,opt,name=player_captured,json=playerCaptured,proto3" json:"player_captured,omitempty"`
+	CpuCaptured    int32                  `protobuf:"varint,9,opt,name=cpu_captured,json=cpuCaptured,proto3" json:"cpu_captured,omitempty"`
+	DeckLeft       int32                  `protobuf:"varint,10,opt,name=deck_left,json=deckLeft,proto3" json:"deck_left,omitempty"`
+	Hand           []*HandSlot            `protobuf:"bytes,11,rep,name=hand,proto3" json:"hand,omitempty"`
+	CpuCards       int32                  `protobuf:"varint,12,opt,name=cpu_cards,json=cpuCards,proto3" json:"cpu_cards,omitempty"`
+	TableTop       *Card                  `protobuf:"bytes,13,opt,name=table_top,json=tableTop,proto3" json:"table_top,omitempty"` // Unset when the table is empty.
+	TableCards     int32                  `protobuf:"varint,14,opt,name=table_cards,json=tableCards,proto3" json:"table_cards,omitempty"`
+	FaceDown       int32                  `protobuf:"varint,15,opt,name=face_down,json=faceDown,proto3" json:"face_down,omitempty"` // Starting cards still hidden under the top card.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GameState) Reset() {
+	*x = GameState{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameState) ProtoMessage() {}
+
+func (x *GameState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameState.ProtoReflect.Descriptor instead.
+func (*GameState) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GameState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GameState) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GameState) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *GameState) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *GameState) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *GameState) GetPlayerPoints() int32 {
+	if x != nil {
+		return x.PlayerPoints
+	}
+	return 0
+}
+
+func (x *GameState) GetCpuPoints() int32 {
+	if x != nil {
+		return x.CpuPoints
+	}
+	return 0
+}
+
+func (x *GameState) GetPlayerCaptured() int32 {
+	if x != nil {
+		return x.PlayerCaptured
+	}
+	return 0
+}
+
+func (x *GameState) GetCpuCaptured() int32 {
+	if x != nil {
+		return x.CpuCaptured
+	}
+	return 0
+}
+
+func (x *GameState) GetDeckLeft() int32 {
+	if x != nil {
+		return x.DeckLeft
+	}
+	return 0
+}
+
+func (x *GameState) GetHand() []*HandSlot {
+	if x != nil {
+		return x.Hand
+	}
+	return nil
+}
+
+func (x *GameState) GetCpuCards() int32 {
+	if x != nil {
+		return x.CpuCards
+	}
+	return 0
+}
+
+func (x *GameState) GetTableTop() *Card {
+	if x != nil {
+		return x.TableTop
+	}
+	return nil
+}
+
+func (x *GameState) GetTableCards() int32 {
+	if x != nil {
+		return x.TableCards
+	}
+	return 0
+}
+
+func (x *GameState) GetFaceDown() int32 {
+	if x != nil {
+		return x.FaceDown
+	}
+	return 0
+}
+
+// A slot of the player's hand; card is unset once it has been played.
+type HandSlot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Card          *Card                  `protobuf:"bytes,1,opt,name=card,proto3" json:"card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandSlot) Reset() {
+	*x = HandSlot{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandSlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandSlot) ProtoMessage() {}
+
+func (x *HandSlot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandSlot.ProtoReflect.Descriptor instead.
+func (*HandSlot) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HandSlot) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+type Move struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	By            Side                   `protobuf:"varint,1,opt,name=by,proto3,enum=pishti.v1.Side" json:"by,omitempty"`
+	Card          *Card                  `protobuf:"bytes,2,opt,name=card,proto3" json:"card,omitempty"`
+	Slot          int32                  `protobuf:"varint,3,opt,name=slot,proto3" json:"slot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Move) GetBy() Side {
+	if x != nil {
+		return x.By
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Move) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *Move) GetSlot() int32 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+type Capture struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	By            Side                   `protobuf:"varint,1,opt,name=by,proto3,enum=pishti.v1.Side" json:"by,omitempty"`
+	Cards         int32                  `protobuf:"varint,2,opt,name=cards,proto3" json:"cards,omitempty"`
+	Points        int32                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	Pisti         bool                   `protobuf:"varint,4,opt,name=pisti,proto3" json:"pisti,omitempty"`
+	Jack          bool                   `protobuf:"varint,5,opt,name=jack,proto3" json:"jack,omitempty"`
+	Final         bool                   `protobuf:"varint,6,opt,name=final,proto3" json:"final,omitempty"` // The last pile, awarded at the end of the game.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Capture) Reset() {
+	*x = Capture{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capture) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capture) ProtoMessage() {}
+
+func (x *Capture) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capture.ProtoReflect.Descriptor instead.
+func (*Capture) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Capture) GetBy() Side {
+	if x != nil {
+		return x.By
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Capture) GetCards() int32 {
+	if x != nil {
+		return x.Cards
+	}
+	return 0
+}
+
+func (x *Capture) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *Capture) GetPisti() bool {
+	if x != nil {
+		return x.Pisti
+	}
+	return false
+}
+
+func (x *Capture) GetJack() bool {
+	if x != nil {
+		return x.Jack
+	}
+	return false
+}
+
+func (x *Capture) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+type History struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Moves         []*Move                `protobuf:"bytes,1,rep,name=moves,proto3" json:"moves,omitempty"`
+	Captures      []*Capture             `protobuf:"bytes,2,rep,name=captures,proto3" json:"captures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *History) Reset() {
+	*x = History{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *History) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*History) ProtoMessage() {}
+
+func (x *History) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use History.ProtoReflect.Descriptor instead.
+func (*History) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *History) GetMoves() []*Move {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+func (x *History) GetCaptures() []*Capture {
+	if x != nil {
+		return x.Captures
+	}
+	return nil
+}
+
+// One thing that happened in a game, with the state it left.
+type GameEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*GameEvent_CardPlayed
+	//	*GameEvent_Capture
+	//	*GameEvent_Deal
+	//	*GameEvent_GameOver
+	Event         isGameEvent_Event `protobuf_oneof:"event"`
+	State         *GameState        `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameEvent) Reset() {
+	*x = GameEvent{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameEvent) ProtoMessage() {}
+
+func (x *GameEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameEvent.ProtoReflect.Descriptor instead.
+func (*GameEvent) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GameEvent) GetEvent() isGameEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *GameEvent) GetCardPlayed() *Move {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_CardPlayed); ok {
+			return x.CardPlayed
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetCapture() *Capture {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_Capture); ok {
+			return x.Capture
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetDeal() *DealEvent {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_Deal); ok {
+			return x.Deal
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetGameOver() *GameOverEvent {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_GameOver); ok {
+			return x.GameOver
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetState() *GameState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type isGameEvent_Event interface {
+	isGameEvent_Event()
+}
+
+type GameEvent_CardPlayed struct {
+	CardPlayed *Move `protobuf:"bytes,1,opt,name=card_played,json=cardPlayed,proto3,oneof"`
+}
+
+type GameEvent_Capture struct {
+	Capture *Capture `protobuf:"bytes,2,opt,name=capture,proto3,oneof"`
+}
+
+type GameEvent_Deal struct {
+	Deal *DealEvent `protobuf:"bytes,3,opt,name=deal,proto3,oneof"`
+}
+
+type GameEvent_GameOver struct {
+	GameOver *GameOverEvent `protobuf:"bytes,4,opt,name=game_over,json=gameOver,proto3,oneof"`
+}
+
+func (*GameEvent_CardPlayed) isGameEvent_Event() {}
+
+func (*GameEvent_Capture) isGameEvent_Event() {}
+
+func (*GameEvent_Deal) isGameEvent_Event() {}
+
+func (*GameEvent_GameOver) isGameEvent_Event() {}
+
+type DealEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DealEvent) Reset() {
+	*x = DealEvent{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DealEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DealEvent) ProtoMessage() {}
+
+func (x *DealEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DealEvent.ProtoReflect.Descriptor instead.
+func (*DealEvent) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{11}
+}
+
+type GameOverEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Winner        Side                   `protobuf:"varint,1,opt,name=winner,proto3,enum=pishti.v1.Side" json:"winner,omitempty"` // Unset for a tie.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameOverEvent) Reset() {
+	*x = GameOverEvent{}
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameOverEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameOverEvent) ProtoMessage() {}
+
+func (x *GameOverEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pishti_v1_pishti_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameOverEvent.ProtoReflect.Descriptor instead.
+func (*GameOverEvent) Descriptor() ([]byte, []int) {
+	return file_proto_pishti_v1_pishti_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GameOverEvent) GetWinner() Side {
+	if x != nil {
+		return x.Winner
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+var File_proto_pishti_v1_pishti_proto protoreflect.FileDescriptor
+
+const file_proto_pishti_v1_pishti_proto_rawDesc = "" +
+	"\n" +
+	"\x1cproto/pishti/v1/pishti.proto\x12\tpishti.v1\".\n" +
+	"\x04Card\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\tR\x04rank\x12\x12\n" +
+	"\x04suit\x18\x02 \x01(\tR\x04suit\"\x19\n" +
+	"\aGameRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"e\n" +
+	"\x11CreateGameRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x12\x17\n" +
+	"\x04seed\x18\x02 \x01(\x03H\x00R\x04seed\x88\x01\x01\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariantB\a\n" +
+	"\x05_seed\"5\n" +
+	"\x0fPlayCardRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04slot\x18\x02 \x01(\x05R\x04slot\"\x14\n" +
+	"\x12DeleteGameResponse\"\xd4\x03\n" +
+	"\tGameState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\tR\x05level\x12\x18\n" +
+	"\avariant\x18\x04 \x01(\tR\avariant\x12\x12\n" +
+	"\x04seed\x18\x05 \x01(\x03R\x04seed\x12#\n" +
+	"\rplayer_points\x18\x06 \x01(\x05R\fplayerPoints\x12\x1d\n" +
+	"\n" +
+	"cpu_points\x18\a \x01(\x05R\tcpuPoints\x12'\n" +
+	"\x0fplayer_captured\x18\b \x01(\x05R\x0eplayerCaptured\x12!\n" +
+	"\fcpu_captured\x18\t \x01(\x05R\vcpuCaptured\x12\x1b\n" +
+	"\tdeck_left\x18\n" +
+	" \x01(\x05R\bdeckLeft\x12'\n" +
+	"\x04hand\x18\v \x03(\v2\x13.pishti.v1.HandSlotR\x04hand\x12\x1b\n" +
+	"\tcpu_cards\x18\f \x01(\x05R\bcpuCards\x12,\n" +
+	"\ttable_top\x18\r \x01(\v2\x0f.pishti.v1.CardR\btableTop\x12\x1f\n" +
+	"\vtable_cards\x18\x0e \x01(\x05R\n" +
+	"tableCards\x12\x1b\n" +
+	"\tface_down\x18\x0f \x01(\x05R\bfaceDown\"/\n" +
+	"\bHandSlot\x12#\n" +
+	"\x04card\x18\x01 \x01(\v2\x0f.pishti.v1.CardR\x04card\"`\n" +
+	"\x04Move\x12\x1f\n" +
+	"\x02by\x18\x01 \x01(\x0e2\x0f.pishti.v1.SideR\x02by\x12#\n" +
+	"\x04card\x18\x02 \x01(\v2\x0f.pishti.v1.CardR\x04card\x12\x12\n" +
+	"\x04slot\x18\x03 \x01(\x05R\x04slot\"\x98\x01\n" +
+	"\aCapture\x12\x1f\n" +
+	"\x02by\x18\x01 \x01(\x0e2\x0f.pishti.v1.SideR\x02by\x12\x14\n" +
+	"\x05cards\x18\x02 \x01(\x05R\x05cards\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x05R\x06points\x12\x14\n" +
+	"\x05pisti\x18\x04 \x01(\bR\x05pisti\x12\x12\n" +
+	"\x04jack\x18\x05 \x01(\bR\x04jack\x12\x14\n" +
+	"\x05final\x18\x06 \x01(\bR\x05final\"`\n" +
+	"\aHistory\x12%\n" +
+	"\x05moves\x18\x01 \x03(\v2\x0f.pishti.v1.MoveR\x05moves\x12.\n" +
+	"\bcaptures\x18\x02 \x03(\v2\x12.pishti.v1.CaptureR\bcaptures\"\x89\x02\n" +
+	"\tGameEvent\x122\n" +
+	"\vcard_played\x18\x01 \x01(\v2\x0f.pishti.v1.MoveH\x00R\n" +
+	"cardPlayed\x12.\n" +
+	"\acapture\x18\x02 \x01(\v2\x12.pishti.v1.CaptureH\x00R\acapture\x12*\n" +
+	"\x04deal\x18\x03 \x01(\v2\x14.pishti.v1.DealEventH\x00R\x04deal\x127\n" +
+	"\tgame_over\x18\x04 \x01(\v2\x18.pishti.v1.GameOverEventH\x00R\bgameOver\x12*\n" +
+	"\x05state\x18\x05 \x01(\v2\x14.pishti.v1.GameStateR\x05stateB\a\n" +
+	"\x05event\"\v\n" +
+	"\tDealEvent\"8\n" +
+	"\rGameOverEvent\x12'\n" +
+	"\x06winner\x18\x01 \x01(\x0e2\x0f.pishti.v1.SideR\x06winner*;\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSIDE_PLAYER\x10\x01\x12\f\n" +
+	"\bSIDE_CPU\x10\x022\xee\x02\n" +
+	"\x06Engine\x12@\n" +
+	"\n" +
+	"CreateGame\x12\x1c.pishti.v1.CreateGameRequest\x1a\x14.pishti.v1.GameState\x124\n" +
+	"\bGetState\x12\x12.pishti.v1.GameRef\x1a\x14.pishti.v1.GameState\x12<\n" +
+	"\bPlayCard\x12\x1a.pishti.v1.PlayCardRequest\x1a\x14.pishti.v1.GameState\x124\n" +
+	"\n" +
+	"GetHistory\x12\x12.pishti.v1.GameRef\x1a\x12.pishti.v1.History\x12?\n" +
+	"\n" +
+	"DeleteGame\x12\x12.pishti.v1.GameRef\x1a\x1d.pishti.v1.DeleteGameResponse\x127\n" +
+	"\tWatchGame\x12\x12.pishti.v1.GameRef\x1a\x14.pishti.v1.GameEvent0\x01B!Z\x1fpishti/proto/pishti/v1;pishtiv1b\x06proto3"
+
+var (
+	file_proto_pishti_v1_pishti_proto_rawDescOnce sync.Once
+	file_proto_pishti_v1_pishti_proto_rawDescData []byte
+)
+
+func file_proto_pishti_v1_pishti_proto_rawDescGZIP() []byte {
+	file_proto_pishti_v1_pishti_proto_rawDescOnce.Do(func() {
+		file_proto_pishti_v1_pishti_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_pishti_v1_pishti_proto_rawDesc), len(file_proto_pishti_v1_pishti_proto_rawDesc)))
+	})
+	return file_proto_pishti_v1_pishti_proto_rawDescData
+}
+
+var file_proto_pishti_v1_pishti_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_pishti_v1_pishti_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_pishti_v1_pishti_proto_goTypes = []any{
+	(Side)(0),                  // 0: pishti.v1.Side
+	(*Card)(nil),               // 1: pishti.v1.Card
+	(*GameRef)(nil),            // 2: pishti.v1.GameRef
+	(*CreateGameRequest)(nil),  // 3: pishti.v1.CreateGameRequest
+	(*PlayCardRequest)(nil),    // 4: pishti.v1.PlayCardRequest
+	(*DeleteGameResponse)(nil), // 5: pishti.v1.DeleteGameResponse
+	(*GameState)(nil),          // 6: pishti.v1.GameState
+	(*HandSlot)(nil),           // 7: pishti.v1.HandSlot
+	(*Move)(nil),               // 8: pishti.v1.Move
+	(*Capture)(nil),            // 9: pishti.v1.Capture
+	(*History)(nil),            // 10: pishti.v1.History
+	(*GameEvent)(nil),          // 11: pishti.v1.GameEvent
+	(*DealEvent)(nil),          // 12: pishti.v1.DealEvent
+	(*GameOverEvent)(nil),      // 13: pishti.v1.GameOverEvent
+}
+var file_proto_pishti_v1_pishti_proto_depIdxs = []int32{
+	7,  // 0: pishti.v1.GameState.hand:type_name -> pishti.v1.HandSlot
+	1,  // 1: pishti.v1.GameState.table_top:type_name -> pishti.v1.Card
+	1,  // 2: pishti.v1.HandSlot.card:type_name -> pishti.v1.Card
+	0,  // 3: pishti.v1.Move.by:type_name -> pishti.v1.Side
+	1,  // 4: pishti.v1.Move.card:type_name -> pishti.v1.Card
+	0,  // 5: pishti.v1.Capture.by:type_name -> pishti.v1.Side
+	8,  // 6: pishti.v1.History.moves:type_name -> pishti.v1.Move
+	9,  // 7: pishti.v1.History.captures:type_name -> pishti.v1.Capture
+	8,  // 8: pishti.v1.GameEvent.card_played:type_name -> pishti.v1.Move
+	9,  // 9: pishti.v1.GameEvent.capture:type_name -> pishti.v1.Capture
+	12, // 10: pishti.v1.GameEvent.deal:type_name -> pishti.v1.DealEvent
+	13, // 11: pishti.v1.GameEvent.game_over:type_name -> pishti.v1.GameOverEvent
+	6,  // 12: pishti.v1.GameEvent.state:type_name -> pishti.v1.GameState
+	0,  // 13: pishti.v1.GameOverEvent.winner:type_name -> pishti.v1.Side
+	3,  // 14: pishti.v1.Engine.CreateGame:input_type -> pishti.v1.CreateGameRequest
+	2,  // 15: pishti.v1.Engine.GetState:input_type -> pishti.v1.GameRef
+	4,  // 16: pishti.v1.Engine.PlayCard:input_type -> pishti.v1.PlayCardRequest
+	2,  // 17: pishti.v1.Engine.GetHistory:input_type -> pishti.v1.GameRef
+	2,  // 18: pishti.v1.Engine.DeleteGame:input_type -> pishti.v1.GameRef
+	2,  // 19: pishti.v1.Engine.WatchGame:input_type -> pishti.v1.GameRef
+	6,  // 20: pishti.v1.Engine.CreateGame:output_type -> pishti.v1.GameState
+	6,  // 21: pishti.v1.Engine.GetState:output_type -> pishti.v1.GameState
+	6,  // 22: pishti.v1.Engine.PlayCard:output_type -> pishti.v1.GameState
+	10, // 23: pishti.v1.Engine.GetHistory:output_type -> pishti.v1.History
+	5,  // 24: pishti.v1.Engine.DeleteGame:output_type -> pishti.v1.DeleteGameResponse
+	11, // 25: pishti.v1.Engine.WatchGame:output_type -> pishti.v1.GameEvent
+	20, // [20:26] is the sub-list for method output_type
+	14, // [14:20] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_proto_pishti_v1_pishti_proto_init() }
+func file_proto_pishti_v1_pishti_proto_init() {
+	if File_proto_pishti_v1_pishti_proto != nil {
+		return
+	}
+	file_proto_pishti_v1_pishti_proto_msgTypes[2].OneofWrappers = []any{}
+	file_proto_pishti_v1_pishti_proto_msgTypes[10].OneofWrappers = []any{
+		(*GameEvent_CardPlayed)(nil),
+		(*GameEvent_Capture)(nil),
+		(*GameEvent_Deal)(nil),
+		(*GameEvent_GameOver)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_pishti_v1_pishti_proto_rawDesc), len(file_proto_pishti_v1_pishti_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_pishti_v1_pishti_proto_goTypes,
+		DependencyIndexes: file_proto_pishti_v1_pishti_proto_depIdxs,
+		EnumInfos:         file_proto_pishti_v1_pishti_proto_enumTypes,
+		MessageInfos:      file_proto_pishti_v1_pishti_proto_msgTypes,
+	}.Build()
+	File_proto_pishti_v1_pishti_proto = out.File
+	file_proto_pishti_v1_pishti_proto_goTypes = nil
+	file_proto_pishti_v1_pishti_proto_depIdxs = nil
+}
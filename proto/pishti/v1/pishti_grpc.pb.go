@@ -0,0 +1,343 @@
+// The Pişti engine as a gRPC service, for bots, analytics and other clients
+// that want typed messages and a stream of game events. It mirrors the HTTP
+// API of server.go: a game is played from the player's seat against the
+// built-in CPU levels, and the CPU's cards stay hidden.
+//
+// The game serves it with --grpc, in grpcserver.go. After changing this
+// file, generate the stubs again with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	    --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	    proto/pishti/v1/pishti.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: proto/pishti/v1/pishti.proto
+
+package pishtiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Engine_CreateGame_FullMethodName = "/pishti.v1.Engine/CreateGame"
+	Engine_GetState_FullMethodName   = "/pishti.v1.Engine/GetState"
+	Engine_PlayCard_FullMethodName   = "/pishti.v1.Engine/PlayCard"
+	Engine_GetHistory_FullMethodName = "/pishti.v1.Engine/GetHistory"
+	Engine_DeleteGame_FullMethodName = "/pishti.v1.Engine/DeleteGame"
+	Engine_WatchGame_FullMethodName  = "/pishti.v1.Engine/WatchGame"
+)
+
+// EngineClient is the client API for Engine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EngineClient interface {
+	// CreateGame deals a new game.
+	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*GameState, error)
+	// GetState returns a game as the player sees it.
+	GetState(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*GameState, error)
+	// PlayCard plays a card of the player's hand and returns once the CPU
+	// has replied.
+	PlayCard(ctx context.Context, in *PlayCardRequest, opts ...grpc.CallOption) (*GameState, error)
+	// GetHistory returns the cards played and the captures made so far.
+	GetHistory(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*History, error)
+	// DeleteGame forgets a game.
+	DeleteGame(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*DeleteGameResponse, error)
+	// WatchGame streams the game's events as they happen, starting with the
+	// current state, until the game is over or deleted.
+	WatchGame(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error)
+}
+
+type engineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEngineClient(cc grpc.ClientConnInterface) EngineClient {
+	return &engineClient{cc}
+}
+
+func (c *engineClient) CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, Engine_CreateGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) GetState(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, Engine_GetState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) PlayCard(ctx context.Context, in *PlayCardRequest, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, Engine_PlayCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) GetHistory(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*History, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(History)
+	err := c.cc.Invoke(ctx, Engine_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) DeleteGame(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (*DeleteGameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteGameResponse)
+	err := c.cc.Invoke(ctx, Engine_DeleteGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineClient) WatchGame(ctx context.Context, in *GameRef, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Engine_ServiceDesc.Streams[0], Engine_WatchGame_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GameRef, GameEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Engine_WatchGameClient = grpc.ServerStreamingClient[GameEvent]
+
+// EngineServer is the server API for Engine service.
+// All implementations must embed UnimplementedEngineServer
+// for forward compatibility.
+type EngineServer interface {
+	// CreateGame deals a new game.
+	CreateGame(context.Context, *CreateGameRequest) (*GameState, error)
+	// GetState returns a game as the player sees it.
+	GetState(context.Context, *GameRef) (*GameState, error)
+	// PlayCard plays a card of the player's hand and returns once the CPU
+	// has replied.
+	PlayCard(context.Context, *PlayCardRequest) (*GameState, error)
+	// GetHistory returns the cards played and the captures made so far.
+	GetHistory(context.Context, *GameRef) (*History, error)
+	// DeleteGame forgets a game.
+	DeleteGame(context.Context, *GameRef) (*DeleteGameResponse, error)
+	// WatchGame streams the game's events as they happen, starting with the
+	// current state, until the game is over or deleted.
+	WatchGame(*GameRef, grpc.ServerStreamingServer[GameEvent]) error
+	mustEmbedUnimplementedEngineServer()
+}
+
+// UnimplementedEngineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEngineServer struct{}
+
+func (UnimplementedEngineServer) CreateGame(context.Context, *CreateGameRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedEngineServer) GetState(context.Context, *GameRef) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (UnimplementedEngineServer) PlayCard(context.Context, *PlayCardRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlayCard not implemented")
+}
+func (UnimplementedEngineServer) GetHistory(context.Context, *GameRef) (*History, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedEngineServer) DeleteGame(context.Context, *GameRef) (*DeleteGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGame not implemented")
+}
+func (UnimplementedEngineServer) WatchGame(*GameRef, grpc.ServerStreamingServer[GameEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGame not implemented")
+}
+func (UnimplementedEngineServer) mustEmbedUnimplementedEngineServer() {}
+func (UnimplementedEngineServer) testEmbeddedByValue()                {}
+
+// UnsafeEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EngineServer will
+// result in compilation errors.
+type UnsafeEngineServer interface {
+	mustEmbedUnimplementedEngineServer()
+}
+
+func RegisterEngineServer(s grpc.ServiceRegistrar, srv EngineServer) {
+	// If the following call pancis, it indicates UnimplementedEngineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Engine_ServiceDesc, srv)
+}
+
+func _Engine_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Engine_CreateGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).CreateGame(ctx, req.(*CreateGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Engine_GetState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).GetState(ctx, req.(*GameRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_PlayCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).PlayCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Engine_PlayCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).PlayCard(ctx, req.(*PlayCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Engine_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).GetHistory(ctx, req.(*GameRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_DeleteGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServer).DeleteGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Engine_DeleteGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServer).DeleteGame(ctx, req.(*GameRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Engine_WatchGame_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GameRef)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EngineServer).WatchGame(m, &grpc.GenericServerStream[GameRef, GameEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Engine_WatchGameServer = grpc.ServerStreamingServer[GameEvent]
+
+// Engine_ServiceDesc is the grpc.ServiceDesc for Engine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Engine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pishti.v1.Engine",
+	HandlerType: (*EngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGame",
+			Handler:    _Engine_CreateGame_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _Engine_GetState_Handler,
+		},
+		{
+			MethodName: "PlayCard",
+			Handler:    _Engine_PlayCard_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _Engine_GetHistory_Handler,
+		},
+		{
+			MethodName: "DeleteGame",
+			Handler:    _Engine_DeleteGame_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGame",
+			Handler:       _Engine_WatchGame_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/pishti/v1/pishti.proto",
+}
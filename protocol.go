@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"pishti/engine"
+)
+
+// engineCommand is the subcommand that speaks the engine protocol on
+// standard input and output: pishti engine. PROTOCOL.md describes it.
+const engineCommand = "engine"
+
+// protocolVersion is the version of the engine protocol. It changes only
+// when a command or reply changes in a way old clients would misread.
+const protocolVersion = 1
+
+// rankCodes and suitCodes are the letters of the protocol's card notation,
+// e.g. "TD" for the Ten of Diamonds, indexed by Rank and Suit.
+var (
+	rankCodes = [...]string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "T", "J", "Q", "K"}
+	suitCodes = [...]string{"H", "D", "C", "S"}
+)
+
+// engineSession is one client's connection to the engine protocol. The
+// client plays the player's seat; the engine plays the CPU's.
+type engineSession struct {
+	casino   *engine.Casino
+	out      io.Writer
+	moves    int // Moves already reported.
+	captures int // Captures already reported.
+	hints    *rand.Rand
+}
+
+// runEngineProtocol serves the engine protocol until quit or the end of the
+// input, returning any error reading it.
+func runEngineProtocol(in io.Reader, out io.Writer) error {
+	s := &engineSession{casino: engine.NewCasino(nil, nil), out: out, hints: rand.New(rand.NewSource(1))}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" {
+			return nil
+		}
+		if err := s.command(fields[0], fields[1:]); err != nil {
+			s.reply("error", err.Error())
+		}
+	}
+	return scanner.Err()
+}
+
+// command carries out one command.
+func (s *engineSession) command(name string, args []string) error {
+	c := s.casino
+	switch name {
+	case "pishti":
+		s.reply("id", "name", "Pishti")
+		s.reply("id", "protocol", strconv.Itoa(protocolVersion))
+		s.reply(append([]string{"option", "level"}, strategyNames()...)...)
+		for _, v := range engine.Variants() {
+			s.reply("option", "variant", v.Name)
+		}
+		s.reply("pishtiok")
+	case "isready":
+		s.reply("readyok")
+	case "newgame":
+		return s.newGame(args)
+	case "state":
+		if c.State() == engine.StateNotStarted {
+			return fmt.Errorf("no game; send newgame")
+		}
+		s.state()
+	case "play":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: play <slot>")
+		}
+		slot, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("bad slot %q", args[0])
+		}
+		if c.State() != engine.StatePlayerTurn {
+			return fmt.Errorf("not your turn")
+		}
+		if err := c.PlayerPlays(slot); err != nil {
+			return err
+		}
+		s.report()
+		advanceGame(c, s.report)
+		s.state()
+	case "bestmove":
+		return s.bestMove(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return nil
+}
+
+// newGame handles newgame [level <name>] [seed <n>] [variant <name>], where
+// the variant's name runs to the end of the line.
+func (s *engineSession) newGame(args []string) error {
+	c := s.casino
+	strategy, variant := engine.Strategies()[0], appConfig.Rules.Variant
+	var seed int64
+	seedSet := false
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return fmt.Errorf("%s needs a value", args[i])
+		}
+		switch args[i] {
+		case "level":
+			st, ok := strategyNamed(args[i+1])
+			if !ok {
+				return fmt.Errorf("unknown level %q", args[i+1])
+			}
+			strategy = st
+		case "seed":
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad seed %q", args[i+1])
+			}
+			seed, seedSet = n, true
+		case "variant":
+			variant = strings.Join(args[i+1:], " ")
+			i = len(args)
+		default:
+			return fmt.Errorf("unknown newgame option %q", args[i])
+		}
+	}
+	if !c.SetVariant(variant) {
+		return fmt.Errorf("unknown variant %q", variant)
+	}
+	c.ResetGame() // Starting again clears the level, so clear first.
+	c.SetLevel(strategy.Level)
+	if seedSet {
+		c.StartGameWithSeed(seed)
+	} else {
+		c.StartGame()
+	}
+	c.Events()
+	s.moves, s.captures = 0, 0
+	s.hints = rand.New(rand.NewSource(c.Seed()))
+	s.reply("game", "seed", strconv.FormatInt(c.Seed(), 10), "level", strategy.Name)
+	advanceGame(c, s.report)
+	s.state()
+	return nil
+}
+
+// bestMove handles bestmove [level]: the card the named strategy, or the
+// game's own level, would play from the player's seat.
+func (s *engineSession) bestMove(args []string) error {
+	c := s.casino
+	if c.State() != engine.StatePlayerTurn {
+		return fmt.Errorf("not your turn")
+	}
+	strategy, ok := strategyNamed(levelName(c.Level()))
+	if len(args) > 0 {
+		strategy, ok = strategyNamed(args[0])
+	}
+	if !ok {
+		return fmt.Errorf("unknown level %q", strings.Join(args, " "))
+	}
+	hand := c.PlayerHand()
+	slot := c.PlayerChoice(strategy, s.hints)
+	if slot < 0 || hand[slot] == nil {
+		slot = -1
+		for i, card := range hand {
+			if card != nil {
+				slot = i
+				break
+			}
+		}
+	}
+	s.reply("bestmove", strconv.Itoa(slot), cardCode(hand[slot]))
+	return nil
+}
+
+// report sends the moves and captures made since the last report.
+func (s *engineSession) report() {
+	c := s.casino
+	for _, e := range c.Events() {
+		if e.Kind == engine.EventDeal {
+			s.reply("deal")
+		}
+	}
+	moves := c.Snapshot().Moves
+	for _, m := range moves[min(s.moves, len(moves)):] {
+		s.reply("move", seatName(m.By), strconv.Itoa(m.Slot), cardCode(m.Card))
+	}
+	s.moves = len(moves)
+	captures, total := c.CapturesSince(s.captures)
+	for _, e := range captures {
+		line := []string{"capture", seatName(e.By), strconv.Itoa(e.Cards), strconv.Itoa(e.Points)}
+		switch {
+		case e.Jack:
+			line = append(line, "jackpisti")
+		case e.Pisti:
+			line = append(line, "pisti")
+		case e.Final:
+			line = append(line, "final")
+		}
+		s.reply(line...)
+	}
+	s.captures = total
+}
+
+// state sends the state line.
+func (s *engineSession) state() {
+	c := s.casino
+	snap := c.Snapshot()
+	turn := "over"
+	switch snap.State {
+	case engine.StatePlayerTurn:
+		turn = "player"
+	case engine.StateCPUTurn, engine.StatePileCaptured:
+		turn = "cpu"
+	}
+	line := []string{"state", turn,
+		"score", strconv.Itoa(snap.PlayerPoints), strconv.Itoa(snap.CPUPoints),
+		"deck", strconv.Itoa(engine.DeckSize - snap.CardsDealt),
+		"table", strconv.Itoa(len(snap.Table)), "top"}
+	hidden := 0
+	if n := len(snap.Table); n > 0 {
+		line = append(line, cardCode(snap.Table[n-1]))
+		if c.IsInitialPile() {
+			hidden = n - 1
+		}
+	} else {
+		line = append(line, "--")
+	}
+	line = append(line, "hidden", strconv.Itoa(hidden), "hand")
+	for _, card := range snap.PlayerHand {
+		line = append(line, cardCode(card))
+	}
+	line = append(line, "cpu", strconv.Itoa(heldCards(snap.CPUHand)))
+	s.reply(line...)
+}
+
+// reply writes a line of space-separated words.
+func (s *engineSession) reply(words ...string) {
+	fmt.Fprintln(s.out, strings.Join(words, " "))
+}
+
+// cardCode returns the protocol's two-letter name of a card, "--" for none.
+func cardCode(c *engine.Card) string {
+	if c == nil {
+		return "--"
+	}
+	return rankCodes[c.GetFace()] + suitCodes[c.GetSuit()]
+}
+
+// seatName returns the protocol's name of a side.
+func seatName(by engine.PlayerID) string {
+	if by == engine.Player {
+		return "player"
+	}
+	return "cpu"
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"pishti/engine"
+)
+
+// lastLine returns the last line written to out.
+func lastLine(out *strings.Builder) string {
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	return lines[len(lines)-1]
+}
+
+func TestEngineProtocolPlaysAGame(t *testing.T) {
+	appConfig = defaultConfig()
+	var out strings.Builder
+	if err := runEngineProtocol(strings.NewReader("pishti\nbogus\nquit\nisready\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "id name Pishti\nid protocol 1\n") ||
+		!strings.Contains(got, "pishtiok\nerror ") || strings.Contains(got, "readyok") {
+		t.Fatalf("handshake:\n%s", got)
+	}
+	out.Reset()
+	s := &engineSession{casino: engine.NewCasino(nil, nil), out: &out}
+	if err := s.command("newgame", strings.Fields("level Intermediate seed 5 variant Plain Pişti")); err != nil {
+		t.Fatal(err)
+	}
+	if s.casino.Variant() != "Plain Pişti" || !strings.HasPrefix(out.String(), "game seed 5 level Intermediate\n") {
+		t.Fatalf("newgame:\n%s", out.String())
+	}
+	for moves := 0; ; moves++ {
+		state := strings.Fields(lastLine(&out))
+		if state[0] != "state" || moves > 30 {
+			t.Fatalf("no state after move %d:\n%s", moves, out.String())
+		}
+		if state[1] == "over" {
+			break
+		}
+		if err := s.command("bestmove", nil); err != nil {
+			t.Fatal(err)
+		}
+		best := strings.Fields(lastLine(&out))
+		if err := s.command("play", best[1:2]); err != nil {
+			t.Fatalf("play %s: %v", best[1], err)
+		}
+	}
+	if got := strings.Count(out.String(), "\nmove "); got != 48 {
+		t.Errorf("%d moves reported, want 48", got)
+	}
+	if err := s.command("play", []string{"0"}); err == nil {
+		t.Error("played after the game was over")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+
+	"pishti/engine"
+)
+
+// turnReminderDelay is how long the player can be idle on their turn before
+// the hand area pulses and a chime plays; zero disables the reminder. It comes
+// from the config file.
+var turnReminderDelay = 10 * time.Second
+
+var (
+	reminderHighlightColor = color.NRGBA{R: 255, G: 215, B: 120, A: 70} // Soft gold glow.
+	reminderIdleColor      = color.NRGBA{R: 255, G: 215, B: 120, A: 0}  // Same hue, fully transparent.
+)
+
+// newHandHighlight creates the rectangle placed behind the player's hand that
+// pulses when the reminder fires. It starts out invisible.
+func newHandHighlight() *canvas.Rectangle {
+	rect := canvas.NewRectangle(reminderIdleColor)
+	rect.CornerRadius = 8
+	return rect
+}
+
+// scheduleTurnReminder (re)starts the idle timer if it is the player's turn,
+// and cancels any pending reminder otherwise.
+func (ui *AppUI) scheduleTurnReminder() {
+	ui.cancelTurnReminder()
+	if turnReminderDelay <= 0 || ui.casino.State() != engine.StatePlayerTurn {
+		return
+	}
+	ui.reminderTimer = time.AfterFunc(turnReminderDelay, func() {
+		fyne.Do(ui.showTurnReminder)
+	})
+}
+
+// cancelTurnReminder stops a pending reminder and any pulse in progress.
+func (ui *AppUI) cancelTurnReminder() {
+	if ui.reminderTimer != nil {
+		ui.reminderTimer.Stop()
+		ui.reminderTimer = nil
+	}
+	if ui.reminderPulse != nil {
+		ui.reminderPulse.Stop()
+		ui.reminderPulse = nil
+	}
+	ui.handHighlight.FillColor = reminderIdleColor
+	ui.handHighlight.Refresh()
+}
+
+// showTurnReminder pulses the hand area and plays the chime, provided the
+// player is still expected to move.
+func (ui *AppUI) showTurnReminder() {
+	if ui.loop.Busy() || ui.casino.State() != engine.StatePlayerTurn {
+		return
+	}
+	PlaySound(SoundReminder)
+	ui.reminderPulse = canvas.NewColorRGBAAnimation(reminderIdleColor, reminderHighlightColor, 700*time.Millisecond, func(c color.Color) {
+		ui.handHighlight.FillColor = c
+		canvas.Refresh(ui.handHighlight)
+	})
+	ui.reminderPulse.AutoReverse = true
+	ui.reminderPulse.RepeatCount = 2
+	ui.reminderPulse.Curve = fyne.AnimationEaseInOut
+	ui.reminderPulse.Start()
+}
+
+// notifyTurn sends a desktop notification when it becomes the player's turn
+// while the window is in the background or the tray, once per turn, unless
+// it is turned off in settings.
+func (ui *AppUI) notifyTurn() {
+	if ui.casino.State() != engine.StatePlayerTurn || ui.spectator != nil {
+		ui.turnNotified = false
+		return
+	}
+	away := ui.inBackground || (ui.tray != nil && ui.tray.hidden)
+	if ui.turnNotified || !away || !fyne.CurrentApp().Preferences().BoolWithFallback(prefNotifyTurn, true) {
+		return
+	}
+	ui.turnNotified = true
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Pishti", T("Your move!")))
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// replayFrameSize is the size of an exported replay, in pixels.
+var replayFrameSize = fyne.NewSize(320, 380)
+
+// replayCardSize is a card in an exported replay, smaller than on screen.
+var replayCardSize = fyne.NewSize(60, 81)
+
+// replaySpeeds are the frame delays offered for exported replays.
+var replaySpeeds = []struct {
+	label string
+	delay time.Duration
+}{
+	{"Slow", 2 * time.Second},
+	{"Normal", time.Second},
+	{"Fast", 400 * time.Millisecond},
+}
+
+// replayEndHold is how many frame delays the final score stays up before an
+// exported replay loops.
+const replayEndHold = 4
+
+// replayOptions picks what an exported replay shows.
+type replayOptions struct {
+	captures bool          // Only the moves that took a pile, instead of every move.
+	delay    time.Duration // Between frames.
+}
+
+// replayStep is one point of a recorded game: the table after the deal,
+// after a move or at the end, and what happened.
+type replayStep struct {
+	snap    engine.Snapshot
+	move    string // "You played the Ace of Spades"; "The deal" or "Final score" without a move.
+	capture string // The pile taken with the move, if one was.
+}
+
+// caption is the step's line in an exported replay.
+func (s replayStep) caption() string {
+	if s.capture != "" && s.snap.State != engine.StateGameOver {
+		return s.capture
+	}
+	return s.move
+}
+
+// recordReplay replays a saved game and records every step of it.
+func recordReplay(save []byte) ([]replayStep, error) {
+	var steps []replayStep
+	c := engine.NewCasino(nil, nil)
+	captures := 0
+	err := c.Replay(save, func() {
+		step := replayStep{snap: c.Snapshot(), move: T("The deal")}
+		if n := len(step.snap.Moves); n > 0 {
+			m := step.snap.Moves[n-1]
+			step.move = describeReplayMove(m)
+		}
+		if len(step.snap.Captures) > captures {
+			step.capture = describeReplayCapture(step.snap.Captures[len(step.snap.Captures)-1])
+		}
+		captures = len(step.snap.Captures)
+		if step.snap.State == engine.StateGameOver {
+			step.move = T("Final score")
+		}
+		steps = append(steps, step)
+	})
+	return steps, err
+}
+
+// renderReplay replays a saved game offscreen into an animated GIF: the deal,
+// the chosen moves and the final score.
+func renderReplay(save []byte, opts replayOptions) (*gif.GIF, error) {
+	steps, err := recordReplay(save)
+	if err != nil {
+		return nil, err
+	}
+	anim := &gif.GIF{}
+	for _, step := range steps {
+		over := step.snap.State == engine.StateGameOver
+		if opts.captures && step.capture == "" && !over && len(step.snap.Moves) > 0 {
+			continue
+		}
+		delay := int(opts.delay / (10 * time.Millisecond)) // GIF delays are in 100ths of a second.
+		if over {
+			delay *= replayEndHold
+		}
+		anim.Image = append(anim.Image, paletted(renderReplayFrame(step.snap, step.caption())))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return anim, nil
+}
+
+// describeReplayMove captions a frame after a card was played.
+func describeReplayMove(m engine.Move) string {
+	if m.By == engine.Player {
+		return Tf("You played the %s", cardName(m.Card))
+	}
+	return Tf("CPU played the %s", cardName(m.Card))
+}
+
+// describeReplayCapture captions a frame on which a pile was taken. Each
+// side has its own captions, as a language may word them differently.
+func describeReplayCapture(e engine.CaptureEvent) string {
+	if e.By == engine.CPU {
+		switch {
+		case e.Jack:
+			return Tf("CPU made a Jack Pişti! +%d", e.Points)
+		case e.Pisti:
+			return Tf("CPU made a Pişti! +%d", e.Points)
+		case e.Final:
+			return Tf("CPU took the last %d cards", e.Cards)
+		}
+		return Tf("CPU took %d cards, +%d", e.Cards, e.Points)
+	}
+	switch {
+	case e.Jack:
+		return Tf("You made a Jack Pişti! +%d", e.Points)
+	case e.Pisti:
+		return Tf("You made a Pişti! +%d", e.Points)
+	case e.Final:
+		return Tf("You took the last %d cards", e.Cards)
+	}
+	return Tf("You took %d cards, +%d", e.Cards, e.Points)
+}
+
+// renderReplayFrame draws the table as it was at one point of the game.
+func renderReplayFrame(snap engine.Snapshot, caption string) image.Image {
+	text := func(line string, size float32) fyne.CanvasObject {
+		t := canvas.NewText(line, color.White)
+		t.TextSize = size
+		t.Alignment = fyne.TextAlignCenter
+		return t
+	}
+	card := func(res fyne.Resource) fyne.CanvasObject {
+		if res == nil {
+			return container.New(&minSizeLayout{min: replayCardSize}) // An empty slot.
+		}
+		img := canvas.NewImageFromResource(res)
+		img.FillMode = canvas.ImageFillContain
+		img.SetMinSize(replayCardSize)
+		return img
+	}
+	hand := func(cards []*engine.Card, faceUp bool) fyne.CanvasObject {
+		row := container.NewHBox()
+		for _, c := range cards {
+			switch {
+			case c == nil:
+				row.Add(card(nil))
+			case faceUp:
+				row.Add(card(getCardResource(c)))
+			default:
+				row.Add(card(resourceCardBack))
+			}
+		}
+		return container.NewCenter(row)
+	}
+	var top fyne.Resource
+	if n := len(snap.Table); n > 0 {
+		top = getCardResource(snap.Table[n-1])
+	}
+	content := container.New(layout.NewVBoxLayout(),
+		text(Tf("You %d - %d CPU", snap.PlayerPoints, snap.CPUPoints), 18),
+		hand(snap.CPUHand, false),
+		layout.NewSpacer(),
+		container.NewCenter(card(top)),
+		text(fmt.Sprintf("%d cards on the table", len(snap.Table)), 12),
+		layout.NewSpacer(),
+		hand(snap.PlayerHand, true),
+		text(caption, 14),
+	)
+	felt := canvas.NewRectangle(color.NRGBA{R: 0x1b, G: 0x5e, B: 0x20, A: 0xff})
+	c := software.NewCanvas()
+	c.SetPadded(false)
+	c.SetContent(container.NewStack(felt, container.NewPadded(content)))
+	c.Resize(replayFrameSize)
+	return c.Capture()
+}
+
+// paletted converts a frame to the 256 colors a GIF holds. The nearest color
+// is taken without dithering, which keeps the felt and the card faces flat.
+// A frame has few distinct colors, so each is only looked up once.
+func paletted(img image.Image) *image.Paletted {
+	b := img.Bounds()
+	p := image.NewPaletted(b, palette.Plan9)
+	nearest := make(map[color.RGBA64]uint8)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBA64Model.Convert(img.At(x, y)).(color.RGBA64)
+			i, ok := nearest[c]
+			if !ok {
+				i = uint8(p.Palette.Index(c))
+				nearest[c] = i
+			}
+			p.SetColorIndex(x, y, i)
+		}
+	}
+	return p
+}
+
+// encodeReplay writes the replay as a GIF, or as an MP4 when name ends in
+// .mp4, which needs ffmpeg on the PATH.
+func encodeReplay(anim *gif.GIF, name string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".mp4") {
+		return buf.Bytes(), nil
+	}
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, errors.New(T("MP4 export needs ffmpeg on the PATH; save a GIF instead"))
+	}
+	dir, err := os.MkdirTemp("", "pishti-replay")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	in, out := filepath.Join(dir, "replay.gif"), filepath.Join(dir, "replay.mp4")
+	if err := os.WriteFile(in, buf.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+	// H.264 needs even sizes, and 4:2:0 pixels play on every device.
+	cmd := exec.Command(ffmpeg, "-y", "-loglevel", "error", "-i", in,
+		"-movflags", "faststart", "-pix_fmt", "yuv420p", "-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2", out)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %v: %s", err, bytes.TrimSpace(msg))
+	}
+	return os.ReadFile(out)
+}
+
+// showReplayExport asks how to export the finished game's replay, then where
+// to save it.
+func (ui *AppUI) showReplayExport() {
+	save, err := ui.casino.Save()
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	showSelect := widget.NewSelect([]string{T("Whole game"), T("Captures only")}, nil)
+	showSelect.SetSelectedIndex(0)
+	speedLabels := make([]string, len(replaySpeeds))
+	for i, s := range replaySpeeds {
+		speedLabels[i] = T(s.label)
+	}
+	speedSelect := widget.NewSelect(speedLabels, nil)
+	speedSelect.SetSelectedIndex(1)
+	formats := []string{"GIF"}
+	if _, err := exec.LookPath("ffmpeg"); err == nil {
+		formats = append(formats, "MP4")
+	}
+	formatSelect := widget.NewSelect(formats, nil)
+	formatSelect.SetSelectedIndex(0)
+	items := []*widget.FormItem{
+		widget.NewFormItem(T("Show"), showSelect),
+		widget.NewFormItem(T("Speed"), speedSelect),
+		widget.NewFormItem(T("Format"), formatSelect),
+	}
+	dialog.ShowForm(T("Export Replay"), T("Export"), T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		opts := replayOptions{
+			captures: showSelect.SelectedIndex() == 1,
+			delay:    replaySpeeds[speedSelect.SelectedIndex()].delay,
+		}
+		anim, err := renderReplay(save, opts)
+		if err != nil {
+			log.Printf("ERROR: Failed to render the replay: %v", err)
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		picker := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if w == nil {
+				return // Cancelled.
+			}
+			// Encoding, and ffmpeg above all, takes a while; the screen stays live.
+			go func() {
+				defer w.Close()
+				data, err := encodeReplay(anim, w.URI().Name())
+				if err == nil {
+					_, err = w.Write(data)
+				}
+				if err != nil {
+					log.Printf("ERROR: Failed to export the replay: %v", err)
+					fyne.Do(func() { dialog.ShowError(err, ui.window) })
+				}
+			}()
+		}, ui.window)
+		picker.SetFileName(fmt.Sprintf("pishti-%d.%s", ui.casino.Seed(), strings.ToLower(formatSelect.Selected)))
+		picker.Show()
+	}, ui.window)
+}
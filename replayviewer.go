@@ -0,0 +1,124 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// replayViewer steps through the finished game on the table's own card
+// widgets, with both hands face up. Its bar covers the top bar while it is
+// open, so the game cannot be changed meanwhile.
+type replayViewer struct {
+	steps   []replayStep
+	at      int
+	slider  *widget.Slider
+	prev    *widget.Button
+	next    *widget.Button
+	export  *widget.Button
+	message string // The message under the table before the replay, put back after it.
+}
+
+// newReplayBar returns the bar of the replay viewer, hidden.
+func (ui *AppUI) newReplayBar() *fyne.Container {
+	r := &replayViewer{slider: widget.NewSlider(0, 1)}
+	r.slider.OnChanged = func(v float64) { ui.showReplayStep(int(v)) }
+	r.prev = widget.NewButtonWithIcon("", theme.MediaSkipPreviousIcon(), func() { ui.showReplayStep(r.at - 1) })
+	r.next = widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), func() { ui.showReplayStep(r.at + 1) })
+	r.export = widget.NewButtonWithIcon(T("Export Replay..."), theme.DocumentSaveIcon(), ui.showReplayExport)
+	closeButton := widget.NewButtonWithIcon(T("Close"), theme.CancelIcon(), ui.closeReplayViewer)
+	ui.replay = r
+	bg := canvas.NewRectangle(color.NRGBA{A: 0xd0})
+	bar := container.NewStack(bg, container.NewBorder(nil, nil, r.prev, container.NewHBox(r.next, r.export, closeButton), r.slider))
+	overlay := container.NewBorder(bar, nil, nil, nil)
+	overlay.Hide()
+	return overlay
+}
+
+// showReplayViewer records the finished game and opens the viewer at its
+// first move.
+func (ui *AppUI) showReplayViewer() {
+	save, err := ui.casino.Save()
+	var steps []replayStep
+	if err == nil {
+		steps, err = recordReplay(save)
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to replay the game: %v", err)
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	r := ui.replay
+	r.steps = steps
+	r.message, _ = ui.view.info.Get()
+	r.slider.Max = float64(len(steps) - 1)
+	ui.replayBar.Show()
+	ui.showReplayStep(0)
+}
+
+// showReplayStep puts the table of the step on screen.
+func (ui *AppUI) showReplayStep(i int) {
+	r := ui.replay
+	if i < 0 || i >= len(r.steps) {
+		return
+	}
+	r.at = i
+	if int(r.slider.Value) != i {
+		r.slider.SetValue(float64(i)) // Calls back here and stops, as the step is shown.
+	}
+	r.prev.Disable()
+	if i > 0 {
+		r.prev.Enable()
+	}
+	r.next.Disable()
+	if i < len(r.steps)-1 {
+		r.next.Enable()
+	}
+	step := r.steps[i]
+	ui.view.showSnapshot(step.snap)
+	msg := Tf("Step %d of %d: %s", i, len(r.steps)-1, step.move)
+	if step.capture != "" {
+		msg += "\n" + step.capture
+	}
+	if step.snap.State == engine.StateGameOver {
+		msg = Tf("Final score: You %d - %d CPU", step.snap.PlayerPoints, step.snap.CPUPoints)
+	}
+	ui.view.info.Set(msg)
+}
+
+// closeReplayViewer puts the finished game back on screen.
+func (ui *AppUI) closeReplayViewer() {
+	if !ui.replayBar.Visible() {
+		return
+	}
+	ui.replayBar.Hide()
+	ui.view.refresh(allParts)
+	ui.view.info.Set(ui.replay.message)
+}
+
+// showSnapshot copies a recorded table into the bindings, with both hands
+// face up. The game's state is left alone, so no card can be played.
+func (v *gameView) showSnapshot(snap engine.Snapshot) {
+	v.playerScore.Set(snap.PlayerPoints)
+	v.cpuScore.Set(snap.CPUPoints)
+	setHand(v.playerHand, snap.PlayerHand, true)
+	setHand(v.cpuHand, snap.CPUHand, true)
+	var top, under shownCard
+	if n := len(snap.Table); n > 0 {
+		top = shownCard{card: snap.Table[n-1], faceUp: true}
+		if n > 1 {
+			// The starting cards stay face down until the first pile is taken.
+			under = shownCard{card: snap.Table[n-2], faceUp: len(snap.Captures) > 0}
+		}
+	}
+	v.tableTop.Set(top)
+	v.tableUnder.Set(under)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// branchPoints returns the indexes of the player's moves in a save, the
+// points an alternative line can be played from.
+func branchPoints(save []byte) ([]int, error) {
+	g, err := engine.DecodeSave(save)
+	if err != nil {
+		return nil, err
+	}
+	var points []int
+	for i, m := range g.Moves {
+		if m.By == engine.Player {
+			points = append(points, i)
+		}
+	}
+	return points, nil
+}
+
+// renderBranchPoint draws the table as it stood before the move with the
+// given index.
+func renderBranchPoint(save []byte, move int, caption string) (fyne.CanvasObject, error) {
+	branched, err := engine.Branch(save, move)
+	if err != nil {
+		return nil, err
+	}
+	c := engine.NewCasino(nil, nil)
+	if err := c.Load(branched); err != nil {
+		return nil, err
+	}
+	img := canvas.NewImageFromImage(renderReplayFrame(c.Snapshot(), caption))
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(replayFrameSize)
+	return img, nil
+}
+
+// showReview lets the player step back through the finished game and play
+// on from any of their moves, as analysis.
+func (ui *AppUI) showReview() {
+	save, err := ui.casino.Save()
+	var points []int
+	if err == nil {
+		points, err = branchPoints(save)
+	}
+	if err == nil && len(points) == 0 {
+		return // No card of the player's to take back.
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to review the game: %v", err)
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	preview := container.NewStack()
+	label := widget.NewLabel("")
+	label.Alignment = fyne.TextAlignCenter
+	slider := widget.NewSlider(1, float64(len(points)))
+	show := func(v float64) {
+		n := int(v)
+		label.SetText(Tf("Before your move %d of %d", n, len(points)))
+		frame, err := renderBranchPoint(save, points[n-1], Tf("Your move %d", n))
+		if err != nil {
+			log.Printf("ERROR: Failed to show move %d: %v", n, err)
+			return
+		}
+		preview.Objects = []fyne.CanvasObject{frame}
+		preview.Refresh()
+	}
+	slider.SetValue(float64(len(points)))
+	show(slider.Value)
+	slider.OnChanged = show
+	content := container.NewVBox(preview, label, slider)
+	dialog.ShowCustomConfirm(T("Review"), T("Play from Here"), T("Close"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		n := int(slider.Value)
+		branched, err := engine.Branch(save, points[n-1])
+		if err == nil {
+			err = ui.startAnalysis(branched, n)
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to play from move %d: %v", n, err)
+			dialog.ShowError(err, ui.window)
+		}
+	}, ui.window)
+}
+
+// startAnalysis loads a game branched before the player's move with the
+// given number and marks it as analysis, which earns no achievements.
+func (ui *AppUI) startAnalysis(branched []byte, move int) error {
+	if err := ui.loadGame(branched); err != nil {
+		return err
+	}
+	ui.analysis, ui.unrated = true, true
+	ui.view.info.Set(Tf("Analysis from your move %d: the game does not count.", move))
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+)
+
+// baseWindowSize is the window the table is laid out for. A larger or
+// smaller window scales the cards, the gaps between them and the text by
+// how much of it fits.
+var baseWindowSize = fyne.NewSize(440, 600)
+
+const (
+	// minLayoutScale and maxLayoutScale bound the scale, so the cards stay
+	// readable on a small laptop and the text sharp on a large monitor.
+	minLayoutScale = 0.75
+	maxLayoutScale = 3
+	// layoutScaleStep is what the scale is rounded to, so dragging the
+	// window's edge only rebuilds the layout now and then. Rounding, rather
+	// than down, leaves the base window at its base size inside its padding.
+	layoutScaleStep = 0.125
+)
+
+// scaledObject is an object given its size for the base window, resized by
+// apply to the size at the current scale.
+type scaledObject struct {
+	base  fyne.Size
+	apply func(fyne.Size)
+}
+
+// layoutScaler keeps the sizes of the table's objects in proportion to the
+// window.
+type layoutScaler struct {
+	scale   float32
+	min     float32 // The smallest scale; 1 on touch screens, which are laid out for fingers.
+	objects []scaledObject
+	content fyne.CanvasObject // Laid out again when the scale changes.
+}
+
+// newLayoutScaler returns a scaler at the base size.
+func newLayoutScaler() *layoutScaler {
+	s := &layoutScaler{scale: 1, min: minLayoutScale}
+	if touchScreen() {
+		s.min = 1
+	}
+	return s
+}
+
+// add sizes the object with apply, now and whenever the scale changes.
+func (s *layoutScaler) add(base fyne.Size, apply func(fyne.Size)) {
+	s.objects = append(s.objects, scaledObject{base: base, apply: apply})
+	apply(scaled(base, s.scale))
+}
+
+// spacer returns an empty gap of the base size, scaled.
+func (s *layoutScaler) spacer(base fyne.Size) *fyne.Container {
+	gap := &minSizeLayout{}
+	s.add(base, func(size fyne.Size) { gap.min = size })
+	return container.New(gap)
+}
+
+// scaleFor returns the scale that fits the base window into one of the given
+// size.
+func (s *layoutScaler) scaleFor(size fyne.Size) float32 {
+	fit := math.Min(float64(size.Width/baseWindowSize.Width), float64(size.Height/baseWindowSize.Height))
+	fit = math.Round(fit/layoutScaleStep) * layoutScaleStep
+	return float32(math.Max(float64(s.min), math.Min(fit, maxLayoutScale)))
+}
+
+// fit rescales the objects for a window of the given size. The text is
+// scaled by the theme, which redraws the whole window, so that is left for
+// after the layout in progress.
+func (s *layoutScaler) fit(size fyne.Size) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return // Not shown yet.
+	}
+	scale := s.scaleFor(size)
+	if scale == s.scale {
+		return
+	}
+	s.scale = scale
+	for _, o := range s.objects {
+		o.apply(scaled(o.base, scale))
+	}
+	fyne.Do(func() {
+		settings := fyne.CurrentApp().Settings()
+		if t, ok := settings.Theme().(*transparentTheme); ok {
+			settings.SetTheme(t.withScale(scale))
+		}
+		if s.content != nil {
+			s.content.Refresh()
+		}
+	})
+}
+
+// scaledLayout fills its container with the content and tells the scaler of
+// every new size.
+type scaledLayout struct {
+	scaler *layoutScaler
+}
+
+// Layout rescales the table for the size, then resizes the content to fill
+// it.
+func (l *scaledLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	l.scaler.fit(size)
+	for _, o := range objects {
+		o.Move(fyne.NewPos(0, 0))
+		o.Resize(size)
+	}
+}
+
+// MinSize returns the window at the smallest scale, so the window can be
+// made smaller than the table's current size.
+func (l *scaledLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return scaled(baseWindowSize, l.scaler.min)
+}
+
+// scaled returns the size with its width and height multiplied by scale.
+func scaled(size fyne.Size, scale float32) fyne.Size {
+	return fyne.NewSize(size.Width*scale, size.Height*scale)
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pishti/engine"
+)
+
+// apiKeyEnv names the environment variable holding the key clients of the
+// API server must send, as "Authorization: Bearer <key>". It is not a flag so
+// it does not show up in process lists.
+const apiKeyEnv = "PISHTI_API_KEY"
+
+// maxAPIGames limits the games the API server keeps at once; finished games
+// stay until deleted.
+const maxAPIGames = 1000
+
+var (
+	// errTooManyGames refuses a new game while maxAPIGames are kept.
+	errTooManyGames = errors.New("too many games; delete finished ones")
+	// errNotPlayersTurn refuses a move while the CPU is to play or the game
+	// is over.
+	errNotPlayersTurn = errors.New("it is not the player's turn")
+)
+
+// apiServer serves games over HTTP, so web frontends and bots can play
+// without the window. The gRPC server of grpcserver.go keeps its games in
+// one too. The routes are:
+//
+//	POST   /games              start a game: {"level": "Beginner", "seed": 7, "variant": "Classic"}
+//	GET    /games/{id}         the game as the player sees it
+//	POST   /games/{id}/moves   play a card of the player's hand: {"slot": 0}
+//	GET    /games/{id}/history the cards played and the captures made
+//	DELETE /games/{id}         forget a game
+//
+// Only the level is required to start a game. A move returns once the CPU
+// has replied, with the new state.
+type apiServer struct {
+	key    string
+	mu     sync.Mutex // Guards games and nextID.
+	games  map[string]*apiGame
+	nextID int
+}
+
+// apiGame is one game of the API server.
+type apiGame struct {
+	id string
+	mu sync.Mutex // Keeps a move and the CPU's reply together.
+	c  *engine.Casino
+}
+
+// apiCard is a card in API responses.
+type apiCard struct {
+	Rank string `json:"rank"`
+	Suit string `json:"suit"`
+}
+
+// apiState is a game as the player sees it: the CPU's cards and the ones
+// face down on the table are only counted.
+type apiState struct {
+	ID             string     `json:"id"`
+	State          string     `json:"state"`
+	Level          string     `json:"level"`
+	Variant        string     `json:"variant"`
+	Seed           int64      `json:"seed"`
+	PlayerPoints   int        `json:"player_points"`
+	CPUPoints      int        `json:"cpu_points"`
+	PlayerCaptured int        `json:"player_captured"`
+	CPUCaptured    int        `json:"cpu_captured"`
+	DeckLeft       int        `json:"deck_left"`
+	Hand           []*apiCard `json:"hand"` // Played slots are null.
+	CPUCards       int        `json:"cpu_cards"`
+	TableTop       *apiCard   `json:"table_top"`
+	TableCards     int        `json:"table_cards"`
+	FaceDown       int        `json:"face_down"` // Starting cards still hidden under the top card.
+}
+
+// apiMove is a card played, in the history.
+type apiMove struct {
+	By   string   `json:"by"`
+	Card *apiCard `json:"card"`
+	Slot int      `json:"slot"`
+}
+
+// apiCapture is a pile taken, in the history.
+type apiCapture struct {
+	By     string `json:"by"`
+	Cards  int    `json:"cards"`
+	Points int    `json:"points"`
+	Pisti  bool   `json:"pisti"`
+	Jack   bool   `json:"jack"`
+	Final  bool   `json:"final"` // The last pile, awarded at the end of the game.
+}
+
+// apiHistory is the record of a game.
+type apiHistory struct {
+	Moves    []apiMove    `json:"moves"`
+	Captures []apiCapture `json:"captures"`
+}
+
+// runAPIServer serves the API on addr until it fails. It refuses to start
+// without a key.
+func runAPIServer(addr, key string) error {
+	if key == "" {
+		return errors.New("set " + apiKeyEnv + " to the key clients must send")
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newAPIServer(key).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("Serving the game API on %s", addr)
+	return srv.ListenAndServe()
+}
+
+// newAPIServer returns a server with no games that accepts the given key.
+func newAPIServer(key string) *apiServer {
+	return &apiServer{key: key, games: make(map[string]*apiGame)}
+}
+
+// handler returns the API's routes, behind the key check.
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /games", s.createGame)
+	mux.HandleFunc("GET /games/{id}", s.withGame(s.getState))
+	mux.HandleFunc("POST /games/{id}/moves", s.withGame(s.postMove))
+	mux.HandleFunc("GET /games/{id}/history", s.withGame(s.getHistory))
+	mux.HandleFunc("DELETE /games/{id}", s.deleteGame)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(key), []byte(s.key)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pishti"`)
+			writeAPIError(w, http.StatusUnauthorized, "missing or wrong API key")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// withGame looks up the game named in the path for a handler, holding its
+// lock while the handler runs.
+func (s *apiServer) withGame(h func(http.ResponseWriter, *http.Request, *apiGame)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g := s.game(r.PathValue("id"))
+		if g == nil {
+			writeAPIError(w, http.StatusNotFound, "no such game")
+			return
+		}
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		h(w, r, g)
+	}
+}
+
+// createGame starts a new game.
+func (s *apiServer) createGame(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level   string `json:"level"`
+		Seed    *int64 `json:"seed"`
+		Variant string `json:"variant"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	g, err := s.newGame(req.Level, req.Seed, req.Variant)
+	switch {
+	case errors.Is(err, errTooManyGames):
+		writeAPIError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/games/"+g.id)
+	writeAPIJSON(w, http.StatusCreated, g.state())
+}
+
+// newGame deals a game at the named level, from the seed if there is one,
+// under the variant or the configured one if it is "".
+func (s *apiServer) newGame(level string, seed *int64, variant string) (*apiGame, error) {
+	strategy, ok := strategyNamed(level)
+	if !ok {
+		return nil, errors.New("unknown level; choose one of " + strings.Join(strategyNames(), ", "))
+	}
+	c := engine.NewCasino(nil, nil)
+	if variant == "" {
+		variant = appConfig.Rules.Variant
+	}
+	if !c.SetVariant(variant) {
+		return nil, errors.New("unknown rule variant " + strconv.Quote(variant))
+	}
+	c.SetLevel(strategy.Level)
+	if seed != nil {
+		c.StartGameWithSeed(*seed)
+	} else {
+		c.StartGame()
+	}
+	advanceGame(c, nil) // Settle anything the opening deal left to do.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.games) >= maxAPIGames {
+		return nil, errTooManyGames
+	}
+	s.nextID++
+	g := &apiGame{id: strconv.Itoa(s.nextID), c: c}
+	s.games[g.id] = g
+	return g, nil
+}
+
+// game returns the game with the ID, or nil if there is none.
+func (s *apiServer) game(id string) *apiGame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.games[id]
+}
+
+// remove forgets the game with the ID, reporting whether there was one.
+func (s *apiServer) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.games[id] == nil {
+		return false
+	}
+	delete(s.games, id)
+	return true
+}
+
+// getState returns the game as the player sees it.
+func (s *apiServer) getState(w http.ResponseWriter, _ *http.Request, g *apiGame) {
+	writeAPIJSON(w, http.StatusOK, g.state())
+}
+
+// postMove plays a card of the player's hand and the CPU's reply.
+func (s *apiServer) postMove(w http.ResponseWriter, r *http.Request, g *apiGame) {
+	var req struct {
+		Slot *int `json:"slot"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Slot == nil {
+		writeAPIError(w, http.StatusBadRequest, `invalid request: send {"slot": N} with N from 0 to 3`)
+		return
+	}
+	if err := g.play(*req.Slot, nil); err != nil {
+		writeAPIError(w, http.StatusConflict, err.Error())
+		return
+	}
+	g.c.Events() // Nobody listens for them, so don't let them pile up.
+	writeAPIJSON(w, http.StatusOK, g.state())
+}
+
+// getHistory returns the cards played and the captures made so far.
+func (s *apiServer) getHistory(w http.ResponseWriter, _ *http.Request, g *apiGame) {
+	writeAPIJSON(w, http.StatusOK, g.history())
+}
+
+// deleteGame forgets a game.
+func (s *apiServer) deleteGame(w http.ResponseWriter, r *http.Request) {
+	if !s.remove(r.PathValue("id")) {
+		writeAPIError(w, http.StatusNotFound, "no such game")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// play plays the card in the slot of the player's hand and the CPU's reply.
+// If step is set, it is called after the move, after each step of the reply
+// and once more at the end. The caller must hold the game's lock.
+func (g *apiGame) play(slot int, step func()) error {
+	if g.c.State() != engine.StatePlayerTurn {
+		return fmt.Errorf("%w: %s", errNotPlayersTurn, g.c.State())
+	}
+	if err := g.c.PlayerPlays(slot); err != nil {
+		return err
+	}
+	if step == nil {
+		advanceGame(g.c, nil)
+		return nil
+	}
+	step()
+	advanceGame(g.c, step)
+	step() // advanceGame does not report the step that ends the game.
+	return nil
+}
+
+// history returns the cards played and the captures made so far.
+func (g *apiGame) history() apiHistory {
+	snap := g.c.Snapshot()
+	h := apiHistory{Moves: []apiMove{}, Captures: []apiCapture{}}
+	for _, m := range snap.Moves {
+		h.Moves = append(h.Moves, apiMove{By: m.By.String(), Card: newAPICard(m.Card), Slot: m.Slot})
+	}
+	for _, e := range snap.Captures {
+		h.Captures = append(h.Captures, apiCapture{
+			By: e.By.String(), Cards: e.Cards, Points: e.Points, Pisti: e.Pisti, Jack: e.Jack, Final: e.Final,
+		})
+	}
+	return h
+}
+
+// state returns the game as the player sees it.
+func (g *apiGame) state() apiState {
+	snap := g.c.Snapshot()
+	st := apiState{
+		ID:             g.id,
+		State:          snap.State.String(),
+		Level:          levelName(snap.Level),
+		Variant:        g.c.Variant(),
+		Seed:           snap.Seed,
+		PlayerPoints:   snap.PlayerPoints,
+		CPUPoints:      snap.CPUPoints,
+		PlayerCaptured: snap.PlayerCaptured,
+		CPUCaptured:    snap.CPUCaptured,
+		DeckLeft:       engine.DeckSize - snap.CardsDealt,
+		TableCards:     len(snap.Table),
+	}
+	for _, card := range snap.PlayerHand {
+		st.Hand = append(st.Hand, newAPICard(card))
+	}
+	for _, card := range snap.CPUHand {
+		if card != nil {
+			st.CPUCards++
+		}
+	}
+	if n := len(snap.Table); n > 0 {
+		st.TableTop = newAPICard(snap.Table[n-1])
+		if g.c.IsInitialPile() {
+			st.FaceDown = n - 1
+		}
+	}
+	return st
+}
+
+// newAPICard returns the API form of a card, nil for none.
+func newAPICard(c *engine.Card) *apiCard {
+	if c == nil {
+		return nil
+	}
+	return &apiCard{Rank: c.GetFace().String(), Suit: c.GetSuit().String()}
+}
+
+// writeAPIJSON writes v as the JSON response.
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("ERROR: Failed to write API response: %v", err)
+	}
+}
+
+// writeAPIError writes an error response: {"error": "..."}.
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeAPIJSON(w, status, map[string]string{"error": msg})
+}
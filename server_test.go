@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// apiCall sends a request to the API server and decodes the JSON reply into
+// out, returning the status.
+func apiCall(t *testing.T, srv *httptest.Server, method, path, key, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAPIPlaysAGame(t *testing.T) {
+	appConfig = defaultConfig()
+	srv := httptest.NewServer(newAPIServer("secret").handler())
+	defer srv.Close()
+	if got := apiCall(t, srv, "POST", "/games", "wrong", `{"level": "Beginner"}`, nil); got != http.StatusUnauthorized {
+		t.Fatalf("wrong key: status %d, want %d", got, http.StatusUnauthorized)
+	}
+	var st apiState
+	if got := apiCall(t, srv, "POST", "/games", "secret", `{"level": "Intermediate", "seed": 5}`, &st); got != http.StatusCreated {
+		t.Fatalf("create: status %d", got)
+	}
+	if st.Seed != 5 || st.Level != "Intermediate" || st.FaceDown != 3 || len(st.Hand) != 4 {
+		t.Fatalf("new game = %+v", st)
+	}
+	path := "/games/" + st.ID
+	for moves := 0; st.State != "game over"; moves++ {
+		if moves > 60 {
+			t.Fatal("the game never ended")
+		}
+		slot := 0
+		for st.Hand[slot] == nil {
+			slot++
+		}
+		body := fmt.Sprintf(`{"slot": %d}`, slot)
+		if got := apiCall(t, srv, "POST", path+"/moves", "secret", body, &st); got != http.StatusOK {
+			t.Fatalf("move %d: status %d", moves, got)
+		}
+	}
+	var errResp map[string]string
+	if got := apiCall(t, srv, "POST", path+"/moves", "secret", `{"slot": 0}`, &errResp); got != http.StatusConflict {
+		t.Errorf("move after the game: status %d, want %d", got, http.StatusConflict)
+	}
+	var h apiHistory
+	apiCall(t, srv, "GET", path+"/history", "secret", "", &h)
+	if len(h.Moves) != 48 {
+		t.Errorf("history has %d moves, want 48", len(h.Moves))
+	}
+	points := 0
+	for _, c := range h.Captures {
+		if c.By == "player" {
+			points += c.Points
+		}
+	}
+	if points > st.PlayerPoints {
+		t.Errorf("captures give the player %d points, more than the %d scored", points, st.PlayerPoints)
+	}
+	if got := apiCall(t, srv, "DELETE", path, "secret", "", nil); got != http.StatusNoContent {
+		t.Errorf("delete: status %d", got)
+	}
+	if got := apiCall(t, srv, "GET", path, "secret", "", nil); got != http.StatusNotFound {
+		t.Errorf("deleted game: status %d, want %d", got, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,718 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// Preference keys used to persist user settings between launches.
+const (
+	prefAnimatedBackground = "animatedBackground"
+	prefMasterVolume       = "masterVolume"
+	prefMusicVolume        = "musicVolume"
+	prefEffectsVolume      = "effectsVolume"
+	prefMuted              = "muted"
+	prefPauseInBackground  = "pauseMusicInBackground"
+	prefAnnouncerLanguage  = "announcerLanguage"
+	prefDuckMusic          = "duckMusic"
+	prefAudioSampleRate    = "audioSampleRate"
+	prefAudioBufferMs      = "audioBufferMs"
+	prefAudioDevice        = "audioDevice"
+	prefPreloadCards       = "preloadCards"
+	prefVariant            = "variant"
+	prefHouseRules         = "houseRules" // JSON of the rules of engine.CustomVariant.
+	prefCardSkin           = "cardSkin"
+	prefTableBackground    = "tableBackground"
+	prefCardBack           = "cardBack"
+	prefFrameStyle         = "frameStyle"
+	prefNotifyTurn         = "notifyTurn"
+	prefPractice           = "practice"
+	prefVoiceControl       = "voiceControl"
+	prefOnlineServer       = "onlineServer"
+	prefMatchTarget        = "matchTarget"
+	prefDefaultLevel       = "defaultLevel"
+	prefGameSpeed          = "gameSpeed"
+	prefMusicOn            = "musicOn"
+	prefEffectsOn          = "effectsOn"
+	prefLanguage           = "language"
+	prefSuspendedGame      = "suspendedGame" // The save of the game in progress when a phone app was last sent away.
+	// Per-effect settings are stored under these prefixes followed by the sound name.
+	prefEffectVolumePrefix  = "effectVolume."
+	prefEffectEnabledPrefix = "effectEnabled."
+)
+
+// announcerOptions lists the announcer choices shown in settings, mapped to language codes.
+var announcerOptions = []struct {
+	label, lang string
+}{
+	{"Off", ""},
+	{"English", "en"},
+	{"Türkçe", "tr"},
+}
+
+// languageOptions lists the interface languages shown in settings, mapped to
+// language tags; "" follows the system.
+var languageOptions = []struct {
+	label, tag string
+}{
+	{"System", ""},
+	{"English", "en"},
+	{"Türkçe", "tr"},
+}
+
+// gameSpeeds lists the paces of the game shown in settings, as multiples of
+// the configured pauses' speed.
+var gameSpeeds = []struct {
+	label string
+	speed float64
+}{
+	{"Slow", 0.5},
+	{"Normal", 1},
+	{"Fast", 2},
+}
+
+// noDefaultLevel is the default level choice that leaves the level unselected.
+const noDefaultLevel = "None"
+
+// gamePacing is the configured pacing at the game speed picked in settings.
+func gamePacing() engine.Pacing {
+	return scaledPacing(fyne.CurrentApp().Preferences().FloatWithFallback(prefGameSpeed, 1))
+}
+
+// selectDefaultLevel puts the level picked in settings, if any, into the
+// level selector for the next game.
+func (ui *AppUI) selectDefaultLevel() {
+	ui.levelSelect.ClearSelected()
+	if s, ok := strategyNamed(fyne.CurrentApp().Preferences().String(prefDefaultLevel)); ok {
+		ui.levelSelect.SetSelected(s.Name)
+	}
+}
+
+// audioSampleRates and audioBufferSizes are the choices offered in the
+// advanced audio settings. A buffer of 0ms lets the audio driver decide.
+var (
+	audioSampleRates = []int{22050, 44100, 48000}
+	audioBufferSizes = []int{0, 10, 20, 40, 80, 160}
+)
+
+// audioSettings returns the sample rate, device buffer length and output
+// device to open the audio context with. They are read once, as the context
+// cannot be reopened. A browser picks its own buffer and device, and the
+// saved rate may not suit it.
+func audioSettings(prefs fyne.Preferences) (int, time.Duration, string) {
+	if inBrowser() {
+		return defaultSampleRate, 0, ""
+	}
+	rate := prefs.IntWithFallback(prefAudioSampleRate, appConfig.Audio.SampleRate)
+	buffer := time.Duration(prefs.IntWithFallback(prefAudioBufferMs, appConfig.Audio.BufferMs)) * time.Millisecond
+	return rate, buffer, prefs.String(prefAudioDevice)
+}
+
+// applySettings applies the persisted preferences to the running app.
+func (ui *AppUI) applySettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	ui.setAnimatedBackground(prefs.BoolWithFallback(prefAnimatedBackground, appConfig.UI.AnimatedBackground))
+	SetMasterVolume(prefs.FloatWithFallback(prefMasterVolume, appConfig.Audio.MasterVolume))
+	SetMusicVolume(prefs.FloatWithFallback(prefMusicVolume, appConfig.Audio.MusicVolume))
+	SetEffectsVolume(prefs.FloatWithFallback(prefEffectsVolume, appConfig.Audio.EffectsVolume))
+	SetSoundRateLimit(appConfig.Audio.SoundRateLimit)
+	for name, n := range appConfig.Audio.MaxVoices {
+		if effect, ok := soundNamed(name); ok {
+			SetMaxVoices(effect, n)
+		} else {
+			log.Printf("ERROR: Unknown sound %q in audio.max_voices", name)
+		}
+	}
+	turnReminderDelay = appConfig.UI.TurnReminder
+	ui.setMuted(prefs.BoolWithFallback(prefMuted, false))
+	SetMusicEnabled(prefs.BoolWithFallback(prefMusicOn, true))
+	SetEffectsEnabled(prefs.BoolWithFallback(prefEffectsOn, true))
+	ui.loop.SetPacing(gamePacing())
+	ui.loop.SetThinkTime(appConfig.AI.ThinkTime)
+	if ui.casino.State() == engine.StateNotStarted {
+		ui.selectDefaultLevel()
+	}
+	ui.setPractice(prefs.Bool(prefPractice))
+	ui.setVoiceControl(prefs.Bool(prefVoiceControl))
+	SetAnnouncerLanguage(prefs.String(prefAnnouncerLanguage))
+	SetDucking(prefs.BoolWithFallback(prefDuckMusic, true))
+	if name := prefs.StringWithFallback(prefVariant, appConfig.Rules.Variant); name == engine.CustomVariant {
+		if err := ui.loop.SetRules(savedHouseRules(prefs)); err != nil {
+			log.Printf("ERROR: Invalid house rules in preferences: %v", err)
+		}
+	} else if !ui.loop.SetVariant(name) {
+		log.Printf("ERROR: Unknown rule variant %q in preferences", name)
+	}
+	ui.loop.SetMatchTarget(prefs.IntWithFallback(prefMatchTarget, appConfig.Rules.MatchTarget))
+	for _, e := range adjustableEffects {
+		name := soundNames[e.effect]
+		SetEffectVolume(e.effect, prefs.FloatWithFallback(prefEffectVolumePrefix+name, 1))
+		SetEffectEnabled(e.effect, prefs.BoolWithFallback(prefEffectEnabledPrefix+name, true))
+	}
+}
+
+// setupFocusHandling pauses the music while the window is unfocused or
+// minimized, if the user enabled that option, and resumes it on focus. It
+// also tracks whether a turn should be notified, and on touch screens keeps
+// the game in case the app is stopped in the background.
+func (ui *AppUI) setupFocusHandling(a fyne.App) {
+	pausedByFocus := false
+	a.Lifecycle().SetOnExitedForeground(func() {
+		ui.inBackground = true
+		if touchScreen() {
+			ui.suspendGame()
+		}
+		if a.Preferences().BoolWithFallback(prefPauseInBackground, true) {
+			pausedByFocus = true
+			PauseMusic()
+		}
+	})
+	a.Lifecycle().SetOnEnteredForeground(func() {
+		ui.inBackground = false
+		// Only resume music that was paused here, not music that never started.
+		if pausedByFocus {
+			pausedByFocus = false
+			ResumeMusic()
+		}
+	})
+}
+
+// toggleMute flips the global mute state.
+func (ui *AppUI) toggleMute() {
+	ui.setMuted(!IsMuted())
+}
+
+// setMuted mutes or unmutes all audio, remembers the choice and updates the mute button.
+func (ui *AppUI) setMuted(mute bool) {
+	SetMuted(mute)
+	fyne.CurrentApp().Preferences().SetBool(prefMuted, mute)
+	if mute {
+		ui.muteButton.SetIcon(theme.VolumeMuteIcon())
+	} else {
+		ui.muteButton.SetIcon(theme.VolumeUpIcon())
+	}
+}
+
+// setAnimatedBackground switches between the animated and the static background.
+func (ui *AppUI) setAnimatedBackground(enabled bool) {
+	if enabled {
+		ui.background.start()
+	} else {
+		ui.background.stopAnimation()
+	}
+}
+
+// newVolumeSlider creates a 0-100% slider bound to a volume preference.
+// Changes are applied live through apply and saved immediately.
+func newVolumeSlider(key string, fallback float64, apply func(float64)) *widget.Slider {
+	prefs := fyne.CurrentApp().Preferences()
+	slider := widget.NewSlider(0, 100)
+	slider.Step = 1
+	slider.SetValue(prefs.FloatWithFallback(key, fallback) * 100)
+	slider.OnChanged = func(value float64) {
+		prefs.SetFloat(key, value/100)
+		apply(value / 100)
+	}
+	return slider
+}
+
+// showSettings opens the settings dialog. Changes are applied and saved immediately.
+func (ui *AppUI) showSettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	var d dialog.Dialog
+	animatedCheck := widget.NewCheck(T("Animated background"), func(enabled bool) {
+		prefs.SetBool(prefAnimatedBackground, enabled)
+		ui.setAnimatedBackground(enabled)
+	})
+	animatedCheck.SetChecked(prefs.BoolWithFallback(prefAnimatedBackground, appConfig.UI.AnimatedBackground))
+	preloadCheck := widget.NewCheck(T("Load all card images at startup"), func(enabled bool) {
+		prefs.SetBool(prefPreloadCards, enabled) // Read by loadResources on the next launch.
+	})
+	preloadCheck.SetChecked(prefs.Bool(prefPreloadCards))
+	skinSelect := ui.newSkinSelect()
+	pauseCheck := widget.NewCheck(T("Pause music when in background"), func(enabled bool) {
+		prefs.SetBool(prefPauseInBackground, enabled)
+	})
+	pauseCheck.SetChecked(prefs.BoolWithFallback(prefPauseInBackground, true))
+	duckCheck := widget.NewCheck(T("Lower music under important effects"), func(enabled bool) {
+		prefs.SetBool(prefDuckMusic, enabled)
+		SetDucking(enabled)
+	})
+	duckCheck.SetChecked(prefs.BoolWithFallback(prefDuckMusic, true))
+	practiceCheck := widget.NewCheck(T("Practice: show the odds of each card (no achievements)"), func(enabled bool) {
+		prefs.SetBool(prefPractice, enabled)
+		ui.setPractice(enabled)
+	})
+	practiceCheck.SetChecked(prefs.Bool(prefPractice))
+	voiceCheck := widget.NewCheck(T("Voice control"), nil)
+	voiceCheck.SetChecked(prefs.Bool(prefVoiceControl)) // Before OnChanged: the recognizer is already running.
+	voiceCheck.OnChanged = func(enabled bool) {
+		prefs.SetBool(prefVoiceControl, enabled)
+		ui.setVoiceControl(enabled)
+	}
+	if len(appConfig.Voice.Command) == 0 || inBrowser() {
+		// A recognizer must be named in the config file first.
+		voiceCheck.Text = T("Voice control (set a recognizer in config.toml)")
+		voiceCheck.Disable()
+	}
+	notifyCheck := widget.NewCheck(T("Notify me of my move when in background"), func(enabled bool) {
+		prefs.SetBool(prefNotifyTurn, enabled)
+	})
+	notifyCheck.SetChecked(prefs.BoolWithFallback(prefNotifyTurn, true))
+	announcerLabels := make([]string, len(announcerOptions))
+	announcerSelect := widget.NewSelect(nil, nil)
+	for i, option := range announcerOptions {
+		announcerLabels[i] = T(option.label)
+		if option.lang == prefs.String(prefAnnouncerLanguage) {
+			announcerSelect.Selected = T(option.label)
+		}
+	}
+	announcerSelect.Options = announcerLabels
+	announcerSelect.OnChanged = func(label string) {
+		for _, option := range announcerOptions {
+			if T(option.label) == label {
+				prefs.SetString(prefAnnouncerLanguage, option.lang)
+				go SetAnnouncerLanguage(option.lang) // Loading clips may take a moment.
+			}
+		}
+	}
+	variantSelect, variantInfo := ui.newVariantSelect()
+	levelSelect := widget.NewSelect(append([]string{T(noDefaultLevel)}, strategyNames()...), func(name string) {
+		if name == T(noDefaultLevel) {
+			name = ""
+		}
+		prefs.SetString(prefDefaultLevel, name)
+	})
+	levelSelect.Selected = T(noDefaultLevel)
+	if s, ok := strategyNamed(prefs.String(prefDefaultLevel)); ok {
+		levelSelect.Selected = s.Name
+	}
+	speedLabels := make([]string, len(gameSpeeds))
+	speedSelect := widget.NewSelect(nil, nil)
+	for i, option := range gameSpeeds {
+		speedLabels[i] = T(option.label)
+		if option.speed == prefs.FloatWithFallback(prefGameSpeed, 1) {
+			speedSelect.Selected = T(option.label)
+		}
+	}
+	speedSelect.Options = speedLabels
+	speedSelect.OnChanged = func(label string) {
+		for _, option := range gameSpeeds {
+			if T(option.label) == label {
+				prefs.SetFloat(prefGameSpeed, option.speed)
+				if ui.spectator == nil { // Watching keeps the speed picked for it.
+					ui.loop.SetPacing(gamePacing())
+				}
+			}
+		}
+	}
+	languageLabels := make([]string, len(languageOptions))
+	languageSelect := widget.NewSelect(nil, nil)
+	for i, option := range languageOptions {
+		languageLabels[i] = T(option.label)
+		if option.tag == prefs.String(prefLanguage) {
+			languageSelect.Selected = T(option.label)
+		}
+	}
+	languageSelect.Options = languageLabels
+	languageSelect.OnChanged = func(label string) {
+		for _, option := range languageOptions {
+			if T(option.label) == label {
+				prefs.SetString(prefLanguage, option.tag)
+				setCardLanguage(option.tag)
+				setMessageLanguage(option.tag)
+			}
+		}
+	}
+	musicCheck := widget.NewCheck(T("Play music"), func(enabled bool) {
+		prefs.SetBool(prefMusicOn, enabled)
+		SetMusicEnabled(enabled)
+	})
+	musicCheck.SetChecked(prefs.BoolWithFallback(prefMusicOn, true))
+	effectsCheck := widget.NewCheck(T("Play sound effects"), func(enabled bool) {
+		prefs.SetBool(prefEffectsOn, enabled)
+		SetEffectsEnabled(enabled)
+	})
+	effectsCheck.SetChecked(prefs.BoolWithFallback(prefEffectsOn, true))
+	volumeForm := widget.NewForm(
+		widget.NewFormItem(T("Master"), newVolumeSlider(prefMasterVolume, appConfig.Audio.MasterVolume, SetMasterVolume)),
+		widget.NewFormItem(T("Music"), newVolumeSlider(prefMusicVolume, appConfig.Audio.MusicVolume, SetMusicVolume)),
+		widget.NewFormItem(T("Effects"), newVolumeSlider(prefEffectsVolume, appConfig.Audio.EffectsVolume, SetEffectsVolume)),
+		widget.NewFormItem(T("Announcer"), announcerSelect),
+	)
+	effectsButton := widget.NewButton(T("Individual Sounds..."), ui.showEffectSettings)
+	testButton := widget.NewButton(T("Test Sounds..."), ui.showSoundTest)
+	advancedButton := widget.NewButton(T("Advanced Audio..."), ui.showAdvancedAudioSettings)
+	tuningButton := widget.NewButton(T("Game Tuning..."), ui.showTuningSettings)
+	if inBrowser() {
+		// The browser owns the audio device, and there is no config file to save.
+		advancedButton.Disable()
+		tuningButton.Disable()
+	}
+	copyCodeButton := widget.NewButton(T("Copy Game Code"), ui.copyGameCode)
+	loadCodeButton := widget.NewButton(T("Load Game Code..."), ui.showLoadGameCode)
+	watchButton := widget.NewButton(T("Watch AI vs AI..."), func() {
+		d.Hide()
+		ui.showSpectate()
+	})
+	passButton := widget.NewButton(T("Pass & Play"), func() {
+		d.Hide()
+		ui.startHotSeat()
+	})
+	onlineButton := widget.NewButton(T("Play Online..."), func() {
+		d.Hide()
+		ui.showOnline()
+	})
+	achievementsButton := widget.NewButton(T("Achievements..."), ui.showAchievementGallery)
+	dailyButton := widget.NewButton(T("Daily Deal"), func() {
+		d.Hide()
+		ui.resetGameUI()
+		ui.startDailyDeal()
+	})
+	// A seed deals the same cards every time, to replay a game or share it.
+	seedEntry := widget.NewEntry()
+	seedEntry.SetPlaceHolder(T("Number"))
+	seedEntry.Validator = validateSeed
+	seedButton := widget.NewButton(T("Deal"), func() {
+		seed, err := strconv.ParseInt(seedEntry.Text, 10, 64)
+		if err != nil {
+			return // Shown by the validator.
+		}
+		d.Hide()
+		ui.resetGameUI()
+		ui.startGameWith(func() bool { return ui.loop.StartGameWithSeed(seed) })
+	})
+	houseRulesButton := widget.NewButton(T("Edit..."), func() {
+		ui.showHouseRules(func() {
+			// OnChanged describes the new rules even if they were selected already.
+			variantSelect.Selected = engine.CustomVariant
+			variantSelect.Refresh()
+			variantSelect.OnChanged(engine.CustomVariant)
+		})
+	})
+	rulesForm := widget.NewForm(widget.NewFormItem(T("Rules"), container.NewBorder(nil, nil, nil, houseRulesButton, variantSelect)))
+	gameForm := widget.NewForm(
+		widget.NewFormItem(T("Match"), ui.newMatchSelect()),
+		widget.NewFormItem(T("Level"), levelSelect),
+		widget.NewFormItem(T("Speed"), speedSelect),
+		widget.NewFormItem(T("Language"), languageSelect),
+		widget.NewFormItem(T("Seed"), container.NewBorder(nil, nil, nil, seedButton, seedEntry)),
+	)
+	gameForm.Items[1].HintText = T("Selected for each new game")
+	gameForm.Items[3].HintText = T("Cards and messages change at once, the rest after a restart")
+	gameForm.Items[4].HintText = T("Deals the same cards for the same number")
+	skinForm := widget.NewForm(widget.NewFormItem(T("Cards"), skinSelect),
+		widget.NewFormItem(T("Table"), ui.newDesignSelect(backgroundPart)),
+		widget.NewFormItem(T("Card back"), ui.newDesignSelect(cardBackPart)),
+		widget.NewFormItem(T("Frames"), ui.newDesignSelect(framePart)))
+	content := container.NewVBox(rulesForm, variantInfo, gameForm, practiceCheck, container.NewGridWithColumns(2, copyCodeButton, loadCodeButton), container.NewGridWithColumns(2, watchButton, passButton), container.NewGridWithColumns(2, dailyButton, onlineButton), achievementsButton, widget.NewSeparator(),
+		skinForm, animatedCheck, preloadCheck, notifyCheck, voiceCheck, widget.NewSeparator(), volumeForm, musicCheck, effectsCheck, pauseCheck, duckCheck,
+		container.NewGridWithColumns(2, effectsButton, testButton), container.NewGridWithColumns(2, advancedButton, tuningButton))
+	// The settings scroll where the window is too short for all of them.
+	scroll := container.NewVScroll(content)
+	scroll.SetMinSize(fyne.NewSize(0, min(content.MinSize().Height, ui.window.Canvas().Size().Height-120)))
+	d = dialog.NewCustom(T("Settings"), T("Close"), scroll, ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// newVariantSelect returns a selector for the registered rule variants and
+// the house rules, and a label describing the chosen one. A new choice
+// applies from the next game.
+func (ui *AppUI) newVariantSelect() (*widget.Select, *widget.Label) {
+	prefs := fyne.CurrentApp().Preferences()
+	variants := engine.Variants()
+	names := make([]string, len(variants), len(variants)+1)
+	for i, v := range variants {
+		names[i] = v.Name
+	}
+	names = append(names, engine.CustomVariant)
+	info := widget.NewLabel("")
+	info.Wrapping = fyne.TextWrapWord
+	describe := func(name string) {
+		if name == engine.CustomVariant {
+			info.SetText(describeRules(savedHouseRules(prefs)) + " " + T("Applies from the next game."))
+		}
+		for _, v := range variants {
+			if v.Name == name {
+				info.SetText(T(v.Description) + " " + T("Applies from the next game."))
+			}
+		}
+	}
+	selector := widget.NewSelect(names, func(name string) {
+		prefs.SetString(prefVariant, name)
+		if name == engine.CustomVariant {
+			ui.loop.SetRules(savedHouseRules(prefs)) // Checked when they were saved.
+		} else {
+			ui.loop.SetVariant(name)
+		}
+		describe(name)
+	})
+	selector.Selected = ui.casino.Variant()
+	describe(selector.Selected)
+	return selector, info
+}
+
+// newMatchSelect returns a selector for the points a match is played to. A
+// new choice applies from the next game.
+func (ui *AppUI) newMatchSelect() *widget.Select {
+	prefs := fyne.CurrentApp().Preferences()
+	labels := make([]string, len(matchTargets))
+	for i, points := range matchTargets {
+		labels[i] = matchTargetLabel(points)
+	}
+	selector := widget.NewSelect(labels, func(label string) {
+		for _, points := range matchTargets {
+			if matchTargetLabel(points) == label {
+				prefs.SetInt(prefMatchTarget, points)
+				ui.loop.SetMatchTarget(points)
+			}
+		}
+	})
+	// A target set in the config file is shown even if it is not a choice.
+	selector.Selected = matchTargetLabel(ui.casino.MatchTarget())
+	return selector
+}
+
+// builtInSkin is the label of the built-in cards among the skins.
+const builtInSkin = "Built-in"
+
+// newSkinSelect returns a selector for the card skins in the skins folder.
+// A new choice is shown at once.
+func (ui *AppUI) newSkinSelect() *widget.Select {
+	prefs := fyne.CurrentApp().Preferences()
+	selector := widget.NewSelect(append([]string{T(builtInSkin)}, skinNames()...), func(name string) {
+		if name == T(builtInSkin) {
+			name = ""
+		}
+		if name == prefs.String(prefCardSkin) {
+			return
+		}
+		prefs.SetString(prefCardSkin, name)
+		useSkin(name)
+		ui.reloadImages()
+	})
+	selector.Selected = T(builtInSkin)
+	if name := prefs.String(prefCardSkin); name != "" {
+		selector.Selected = name
+	}
+	if inBrowser() {
+		selector.Disable() // There is no skins folder.
+	}
+	return selector
+}
+
+// newDesignSelect returns a selector for the bundled designs of a part of the
+// table. A new choice is shown at once.
+func (ui *AppUI) newDesignSelect(part tablePart) *widget.Select {
+	prefs := fyne.CurrentApp().Preferences()
+	selector := widget.NewSelect(append([]string{T(classicDesign)}, part.designs()...), func(name string) {
+		if name == T(classicDesign) {
+			name = ""
+		}
+		if name == prefs.String(part.pref) {
+			return
+		}
+		prefs.SetString(part.pref, name)
+		useTableDesign(part, name)
+		ui.reloadImages()
+	})
+	selector.Selected = T(classicDesign)
+	if name := tableDesigns[part]; name != "" {
+		selector.Selected = name
+	}
+	return selector
+}
+
+// showEffectSettings opens a dialog to turn individual sound effects down or off.
+func (ui *AppUI) showEffectSettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	form := widget.NewForm()
+	for _, e := range adjustableEffects {
+		effect, name := e.effect, soundNames[e.effect]
+		slider := newVolumeSlider(prefEffectVolumePrefix+name, 1, func(volume float64) {
+			SetEffectVolume(effect, volume)
+		})
+		enabledCheck := widget.NewCheck("", func(enabled bool) {
+			prefs.SetBool(prefEffectEnabledPrefix+name, enabled)
+			SetEffectEnabled(effect, enabled)
+			if enabled {
+				slider.Enable()
+			} else {
+				slider.Disable()
+			}
+		})
+		enabledCheck.SetChecked(prefs.BoolWithFallback(prefEffectEnabledPrefix+name, true))
+		if !enabledCheck.Checked {
+			slider.Disable()
+		}
+		form.Append(T(e.label), container.NewBorder(nil, nil, enabledCheck, nil, slider))
+	}
+	d := dialog.NewCustom(T("Individual Sounds"), T("Close"), container.NewVScroll(form), ui.window)
+	d.Resize(fyne.NewSize(380, 480))
+	d.Show()
+}
+
+// bufferLabel formats a device buffer choice for display.
+func bufferLabel(ms int) string {
+	if ms == 0 {
+		return T("Automatic")
+	}
+	return fmt.Sprintf("%d ms", ms)
+}
+
+// showAdvancedAudioSettings opens a dialog for the audio device parameters.
+// Small buffers lower the latency of card sounds but may crackle on some
+// systems. The output device can be picked where the platform lists its
+// devices. The changes take effect when the game is restarted.
+func (ui *AppUI) showAdvancedAudioSettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	rateOptions := make([]string, len(audioSampleRates))
+	for i, rate := range audioSampleRates {
+		rateOptions[i] = strconv.Itoa(rate) + " Hz"
+	}
+	rateSelect := widget.NewSelect(rateOptions, func(label string) {
+		prefs.SetInt(prefAudioSampleRate, audioSampleRates[indexOf(rateOptions, label)])
+	})
+	bufferOptions := make([]string, len(audioBufferSizes))
+	for i, ms := range audioBufferSizes {
+		bufferOptions[i] = bufferLabel(ms)
+	}
+	bufferSelect := widget.NewSelect(bufferOptions, func(label string) {
+		prefs.SetInt(prefAudioBufferMs, audioBufferSizes[indexOf(bufferOptions, label)])
+	})
+	devices := audioDevices()
+	deviceOptions := []string{T("System default")}
+	for _, d := range devices {
+		deviceOptions = append(deviceOptions, d.Name)
+	}
+	deviceSelect := widget.NewSelect(deviceOptions, nil)
+	showCurrent := func() {
+		rate, buffer, device := audioSettings(prefs)
+		rateSelect.SetSelected(strconv.Itoa(rate) + " Hz")
+		bufferSelect.SetSelected(bufferLabel(int(buffer / time.Millisecond)))
+		// Only a choice made in the dialog is saved, so an unplugged device stays picked.
+		onChanged := deviceSelect.OnChanged
+		deviceSelect.OnChanged = nil
+		selected := 0
+		for i, d := range devices {
+			if d.ID == device {
+				selected = i + 1
+			}
+		}
+		deviceSelect.SetSelectedIndex(selected)
+		deviceSelect.OnChanged = onChanged
+	}
+	showCurrent()
+	deviceSelect.OnChanged = func(label string) {
+		id := ""
+		if i := indexOf(deviceOptions, label); i > 0 {
+			id = devices[i-1].ID
+		}
+		prefs.SetString(prefAudioDevice, id)
+	}
+	resetButton := widget.NewButton(T("Restore Safe Defaults"), func() {
+		prefs.RemoveValue(prefAudioSampleRate)
+		prefs.RemoveValue(prefAudioBufferMs)
+		prefs.RemoveValue(prefAudioDevice)
+		showCurrent()
+	})
+	form := widget.NewForm(
+		widget.NewFormItem(T("Sample rate"), rateSelect),
+		widget.NewFormItem(T("Buffer"), bufferSelect),
+	)
+	if len(devices) > 0 {
+		form.Append(T("Output device"), deviceSelect) // Only offered where the platform lists its devices.
+	}
+	note := widget.NewLabel(T("Raise the buffer if sounds crackle, lower it if card sounds lag.\nChanges take effect after restarting Pishti."))
+	note.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustom(T("Advanced Audio"), T("Close"), container.NewVBox(form, note, resetButton), ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// showTuningSettings opens a dialog for the timing values kept in the config
+// file. Saving writes the file and applies the new pauses straight away.
+func (ui *AppUI) showTuningSettings() {
+	cfg := appConfig
+	fields := []struct {
+		label string
+		value *time.Duration
+	}{
+		{"CPU delay", &cfg.AI.CPUDelay},
+		{"Capture pause", &cfg.AI.CapturePause},
+		{"End of hand pause", &cfg.AI.EndOfHandPause},
+		{"Expert thinking time", &cfg.AI.ThinkTime},
+		{"Card animation", &cfg.UI.CardAnimation},
+		{"Turn reminder", &cfg.UI.TurnReminder},
+	}
+	entries := make([]*widget.Entry, len(fields))
+	items := make([]*widget.FormItem, len(fields))
+	for i, f := range fields {
+		entries[i] = widget.NewEntry()
+		entries[i].SetText(f.value.String())
+		entries[i].Validator = validateDuration
+		items[i] = widget.NewFormItem(T(f.label), entries[i])
+	}
+	items[len(items)-2].HintText = T("0s turns the animations off")
+	items[len(items)-1].HintText = T("0s turns the reminder off")
+	d := dialog.NewForm(T("Game Tuning"), T("Save"), T("Cancel"), items, func(save bool) {
+		if !save {
+			return
+		}
+		for i, f := range fields {
+			*f.value, _ = time.ParseDuration(entries[i].Text) // Checked by the validator.
+		}
+		appConfig = cfg
+		ui.loop.SetPacing(gamePacing())
+		ui.loop.SetThinkTime(cfg.AI.ThinkTime)
+		turnReminderDelay = cfg.UI.TurnReminder
+		if err := saveConfig(cfg); err != nil {
+			log.Printf("ERROR: Failed to save config file: %v", err)
+			dialog.ShowError(err, ui.window)
+		}
+	}, ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// validateSeed accepts a whole number to deal a game from.
+func validateSeed(text string) error {
+	if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+		return errors.New(T("use a whole number"))
+	}
+	return nil
+}
+
+// validateDuration accepts a non-negative duration such as "500ms" or "1.5s".
+func validateDuration(text string) error {
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return errors.New(T("use a duration like 500ms or 1.5s"))
+	}
+	if d < 0 {
+		return errors.New(T("must not be negative"))
+	}
+	return nil
+}
+
+// indexOf returns the position of value in options, or 0 if it is missing.
+func indexOf(options []string, value string) int {
+	for i, option := range options {
+		if option == value {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// resultCardSize is the size of the shared result image, in device
+// independent pixels; it is rendered at twice that for sharp text in chats.
+var resultCardSize = fyne.NewSize(360, 220)
+
+// resultSummary is what the result card tells about a finished game.
+type resultSummary struct {
+	headline                string // "You Win!", "CPU Wins!" or "It's a Tie!".
+	playerPoints, cpuPoints int
+	level, variant          string
+	playerPistis, cpuPistis int
+	duration                time.Duration
+	seed                    int64
+	daily                   string // The date of the Daily Deal the game was, if it was one.
+}
+
+// newResultSummary sums up the finished game, which lasted duration; 0
+// leaves the duration out.
+func newResultSummary(c *engine.Casino, duration time.Duration) resultSummary {
+	snap := c.Snapshot()
+	s := resultSummary{
+		headline:     T("It's a Tie!"),
+		playerPoints: snap.PlayerPoints,
+		cpuPoints:    snap.CPUPoints,
+		level:        levelName(snap.Level),
+		variant:      c.Variant(),
+		duration:     duration.Round(time.Second),
+		seed:         snap.Seed,
+	}
+	switch {
+	case s.playerPoints > s.cpuPoints:
+		s.headline = T("You Win!")
+	case s.cpuPoints > s.playerPoints:
+		s.headline = T("CPU Wins!")
+	}
+	for _, e := range snap.Captures {
+		if !e.Pisti {
+			continue
+		}
+		if e.By == engine.Player {
+			s.playerPistis++
+		} else {
+			s.cpuPistis++
+		}
+	}
+	return s
+}
+
+// lines returns the details below the headline, also used as the text
+// copied to the clipboard.
+func (s resultSummary) lines() []string {
+	var last string
+	switch {
+	case s.duration > 0 && s.daily != "":
+		last = Tf("Played in %s, Daily Deal of %s", s.duration, s.daily)
+	case s.duration > 0:
+		last = Tf("Played in %s, seed %d", s.duration, s.seed)
+	case s.daily != "":
+		last = Tf("Daily Deal of %s", s.daily)
+	default:
+		last = Tf("Seed %d", s.seed)
+	}
+	return []string{
+		Tf("You %d - %d CPU", s.playerPoints, s.cpuPoints),
+		Tf("%s level, %s rules", s.level, s.variant),
+		Tf("Piştis: you %d, CPU %d", s.playerPistis, s.cpuPistis),
+		last,
+	}
+}
+
+// text returns the summary as plain text for pasting into a chat.
+func (s resultSummary) text() string {
+	return "Pishti: " + s.headline + "\n" + strings.Join(s.lines(), "\n")
+}
+
+// render draws the result card offscreen.
+func (s resultSummary) render() image.Image {
+	felt := canvas.NewRectangle(color.NRGBA{R: 0x1b, G: 0x5e, B: 0x20, A: 0xff})
+	text := func(line string, size float32, c color.Color, bold bool) fyne.CanvasObject {
+		t := canvas.NewText(line, c)
+		t.TextSize = size
+		t.TextStyle.Bold = bold
+		t.Alignment = fyne.TextAlignCenter
+		return t
+	}
+	texts := []fyne.CanvasObject{
+		text("Pishti", 16, color.NRGBA{R: 0xff, G: 0xd5, B: 0x4f, A: 0xff}, true),
+		text(s.headline, 28, color.White, true),
+	}
+	for _, line := range s.lines() {
+		texts = append(texts, text(line, 15, color.White, false))
+	}
+	content := container.NewStack(felt, container.NewCenter(container.New(layout.NewVBoxLayout(), texts...)))
+	c := software.NewCanvas()
+	c.SetPadded(false)
+	c.SetScale(2)
+	c.SetContent(content)
+	c.Resize(resultCardSize)
+	return c.Capture()
+}
+
+// showShareResult shows the finished game's result card, to be saved as a
+// PNG or copied as text, and offers to export the game's replay. Images cannot be put on the clipboard portably, so
+// the clipboard gets the same summary as text.
+func (ui *AppUI) showShareResult() {
+	summary := newResultSummary(ui.casino, ui.gameDuration)
+	summary.daily = ui.daily
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, summary.render()); err != nil {
+		log.Printf("ERROR: Failed to render the result image: %v", err)
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	data := buf.Bytes()
+	preview := canvas.NewImageFromResource(fyne.NewStaticResource("result.png", data))
+	preview.FillMode = canvas.ImageFillContain
+	preview.SetMinSize(resultCardSize)
+	var d dialog.Dialog
+	saveButton := widget.NewButton(T("Save Image..."), func() {
+		save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if w == nil {
+				return // Cancelled.
+			}
+			defer w.Close()
+			if _, err := w.Write(data); err != nil {
+				log.Printf("ERROR: Failed to save the result image: %v", err)
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+		save.SetFileName(fmt.Sprintf("pishti-%d.png", summary.seed))
+		save.Show()
+	})
+	copyButton := widget.NewButton(T("Copy Text"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(summary.text())
+		d.Hide()
+	})
+	exportButton := widget.NewButton(T("Export Replay..."), func() {
+		d.Hide()
+		ui.showReplayExport()
+	})
+	d = dialog.NewCustom(T("Share Result"), T("Close"), container.NewVBox(preview, container.NewGridWithColumns(2, saveButton, copyButton), exportButton), ui.window)
+	d.Show()
+}
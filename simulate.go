@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"pishti/engine"
+)
+
+// simulateCommand is the subcommand that plays CPU levels against each other
+// without a window: pishti simulate [flags].
+const simulateCommand = "simulate"
+
+// baselineSeat names the player seat that always plays its first card, the
+// opponent --headless-sim measures the levels against.
+const baselineSeat = "Baseline"
+
+// matchupStats adds up the games of one pairing of player and CPU seats.
+type matchupStats struct {
+	player, cpu                string // Seat names.
+	games                      int
+	playerWins, cpuWins, draws int
+	playerPoints, cpuPoints    int
+	playerPistis, cpuPistis    int
+	marginSquares              int // Sum of each game's margin squared, for the spread.
+}
+
+// runSimulate runs the simulate subcommand with its arguments and writes the
+// report to out, or to the file named by -o.
+func runSimulate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("pishti "+simulateCommand, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	games := fs.Int("games", 100, "games to play per matchup")
+	players := fs.String("player", baselineSeat, "comma-separated levels for the player's seat, "+baselineSeat+" or all")
+	cpus := fs.String("cpu", "all", "comma-separated levels for the CPU's seat, or all")
+	seed := fs.Int64("seed", 0, "seed of the first game; each game uses the next")
+	variant := fs.String("variant", appConfig.Rules.Variant, "rule variant to play under")
+	format := fs.String("format", "markdown", "report format: markdown or csv")
+	output := fs.String("o", "", "write the report to this `file` instead of standard output")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w\n\n%s", err, flagUsage(fs))
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q\n\n%s", fs.Arg(0), flagUsage(fs))
+	}
+	if *games <= 0 {
+		return errors.New("-games needs a positive number of games")
+	}
+	if *format != "markdown" && *format != "csv" {
+		return fmt.Errorf("unknown report format %q; choose markdown or csv", *format)
+	}
+	playerSeats, err := parseSeats(*players, true)
+	if err != nil {
+		return err
+	}
+	cpuSeats, err := parseSeats(*cpus, false)
+	if err != nil {
+		return err
+	}
+	var stats []*matchupStats
+	for _, p := range playerSeats {
+		for _, c := range cpuSeats {
+			m, err := simulateMatchup(p, c, *variant, *seed, *games)
+			if err != nil {
+				return err
+			}
+			stats = append(stats, m)
+		}
+	}
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "csv" {
+		return writeSimCSV(out, stats)
+	}
+	return writeSimMarkdown(out, stats, *variant, *seed, *games)
+}
+
+// simSeat is a strategy taking part in a simulation.
+type simSeat struct {
+	name  string
+	level engine.GameLevel // LevelNotSelected for the baseline player.
+}
+
+// parseSeats resolves a comma-separated list of level names, where "all"
+// stands for every registered level. The baseline is only allowed for the
+// player's seat.
+func parseSeats(list string, baseline bool) ([]simSeat, error) {
+	var seats []simSeat
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case strings.EqualFold(name, "all"):
+			for _, s := range engine.Strategies() {
+				seats = append(seats, simSeat{name: s.Name, level: s.Level})
+			}
+		case baseline && strings.EqualFold(name, baselineSeat):
+			seats = append(seats, simSeat{name: baselineSeat, level: engine.LevelNotSelected})
+		default:
+			s, ok := strategyNamed(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown level %q; choose from %s", name, strings.Join(strategyNames(), ", "))
+			}
+			seats = append(seats, simSeat{name: s.Name, level: s.Level})
+		}
+	}
+	return seats, nil
+}
+
+// simulateMatchup plays games games between two seats, with seeds counting
+// up from seed.
+func simulateMatchup(player, cpu simSeat, variant string, seed int64, games int) (*matchupStats, error) {
+	m := &matchupStats{player: player.name, cpu: cpu.name, games: games}
+	for i := 0; i < games; i++ {
+		r, err := engine.SimulateMatch(player.level, cpu.level, variant, seed+int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("%s against %s: %w", player.name, cpu.name, err)
+		}
+		switch r.Winner() {
+		case engine.Player:
+			m.playerWins++
+		case engine.CPU:
+			m.cpuWins++
+		default:
+			m.draws++
+		}
+		m.playerPoints += r.PlayerPoints
+		m.cpuPoints += r.CPUPoints
+		margin := r.PlayerPoints - r.CPUPoints
+		m.marginSquares += margin * margin
+		m.playerPistis += r.PlayerPistis
+		m.cpuPistis += r.CPUPistis
+	}
+	return m, nil
+}
+
+// perGame returns n averaged over the matchup's games.
+func (m *matchupStats) perGame(n int) float64 {
+	return float64(n) / float64(m.games)
+}
+
+// marginError returns how far, at 95% confidence, the true average margin
+// may lie from the matchup's average margin. A margin further from 0 than
+// this is more than a lucky run of deals.
+func (m *matchupStats) marginError() float64 {
+	if m.games < 2 {
+		return math.Inf(1)
+	}
+	mean := m.perGame(m.playerPoints - m.cpuPoints)
+	variance := (float64(m.marginSquares) - float64(m.games)*mean*mean) / float64(m.games-1)
+	return 1.96 * math.Sqrt(max(variance, 0)/float64(m.games))
+}
+
+// writeSimMarkdown writes the report as a Markdown table.
+func writeSimMarkdown(w io.Writer, stats []*matchupStats, variant string, seed int64, games int) error {
+	var b strings.Builder
+	b.WriteString("# Pishti simulation\n\n")
+	fmt.Fprintf(&b, "%d games per matchup under %s rules, seeds %d to %d.\n\n", games, variant, seed, seed+int64(games)-1)
+	b.WriteString("| Player seat | CPU seat | Player wins | CPU wins | Draws | Player win rate | Average score | Average margin | 95% interval | Piştis per game |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, m := range stats {
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %.1f%% | %.1f to %.1f | %+.1f | ±%.1f%s | %.2f to %.2f |\n",
+			m.player, m.cpu, m.playerWins, m.cpuWins, m.draws, 100*m.perGame(m.playerWins),
+			m.perGame(m.playerPoints), m.perGame(m.cpuPoints), m.perGame(m.playerPoints-m.cpuPoints),
+			m.marginError(), significant(m), m.perGame(m.playerPistis), m.perGame(m.cpuPistis))
+	}
+	b.WriteString("\nThe margin is the player seat's points minus the CPU seat's. * marks a margin whose\n")
+	b.WriteString("95% interval leaves out 0: at these many games, one seat really plays better. Only the\n")
+	b.WriteString("CPU seat is told which discards are safe, so play both ways round to compare two levels.\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// significant returns "*" when the matchup's margin is too large for chance.
+func significant(m *matchupStats) string {
+	if math.Abs(m.perGame(m.playerPoints-m.cpuPoints)) > m.marginError() {
+		return " *"
+	}
+	return ""
+}
+
+// writeSimCSV writes the report as CSV, one row per matchup.
+func writeSimCSV(w io.Writer, stats []*matchupStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"player_seat", "cpu_seat", "games", "player_wins", "cpu_wins", "draws",
+		"player_win_rate", "player_avg_points", "cpu_avg_points", "avg_margin", "margin_ci95", "player_pistis_per_game", "cpu_pistis_per_game"})
+	float := func(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
+	for _, m := range stats {
+		cw.Write([]string{m.player, m.cpu, strconv.Itoa(m.games), strconv.Itoa(m.playerWins), strconv.Itoa(m.cpuWins), strconv.Itoa(m.draws),
+			float(m.perGame(m.playerWins)), float(m.perGame(m.playerPoints)), float(m.perGame(m.cpuPoints)),
+			float(m.perGame(m.playerPoints - m.cpuPoints)), float(m.marginError()), float(m.perGame(m.playerPistis)), float(m.perGame(m.cpuPistis))})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"pishti/engine"
+)
+
+func TestSimulateReport(t *testing.T) {
+	appConfig = defaultConfig()
+	var out strings.Builder
+	if err := runSimulate([]string{"-games", "5", "-player", "Baseline,Advanced", "-cpu", "all", "-format", "csv"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := len(engine.Strategies())
+	if want := 1 + 2*levels; len(rows) != want {
+		t.Fatalf("report has %d rows, want %d:\n%s", len(rows), want, out.String())
+	}
+	if rows[1][0] != "Baseline" || rows[1+levels][0] != "Advanced" || rows[1+levels][1] != "Beginner" {
+		t.Errorf("matchups out of order:\n%s", out.String())
+	}
+	if rows[0][10] != "margin_ci95" || rows[1][10] == "0.0000" {
+		t.Errorf("no interval for the margin:\n%s", out.String())
+	}
+	m := &matchupStats{games: 4, playerPoints: 8, marginSquares: 4 * 4}
+	if m.marginError() != 0 || significant(m) == "" {
+		t.Errorf("a steady +2 margin has an interval of ±%f", m.marginError())
+	}
+	if err := runSimulate([]string{"-cpu", "Baseline"}, &out); err == nil {
+		t.Error("the baseline was accepted in the CPU's seat")
+	}
+}
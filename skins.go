@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/png" // Decodes the sizes of a skin's bitmaps.
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pishti/engine"
+)
+
+// A skin is a folder in the skins folder holding card images named like the
+// built-in ones: 1.png to 52.png and back.png. The cards run Ace to King in
+// each suit, Hearts first, then Diamonds, Clubs and Spades, so 1 is the Ace
+// of Hearts, 14 the Ace of Diamonds and 52 the King of Spades. SVG files are
+// accepted too. A card the skin leaves out keeps its built-in image. The
+// bitmaps must all be the same size and shaped like a card, or the whole
+// skin is refused rather than a deck of mixed cards shown.
+
+// cardImageDir is where the card images live among the assets.
+const cardImageDir = "assets/cards/"
+
+// cardAspect is the width of a card over its height, as for the built-in
+// cards, and skinAspectSlack how far a skin's cards may be from it.
+const (
+	cardAspect      = 71.0 / 96
+	skinAspectSlack = 0.1
+)
+
+// skinExtensions lists the image types accepted in a skin, preferred first
+// as for the built-in cards.
+var skinExtensions = []string{".svg", ".png"}
+
+// skinArt holds the chosen skin's images over the built-in ones, or is nil
+// for the built-in cards. It is only used on the UI goroutine.
+var skinArt fs.FS
+
+// skinFS lays a skin's card images over the assets. Whatever the skin does
+// not have, in either image type, is read from assetFS.
+type skinFS struct {
+	skin fs.FS
+}
+
+// Open opens a file, taking card images from the skin where it has them.
+func (s skinFS) Open(name string) (fs.File, error) {
+	file, ok := strings.CutPrefix(name, cardImageDir)
+	if !ok {
+		return assetFS.Open(name)
+	}
+	stem := strings.TrimSuffix(file, path.Ext(file))
+	for _, ext := range skinExtensions {
+		if _, err := fs.Stat(s.skin, stem+ext); err != nil {
+			continue
+		}
+		if stem+ext != file {
+			// The skin has the card in the other type; hide the built-in
+			// one so the skin's is picked.
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return s.skin.Open(file)
+	}
+	return assetFS.Open(name)
+}
+
+// cardArtFS returns where the image at path p is read from.
+func cardArtFS(p string) fs.FS {
+	if skinArt != nil && strings.HasPrefix(p, cardImageDir) {
+		return skinArt
+	}
+	return assetFS
+}
+
+// skinImageNames returns the names of the images a complete skin has.
+func skinImageNames() []string {
+	names := make([]string, 0, engine.DeckSize+1)
+	for i := 1; i <= engine.DeckSize; i++ {
+		names = append(names, strconv.Itoa(i))
+	}
+	return append(names, "back")
+}
+
+// missingSkinImages returns the names of the images the skin lacks.
+func missingSkinImages(skin fs.FS) []string {
+	var missing []string
+	for _, name := range skinImageNames() {
+		found := false
+		for _, ext := range skinExtensions {
+			if _, err := fs.Stat(skin, name+ext); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// checkSkinImages reports a bitmap of the skin that cannot be read, is not
+// shaped like a card or is not the size of the others.
+func checkSkinImages(skin fs.FS) error {
+	var size image.Point
+	first := ""
+	for _, name := range skinImageNames() {
+		file := name + ".png"
+		if _, err := fs.Stat(skin, name+".svg"); err == nil {
+			continue // Drawn at any size.
+		}
+		f, err := skin.Open(file)
+		if err != nil {
+			continue // Missing; the built-in image is used.
+		}
+		cfg, _, err := image.DecodeConfig(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		aspect := float64(cfg.Width) / float64(cfg.Height)
+		switch got := image.Pt(cfg.Width, cfg.Height); {
+		case math.Abs(aspect-cardAspect) > skinAspectSlack:
+			return fmt.Errorf("%s is %dx%d, not shaped like a card", file, cfg.Width, cfg.Height)
+		case first == "":
+			size, first = got, file
+		case got != size:
+			return fmt.Errorf("%s is %dx%d and %s %dx%d", file, cfg.Width, cfg.Height, first, size.X, size.Y)
+		}
+	}
+	return nil
+}
+
+// skinNames returns the skins in the skins folder, sorted.
+func skinNames() []string {
+	dir := dataPath(skinsDir)
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// useSkin switches the card images to the named skin, or back to the
+// built-in cards for "". A skin that cannot be found is reported and the
+// built-in cards are kept. The images are loaded again by loadResources or
+// reloadImages.
+func useSkin(name string) {
+	skinArt = nil
+	if name == "" {
+		return
+	}
+	root := dataPath(skinsDir)
+	dir := filepath.Join(root, name)
+	if info, err := os.Stat(dir); root == "" || err != nil || !info.IsDir() {
+		log.Printf("ERROR: Card skin %q not found in the skins folder", name)
+		reportProblem(problemSkin, "The chosen card skin is missing; the built-in cards are shown.")
+		return
+	}
+	skin := os.DirFS(dir)
+	switch missing := missingSkinImages(skin); {
+	case len(missing) == len(skinImageNames()):
+		log.Printf("ERROR: Card skin %s has no card images; name them 1.png to 52.png and back.png", dir)
+		reportProblem(problemSkin, "The chosen card skin has no card images; the built-in cards are shown.")
+		return
+	case len(missing) > 0:
+		log.Printf("WARNING: Card skin %s lacks %d images (%s); the built-in ones are used for them",
+			dir, len(missing), strings.Join(missing, ", "))
+	}
+	if err := checkSkinImages(skin); err != nil {
+		log.Printf("ERROR: Card skin %s is not used: %v", dir, err)
+		reportProblem(problemSkin, "The chosen card skin has images of the wrong size; the built-in cards are shown.")
+		return
+	}
+	log.Printf("Using card skin %s", dir)
+	skinArt = skinFS{skin: skin}
+}
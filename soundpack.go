@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// soundPackExtensions lists the file types accepted in a sound pack, in the
+// order they are tried when several files share a name.
+var soundPackExtensions = []string{".mp3", ".ogg", ".wav"}
+
+// maxEffectDuration caps custom sound effects so a mislabelled music file
+// cannot be used as, say, the card-play click.
+const maxEffectDuration = 30 // Seconds.
+
+// soundPackDirs returns the directories searched for user sounds, in priority
+// order: a sounds folder next to the executable, then one in the data directory.
+func soundPackDirs() []string {
+	var dirs []string
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Join(filepath.Dir(exe), "sounds"))
+	}
+	if dir := dataPath(soundsDir); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// loadCustomSound returns the decoded audio of the first valid file named
+// after the sound in the given directories, or nil if there is none.
+func loadCustomSound(name string, dirs []string) []byte {
+	for _, dir := range dirs {
+		for _, ext := range soundPackExtensions {
+			path := filepath.Join(dir, name+ext)
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				log.Printf("ERROR: Failed to read custom sound %s: %v", path, err)
+				continue
+			}
+			pcm, err := decodeAudio(path, data)
+			if err == nil {
+				err = validateSound(pcm)
+			}
+			if err != nil {
+				log.Printf("ERROR: Ignoring custom sound %s: %v", path, err)
+				continue
+			}
+			log.Printf("Using custom sound %s", path)
+			return pcm
+		}
+	}
+	return nil
+}
+
+// findCustomMusic returns the path and contents of the first user music file
+// that can be decoded, or a nil slice if there is none. The file is checked by
+// decoding its beginning only, since music is streamed while it plays.
+func findCustomMusic(name string, dirs []string) (string, []byte) {
+	for _, dir := range dirs {
+		for _, ext := range soundPackExtensions {
+			path := filepath.Join(dir, name+ext)
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				err = validateMusic(path, data)
+			}
+			if err != nil {
+				log.Printf("ERROR: Ignoring custom music %s: %v", path, err)
+				continue
+			}
+			log.Printf("Using custom music %s", path)
+			return path, data
+		}
+	}
+	return "", nil
+}
+
+// validateMusic checks that a music file decodes and produces some audio.
+func validateMusic(path string, data []byte) error {
+	stream, err := openAudioStream(path, data)
+	if err != nil {
+		return err
+	}
+	probe := make([]byte, 4096)
+	if n, err := io.ReadFull(stream, probe); n == 0 {
+		return fmt.Errorf("file contains no audio: %v", err)
+	}
+	return nil
+}
+
+// validateSound checks that decoded audio is usable as a sound effect.
+func validateSound(pcm []byte) error {
+	if len(pcm) == 0 {
+		return errors.New("file contains no audio")
+	}
+	seconds := len(pcm) / (sampleRate * channelCount * bytesPerSample)
+	if seconds > maxEffectDuration {
+		return fmt.Errorf("sound effect is %ds long, the limit is %ds", seconds, maxEffectDuration)
+	}
+	return nil
+}
+
+// reportUnknownSoundFiles logs files in the sound pack directories that do not
+// match any effect name, which usually means a typo in the file name.
+func reportUnknownSoundFiles(dirs []string) {
+	known := make(map[string]bool, len(soundNames))
+	for _, name := range soundNames {
+		known[name] = true
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // A missing directory simply means there is no sound pack.
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			base := strings.TrimSuffix(name, filepath.Ext(name))
+			if !entry.IsDir() && !known[variantBase(base)] { // Subfolders such as "announcer" are skipped.
+				log.Printf("WARNING: Unrecognized file %s in sound pack %s", name, dir)
+			}
+		}
+	}
+}
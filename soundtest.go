@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// announcementLabels names the announcer clips in the sound test panel, in display order.
+var announcementLabels = []struct {
+	effect SoundEffect
+	label  string
+}{
+	{SoundAnnouncePisti, "Announcer: Pişti"},
+	{SoundAnnounceJackPisti, "Announcer: Jack Pişti"},
+	{SoundAnnouncePlayerWins, "Announcer: You win"},
+	{SoundAnnounceCPUWins, "Announcer: CPU wins"},
+	{SoundAnnounceTie, "Announcer: Tie"},
+	{SoundAnnounceMilestone50, "Announcer: 50 points"},
+	{SoundAnnounceMilestone100, "Announcer: 100 points"},
+}
+
+// playMusicForTest starts the background music, or resumes it if it was paused.
+func playMusicForTest() {
+	soundMutex.Lock()
+	hasMusic := backgroundPlayer != nil
+	soundMutex.Unlock()
+	if hasMusic {
+		ResumeMusic()
+	} else {
+		PlayBackgroundMusic()
+	}
+}
+
+// volumeText formats a volume level for display.
+func volumeText(volume float64) string {
+	if volume == 0 {
+		return T("Off")
+	}
+	return fmt.Sprintf("%.0f%%", volume*100)
+}
+
+// showSoundTest opens a panel listing every sound with a button to play it,
+// so sound packs and volume settings can be checked without playing a game.
+func (ui *AppUI) showSoundTest() {
+	list := container.NewVBox()
+	addRow := func(label string, volume float64, play func()) {
+		button := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), play)
+		volumeLabel := widget.NewLabel(volumeText(volume))
+		list.Add(container.NewBorder(nil, nil, button, volumeLabel, widget.NewLabel(label)))
+	}
+	addRow(T("Music"), EffectiveVolume(SoundBackground), playMusicForTest)
+	for _, e := range adjustableEffects {
+		effect := e.effect
+		addRow(T(e.label), EffectiveVolume(effect), func() { PlaySound(effect) })
+	}
+	announcerOn := fyne.CurrentApp().Preferences().String(prefAnnouncerLanguage) != ""
+	for _, e := range announcementLabels {
+		effect := e.effect
+		volume := 0.0 // The announcer is either off or follows the effect volumes.
+		if announcerOn {
+			volume = EffectiveVolume(effect)
+		}
+		addRow(T(e.label), volume, func() { Announce(effect) })
+	}
+	d := dialog.NewCustom(T("Sound Test"), T("Close"), container.NewVScroll(list), ui.window)
+	d.Resize(fyne.NewSize(380, 480))
+	d.Show()
+}
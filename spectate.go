@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// spectateOptions is the match being watched: a strategy in the player's
+// seat against the CPU's level.
+type spectateOptions struct {
+	player, cpu engine.Strategy
+	speed       float64 // Multiplies the pace of the moves; 2 is twice as fast.
+	showHands   bool    // Show the CPU's hand face up as well as the player's.
+}
+
+// scaledPacing is the configured pacing sped up by speed.
+func scaledPacing(speed float64) engine.Pacing {
+	p := appConfig.AI.pacing()
+	scale := func(d time.Duration) time.Duration { return time.Duration(float64(d) / speed) }
+	return engine.Pacing{CPUDelay: scale(p.CPUDelay), CapturePause: scale(p.CapturePause), EndOfHandPause: scale(p.EndOfHandPause)}
+}
+
+// showSpectate asks which two levels should play each other, and how, then
+// starts the match in place of the current game.
+func (ui *AppUI) showSpectate() {
+	names := strategyNames()
+	playerSelect := widget.NewSelect(names, nil)
+	playerSelect.SetSelectedIndex(len(names) - 1)
+	cpuSelect := widget.NewSelect(names, nil)
+	cpuSelect.SetSelectedIndex(0)
+	speedSlider := widget.NewSlider(0.5, 4)
+	speedSlider.Step = 0.5
+	speedSlider.SetValue(1)
+	speedLabel := widget.NewLabel("")
+	speedSlider.OnChanged = func(v float64) { speedLabel.SetText(fmt.Sprintf("%gx", v)) }
+	speedSlider.OnChanged(speedSlider.Value)
+	handsCheck := widget.NewCheck(T("Show both hands"), nil)
+	handsCheck.SetChecked(true)
+	items := []*widget.FormItem{
+		widget.NewFormItem(T("Bottom"), playerSelect),
+		widget.NewFormItem(T("Top"), cpuSelect),
+		widget.NewFormItem(T("Speed"), speedSlider),
+		widget.NewFormItem("", speedLabel),
+		widget.NewFormItem("", handsCheck),
+	}
+	items[0].HintText = T("Plays in your seat. This ends the game in progress.")
+	d := dialog.NewForm(T("Watch AI vs AI"), T("Watch"), T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		player, _ := strategyNamed(playerSelect.Selected)
+		cpu, _ := strategyNamed(cpuSelect.Selected)
+		ui.startSpectating(spectateOptions{player: player, cpu: cpu, speed: speedSlider.Value, showHands: handsCheck.Checked})
+	}, ui.window)
+	d.Resize(fyne.NewSize(360, d.MinSize().Height))
+	d.Show()
+}
+
+// startSpectating starts a new game played by two strategies, which goes on
+// without the player until New Game or a loaded game ends it.
+func (ui *AppUI) startSpectating(opts spectateOptions) {
+	ui.stopSpectating()
+	ui.stopHotSeat()
+	ui.stopOnline()
+	ui.spectator = &opts
+	ui.levelSelect.SetSelected(opts.cpu.Name)
+	ui.loop.SetPacing(scaledPacing(opts.speed))
+	ui.loop.Spectate(&opts.player)
+	ui.revealBeforeSpectating = ui.view.revealCPU
+	ui.view.setRevealCPU(opts.showHands || ui.view.revealCPU)
+	ui.cancelTurnReminder()
+	if ui.startGameWith(ui.loop.StartGame) {
+		ui.view.info.Set(Tf("Watching %s (bottom) against %s (top).", opts.player.Name, opts.cpu.Name))
+	}
+}
+
+// stopSpectating gives the player's seat back, at the usual pace.
+func (ui *AppUI) stopSpectating() {
+	if ui.spectator == nil {
+		return
+	}
+	ui.spectator = nil
+	ui.loop.Spectate(nil)
+	ui.loop.SetPacing(gamePacing())
+	ui.view.setRevealCPU(ui.revealBeforeSpectating)
+}
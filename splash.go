@@ -0,0 +1,52 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showSplash displays a splash window with sound loading progress until the
+// audio is ready, then closes it and shows the main window. Drivers without
+// splash window support show the main window straight away.
+func showSplash(a fyne.App, main fyne.Window) {
+	drv, ok := a.Driver().(desktop.Driver)
+	if !ok {
+		main.Show()
+		return
+	}
+	splash := drv.CreateSplashWindow()
+	logo := canvas.NewImageFromResource(resourceIcon)
+	logo.FillMode = canvas.ImageFillContain
+	logo.SetMinSize(fyne.NewSize(96, 96))
+	progress := widget.NewProgressBar()
+	splash.SetContent(container.NewPadded(container.NewVBox(
+		logo,
+		widget.NewLabelWithStyle(T("Loading sounds..."), fyne.TextAlignCenter, fyne.TextStyle{}),
+		progress,
+	)))
+	splash.Resize(fyne.NewSize(260, 0))
+	splash.CenterOnScreen()
+	splash.Show()
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-AudioReady():
+				fyne.Do(func() {
+					main.Show() // Shown first so the app never runs without an open window.
+					splash.Close()
+				})
+				return
+			case <-ticker.C:
+				loaded, total := AudioLoadProgress()
+				fyne.Do(func() { progress.SetValue(float64(loaded) / float64(total)) })
+			}
+		}
+	}()
+}
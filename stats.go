@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+)
+
+// statsFile is the file in the data directory that holds the player's
+// lifetime statistics.
+const statsFile = "stats.json"
+
+// levelStats sums up the finished games played against one level.
+type levelStats struct {
+	Played     int `json:"played"`
+	Wins       int `json:"wins"`
+	Losses     int `json:"losses"`
+	Ties       int `json:"ties"`
+	Points     int `json:"points"` // The player's points in all games, for the average.
+	Pistis     int `json:"pistis"` // Jack Piştis included.
+	JackPistis int `json:"jack_pistis"`
+}
+
+// add counts the other games in as well.
+func (s *levelStats) add(o levelStats) {
+	s.Played += o.Played
+	s.Wins += o.Wins
+	s.Losses += o.Losses
+	s.Ties += o.Ties
+	s.Points += o.Points
+	s.Pistis += o.Pistis
+	s.JackPistis += o.JackPistis
+}
+
+// averagePoints returns the player's mean score per game.
+func (s levelStats) averagePoints() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+	return float64(s.Points) / float64(s.Played)
+}
+
+// statsStore is the player's profile of lifetime statistics. A nil store,
+// for platforms without a data directory, records nothing.
+type statsStore struct {
+	path   string
+	Levels map[string]*levelStats `json:"levels"` // By level name.
+}
+
+// loadStats reads the statistics in the data directory.
+func loadStats() *statsStore {
+	path := dataPath(statsFile)
+	if path == "" {
+		return nil
+	}
+	s := &statsStore{path: path, Levels: make(map[string]*levelStats)}
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("ERROR: Failed to read the statistics: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, s); err != nil {
+			log.Printf("ERROR: Failed to read the statistics in %s: %v", path, err)
+		}
+	}
+	if s.Levels == nil {
+		s.Levels = make(map[string]*levelStats) // The file said "levels": null.
+	}
+	return s
+}
+
+// record counts the finished game in and saves the statistics.
+func (s *statsStore) record(c *engine.Casino) {
+	if s == nil {
+		return
+	}
+	snap := c.Snapshot()
+	name := levelName(snap.Level)
+	ls := s.Levels[name]
+	if ls == nil {
+		ls = &levelStats{}
+		s.Levels[name] = ls
+	}
+	ls.Played++
+	switch {
+	case snap.PlayerPoints > snap.CPUPoints:
+		ls.Wins++
+	case snap.PlayerPoints < snap.CPUPoints:
+		ls.Losses++
+	default:
+		ls.Ties++
+	}
+	ls.Points += snap.PlayerPoints
+	ls.Pistis += playerPistis(snap, false)
+	ls.JackPistis += playerPistis(snap, true)
+	if err := s.save(); err != nil {
+		log.Printf("ERROR: Failed to save the statistics: %v", err)
+	}
+}
+
+// wins returns the games won at the level, 0 without a store.
+func (s *statsStore) wins(level engine.GameLevel) int {
+	if s == nil || s.Levels[levelName(level)] == nil {
+		return 0
+	}
+	return s.Levels[levelName(level)].Wins
+}
+
+// save writes the statistics.
+func (s *statsStore) save() error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0o644)
+}
+
+// rows returns the statistics of each level played, in the order of the
+// levels, and their total last.
+func (s *statsStore) rows() (names []string, rows []levelStats) {
+	var total levelStats
+	for _, st := range engine.Strategies() {
+		if ls := s.Levels[st.Name]; ls != nil && ls.Played > 0 {
+			names = append(names, st.Name)
+			rows = append(rows, *ls)
+			total.add(*ls)
+		}
+	}
+	if len(rows) > 1 {
+		names = append(names, T("All"))
+		rows = append(rows, total)
+	}
+	return names, rows
+}
+
+// showStats shows the lifetime statistics, a column per level played.
+func (ui *AppUI) showStats() {
+	if ui.stats == nil {
+		dialog.ShowInformation(T("Statistics"), T("Statistics need a user data directory, which this device does not have."), ui.window)
+		return
+	}
+	names, rows := ui.stats.rows()
+	if len(rows) == 0 {
+		dialog.ShowInformation(T("Statistics"), T("No games finished yet. Games watched, practiced or analysed are not counted."), ui.window)
+		return
+	}
+	// A column per level keeps the table narrow enough for a phone.
+	grid := container.NewGridWithColumns(len(rows) + 1)
+	grid.Add(widget.NewLabel(""))
+	for _, name := range names {
+		grid.Add(widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	lines := []struct {
+		label string
+		value func(levelStats) string
+	}{
+		{"Played", func(r levelStats) string { return fmt.Sprint(r.Played) }},
+		{"Won", func(r levelStats) string { return fmt.Sprint(r.Wins) }},
+		{"Lost", func(r levelStats) string { return fmt.Sprint(r.Losses) }},
+		{"Tied", func(r levelStats) string { return fmt.Sprint(r.Ties) }},
+		{"Piştis", func(r levelStats) string { return fmt.Sprint(r.Pistis) }},
+		{"Jack Piştis", func(r levelStats) string { return fmt.Sprint(r.JackPistis) }},
+		{"Average score", func(r levelStats) string { return fmt.Sprintf("%.1f", r.averagePoints()) }},
+	}
+	for _, line := range lines {
+		grid.Add(widget.NewLabel(T(line.label)))
+		for _, r := range rows {
+			grid.Add(widget.NewLabelWithStyle(line.value(r), fyne.TextAlignCenter, fyne.TextStyle{}))
+		}
+	}
+	dialog.ShowCustom(T("Statistics"), T("Close"), grid, ui.window)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+
+	"fyne.io/fyne/v2"
+
+	"pishti/engine"
+)
+
+// Phones and tablets may stop an app for good while it is in the
+// background, so a game in progress is saved whenever the app leaves the
+// screen and loaded again when it is next opened. A desktop game ends with
+// its window, as the player asked for.
+
+// suspendGame keeps the game in progress in the preferences, or clears the
+// one kept earlier if there is no game to keep. Only games against the CPU
+// are kept; the deals before one of a match are not.
+func (ui *AppUI) suspendGame() {
+	prefs := fyne.CurrentApp().Preferences()
+	state := ui.casino.State()
+	if state == engine.StateNotStarted || state == engine.StateGameOver ||
+		ui.spectator != nil || ui.hotSeat != nil || ui.remote != nil {
+		prefs.RemoveValue(prefSuspendedGame)
+		return
+	}
+	data, err := ui.casino.Save()
+	if err != nil {
+		log.Printf("ERROR: Failed to save the game for later: %v", err)
+		return
+	}
+	prefs.SetString(prefSuspendedGame, string(data))
+}
+
+// resumeSuspendedGame loads the game kept by suspendGame, unless a game was
+// started already, as from the command line. The kept game is used once.
+func (ui *AppUI) resumeSuspendedGame() {
+	prefs := fyne.CurrentApp().Preferences()
+	data := prefs.String(prefSuspendedGame)
+	prefs.RemoveValue(prefSuspendedGame)
+	if data == "" || ui.casino.State() != engine.StateNotStarted {
+		return
+	}
+	if err := ui.loadGame([]byte(data)); err != nil {
+		log.Printf("ERROR: Failed to resume the saved game: %v", err)
+		ui.resetGameUI()
+		return
+	}
+	ui.view.info.Set(T("Your game was restored where you left it."))
+}
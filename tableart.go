@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The table's background, the card back and the frames around the hands
+// each come in bundled designs besides the classic art: an image per design
+// in the folder of its part, named after it, such as
+// assets/ui/frames/Gold.svg. A skin's own card back only shows with the
+// classic back.
+
+// classicDesign is the label of the classic art among the designs.
+const classicDesign = "Classic"
+
+// tablePart is a part of the table's look that a design can be picked for.
+type tablePart struct {
+	pref string // Keeps the name of the chosen design; "" for the classic one.
+	dir  string // The folder of the bundled designs.
+}
+
+var (
+	backgroundPart = tablePart{pref: prefTableBackground, dir: "assets/ui/backgrounds"}
+	cardBackPart   = tablePart{pref: prefCardBack, dir: "assets/ui/backs"}
+	framePart      = tablePart{pref: prefFrameStyle, dir: "assets/ui/frames"}
+	tableParts     = []tablePart{backgroundPart, cardBackPart, framePart}
+)
+
+// tableDesigns holds the chosen design of each part, "" for the classic
+// art. It is only used on the UI goroutine.
+var tableDesigns = map[tablePart]string{}
+
+// designs returns the names of the part's bundled designs, sorted.
+func (p tablePart) designs() []string {
+	entries, err := fs.ReadDir(assetFS, p.dir)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+		if !e.IsDir() && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// imagePath returns the asset path of the chosen design's image, preferring
+// a vector one as cardAssetPath does, or classic for the classic art.
+func (p tablePart) imagePath(classic string) string {
+	name := tableDesigns[p]
+	if name == "" {
+		return classic
+	}
+	svg := p.dir + "/" + name + ".svg"
+	if _, err := fs.Stat(assetFS, svg); err == nil {
+		return svg
+	}
+	return p.dir + "/" + name + ".png"
+}
+
+// useTableDesign picks the named design for the part, or the classic art for
+// "". A design that is not bundled, as one of an older release, is logged
+// and the classic art is kept. The images are loaded again by loadResources
+// or reloadImages.
+func useTableDesign(p tablePart, name string) {
+	delete(tableDesigns, p)
+	if name == "" {
+		return
+	}
+	for _, design := range p.designs() {
+		if design == name {
+			tableDesigns[p] = name
+			return
+		}
+	}
+	log.Printf("ERROR: No design %q in %s; the classic one is used", name, p.dir)
+}
@@ -9,13 +9,28 @@ import (
 // transparentTheme is a custom theme that makes specific widgets transparent.
 type transparentTheme struct {
 	fyne.Theme
+	touch bool    // Enlarge the controls for fingers; see Size.
+	scale float32 // How much the window is scaled from its base size; see scaledLayout.
 }
 
-// newTransparentTheme wraps the provided theme.
-func newTransparentTheme(t fyne.Theme) fyne.Theme {
-	return &transparentTheme{Theme: t}
+// newTransparentTheme wraps the provided theme, with larger controls if
+// touch is set.
+func newTransparentTheme(t fyne.Theme, touch bool) fyne.Theme {
+	return &transparentTheme{Theme: t, touch: touch, scale: 1}
 }
 
+// withScale returns a copy of the theme with its sizes, text included,
+// multiplied by scale.
+func (t *transparentTheme) withScale(scale float32) fyne.Theme {
+	scaled := *t
+	scaled.scale = scale
+	return &scaled
+}
+
+// touchSizeScale is how much larger the padding inside buttons and selects
+// and their icons are on touch screens.
+const touchSizeScale = 1.5
+
 // Color overrides the default color for specific widget states.
 func (t *transparentTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
 	// Make button and input backgrounds transparent.
@@ -56,3 +71,13 @@ func (t *transparentTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVar
 func (t *transparentTheme) Variant() fyne.ThemeVariant {
 	return theme.VariantDark
 }
+
+// Size returns the base theme's sizes at the window's scale, with the tap
+// targets of buttons and selects enlarged on touch screens.
+func (t *transparentTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := t.Theme.Size(name) * t.scale
+	if t.touch && (name == theme.SizeNameInnerPadding || name == theme.SizeNameInlineIcon) {
+		return size * touchSizeScale
+	}
+	return size
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+
+	"pishti/engine"
+)
+
+const tickerLines = 3 // Number of recent events shown above the player's hand.
+
+// captureTicker shows the last few capture events, newest at the bottom,
+// with older lines faded out so the list reads as scrolling upwards.
+type captureTicker struct {
+	lines   []*canvas.Text
+	content *fyne.Container
+}
+
+// newCaptureTicker creates an empty ticker.
+func newCaptureTicker() *captureTicker {
+	t := &captureTicker{content: container.NewVBox()}
+	for i := 0; i < tickerLines; i++ {
+		line := canvas.NewText("", color.White)
+		line.TextSize = 11
+		line.Alignment = fyne.TextAlignCenter
+		t.lines = append(t.lines, line)
+		t.content.Add(line)
+	}
+	return t
+}
+
+// update redraws the ticker from the given events (oldest first).
+func (t *captureTicker) update(events []engine.CaptureEvent) {
+	// Align the newest event with the bottom line.
+	offset := tickerLines - len(events)
+	for i, line := range t.lines {
+		text := ""
+		if idx := i - offset; idx >= 0 {
+			text = formatCaptureEvent(events[idx])
+		}
+		if line.Text == text {
+			continue
+		}
+		line.Text = text
+		// Older lines are drawn more transparent than the newest one.
+		alpha := uint8(255 * (i + 1) / tickerLines)
+		line.Color = color.NRGBA{R: 255, G: 255, B: 255, A: alpha}
+		line.Refresh()
+	}
+}
+
+// formatCaptureEvent renders a capture as a short ticker line.
+func formatCaptureEvent(e engine.CaptureEvent) string {
+	// Each side has its own lines, as a language may word them differently.
+	if e.By == engine.CPU {
+		switch {
+		case e.Pisti:
+			return Tf("CPU: Pişti! +%d", e.Points)
+		case e.Final:
+			return Tf("CPU took the last %d cards (+%d)", e.Cards, e.Points)
+		default:
+			return Tf("CPU captured %d cards (+%d)", e.Cards, e.Points)
+		}
+	}
+	switch {
+	case e.Pisti:
+		return Tf("You: Pişti! +%d", e.Points)
+	case e.Final:
+		return Tf("You took the last %d cards (+%d)", e.Cards, e.Points)
+	default:
+		return Tf("You captured %d cards (+%d)", e.Cards, e.Points)
+	}
+}
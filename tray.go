@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	"pishti/engine"
+)
+
+// trayState tracks the system tray menu so it can reflect whose move it is.
+type trayState struct {
+	app        desktop.App
+	menu       *fyne.Menu
+	statusItem *fyne.MenuItem
+	icon       fyne.Resource
+	badgeIcon  fyne.Resource // Icon with a "your move" badge drawn on it.
+	hidden     bool          // Whether the window is currently minimized to the tray.
+	badged     bool
+}
+
+// setupSystemTray installs the tray icon and menu on desktop platforms.
+// On other platforms it does nothing and ui.tray stays nil.
+func (ui *AppUI) setupSystemTray(a fyne.App) {
+	desk, ok := a.(desktop.App)
+	if !ok {
+		return
+	}
+	t := &trayState{app: desk, icon: resourceIcon, badgeIcon: badgedIcon(resourceIcon)}
+	t.statusItem = fyne.NewMenuItem(T("No move pending"), nil)
+	t.statusItem.Disabled = true
+	showItem := fyne.NewMenuItem(T("Show Pishti"), ui.restoreFromTray)
+	hideItem := fyne.NewMenuItem(T("Minimize to Tray"), ui.minimizeToTray)
+	t.menu = fyne.NewMenu("Pishti", t.statusItem, fyne.NewMenuItemSeparator(), showItem, hideItem)
+	desk.SetSystemTrayMenu(t.menu)
+	desk.SetSystemTrayIcon(t.icon)
+	ui.tray = t
+}
+
+// minimizeToTray hides the window; it can be restored from the tray menu.
+func (ui *AppUI) minimizeToTray() {
+	if ui.tray == nil {
+		return
+	}
+	ui.tray.hidden = true
+	ui.window.Hide()
+	ui.updateTrayBadge()
+}
+
+// restoreFromTray shows the window again and clears the badge.
+func (ui *AppUI) restoreFromTray() {
+	if ui.tray == nil {
+		return
+	}
+	ui.tray.hidden = false
+	ui.window.Show()
+	ui.window.RequestFocus()
+	ui.updateTrayBadge()
+}
+
+// updateTrayBadge shows the badge while the window is in the tray and the
+// player is expected to move.
+func (ui *AppUI) updateTrayBadge() {
+	t := ui.tray
+	if t == nil {
+		return
+	}
+	badged := t.hidden && ui.casino.State() == engine.StatePlayerTurn && ui.spectator == nil
+	if badged == t.badged {
+		return // Avoid rebuilding the tray menu when nothing changed.
+	}
+	t.badged = badged
+	if badged {
+		t.statusItem.Label = T("Your move!")
+		t.app.SetSystemTrayIcon(t.badgeIcon)
+	} else {
+		t.statusItem.Label = T("No move pending")
+		t.app.SetSystemTrayIcon(t.icon)
+	}
+	t.menu.Refresh()
+}
+
+// badgedIcon draws a red notification dot in the top-right corner of the icon.
+// If the icon cannot be decoded, the original is returned unchanged.
+func badgedIcon(icon fyne.Resource) fyne.Resource {
+	src, err := png.Decode(bytes.NewReader(icon.Content()))
+	if err != nil {
+		log.Printf("ERROR: Failed to decode tray icon: %v", err)
+		return icon
+	}
+	bounds := src.Bounds()
+	img := image.NewNRGBA(bounds)
+	draw.Draw(img, bounds, src, bounds.Min, draw.Src)
+	radius := bounds.Dx() / 5
+	cx, cy := bounds.Max.X-radius-1, bounds.Min.Y+radius+1
+	badgeColor := color.NRGBA{R: 220, G: 30, B: 30, A: 255}
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= radius*radius {
+				img.Set(x, y, badgeColor)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		log.Printf("ERROR: Failed to encode tray badge icon: %v", err)
+		return icon
+	}
+	return fyne.NewStaticResource("icon_badge.png", buf.Bytes())
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"pishti/engine"
+)
+
+// textClient plays Pişti in a terminal, reading commands line by line, so
+// the game can be played over SSH or on a machine without a display. It
+// drives the casino directly: there is nothing to animate, so the CPU
+// replies at once.
+type textClient struct {
+	casino   *engine.Casino
+	in       *bufio.Scanner
+	out      io.Writer
+	moves    int // Moves already shown.
+	captures int // Captures already shown.
+}
+
+// runTUI plays games in the terminal until the player quits or the input
+// ends, returning any error reading it. --level and --seed pick the first
+// game as in the window.
+func runTUI(opts launchOptions, in io.Reader, out io.Writer) error {
+	t := &textClient{casino: engine.NewCasino(nil, nil), in: bufio.NewScanner(in), out: out}
+	if !t.casino.SetVariant(appConfig.Rules.Variant) {
+		log.Printf("ERROR: Unknown rule variant %q in config file", appConfig.Rules.Variant)
+	}
+	fmt.Fprintln(out, "Welcome to Pishti! Type a card's number to play it, or \"help\" for commands.")
+	for first := true; ; first = false {
+		s, ok := t.chooseLevel(opts.level)
+		if !ok {
+			return t.in.Err()
+		}
+		c := t.casino
+		c.ResetGame() // Starting again clears the level, so clear first.
+		c.SetLevel(s.Level)
+		if first && opts.seedSet {
+			c.StartGameWithSeed(opts.seed)
+		} else {
+			c.StartGame()
+		}
+		t.moves, t.captures = 0, 0
+		fmt.Fprintf(out, "\nNew game at %s level, seed %d.\n", s.Name, c.Seed())
+		if !t.play() {
+			return t.in.Err()
+		}
+		if answer, ok := t.prompt("Play again? (y/n) "); !ok || !strings.HasPrefix(strings.ToLower(answer), "y") {
+			return t.in.Err()
+		}
+	}
+}
+
+// prompt asks for a line of input. It reports false once the input ends.
+func (t *textClient) prompt(question string) (string, bool) {
+	fmt.Fprint(t.out, question)
+	if !t.in.Scan() {
+		fmt.Fprintln(t.out)
+		return "", false
+	}
+	return strings.TrimSpace(t.in.Text()), true
+}
+
+// chooseLevel returns the named level, or asks for one if name is empty.
+func (t *textClient) chooseLevel(name string) (engine.Strategy, bool) {
+	if s, ok := strategyNamed(name); ok {
+		return s, true
+	}
+	strategies := engine.Strategies()
+	for {
+		fmt.Fprintln(t.out, "\nLevels:")
+		for i, s := range strategies {
+			fmt.Fprintf(t.out, "  %d) %s - %s\n", i+1, s.Name, s.Description)
+		}
+		answer, ok := t.prompt("Choose a level: ")
+		if !ok || answer == "q" || answer == "quit" {
+			return engine.Strategy{}, false
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(strategies) {
+			return strategies[n-1], true
+		}
+		if s, ok := strategyNamed(answer); ok {
+			return s, true
+		}
+		fmt.Fprintf(t.out, "No level %q.\n", answer)
+	}
+}
+
+// play runs one game to the end. It reports false if the player quit.
+func (t *textClient) play() bool {
+	c := t.casino
+	for {
+		advanceGame(c, t.report)
+		t.report()
+		if c.State() == engine.StateGameOver {
+			t.showResult()
+			return true
+		}
+		t.showTable()
+		answer, ok := t.prompt("Your move: ")
+		if !ok {
+			return false
+		}
+		switch strings.ToLower(answer) {
+		case "q", "quit":
+			return false
+		case "h", "help", "?":
+			fmt.Fprintln(t.out, "1-4 - play the card in that slot\n"+
+				"u, undo - take back your last card and the CPU's reply\n"+
+				"r, redo - play the undone cards again\n"+
+				"q, quit - leave the game")
+		case "u", "undo":
+			if !c.Undo() {
+				fmt.Fprintln(t.out, "Nothing to undo.")
+			}
+			t.caughtUp()
+		case "r", "redo":
+			if !c.Redo() {
+				fmt.Fprintln(t.out, "Nothing to redo.")
+			}
+			t.caughtUp()
+		default:
+			slot, err := strconv.Atoi(answer)
+			if err != nil || slot < 1 || slot > engine.HandSize {
+				fmt.Fprintf(t.out, "Type a card's number from 1 to %d, or \"help\".\n", engine.HandSize)
+				continue
+			}
+			if err := c.PlayerPlays(slot - 1); err != nil {
+				fmt.Fprintf(t.out, "You cannot play slot %d.\n", slot)
+			}
+		}
+	}
+}
+
+// advanceGame carries out the CPU's moves, captures and deals until it is
+// the player's turn or the game is over, calling report after each step if
+// it is not nil. Clients without animations, such as the terminal and the
+// API server, use it to answer a move at once.
+func advanceGame(c *engine.Casino, report func()) {
+	for steps := 0; steps < 4*engine.DeckSize; steps++ {
+		err := c.CheckEndOfHand()
+		switch c.State() {
+		case engine.StateCPUTurn:
+			err = c.CPUPlays()
+		case engine.StatePileCaptured:
+			c.FinalizeCapture()
+		case engine.StatePlayerTurn:
+			if !c.IsHandFinished() {
+				return
+			}
+		default:
+			return
+		}
+		if err != nil {
+			log.Printf("ERROR: Game step failed: %v", err)
+		}
+		if report != nil {
+			report()
+		}
+	}
+}
+
+// report prints the moves and captures made since the last report.
+func (t *textClient) report() {
+	c := t.casino
+	for _, e := range c.Events() {
+		if e.Kind == engine.EventDeal {
+			fmt.Fprintln(t.out, "A new hand is dealt.")
+		}
+	}
+	moves := c.Snapshot().Moves
+	for _, m := range moves[min(t.moves, len(moves)):] {
+		if m.By == engine.Player {
+			fmt.Fprintf(t.out, "You play the %s.\n", cardName(m.Card))
+		} else {
+			fmt.Fprintf(t.out, "CPU plays the %s.\n", cardName(m.Card))
+		}
+	}
+	t.moves = len(moves)
+	captures, total := c.CapturesSince(t.captures)
+	for _, e := range captures {
+		fmt.Fprintln(t.out, formatCaptureEvent(e))
+	}
+	t.captures = total
+	if msg := c.InitialPileCaptureMessage(); msg != "" {
+		fmt.Fprintln(t.out, msg)
+		c.ClearInitialPileCaptureMessage()
+	}
+}
+
+// caughtUp marks everything in the game as shown, after an undo or redo
+// changed it all at once.
+func (t *textClient) caughtUp() {
+	c := t.casino
+	c.Events()
+	t.moves = len(c.Snapshot().Moves)
+	_, t.captures = c.CapturesSince(0)
+}
+
+// showTable prints the scores, the table and both hands.
+func (t *textClient) showTable() {
+	s := t.casino.Snapshot()
+	fmt.Fprintf(t.out, "\nYou %d - %d CPU, %d cards left in the deck\n", s.PlayerPoints, s.CPUPoints, engine.DeckSize-s.CardsDealt)
+	cpu := make([]string, len(s.CPUHand))
+	for i, card := range s.CPUHand {
+		cpu[i] = "[  ]"
+		if card != nil {
+			cpu[i] = "[##]"
+		}
+	}
+	fmt.Fprintf(t.out, "CPU:   %s\n", strings.Join(cpu, " "))
+	switch n := len(s.Table); {
+	case n == 0:
+		fmt.Fprintln(t.out, "Table: empty")
+	case t.casino.IsInitialPile():
+		fmt.Fprintf(t.out, "Table: %s on %d face-down cards\n", cardName(s.Table[n-1]), n-1)
+	case n == 1:
+		fmt.Fprintf(t.out, "Table: %s\n", cardName(s.Table[0]))
+	default:
+		fmt.Fprintf(t.out, "Table: %s on the %s, %d cards\n", cardName(s.Table[n-1]), cardName(s.Table[n-2]), n)
+	}
+	hand := make([]string, 0, len(s.PlayerHand))
+	for i, card := range s.PlayerHand {
+		if card != nil {
+			hand = append(hand, fmt.Sprintf("%d) %s", i+1, cardName(card)))
+		}
+	}
+	fmt.Fprintf(t.out, "You:   %s\n", strings.Join(hand, "   "))
+}
+
+// showResult prints the final score.
+func (t *textClient) showResult() {
+	player, cpu := t.casino.PlayerPoints(), t.casino.CPUPoints()
+	switch {
+	case player > cpu:
+		fmt.Fprintf(t.out, "\nYou Win! Final Score: You %d - %d CPU\n", player, cpu)
+	case cpu > player:
+		fmt.Fprintf(t.out, "\nCPU Wins! Final Score: You %d - %d CPU\n", player, cpu)
+	default:
+		fmt.Fprintf(t.out, "\nIt's a Tie! Final Score: You %d - %d CPU\n", player, cpu)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTUIPlaysAGame(t *testing.T) {
+	appConfig = defaultConfig()
+	// Always play the first card held; bad and extra commands are ignored.
+	input := "9\nhelp\nu\n" + strings.Repeat("1\n2\n3\n4\n", 30) + "n\n"
+	var out strings.Builder
+	opts := launchOptions{level: "Beginner", seed: 7, seedSet: true}
+	if err := runTUI(opts, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("runTUI: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"seed 7", "CPU plays the", "A new hand is dealt.", "Final Score: You"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTUICardNames(t *testing.T) {
+	appConfig = defaultConfig()
+	setCardLanguage("tr-TR")
+	t.Cleanup(func() { setCardLanguage("") })
+	input := strings.Repeat("1\n2\n3\n4\n", 30) + "n\n"
+	var out strings.Builder
+	opts := launchOptions{level: "Beginner", seed: 7, seedSet: true}
+	if err := runTUI(opts, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("runTUI: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, " of ") {
+		t.Errorf("English card names in Turkish output:\n%s", got)
+	}
+	for _, want := range []string{"Kupa", "Valesi"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCLIFlag(t *testing.T) {
+	opts, err := parseLaunchOptions([]string{"--cli", "--level", "advanced"})
+	if err != nil || !opts.tui {
+		t.Fatalf("--cli gave %+v, %v; want the terminal game", opts, err)
+	}
+}
@@ -0,0 +1,1303 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io/fs"
+	"maps"
+	"math/rand"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"pishti/engine"
+	"pishti/online"
+)
+
+// stepClock is a Clock whose pending calls only run when the test fires
+// them, however long they were scheduled for.
+type stepClock struct {
+	mu      sync.Mutex
+	pending []*stepTimer
+}
+
+// stepTimer is a call waiting on a stepClock.
+type stepTimer struct {
+	clock   *stepClock
+	f       func()
+	stopped bool
+}
+
+func (c *stepClock) Now() time.Time {
+	return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (c *stepClock) AfterFunc(_ time.Duration, f func()) engine.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &stepTimer{clock: c, f: f}
+	c.pending = append(c.pending, t)
+	return t
+}
+
+func (t *stepTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	pending := !t.stopped
+	t.stopped = true
+	return pending
+}
+
+// fire runs the calls still pending and reports whether there were any.
+func (c *stepClock) fire() bool {
+	c.mu.Lock()
+	due := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	fired := false
+	for _, t := range due {
+		if !t.Stop() {
+			continue
+		}
+		t.f()
+		fired = true
+	}
+	return fired
+}
+
+// testGame is a game screen in a test window, driven by its stepClock.
+type testGame struct {
+	t      *testing.T
+	ui     *AppUI
+	clock  *stepClock
+	pacing engine.Pacing
+}
+
+// newTestGame builds the full game screen around a casino with a fixed
+// seed, in a test app that draws nothing.
+func newTestGame(t *testing.T) *testGame {
+	t.Helper()
+	test.NewTempApp(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir()) // Achievements are recorded there.
+	loadResources(false)
+	appConfig = defaultConfig()
+	appConfig.UI.TurnReminder = 0 // Its timer would outlive the test.
+	g := &testGame{t: t, clock: &stepClock{}, pacing: appConfig.AI.pacing()}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	casino := engine.NewCasino(rand.NewSource(1), g.clock)
+	g.ui = newAppUI(ctx, test.NewTempWindow(t, nil), casino, g.pacing)
+	g.ui.window.SetContent(g.ui.buildLayout())
+	g.ui.applySettings()
+	g.ui.updateUI()
+	return g
+}
+
+// settle runs the CPU's paced steps until the game waits for the player or
+// is over, including the screen updates that follow each step.
+func (g *testGame) settle() {
+	g.t.Helper()
+	for i := 0; g.clock.fire(); i++ {
+		// A command queues behind the step just posted, so once it returns
+		// the step and its screen update are done.
+		g.ui.loop.SetPacing(g.pacing)
+		if i > 100 {
+			g.t.Fatal("the engine loop never stopped")
+		}
+	}
+}
+
+// selectLevel picks a level in the top bar.
+func (g *testGame) selectLevel(name string) {
+	g.ui.levelSelect.SetSelected(name)
+}
+
+// tap taps a widget and lets the CPU reply.
+func (g *testGame) tap(w fyne.Tappable) {
+	test.Tap(w)
+	g.settle()
+}
+
+// info returns the message under the table.
+func (g *testGame) info() string {
+	return g.ui.infoLabel.Text
+}
+
+// playableSlot returns the first slot of the player's hand that can be
+// played, or -1.
+func (g *testGame) playableSlot() int {
+	for i := range g.ui.playerCardWidgets {
+		if g.ui.canPlayCard(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkScreen fails the test if the widgets disagree with the engine.
+func (g *testGame) checkScreen() {
+	g.t.Helper()
+	c := g.ui.casino
+	for i, card := range c.PlayerHand() {
+		var want fyne.Resource
+		if card != nil {
+			want = getCardResource(card)
+		}
+		if got := g.ui.playerCardWidgets[i].Resource; got != want {
+			g.t.Fatalf("player slot %d shows %v, want %v", i, got, want)
+		}
+	}
+	for i, card := range c.CPUHand() {
+		if shown := g.ui.cpuCardWidgets[i].Resource != nil; shown != (card != nil) {
+			g.t.Fatalf("CPU slot %d drawn: %v, holds a card: %v", i, shown, card != nil)
+		}
+	}
+	wantScores := []string{
+		fmt.Sprintf("Your Score: %d", c.PlayerPoints()),
+		fmt.Sprintf("CPU Score: %d", c.CPUPoints()),
+	}
+	for i, label := range g.scoreLabels() {
+		if label.Text != wantScores[i] {
+			g.t.Fatalf("score label reads %q, want %q", label.Text, wantScores[i])
+		}
+	}
+}
+
+// scoreLabels finds the player's and the CPU's score labels on screen.
+func (g *testGame) scoreLabels() []*widget.Label {
+	var labels []*widget.Label
+	var walk func(fyne.CanvasObject)
+	walk = func(o fyne.CanvasObject) {
+		switch o := o.(type) {
+		case *widget.Label:
+			if strings.HasPrefix(o.Text, "Your Score: ") || strings.HasPrefix(o.Text, "CPU Score: ") {
+				labels = append(labels, o)
+			}
+		case *fyne.Container:
+			for _, child := range o.Objects {
+				walk(child)
+			}
+		}
+	}
+	walk(g.ui.window.Content())
+	if len(labels) != 2 {
+		g.t.Fatalf("found %d score labels, want 2", len(labels))
+	}
+	return labels
+}
+
+// playToEnd plays the first playable card until the game is over, failing
+// if the player is ever left without a card to play.
+func (g *testGame) playToEnd() {
+	g.t.Helper()
+	for moves := 0; g.ui.casino.State() != engine.StateGameOver; moves++ {
+		slot := g.playableSlot()
+		if slot < 0 {
+			g.t.Fatalf("no card can be played in state %v, loop busy: %v", g.ui.casino.State(), g.ui.loop.Busy())
+		}
+		if moves > 60 {
+			g.t.Fatal("the game never ended")
+		}
+		g.tap(g.ui.playerCardWidgets[slot])
+		g.checkScreen()
+	}
+}
+
+func TestStartNeedsALevel(t *testing.T) {
+	g := newTestGame(t)
+	g.tap(g.ui.startButton)
+	if got := g.ui.casino.State(); got != engine.StateNotStarted {
+		t.Fatalf("state after Start without a level = %v, want %v", got, engine.StateNotStarted)
+	}
+	if got, want := g.info(), "Please select a level first!"; got != want {
+		t.Fatalf("info = %q, want %q", got, want)
+	}
+}
+
+func TestPlayFullGames(t *testing.T) {
+	for _, s := range engine.Strategies() {
+		t.Run(s.Name, func(t *testing.T) {
+			g := newTestGame(t)
+			g.selectLevel(s.Name)
+			g.tap(g.ui.startButton)
+			if g.ui.startButton.Text != "New Game" || !g.ui.levelSelect.Disabled() {
+				t.Fatalf("top bar not switched to a running game: start %q, level select disabled %v",
+					g.ui.startButton.Text, g.ui.levelSelect.Disabled())
+			}
+			g.checkScreen()
+			g.playToEnd()
+			if g.ui.replayButton.Hidden || !g.ui.undoButton.Hidden {
+				t.Fatal("replay button not in the undo button's place after the game")
+			}
+			if !strings.Contains(g.info(), "Final Score") {
+				t.Fatalf("info after the game = %q, want the final score", g.info())
+			}
+			seed := g.ui.casino.Seed()
+			g.tap(g.ui.replayButton)
+			if g.ui.casino.Seed() != seed || g.ui.casino.State() == engine.StateGameOver {
+				t.Fatalf("replay dealt seed %d in state %v, want seed %d in play", g.ui.casino.Seed(), g.ui.casino.State(), seed)
+			}
+			g.playToEnd()
+		})
+	}
+}
+
+func TestUndoButton(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	if !g.ui.undoButton.Disabled() {
+		t.Fatal("undo enabled before any move")
+	}
+	before := g.ui.casino.PlayerHand()
+	slot := g.playableSlot()
+	g.tap(g.ui.playerCardWidgets[slot])
+	if g.ui.undoButton.Disabled() {
+		t.Fatal("undo disabled after a move")
+	}
+	g.tap(g.ui.undoButton)
+	g.checkScreen()
+	if got := g.ui.casino.PlayerHand(); got[slot] != before[slot] {
+		t.Fatalf("slot %d holds %v after undo, want %v", slot, got[slot], before[slot])
+	}
+	if g.playableSlot() != 0 {
+		t.Fatal("cards not playable again after undo")
+	}
+	g.playToEnd()
+}
+
+func TestOverlayExport(t *testing.T) {
+	g := newTestGame(t)
+	path := filepath.Join(t.TempDir(), "overlay.json")
+	g.ui.overlay = newOverlayExport(path)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st overlayState
+	if err := json.Unmarshal(b, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Level != "Beginner" || st.PlayerCards != 3 || st.CPUCards != 3 || st.LastEvent == "" {
+		t.Fatalf("overlay after the first round = %+v", st)
+	}
+	if st.PlayerPoints != g.ui.casino.PlayerPoints() || st.CPUPoints != g.ui.casino.CPUPoints() {
+		t.Fatalf("overlay scores %d-%d, game %d-%d", st.PlayerPoints, st.CPUPoints, g.ui.casino.PlayerPoints(), g.ui.casino.CPUPoints())
+	}
+}
+
+func TestEventHooks(t *testing.T) {
+	dir := t.TempDir()
+	script := `
+[[hook]]
+event = "card played"
+by = "CPU"
+message = "CPU answered, {player_score} to {cpu_score}"
+
+[[hook]]
+event = "no such event"
+log = "never"
+`
+	if err := os.WriteFile(filepath.Join(dir, "test.toml"), []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := newTestGame(t)
+	g.ui.hooks = loadEventHooks(dir)
+	if g.ui.hooks == nil || len(g.ui.hooks.rules) != 1 {
+		t.Fatalf("loaded %+v, want the one valid hook", g.ui.hooks)
+	}
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	want := fmt.Sprintf("CPU answered, %d to %d", g.ui.casino.PlayerPoints(), g.ui.casino.CPUPoints())
+	if got := g.info(); got != want {
+		t.Fatalf("info = %q, want %q", got, want)
+	}
+
+	// A Lua script sees the same events, inside its sandbox.
+	dir = t.TempDir()
+	scripts := map[string]string{
+		"runaway.lua": `pishti.on("card played", function(e) while true do end end)`,
+		"answer.lua": `
+local sandboxed = os == nil and io == nil and debug == nil and dofile == nil and require == nil
+pishti.on("card played", function(e)
+	if e.by == "CPU" then
+		pishti.message("Lua: CPU played from slot " .. e.slot .. ", " .. e.player_score .. " to " .. e.cpu_score .. (sandboxed and "" or ", unsandboxed"))
+	end
+end)`,
+		"escape.lua": `os.execute("echo escaped")`,
+		"huge.lua":   `local s = string.rep("x", 1e9)`,
+		"wrap.lua":   `local s = ("aa"):rep(2^62)`,
+		"nan.lua":    `local s = ("aa"):rep(0/0)`,
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.ui.hooks = loadEventHooks(dir)
+	if g.ui.hooks == nil || len(g.ui.hooks.scripts) != 2 || len(g.ui.hooks.rules) != 0 {
+		t.Fatalf("loaded %+v, want the runaway and answer scripts", g.ui.hooks)
+	}
+	g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	moves := g.ui.casino.Snapshot().Moves
+	want = fmt.Sprintf("Lua: CPU played from slot %d, %d to %d", moves[len(moves)-1].Slot, g.ui.casino.PlayerPoints(), g.ui.casino.CPUPoints())
+	if got := g.info(); got != want {
+		t.Fatalf("info = %q, want %q", got, want)
+	}
+}
+
+func TestCardSkin(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	skin := filepath.Join(dataPath(skinsDir), "mine")
+	if err := os.MkdirAll(skin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ace := testPNG(t, 142, 192)
+	for name, data := range map[string]string{"1.png": ace, "2.svg": "<svg/>"} {
+		if err := os.WriteFile(filepath.Join(skin, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if names := skinNames(); len(names) != 1 || names[0] != "mine" {
+		t.Fatalf("skinNames() = %v, want [mine]", names)
+	}
+	if missing := missingSkinImages(os.DirFS(skin)); len(missing) != len(skinImageNames())-2 {
+		t.Fatalf("%d images missing, want %d", len(missing), len(skinImageNames())-2)
+	}
+	useSkin("mine")
+	t.Cleanup(func() { useSkin("") })
+	for name, want := range map[string]string{"1": ace, "2": "<svg/>"} {
+		p := cardAssetPath(name)
+		if data, err := fs.ReadFile(cardArtFS(p), p); err != nil || string(data) != want {
+			t.Errorf("card %s read %q from %s (%v), want the skin's %q", name, data, p, err, want)
+		}
+	}
+	builtIn, _ := fs.ReadFile(embeddedAssets, "assets/cards/3.png")
+	if data, err := fs.ReadFile(cardArtFS(cardAssetPath("3")), cardAssetPath("3")); err != nil || !bytes.Equal(data, builtIn) {
+		t.Errorf("card 3 did not fall back to the built-in image: %v", err)
+	}
+	// A skin of mixed or misshapen cards is refused whole.
+	for _, bad := range []string{testPNG(t, 71, 96), testPNG(t, 192, 142), "not a png"} {
+		if err := os.WriteFile(filepath.Join(skin, "3.png"), []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		useSkin("mine")
+		if skinArt != nil {
+			t.Errorf("a skin with a bad image is used: %v", checkSkinImages(os.DirFS(skin)))
+		}
+	}
+}
+
+// testPNG returns a blank PNG image of the given size.
+func testPNG(t *testing.T, width, height int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// middlePixel returns the colour in the middle of a window showing the
+// object.
+func middlePixel(t *testing.T, o fyne.CanvasObject) color.Color {
+	w := test.NewTempWindow(t, o)
+	w.Resize(fyne.NewSize(100, 120))
+	shot := w.Canvas().Capture()
+	return shot.At(shot.Bounds().Dx()/2, shot.Bounds().Dy()/2)
+}
+
+func TestTableDesigns(t *testing.T) {
+	g := newTestGame(t)
+	t.Cleanup(func() {
+		for _, part := range tableParts {
+			useTableDesign(part, "")
+		}
+		loadUIArt()
+	})
+	for _, part := range tableParts {
+		designs := part.designs()
+		if len(designs) < 2 {
+			t.Fatalf("%s has designs %v", part.dir, designs)
+		}
+		for _, name := range designs {
+			useTableDesign(part, name)
+			loadUIArt()
+			res := map[tablePart]fyne.Resource{backgroundPart: resourceBackground, cardBackPart: resourceCardBack, framePart: resourceFrame}[part]
+			if res == nil || res.Name() != name+".svg" {
+				t.Fatalf("design %s of %s loaded %v", name, part.dir, res)
+			}
+			// The toolkit can draw it: its middle is not left blank.
+			if middlePixel(t, canvas.NewImageFromResource(res)) == middlePixel(t, canvas.NewImageFromResource(nil)) {
+				t.Errorf("design %s of %s draws nothing", name, part.dir)
+			}
+		}
+		useTableDesign(part, "Missing")
+		if tableDesigns[part] != "" {
+			t.Errorf("a missing design of %s is used: %q", part.dir, tableDesigns[part])
+		}
+	}
+	// A chosen card back is shown on the CPU's cards.
+	useTableDesign(cardBackPart, "Lattice")
+	g.ui.reloadImages()
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	if res := g.ui.cpuCardWidgets[0].Resource; res == nil || res.Name() != resourceCardBack.Name() || !strings.Contains(res.Name(), "Lattice") {
+		t.Errorf("the CPU's card shows %v", res)
+	}
+}
+
+func TestShareResult(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	if g.ui.shareButton.Hidden {
+		t.Fatal("share button hidden after the game")
+	}
+	s := newResultSummary(g.ui.casino, g.ui.gameDuration)
+	score := fmt.Sprintf("You %d - %d CPU", g.ui.casino.PlayerPoints(), g.ui.casino.CPUPoints())
+	if text := s.text(); !strings.Contains(text, score) || !strings.Contains(text, "Beginner level") {
+		t.Fatalf("result text %q lacks the score %q or the level", text, score)
+	}
+	if size := s.render().Bounds().Size(); size.X != 2*int(resultCardSize.Width) || size.Y != 2*int(resultCardSize.Height) {
+		t.Fatalf("result image is %v", size)
+	}
+}
+
+func TestReplayExport(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	save, err := g.ui.casino.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := renderReplay(save, replayOptions{delay: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The deal, every move and the final score.
+	if want := len(g.ui.casino.Snapshot().Moves) + 2; len(full.Image) != want {
+		t.Fatalf("whole game has %d frames, want %d", len(full.Image), want)
+	}
+	if last := full.Delay[len(full.Delay)-1]; last != 100*replayEndHold {
+		t.Fatalf("final frame shows for %d hundredths, want %d", last, 100*replayEndHold)
+	}
+	captures, err := renderReplay(save, replayOptions{captures: true, delay: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(captures.Image) < 2 || len(captures.Image) >= len(full.Image) {
+		t.Fatalf("captures only has %d frames of %d", len(captures.Image), len(full.Image))
+	}
+	data, err := encodeReplay(captures, "replay.gif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gif.DecodeAll(bytes.NewReader(data)); err != nil {
+		t.Fatalf("exported GIF does not decode: %v", err)
+	}
+}
+
+func TestGameCodeRoundTrip(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Intermediate")
+	g.tap(g.ui.startButton)
+	for i := 0; i < 3; i++ {
+		g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	}
+	code, err := g.ui.casino.GameCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := g.ui.casino.StateHash()
+	other := newTestGame(t)
+	data, err := engine.DecodeGameCode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.ui.loadGame(data); err != nil {
+		t.Fatal(err)
+	}
+	other.settle()
+	if got := other.ui.casino.StateHash(); got != want {
+		t.Fatalf("the loaded game differs from the coded one:\n got %+v\nwant %+v", other.ui.casino.Snapshot(), g.ui.casino.Snapshot())
+	}
+	if other.ui.levelSelect.Selected != "Intermediate" || !other.ui.levelSelect.Disabled() {
+		t.Fatalf("level select shows %q, disabled %v", other.ui.levelSelect.Selected, other.ui.levelSelect.Disabled())
+	}
+	other.checkScreen()
+}
+
+// recordingMirror is an achievement store integration that remembers what
+// it was passed.
+type recordingMirror struct{ ids []string }
+
+func (m *recordingMirror) name() string { return "test" }
+
+func (m *recordingMirror) unlock(ids []string) error {
+	m.ids = append(m.ids, ids...)
+	return nil
+}
+
+func TestAchievements(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mirror := &recordingMirror{}
+	achievementMirrors = []achievementMirror{mirror}
+	t.Cleanup(func() { achievementMirrors = nil })
+	// A strong player beats Beginner soon enough.
+	strong, _ := strategyNamed("Advanced")
+	var c *engine.Casino
+	for seed := int64(1); c == nil || c.PlayerPoints() <= c.CPUPoints(); seed++ {
+		if seed > 50 {
+			t.Fatal("no game won")
+		}
+		c = engine.NewCasino(nil, nil)
+		c.SetLevel(engine.LevelBeginner)
+		c.StartGameWithSeed(seed)
+		rng := rand.New(rand.NewSource(seed))
+		for advanceGame(c, nil); c.State() == engine.StatePlayerTurn; advanceGame(c, nil) {
+			if err := c.PlayerPlays(c.PlayerChoice(strong, rng)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	s := loadAchievements()
+	titles := s.check(c, nil)
+	var want []string
+	for _, a := range achievements {
+		if a.earned(c.Snapshot(), nil) {
+			want = append(want, a.id)
+		}
+	}
+	if len(titles) != len(want) || !strings.Contains(fmt.Sprint(want), "first_win") {
+		t.Fatalf("unlocked %v, want %v including first_win", titles, want)
+	}
+	if again := s.check(c, nil); again != nil {
+		t.Fatalf("unlocked %v a second time", again)
+	}
+	if fmt.Sprint(mirror.ids) != fmt.Sprint(want) {
+		t.Fatalf("mirrored %v, want %v", mirror.ids, want)
+	}
+	mirror.ids = nil
+	if got := loadAchievements().ids(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("record holds %v, want %v", got, want)
+	}
+	if fmt.Sprint(mirror.ids) != fmt.Sprint(want) {
+		t.Fatalf("loading mirrored %v, want %v", mirror.ids, want)
+	}
+	// Regular counts the wins in the statistics, not this game's.
+	stats := &statsStore{Levels: map[string]*levelStats{"Advanced": {Wins: advancedWinsTarget - 1}}}
+	if got := s.check(c, stats); got != nil {
+		t.Fatalf("unlocked %v with %d Advanced wins", got, advancedWinsTarget-1)
+	}
+	stats.Levels["Advanced"].Wins++
+	if got := s.check(c, stats); fmt.Sprint(got) != "[Regular]" {
+		t.Fatalf("unlocked %v with %d Advanced wins, want Regular", got, advancedWinsTarget)
+	}
+}
+
+func TestAchievementToastAndGallery(t *testing.T) {
+	g := newTestGame(t)
+	g.ui.showAchievementGallery() // Nothing unlocked yet.
+	for games := 0; g.ui.achievements.ids() == nil; games++ {
+		if games > 10 {
+			t.Fatal("no achievement unlocked")
+		}
+		g.ui.resetGameUI()
+		g.selectLevel("Beginner")
+		g.tap(g.ui.startButton)
+		g.playToEnd()
+	}
+	if !strings.HasPrefix(g.ui.toast.text.Text, "Achievement unlocked: ") {
+		t.Errorf("the toast reads %q", g.ui.toast.text.Text)
+	}
+	if g.ui.toast.overlay.Visible() {
+		t.Error("the toast is still shown after its time")
+	}
+	if strings.Contains(g.info(), "Achievement") {
+		t.Errorf("the unlock is also in the message %q", g.info())
+	}
+	id := g.ui.achievements.ids()[0]
+	if at, ok := g.ui.achievements.unlockedAt(id); !ok || at.IsZero() {
+		t.Errorf("%s was unlocked at %v, %v", id, at, ok)
+	}
+	if _, ok := g.ui.achievements.unlockedAt("no_such_achievement"); ok {
+		t.Error("an unknown achievement is unlocked")
+	}
+	overlays := len(g.ui.window.Canvas().Overlays().List())
+	g.ui.showAchievementGallery()
+	if len(g.ui.window.Canvas().Overlays().List()) != overlays+1 {
+		t.Error("the gallery was not shown")
+	}
+}
+
+func TestTurnNotification(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	test.AssertNotificationSent(t, nil, func() {
+		g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	})
+	g.ui.inBackground = true
+	want := &fyne.Notification{Title: "Pishti", Content: "Your move!"}
+	test.AssertNotificationSent(t, want, func() {
+		g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	})
+	fyne.CurrentApp().Preferences().SetBool(prefNotifyTurn, false)
+	test.AssertNotificationSent(t, nil, func() {
+		g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	})
+}
+
+func TestKeyboardPlay(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	c := g.ui.window.Canvas()
+	c.SetOnTypedKey(g.ui.handleKey) // As main does.
+	// Tab reaches every card in the hand and none of the others.
+	reached := map[fyne.Focusable]bool{}
+	for i := 0; i < 40; i++ {
+		c.FocusNext()
+		reached[c.Focused()] = true
+	}
+	for i, w := range g.ui.playerCardWidgets {
+		if !reached[w] {
+			t.Errorf("Tab never reaches card %d", i)
+		}
+	}
+	for _, w := range append(g.ui.cpuCardWidgets, g.ui.tableCardWidget) {
+		if reached[w] {
+			t.Error("Tab reaches a card that cannot be played")
+		}
+	}
+	c.Focus(g.ui.playerCardWidgets[0])
+	g.ui.playerCardWidgets[0].TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	if c.Focused() != g.ui.playerCardWidgets[3] {
+		t.Fatal("Left from the first card does not wrap to the last")
+	}
+	played := g.ui.casino.PlayerHand()[3]
+	g.ui.playerCardWidgets[3].TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	g.settle()
+	if hand := g.ui.casino.PlayerHand(); hand[3] != nil || played == nil {
+		t.Fatalf("Enter did not play the focused card, hand %v", hand)
+	}
+	g.ui.playerCardWidgets[3].TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight})
+	if c.Focused() != g.ui.playerCardWidgets[0] {
+		t.Fatal("Right from the last card does not wrap to the first")
+	}
+	g.ui.playerCardWidgets[0].TypedKey(&fyne.KeyEvent{Name: fyne.KeyM})
+	if !IsMuted() {
+		t.Fatal("shortcuts are lost while a card has the focus")
+	}
+	g.ui.toggleMute()
+	g.checkScreen()
+}
+
+func TestSpectate(t *testing.T) {
+	g := newTestGame(t)
+	player, _ := strategyNamed("Advanced")
+	cpu, _ := strategyNamed("Beginner")
+	g.ui.startSpectating(spectateOptions{player: player, cpu: cpu, speed: 2, showHands: true})
+	if g.ui.levelSelect.Selected != "Beginner" || !strings.Contains(g.info(), "Watching Advanced") {
+		t.Fatalf("level %q, message %q", g.ui.levelSelect.Selected, g.info())
+	}
+	if shown, _ := g.ui.view.cpuHand[0].Get(); !shown.faceUp {
+		t.Error("the CPU's hand is hidden")
+	}
+	if g.ui.canPlayCard(0) {
+		t.Error("the player may play during the match")
+	}
+	g.settle()
+	if g.ui.casino.State() != engine.StateGameOver {
+		t.Fatalf("the match stopped in %s", g.ui.casino.State())
+	}
+	g.checkScreen()
+	g.ui.resetGameUI()
+	if g.ui.spectator != nil || g.ui.view.revealCPU {
+		t.Error("still spectating after a reset")
+	}
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	if !g.ui.canPlayCard(g.playableSlot()) {
+		t.Error("the player cannot play after the match")
+	}
+}
+
+func TestPracticeOdds(t *testing.T) {
+	g := newTestGame(t)
+	g.ui.setPractice(true)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	odds := g.ui.casino.PlayerOdds()
+	for i, b := range g.ui.oddsBadges {
+		if !b.content.Visible() || b.text.Text != describeOdds(odds[i]) {
+			t.Errorf("slot %d shows %q, visible %v; want %q", i, b.text.Text, b.content.Visible(), describeOdds(odds[i]))
+		}
+	}
+	g.playToEnd()
+	if !g.ui.unrated || g.ui.achievements.ids() != nil {
+		t.Errorf("practice game unlocked %v", g.ui.achievements.ids())
+	}
+	for i, b := range g.ui.oddsBadges {
+		if b.content.Visible() {
+			t.Errorf("slot %d shows odds after the game", i)
+		}
+	}
+	g.ui.setPractice(false)
+	g.tap(g.ui.replayButton)
+	if g.ui.unrated {
+		t.Error("a game without practice is unrated")
+	}
+}
+
+func TestReviewBranch(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	if !g.ui.reviewButton.Visible() {
+		t.Fatal("no review button after the game")
+	}
+	g.ui.showReview()
+	save, err := g.ui.casino.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := branchPoints(save)
+	if err != nil || len(points) != engine.DeckSize/2-engine.HandSize/2 {
+		t.Fatalf("%d branch points, %v", len(points), err)
+	}
+	if _, err := renderBranchPoint(save, points[2], "Your move 3"); err != nil {
+		t.Fatal(err)
+	}
+	branched, err := engine.Branch(save, points[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ui.startAnalysis(branched, 3); err != nil {
+		t.Fatal(err)
+	}
+	g.settle()
+	if s := g.ui.casino.Snapshot(); s.State != engine.StatePlayerTurn || len(s.Moves) != points[2] {
+		t.Fatalf("analysis starts in %s after %d moves, want the player's move after %d", s.State, len(s.Moves), points[2])
+	}
+	if g.ui.levelSelect.Selected != "Beginner" || !strings.HasPrefix(g.info(), "Analysis") {
+		t.Fatalf("level %q, message %q", g.ui.levelSelect.Selected, g.info())
+	}
+	g.playToEnd()
+	if !strings.HasPrefix(g.info(), "Analysis: ") || !g.ui.unrated {
+		t.Fatalf("analysis ended with %q, unrated %v", g.info(), g.ui.unrated)
+	}
+	g.tap(g.ui.replayButton)
+	if g.ui.analysis || g.ui.unrated {
+		t.Error("a replayed deal still counts as analysis")
+	}
+}
+
+func TestParseVoiceCommand(t *testing.T) {
+	hand := []*engine.Card{
+		engine.NewCard(engine.Seven, engine.Hearts, ""),
+		nil,
+		engine.NewCard(engine.Jack, engine.Clubs, ""),
+		engine.NewCard(engine.Three, engine.Spades, ""),
+	}
+	for _, tc := range []struct {
+		phrase  string
+		command voiceCommand
+		slot    int
+	}{
+		{"Undo!", voiceUndo, -1},
+		{"take that back", voiceNone, -1},
+		{"take back", voiceUndo, -1},
+		{"play the first card", voicePlay, 0},
+		{"play the second one", voicePlay, 2},
+		{"play the last card", voicePlay, 3},
+		{"play card 3", voicePlay, 3},
+		{"play card three", voicePlay, 3},
+		{"play the three", voicePlay, 3},
+		{"play the jack", voicePlay, 2},
+		{"seven of hearts", voicePlay, 0},
+		{"play a spade", voicePlay, 3},
+		{"play the seven of spades", voicePlay, -1},
+		{"play the fourth card", voicePlay, -1},
+		{"what a game", voiceNone, -1},
+	} {
+		command, slot := parseVoiceCommand(tc.phrase, hand)
+		if command != tc.command || slot != tc.slot {
+			t.Errorf("%q = %d, slot %d; want %d, slot %d", tc.phrase, command, slot, tc.command, tc.slot)
+		}
+	}
+}
+
+func TestVoicePlays(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.ui.handleVoice("play the first card")
+	g.settle()
+	if g.ui.casino.PlayerHand()[0] != nil {
+		t.Fatal("the first card was not played")
+	}
+	g.ui.handleVoice("undo")
+	if g.ui.casino.PlayerHand()[0] == nil {
+		t.Fatal("the play was not undone")
+	}
+	g.checkScreen()
+}
+
+func TestHotSeat(t *testing.T) {
+	g := newTestGame(t)
+	g.ui.startHotSeat()
+	g.settle()
+	faceUp := func(hand [engine.HandSize]binding.Item[shownCard]) bool {
+		shown, _ := hand[0].Get()
+		return shown.faceUp
+	}
+	for moves := 0; g.ui.casino.State() != engine.StateGameOver; moves++ {
+		if moves > 60 {
+			t.Fatal("the game never ended")
+		}
+		if !g.ui.pass.overlay.Visible() || faceUp(g.ui.view.playerHand) || faceUp(g.ui.view.cpuHand) {
+			t.Fatalf("move %d: the hands are not hidden while the device is passed on", moves)
+		}
+		if g.ui.canPlayCard(0) || g.ui.canPlayOpponentCard(0) {
+			t.Fatalf("move %d: a card can be played before the device was passed on", moves)
+		}
+		test.Tap(g.ui.pass.ready)
+		if g.ui.pass.overlay.Visible() {
+			t.Fatal("the overlay stayed up")
+		}
+		if g.ui.casino.State() == engine.StateCPUTurn {
+			if faceUp(g.ui.view.playerHand) || !faceUp(g.ui.view.cpuHand) {
+				t.Fatal("Player 2 does not see only their own hand")
+			}
+			slot := 0
+			for !g.ui.canPlayOpponentCard(slot) {
+				slot++
+			}
+			g.tap(g.ui.cpuCardWidgets[slot])
+		} else {
+			if !faceUp(g.ui.view.playerHand) || faceUp(g.ui.view.cpuHand) {
+				t.Fatal("Player 1 does not see only their own hand")
+			}
+			g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+		}
+	}
+	if !strings.Contains(g.info(), "Player 1 ") || !g.ui.unrated || g.ui.achievements.ids() != nil {
+		t.Errorf("game over message %q, unlocked %v", g.info(), g.ui.achievements.ids())
+	}
+	g.ui.resetGameUI()
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	if g.ui.hotSeat != nil || g.ui.pass.overlay.Visible() || g.ui.casino.State() != engine.StatePlayerTurn || !faceUp(g.ui.view.playerHand) {
+		t.Fatal("the CPU did not get its seat back")
+	}
+	g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	if g.ui.casino.State() == engine.StateCPUTurn {
+		t.Error("the CPU did not reply")
+	}
+	g.checkScreen()
+}
+
+func TestOnlinePlay(t *testing.T) {
+	g := newTestGame(t)
+	s, err := online.NewServer(engine.Variants()[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	// Updates arrive from the connection's goroutine.
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !done(); time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s; the message is %q", what, g.info())
+			}
+		}
+	}
+	host, err := online.Dial(url, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ui.startOnline(host)
+	waitFor("the room to open", func() bool { return strings.Contains(g.info(), "waiting for your opponent") })
+	guest, err := online.Dial(url, host.Room)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer guest.Close()
+	waitFor("the deal", func() bool { return g.playableSlot() >= 0 })
+	slot := g.playableSlot()
+	played := g.ui.view.playerCard(slot)
+	test.Tap(g.ui.playerCardWidgets[slot])
+	waitFor("the opponent's turn", func() bool { return strings.Contains(g.info(), "opponent's move") })
+	if g.ui.view.playerCard(slot) != nil {
+		t.Errorf("the %v is still in the hand", played)
+	}
+	if g.playableSlot() >= 0 {
+		t.Error("a card can be played on the opponent's turn")
+	}
+	for i, w := range g.ui.cpuCardWidgets {
+		if w.Resource != resourceCardBack {
+			t.Errorf("the opponent's slot %d is not face down", i)
+		}
+	}
+	g.ui.resetGameUI()
+	if g.ui.remote != nil || g.ui.casino.State() != engine.StateNotStarted {
+		t.Error("the online game was not left")
+	}
+	for {
+		m, err := guest.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.State != nil && m.State.Waiting {
+			break
+		}
+	}
+}
+
+func TestLifetimeStats(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	c := g.ui.casino
+	want := levelStats{Played: 1, Points: c.PlayerPoints(), Pistis: playerPistis(c.Snapshot(), false), JackPistis: playerPistis(c.Snapshot(), true)}
+	switch {
+	case c.PlayerPoints() > c.CPUPoints():
+		want.Wins = 1
+	case c.PlayerPoints() < c.CPUPoints():
+		want.Losses = 1
+	default:
+		want.Ties = 1
+	}
+	if got := loadStats().Levels["Beginner"]; got == nil || *got != want {
+		t.Fatalf("saved %+v, want %+v", got, want)
+	}
+	// A practice game is not counted.
+	g.ui.resetGameUI()
+	g.ui.setPractice(true)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	names, rows := loadStats().rows()
+	if len(rows) != 1 || names[0] != "Beginner" || rows[0].Played != 1 {
+		t.Errorf("after a practice game the statistics are %v %+v", names, rows)
+	}
+	g.ui.showStats()
+}
+
+func TestReplayViewer(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Advanced")
+	g.tap(g.ui.startButton)
+	g.playToEnd()
+	over := g.info()
+	g.tap(g.ui.watchButton)
+	if !g.ui.replayBar.Visible() || !strings.HasPrefix(g.info(), "Step 0 of ") {
+		t.Fatalf("the viewer did not open at the deal: %q", g.info())
+	}
+	for i, w := range g.ui.cpuCardWidgets {
+		if w.Resource == nil || w.Resource == resourceCardBack {
+			t.Errorf("the CPU's slot %d is not shown face up at the deal", i)
+		}
+	}
+	if !g.ui.replay.prev.Disabled() {
+		t.Error("there is a step before the deal")
+	}
+	g.tap(g.ui.replay.next)
+	first := g.ui.casino.Snapshot().Moves[0]
+	if top, _ := g.ui.view.tableTop.Get(); top.card.String() != first.Card.String() || !strings.Contains(g.info(), cardName(first.Card)) {
+		t.Errorf("step 1 shows %v and %q, want the %v", top.card, g.info(), first.Card)
+	}
+	g.ui.replay.slider.SetValue(g.ui.replay.slider.Max)
+	if !strings.HasPrefix(g.info(), "Final score") || !g.ui.replay.next.Disabled() {
+		t.Errorf("the last step shows %q", g.info())
+	}
+	g.tap(g.ui.replay.prev)
+	overlays := len(g.ui.window.Canvas().Overlays().List())
+	g.tap(g.ui.replay.export)
+	if len(g.ui.window.Canvas().Overlays().List()) != overlays+1 {
+		t.Error("exporting from the viewer opened no dialog")
+	}
+	g.ui.closeReplayViewer()
+	if g.ui.replayBar.Visible() || g.info() != over {
+		t.Errorf("after closing the message is %q, want %q", g.info(), over)
+	}
+	g.checkScreen()
+}
+
+func TestMatchPlay(t *testing.T) {
+	g := newTestGame(t)
+	g.ui.loop.SetMatchTarget(51)
+	g.selectLevel("Advanced")
+	g.tap(g.ui.startButton)
+	if line, _ := g.ui.view.match.Get(); line != "Deal 1 — You 0, CPU 0" {
+		t.Fatalf("the match starts with %q", line)
+	}
+	for deal := 1; ; deal++ {
+		if deal > 20 {
+			t.Fatal("the match never ended")
+		}
+		g.playToEnd()
+		m := g.ui.casino.Match()
+		if line, _ := g.ui.view.match.Get(); line != matchLine(m) || m.Deal != deal {
+			t.Fatalf("deal %d shows %q for %+v", deal, line, m)
+		}
+		if m.Over {
+			if !strings.Contains(g.info(), "wins the match to 51!") || g.ui.startButton.Text != "New Game" {
+				t.Errorf("the match ended with %q and a %q button", g.info(), g.ui.startButton.Text)
+			}
+			break
+		}
+		if g.ui.startButton.Text != "Next Deal" || g.ui.replayButton.Visible() || !strings.Contains(g.info(), "Match to 51") {
+			t.Fatalf("after deal %d: %q button, %q", deal, g.ui.startButton.Text, g.info())
+		}
+		g.tap(g.ui.startButton)
+		if g.ui.casino.State() != engine.StatePlayerTurn || g.ui.casino.Match().Deal != deal+1 {
+			t.Fatalf("Next Deal left the game in %s", g.ui.casino.State())
+		}
+	}
+	// Single games show no match score.
+	g.ui.loop.SetMatchTarget(0)
+	g.ui.resetGameUI()
+	g.selectLevel("Advanced")
+	g.tap(g.ui.startButton)
+	if line, _ := g.ui.view.match.Get(); line != "" {
+		t.Errorf("a single game shows %q", line)
+	}
+}
+
+func TestGamePreferences(t *testing.T) {
+	g := newTestGame(t)
+	prefs := fyne.CurrentApp().Preferences()
+	prefs.SetString(prefDefaultLevel, "Intermediate")
+	prefs.SetFloat(prefGameSpeed, 2)
+	g.ui.applySettings()
+	if g.ui.levelSelect.Selected != "Intermediate" || g.ui.casino.Level() != engine.LevelIntermediate {
+		t.Fatalf("the default level was not selected: %q", g.ui.levelSelect.Selected)
+	}
+	if got, want := gamePacing().CPUDelay, appConfig.AI.CPUDelay/2; got != want {
+		t.Errorf("fast games wait %v for the CPU, want %v", got, want)
+	}
+	g.tap(g.ui.startButton)
+	g.ui.resetGameUI()
+	if g.ui.levelSelect.Selected != "Intermediate" {
+		t.Errorf("after a game the level is %q", g.ui.levelSelect.Selected)
+	}
+	// An unknown name leaves the level to be picked.
+	prefs.SetString(prefDefaultLevel, "Grandmaster")
+	g.ui.resetGameUI()
+	if g.ui.levelSelect.Selected != "" {
+		t.Errorf("an unknown default level selected %q", g.ui.levelSelect.Selected)
+	}
+	g.ui.showSettings()
+}
+
+func TestMaxVoicesSetting(t *testing.T) {
+	g := newTestGame(t)
+	soundMutex.Lock()
+	saved := maps.Clone(maxVoices)
+	soundMutex.Unlock()
+	t.Cleanup(func() {
+		soundMutex.Lock()
+		maxVoices = saved
+		soundMutex.Unlock()
+	})
+	appConfig.Audio.MaxVoices = map[string]int{"capture": 1, "pisti": 3, "fanfare": 9}
+	g.ui.applySettings()
+	soundMutex.Lock()
+	defer soundMutex.Unlock()
+	if voiceLimit(SoundCapture) != 1 || voiceLimit(SoundPisti) != 3 || voiceLimit(SoundCardPlay) != 4 {
+		t.Errorf("caps are %d for captures, %d for pişti and %d for cards, want 1, 3 and the built-in 4",
+			voiceLimit(SoundCapture), voiceLimit(SoundPisti), voiceLimit(SoundCardPlay))
+	}
+}
+
+func TestHouseRulesPreference(t *testing.T) {
+	g := newTestGame(t)
+	prefs := fyne.CurrentApp().Preferences()
+	house := engine.Rules{PistiPoints: 5, JackPistiPoints: 50, MajorityPoints: 1, DeuceOfClubsPoints: 2, TenOfDiamondsPoints: 3}
+	b, _ := json.Marshal(house)
+	prefs.SetString(prefHouseRules, string(b))
+	prefs.SetString(prefVariant, engine.CustomVariant)
+	g.ui.applySettings()
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	if got := g.ui.casino.Snapshot().Rules; got != house {
+		t.Errorf("the game is played with %+v, want %+v", got, house)
+	}
+	if got := describeRules(house); !strings.Contains(got, "Jack Pişti 50") || strings.Contains(got, "nobody") {
+		t.Errorf("described as %q", got)
+	}
+	// Broken preferences fall back to the standard rules.
+	prefs.SetString(prefHouseRules, "{")
+	if savedHouseRules(prefs) != engine.StandardRules {
+		t.Error("broken house rules were used")
+	}
+	g.ui.showSettings()
+}
+
+func TestCardAnimations(t *testing.T) {
+	g := newTestGame(t)
+	if cardAnimationTime() <= 0 {
+		t.Fatal("cards do not slide by default")
+	}
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	for moves := 0; g.ui.casino.State() != engine.StateGameOver && moves < 60; moves++ {
+		// The test app finishes each slide at once, so nothing is left in flight.
+		if g.ui.isAnimating() || len(g.ui.cards.layer.Objects) > 0 || !g.ui.tableCardWidget.Visible() {
+			t.Fatalf("move %d: %d cards in flight, table card shown: %v", moves, len(g.ui.cards.layer.Objects), g.ui.tableCardWidget.Visible())
+		}
+		slot := g.playableSlot()
+		if slot < 0 {
+			t.Fatal("no card can be played")
+		}
+		g.tap(g.ui.playerCardWidgets[slot])
+		g.checkScreen()
+	}
+	// Each card played slides to the table, and each pile taken to a score.
+	snap := g.ui.casino.Snapshot()
+	if want := len(snap.Moves) + len(snap.Captures); g.ui.cards.flights != want {
+		t.Errorf("%d cards slid for %d moves and %d captures", g.ui.cards.flights, len(snap.Moves), len(snap.Captures))
+	}
+	g.ui.resetGameUI()
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.ui.cards.running++ // As if a card were still sliding.
+	if slot := g.playableSlot(); slot >= 0 {
+		t.Errorf("slot %d can be played while a card slides", slot)
+	}
+	g.ui.cards.running--
+	if g.playableSlot() < 0 {
+		t.Error("no card can be played once the slide is over")
+	}
+}
+
+func TestDailyDeal(t *testing.T) {
+	g := newTestGame(t)
+	today := dailyDate(time.Now())
+	if dailySeed(today) == dailySeed("2001-01-01") || dailySeed(today) < 0 {
+		t.Fatalf("daily seed %d", dailySeed(today))
+	}
+	g.selectLevel("Beginner")
+	g.ui.startDailyDeal()
+	g.settle()
+	if g.ui.casino.Snapshot().Seed != dailySeed(today) || !strings.Contains(g.info(), today) {
+		t.Fatalf("the Daily Deal was dealt from seed %d: %q", g.ui.casino.Snapshot().Seed, g.info())
+	}
+	g.playToEnd()
+	points := g.ui.casino.PlayerPoints()
+	if want := fmt.Sprintf("%d points, your best today %d.", points, points); !strings.Contains(g.info(), want) {
+		t.Errorf("game over reads %q, want %q", g.info(), want)
+	}
+	if s := newResultSummary(g.ui.casino, 0); !strings.Contains(s.text(), "Seed ") {
+		t.Errorf("result text %q", s.text())
+	}
+	// A better score of the day stays the best.
+	prefs := fyne.CurrentApp().Preferences()
+	prefs.SetString(prefDailyBestPrefix+"Beginner", fmt.Sprintf("%s|%d", today, points+50))
+	g.ui.replaySameDeal()
+	g.settle()
+	g.playToEnd()
+	if want := fmt.Sprintf("your best today %d.", points+50); !strings.Contains(g.info(), want) {
+		t.Errorf("game over reads %q, want %q", g.info(), want)
+	}
+	// Any other game is not a Daily Deal.
+	g.ui.resetGameUI()
+	g.selectLevel("Beginner")
+	g.ui.startGameWith(func() bool { return g.ui.loop.StartGameWithSeed(42) })
+	g.settle()
+	g.playToEnd()
+	if g.ui.daily != "" || strings.Contains(g.info(), "Daily Deal") || g.ui.casino.Snapshot().Seed != 42 {
+		t.Errorf("seeded game over reads %q", g.info())
+	}
+	if validateSeed("12a") == nil || validateSeed("-7") != nil {
+		t.Error("seeds are not validated")
+	}
+}
+
+func TestWindowScaling(t *testing.T) {
+	g := newTestGame(t)
+	for _, c := range []struct {
+		window fyne.Size
+		scale  float32
+	}{
+		{baseWindowSize, 1},
+		{fyne.NewSize(880, 1300), 2},    // The smaller side decides.
+		{fyne.NewSize(600, 700), 1.125}, // Rounded to a step.
+		{fyne.NewSize(200, 200), minLayoutScale},
+		{fyne.NewSize(5000, 5000), maxLayoutScale},
+	} {
+		g.ui.window.Resize(c.window)
+		card := fyne.NewSize(71*c.scale, 96*c.scale)
+		if g.ui.scaler.scale != c.scale || g.ui.playerCardWidgets[0].MinSize() != card || g.ui.tableCardWidget.Size() != card {
+			t.Errorf("a %v window is scaled by %v with cards of %v, want %v and %v",
+				c.window, g.ui.scaler.scale, g.ui.playerCardWidgets[0].MinSize(), c.scale, card)
+		}
+	}
+}
+
+func TestSuspendedGame(t *testing.T) {
+	g := newTestGame(t)
+	g.selectLevel("Beginner")
+	g.tap(g.ui.startButton)
+	g.tap(g.ui.playerCardWidgets[g.playableSlot()])
+	want := g.ui.casino.Snapshot()
+	g.ui.suspendGame()
+	g.ui.resetGameUI()
+	g.ui.resumeSuspendedGame()
+	g.settle()
+	if got := g.ui.casino.Snapshot(); got.Seed != want.Seed || len(got.Moves) != len(want.Moves) || !strings.Contains(g.info(), "restored") {
+		t.Fatalf("resumed at move %d of seed %d, want %d of %d: %q", len(got.Moves), got.Seed, len(want.Moves), want.Seed, g.info())
+	}
+	g.checkScreen()
+	// The kept game is used once, and a finished one is not kept.
+	g.playToEnd()
+	g.ui.suspendGame()
+	g.ui.resetGameUI()
+	g.ui.resumeSuspendedGame()
+	if g.ui.casino.State() != engine.StateNotStarted {
+		t.Errorf("a finished game was resumed in state %v", g.ui.casino.State())
+	}
+}
+
+func TestEffectsToggle(t *testing.T) {
+	t.Cleanup(func() { SetEffectsEnabled(true) })
+	SetEffectsEnabled(false)
+	if EffectiveVolume(SoundCapture) != 0 || EffectiveVolume(SoundAnnouncePisti) != 0 || EffectiveVolume(SoundBackground) == 0 {
+		t.Errorf("with the effects off, a capture plays at %v, the announcer at %v and the music at %v",
+			EffectiveVolume(SoundCapture), EffectiveVolume(SoundAnnouncePisti), EffectiveVolume(SoundBackground))
+	}
+	SetEffectsEnabled(true)
+	if EffectiveVolume(SoundCapture) == 0 {
+		t.Error("the effects stay silent once turned back on")
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/data/binding"
+
+	"pishti/engine"
+)
+
+// shownCard is what a card slot on screen displays: the card, if any, and
+// whether it is face up.
+type shownCard struct {
+	card   *engine.Card
+	faceUp bool
+}
+
+// resource returns the image of the shown card; nil leaves the slot empty.
+func (s shownCard) resource() fyne.Resource {
+	switch {
+	case s.card == nil:
+		return nil
+	case s.faceUp:
+		return getCardResource(s.card)
+	default:
+		return resourceCardBack
+	}
+}
+
+// gameView is the view model between the engine and the widgets. It holds
+// what the screen shows as data bindings, refreshed from the casino when its
+// events say something changed; the widgets follow through binding
+// listeners and never read the casino themselves.
+type gameView struct {
+	casino      *engine.Casino
+	playerScore binding.Int
+	cpuScore    binding.Int
+	match       binding.String // The running score of a match, "" outside one.
+	info        binding.String // The message under the table.
+	playerHand  [engine.HandSize]binding.Item[shownCard]
+	cpuHand     [engine.HandSize]binding.Item[shownCard]
+	tableTop    binding.Item[shownCard]
+	tableUnder  binding.Item[shownCard] // The card peeking out under the top one.
+	state       binding.Item[engine.GameState]
+	canUndo     binding.Bool
+	revealCPU   bool     // Show the CPU's hand face up, for the debug console.
+	hidePlayer  bool     // Show the player's hand face down, while a Pass & Play device changes hands.
+	redraws     []func() // Re-render every bound card, for reloaded images.
+}
+
+// newGameView returns a view model of the casino showing an empty table.
+func newGameView(c *engine.Casino) *gameView {
+	same := func(a, b shownCard) bool { return a == b }
+	v := &gameView{
+		casino:      c,
+		playerScore: binding.NewInt(),
+		cpuScore:    binding.NewInt(),
+		match:       binding.NewString(),
+		info:        binding.NewString(),
+		tableTop:    binding.NewItem(same),
+		tableUnder:  binding.NewItem(same),
+		state:       binding.NewItem(func(a, b engine.GameState) bool { return a == b }),
+		canUndo:     binding.NewBool(),
+	}
+	for i := range v.playerHand {
+		v.playerHand[i] = binding.NewItem(same)
+		v.cpuHand[i] = binding.NewItem(same)
+	}
+	return v
+}
+
+// viewParts selects what refresh reads back from the casino.
+type viewParts struct {
+	scores, playerHand, cpuHand, table, controls bool
+}
+
+// allParts refreshes the whole view.
+var allParts = viewParts{true, true, true, true, true}
+
+// refresh copies the selected parts of the game into the bindings. Only the
+// bindings whose value changed notify their widgets.
+func (v *gameView) refresh(parts viewParts) {
+	c := v.casino
+	if parts.scores {
+		v.playerScore.Set(c.PlayerPoints())
+		v.cpuScore.Set(c.CPUPoints())
+		v.match.Set(matchLine(c.Match()))
+	}
+	if parts.playerHand {
+		setHand(v.playerHand, c.PlayerHand(), !v.hidePlayer)
+	}
+	if parts.cpuHand {
+		setHand(v.cpuHand, c.CPUHand(), v.revealCPU)
+	}
+	if parts.table {
+		var top, under shownCard
+		if table := c.TableCards(); len(table) > 0 {
+			top = shownCard{card: table[len(table)-1], faceUp: true}
+			if len(table) > 1 {
+				// At the very start of the game, the cards under the top card are face down.
+				under = shownCard{card: table[len(table)-2], faceUp: !c.IsInitialPile()}
+			}
+		}
+		v.tableTop.Set(top)
+		v.tableUnder.Set(under)
+	}
+	if parts.controls {
+		v.state.Set(c.State())
+		v.canUndo.Set(c.CanUndo())
+	}
+}
+
+// setHand puts a hand into its slot bindings.
+func setHand(slots [engine.HandSize]binding.Item[shownCard], hand []*engine.Card, faceUp bool) {
+	for i, slot := range slots {
+		slot.Set(shownCard{card: hand[i], faceUp: faceUp})
+	}
+}
+
+// setRevealCPU shows or hides the CPU's cards.
+func (v *gameView) setRevealCPU(reveal bool) {
+	v.revealCPU = reveal
+	v.refresh(viewParts{cpuHand: true})
+}
+
+// setFaceUp shows or hides each hand, for the seat of a Pass & Play game
+// that has the device.
+func (v *gameView) setFaceUp(player, cpu bool) {
+	v.hidePlayer, v.revealCPU = !player, cpu
+	v.refresh(viewParts{playerHand: true, cpuHand: true})
+}
+
+// currentState returns the game state as last refreshed.
+func (v *gameView) currentState() engine.GameState {
+	state, _ := v.state.Get()
+	return state
+}
+
+// playerCard returns the card shown in a slot of the player's hand.
+func (v *gameView) playerCard(slot int) *engine.Card {
+	shown, _ := v.playerHand[slot].Get()
+	return shown.card
+}
+
+// bindCard makes a card widget show the card in a binding, now and whenever
+// it changes. show receives the image to draw, nil for an empty slot.
+func (v *gameView) bindCard(slot binding.Item[shownCard], show func(fyne.Resource)) {
+	render := func() {
+		shown, _ := slot.Get()
+		show(shown.resource())
+	}
+	slot.AddListener(binding.NewDataListener(render))
+	v.redraws = append(v.redraws, render)
+}
+
+// showOnImage returns a bindCard target that draws into an image.
+func showOnImage(img *canvas.Image) func(fyne.Resource) {
+	return func(res fyne.Resource) {
+		if img.Resource != res {
+			img.Resource = res
+			img.Image = nil // Clear any previously decoded image data.
+			img.Refresh()
+		}
+	}
+}
+
+// showOnCard returns a bindCard target that draws into a card widget.
+func showOnCard(w *clickableImage) func(fyne.Resource) {
+	return func(res fyne.Resource) {
+		if w.Resource != res {
+			w.Resource = res
+			w.Refresh()
+		}
+	}
+}
+
+// redraw renders every bound card again, after the images were reloaded.
+func (v *gameView) redraw() {
+	for _, render := range v.redraws {
+		render()
+	}
+}
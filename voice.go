@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+
+	"pishti/engine"
+)
+
+// voiceCommand is what a spoken phrase asks for.
+type voiceCommand int
+
+const (
+	voiceNone voiceCommand = iota // Not a command; most of what a microphone hears.
+	voicePlay
+	voiceUndo
+)
+
+// voiceOrdinals name a card by its place among the cards held.
+var voiceOrdinals = map[string]int{"first": 0, "second": 1, "third": 2, "fourth": 3}
+
+// voiceNumbers are the spoken numbers, also standing for digits.
+var voiceNumbers = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// voiceRanks and voiceSuits are the spoken names of the ranks and suits.
+var (
+	voiceRanks = map[string]engine.Rank{
+		"ace": engine.Ace, "two": engine.Deuce, "deuce": engine.Deuce, "three": engine.Three, "four": engine.Four,
+		"five": engine.Five, "six": engine.Six, "seven": engine.Seven, "eight": engine.Eight, "nine": engine.Nine,
+		"ten": engine.Ten, "jack": engine.Jack, "queen": engine.Queen, "king": engine.King,
+	}
+	voiceSuits = map[string]engine.Suit{
+		"heart": engine.Hearts, "diamond": engine.Diamonds, "club": engine.Clubs, "spade": engine.Spades,
+	}
+)
+
+// parseVoiceCommand reads a phrase such as "undo", "play the third card",
+// "play card two", "play the jack" or "seven of hearts". For voicePlay it
+// also returns the hand slot of the card meant, or -1 if no card held fits.
+func parseVoiceCommand(phrase string, hand []*engine.Card) (voiceCommand, int) {
+	words := strings.FieldsFunc(strings.ToLower(phrase), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var held []int
+	for i, c := range hand {
+		if c != nil {
+			held = append(held, i)
+		}
+	}
+	place, rank, suit := -1, engine.Rank(-1), engine.Suit(-1)
+	for i, w := range words {
+		if n, err := strconv.Atoi(w); err == nil && n >= 1 && n <= len(voiceNumbers) {
+			w = voiceNumbers[n-1]
+		}
+		prev, next := "", ""
+		if i > 0 {
+			prev = words[i-1]
+		}
+		if i+1 < len(words) {
+			next = words[i+1]
+		}
+		n, isOrdinal := voiceOrdinals[w]
+		switch {
+		case w == "undo" || w == "take" && next == "back":
+			return voiceUndo, -1
+		case isOrdinal:
+			place = n
+		case w == "last":
+			place = len(held) - 1
+		case prev == "card" || prev == "number":
+			// "Card three" is a place in the hand, not a rank.
+			for n, number := range voiceNumbers[:engine.HandSize] {
+				if w == number {
+					place = n
+				}
+			}
+		}
+		if r, ok := voiceRanks[w]; ok && prev != "card" && prev != "number" {
+			rank = r
+		}
+		if s, ok := voiceSuits[strings.TrimSuffix(w, "s")]; ok {
+			suit = s
+		}
+	}
+	switch {
+	case place >= 0:
+		if place < len(held) {
+			return voicePlay, held[place]
+		}
+		return voicePlay, -1
+	case rank >= 0 || suit >= 0:
+		for _, i := range held {
+			if (rank < 0 || hand[i].GetFace() == rank) && (suit < 0 || hand[i].GetSuit() == suit) {
+				return voicePlay, i
+			}
+		}
+		return voicePlay, -1
+	}
+	return voiceNone, -1
+}
+
+// voiceListener runs the speech recognizer while voice control is on.
+type voiceListener struct {
+	cmd     *exec.Cmd
+	stopped atomic.Bool // Set before the recognizer is killed on purpose.
+}
+
+// setVoiceControl starts or stops listening for spoken commands. Without a
+// recognizer in the config file it does nothing.
+func (ui *AppUI) setVoiceControl(on bool) {
+	if ui.voice != nil {
+		ui.voice.stopped.Store(true)
+		ui.voice.cmd.Process.Kill()
+		ui.voice = nil
+	}
+	args := appConfig.Voice.Command
+	if !on || len(args) == 0 {
+		return
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	out, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to start the speech recognizer %s: %v", args[0], err)
+		return
+	}
+	l := &voiceListener{cmd: cmd}
+	ui.voice = l
+	go func() {
+		lines := bufio.NewScanner(out)
+		for lines.Scan() {
+			phrase := lines.Text()
+			fyne.Do(func() { ui.handleVoice(phrase) })
+		}
+		if err := cmd.Wait(); err != nil && !l.stopped.Load() {
+			log.Printf("ERROR: The speech recognizer stopped: %v", err)
+		}
+	}()
+}
+
+// handleVoice carries out a phrase the recognizer heard. Phrases that are
+// not commands are ignored, as the microphone hears everything said.
+func (ui *AppUI) handleVoice(phrase string) {
+	command, slot := parseVoiceCommand(phrase, ui.casino.PlayerHand())
+	switch command {
+	case voiceUndo:
+		if !ui.undoButton.Disabled() && ui.undoButton.Visible() {
+			ui.undoButton.OnTapped()
+		}
+	case voicePlay:
+		switch {
+		case slot < 0:
+			ui.view.info.Set(T("You do not hold that card."))
+		case ui.canPlayCard(slot):
+			ui.playerPlays(slot)
+		}
+	}
+}